@@ -8,6 +8,29 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			if err := serve(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error running SQDesk server: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "view":
+			if err := view(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error viewing file: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "sync":
+			if err := syncShared(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error syncing shared library: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	app, err := tui.New()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing SQDesk: %v\n", err)
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/server"
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+)
+
+// serve runs "sqdesk serve": JSON-RPC over a Unix socket, or over
+// stdin/stdout with --stdio for editors that spawn the server
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	socket := flags.String("socket", "", "Unix socket to listen on (default: sqdesk.sock in the config directory)")
+	stdio := flags.Bool("stdio", false, "serve a single client on stdin/stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if _, err := shared.Apply(cfg); err != nil {
+		return fmt.Errorf("failed to load shared library: %w", err)
+	}
+	srv := server.New(cfg)
+	defer srv.Close()
+
+	if *stdio {
+		return srv.ServeConn(os.Stdin, os.Stdout)
+	}
+
+	path := *socket
+	if path == "" {
+		dir, err := config.GetConfigDir()
+		if err != nil {
+			return err
+		}
+		if err := config.EnsureConfigDir(); err != nil {
+			return err
+		}
+		path = filepath.Join(dir, "sqdesk.sock")
+	}
+	path = config.ExpandPath(path)
+
+	// A socket left behind by a crashed server blocks Listen
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("a server is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "SQDesk server listening on %s\n", path)
+	return srv.Serve(listener)
+}
+
+// listenPrivate listens on a Unix socket at path that only the owner may
+// connect to, since it runs queries with the configured credentials. The
+// socket is made 0600 inside a new 0700 directory and then moved into
+// place, so others never get a chance to connect.
+func listenPrivate(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".sqdesk-sock-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(tmp, 0o600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+)
+
+// syncShared runs "sqdesk sync": it clones or pulls the shared library and
+// reports what it holds
+func syncShared(args []string) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.Shared.Enabled() {
+		return errors.New("no shared library; set shared.repo or shared.dir in the config")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	output, err := shared.Sync(ctx, cfg.Shared)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		fmt.Println(output)
+	}
+
+	dir, err := cfg.Shared.SharedDir()
+	if err != nil {
+		return err
+	}
+	lib, err := shared.Load(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Shared library %s: %d snippets, %d connections\n", dir, len(lib.Snippets), len(lib.Connections))
+	return nil
+}
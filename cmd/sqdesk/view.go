@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/febritecno/sqdesk-cli/internal/tui"
+)
+
+// view runs "sqdesk view <file>": it shows the rows of a CSV, JSON or
+// Parquet file in the results grid, without a database
+func view(args []string) error {
+	flags := flag.NewFlagSet("view", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: sqdesk view <file.csv|file.json|file.parquet>")
+	}
+
+	app, err := tui.NewViewer(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return app.Run()
+}
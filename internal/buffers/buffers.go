@@ -0,0 +1,135 @@
+// Package buffers keeps the editor text of each connection with its undo
+// history in the config directory, so a session can be picked up again
+// after a restart.
+package buffers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// dirName is the buffers directory in the config directory
+const dirName = "buffers"
+
+// State is one undo step: the text and where the cursor was
+type State struct {
+	Text   string `json:"text"`
+	Cursor int    `json:"cursor"`
+}
+
+// Buffer is the editor text of a connection and its undo history
+type Buffer struct {
+	Connection string    `json:"connection"`
+	Saved      time.Time `json:"saved"`
+	Text       string    `json:"text"`
+	Cursor     int       `json:"cursor"`
+	History    []State   `json:"history,omitempty"`
+	Index      int       `json:"index"` // Current state in History
+}
+
+// Store is the buffers directory, one file per connection
+type Store struct {
+	dir   string
+	limit int
+}
+
+// NewStore opens the buffers directory, keeping up to limit undo steps of
+// each buffer
+func NewStore(limit int) (*Store, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: filepath.Join(dir, dirName), limit: limit}, nil
+}
+
+// Load returns the buffer saved for connection; ok is false when there is
+// none
+func (s *Store) Load(connection string) (b Buffer, ok bool, err error) {
+	data, err := os.ReadFile(s.path(connection))
+	if os.IsNotExist(err) {
+		return b, false, nil
+	}
+	if err != nil {
+		return b, false, fmt.Errorf("buffers: %w", err)
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, false, fmt.Errorf("buffers: %s: %w", s.path(connection), err)
+	}
+	// A file renamed by hand doesn't belong to connection
+	if b.Connection != connection {
+		return Buffer{}, false, nil
+	}
+	if b.Index < 0 || b.Index >= len(b.History) {
+		b.History, b.Index = nil, 0
+	}
+	// The file may have been edited; keep cursors inside their text
+	b.Cursor = clampCursor(b.Cursor, b.Text)
+	for i := range b.History {
+		b.History[i].Cursor = clampCursor(b.History[i].Cursor, b.History[i].Text)
+	}
+	return b, true, nil
+}
+
+// Save replaces the buffer of b.Connection, dropping the undo steps past
+// the limit, oldest first but never the current one. The file is readable
+// by the owner only since queries may hold data.
+func (s *Store) Save(b Buffer) error {
+	if n := len(b.History); s.limit > 0 && n > s.limit {
+		start := min(n-s.limit, max(b.Index, 0))
+		b.History = b.History[start : start+s.limit]
+		b.Index -= start
+	}
+	if b.Saved.IsZero() {
+		b.Saved = time.Now()
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("buffers: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("buffers: %w", err)
+	}
+	path := s.path(b.Connection)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("buffers: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("buffers: %w", err)
+	}
+	return nil
+}
+
+// clampCursor limits a cursor offset to [0, len(text)]
+func clampCursor(cursor int, text string) int {
+	return min(max(cursor, 0), len(text))
+}
+
+// path returns the file of connection's buffer. A short hash of the name
+// keeps names that fileSafe maps alike, such as "a/b" and "a:b", apart.
+func (s *Store) path(connection string) string {
+	sum := sha256.Sum256([]byte(connection))
+	return filepath.Join(s.dir, fileSafe(connection)+"-"+hex.EncodeToString(sum[:4])+".json")
+}
+
+// fileSafe replaces characters that don't belong in file names
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
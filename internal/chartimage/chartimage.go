@@ -0,0 +1,170 @@
+// Package chartimage draws the charts of the results pane as PNG images,
+// with the shapes only: labels and values stay in the text export.
+package chartimage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// Kind is the type of chart to draw
+type Kind int
+
+const (
+	Bar Kind = iota
+	Line
+	Pie
+)
+
+// Image size and margin in pixels
+const (
+	width  = 960
+	height = 540
+	margin = 40
+)
+
+var (
+	background = color.RGBA{0x28, 0x2a, 0x36, 0xff}
+	axis       = color.RGBA{0x62, 0x72, 0xa4, 0xff}
+	// palette colors bars and pie slices in turn
+	palette = []color.RGBA{
+		{0xbd, 0x93, 0xf9, 0xff},
+		{0x8b, 0xe9, 0xfd, 0xff},
+		{0x50, 0xfa, 0x7b, 0xff},
+		{0xff, 0xb8, 0x6c, 0xff},
+		{0xff, 0x79, 0xc6, 0xff},
+		{0xf1, 0xfa, 0x8c, 0xff},
+	}
+)
+
+// WritePNG draws values as a chart of kind and writes it to w as a PNG.
+// Bars run across like in the results pane; negative bars and slices are
+// left out.
+func WritePNG(w io.Writer, kind Kind, values []float64) error {
+	if len(values) == 0 {
+		return fmt.Errorf("no values to chart")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), background)
+	switch kind {
+	case Line:
+		drawLine(img, values)
+	case Pie:
+		drawPie(img, values)
+	default:
+		drawBars(img, values)
+	}
+	return png.Encode(w, img)
+}
+
+// fill paints rect with c
+func fill(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawBars draws one bar per value from a left axis
+func drawBars(img *image.RGBA, values []float64) {
+	top := 0.0
+	for _, v := range values {
+		top = math.Max(top, v)
+	}
+	fill(img, image.Rect(margin-2, margin, margin, height-margin), axis)
+	if top <= 0 {
+		return
+	}
+	slot := float64(height-2*margin) / float64(len(values))
+	gap := math.Min(slot/4, 4)
+	for i, v := range values {
+		if v <= 0 {
+			continue
+		}
+		y0 := margin + int(float64(i)*slot+gap/2)
+		y1 := margin + int(float64(i+1)*slot-gap/2)
+		length := int(v / top * float64(width-2*margin))
+		fill(img, image.Rect(margin, y0, margin+length, max(y1, y0+1)), palette[i%len(palette)])
+	}
+}
+
+// drawLine draws the values as a line over a bottom axis, scaled between
+// their minimum and maximum
+func drawLine(img *image.RGBA, values []float64) {
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if hi == lo {
+		hi, lo = hi+1, lo-1
+	}
+	fill(img, image.Rect(margin, height-margin, width-margin, height-margin+2), axis)
+	point := func(i int) (float64, float64) {
+		x := float64(margin)
+		if len(values) > 1 {
+			x += float64(i) * float64(width-2*margin) / float64(len(values)-1)
+		}
+		y := float64(height-margin) - (values[i]-lo)/(hi-lo)*float64(height-2*margin)
+		return x, y
+	}
+	if len(values) == 1 {
+		x, y := point(0)
+		fill(img, image.Rect(int(x)-2, int(y)-2, int(x)+3, int(y)+3), palette[0])
+		return
+	}
+	for i := 1; i < len(values); i++ {
+		x0, y0 := point(i - 1)
+		x1, y1 := point(i)
+		steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+		for s := 0; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			x, y := int(x0+(x1-x0)*t), int(y0+(y1-y0)*t)
+			fill(img, image.Rect(x-1, y-1, x+2, y+2), palette[0])
+		}
+	}
+}
+
+// drawPie draws a slice per positive value, clockwise from the top
+func drawPie(img *image.RGBA, values []float64) {
+	total := 0.0
+	for _, v := range values {
+		total += math.Max(v, 0)
+	}
+	if total <= 0 {
+		return
+	}
+	// Slice i ends at ends[i], a fraction of the full turn
+	ends := make([]float64, len(values))
+	sum := 0.0
+	for i, v := range values {
+		sum += math.Max(v, 0)
+		ends[i] = sum / total
+	}
+
+	cx, cy := width/2, height/2
+	radius := float64(height/2 - margin)
+	for y := cy - int(radius); y <= cy+int(radius); y++ {
+		for x := cx - int(radius); x <= cx+int(radius); x++ {
+			dx, dy := float64(x-cx), float64(y-cy)
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			turn := math.Atan2(dx, -dy) / (2 * math.Pi)
+			if turn < 0 {
+				turn++
+			}
+			for i, end := range ends {
+				if turn < end {
+					img.SetRGBA(x, y, palette[i%len(palette)])
+					break
+				}
+			}
+		}
+	}
+}
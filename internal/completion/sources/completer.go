@@ -0,0 +1,92 @@
+package sources
+
+import (
+	"sort"
+
+	"github.com/febritecno/sqdesk-cli/internal/completion"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// Completer completes SQL from keywords of the connection's dialect, its
+// schema and the queries added to its history, for callers without an
+// editor such as the server
+type Completer struct {
+	engine   *completion.Engine
+	keywords *KeywordSource
+	schema   *SchemaSource
+	history  *HistorySource
+	tables   []string
+}
+
+// NewCompleter returns a Completer with generic SQL keywords and no schema
+func NewCompleter() *Completer {
+	c := &Completer{
+		engine:   completion.NewEngine(),
+		keywords: NewKeywordSource(),
+		schema:   NewSchemaSource(),
+		history:  NewHistorySource(),
+	}
+	c.engine.RegisterSource(c.keywords)
+	c.engine.RegisterSource(c.schema)
+	c.engine.RegisterSource(c.history)
+	return c
+}
+
+// RegisterSource adds a source of completions
+func (c *Completer) RegisterSource(source completion.Source) {
+	c.engine.RegisterSource(source)
+	c.engine.ClearCache()
+}
+
+// SetDriver adds the keywords of a driver's dialect ("postgres", "mysql",
+// ...); empty keeps generic SQL only
+func (c *Completer) SetDriver(driver string) {
+	c.keywords.SetDriver(driver)
+	c.engine.ClearCache()
+}
+
+// SetSchema replaces the tables, columns, indexes and foreign keys that
+// are suggested
+func (c *Completer) SetSchema(schema *db.Schema) {
+	c.schema.Clear()
+	c.tables = nil
+	var tables []TableInfo
+	indexes := make(map[string][]IndexInfo)
+	foreignKeys := make(map[string][]ForeignKeyInfo)
+	if schema != nil {
+		for name, table := range schema.Tables {
+			c.tables = append(c.tables, name)
+			columns := make([]ColumnInfo, len(table.Columns))
+			for i, col := range table.Columns {
+				columns[i] = ColumnInfo{Name: col.Name, Type: col.Type, Nullable: col.Nullable, IsPrimary: col.IsPK}
+			}
+			c.schema.SetColumns(name, columns)
+			for _, idx := range table.Indexes {
+				indexes[name] = append(indexes[name], IndexInfo{Name: idx.Name, Columns: idx.Columns, Unique: idx.Unique})
+			}
+			for _, fk := range table.ForeignKeys {
+				foreignKeys[name] = append(foreignKeys[name], ForeignKeyInfo{Columns: fk.Columns, RefTable: fk.RefTable, RefColumns: fk.RefColumns})
+			}
+		}
+	}
+	sort.Strings(c.tables)
+	for _, name := range c.tables {
+		tables = append(tables, TableInfo{Name: name})
+	}
+	c.schema.SetTables(tables)
+	c.schema.SetIndexes(indexes)
+	c.schema.SetForeignKeys(foreignKeys)
+	c.engine.ClearCache()
+}
+
+// AddHistory remembers a query for history completions
+func (c *Completer) AddHistory(query string) {
+	c.history.AddQuery(query)
+	c.engine.ClearCache()
+}
+
+// Complete returns the suggestions for the byte offset cursor in query,
+// best first. database is the current database name, if any.
+func (c *Completer) Complete(query string, cursor int, database string) []completion.CompletionItem {
+	return c.engine.Complete(query, cursor, database, c.tables)
+}
@@ -36,14 +36,44 @@ var sqlKeywords = map[string][]string{
 	},
 }
 
+// cqlKeywords are CQL (Cassandra/Scylla) keywords added when a CQL driver is active
+var cqlKeywords = []string{
+	"KEYSPACE", "KEYSPACES", "REPLICATION", "DURABLE_WRITES", "ALLOW", "FILTERING",
+	"USING", "TTL", "WRITETIME", "TOKEN", "BATCH", "APPLY", "UNLOGGED",
+	"PRIMARY", "KEY", "CLUSTERING", "PARTITION", "PER", "COMPACT", "STORAGE",
+	"MATERIALIZED", "VIEW", "CONTAINS", "IF", "EXISTS", "TYPE", "FROZEN",
+}
+
+// cqlTypes are CQL-specific column types
+var cqlTypes = []string{
+	"ASCII", "VARINT", "UUID", "TIMEUUID", "INET", "DURATION", "COUNTER",
+	"MAP", "LIST", "SET", "TUPLE",
+}
+
+// DialectKeywords returns extra keywords for a driver's SQL dialect
+func DialectKeywords(driver string) []string {
+	switch driver {
+	case "cassandra":
+		return append(append([]string{}, cqlKeywords...), cqlTypes...)
+	}
+	return nil
+}
+
 // KeywordSource provides SQL keyword completions
-type KeywordSource struct{}
+type KeywordSource struct {
+	dialect []string
+}
 
 // NewKeywordSource creates a new keyword source
 func NewKeywordSource() *KeywordSource {
 	return &KeywordSource{}
 }
 
+// SetDriver adds the dialect keywords for the active driver
+func (s *KeywordSource) SetDriver(driver string) {
+	s.dialect = DialectKeywords(driver)
+}
+
 // Name returns the source name
 func (s *KeywordSource) Name() string {
 	return "keywords"
@@ -87,6 +117,23 @@ func (s *KeywordSource) Complete(ctx completion.Context) ([]completion.Completio
 		}
 	}
 	
+	// Add dialect keywords (e.g. CQL)
+	for _, kw := range s.dialect {
+		item := completion.CompletionItem{
+			Label:      kw,
+			InsertText: kw + " ",
+			Kind:       completion.KindKeyword,
+			Detail:     "Dialect Keyword",
+			Source:     s.Name(),
+			Score:      40,
+			FilterText: kw,
+		}
+		if ctx.Word != "" && strings.HasPrefix(strings.ToLower(kw), strings.ToLower(ctx.Word)) {
+			item.Score += 30
+		}
+		items = append(items, item)
+	}
+	
 	// Add functions
 	for _, fn := range sqlKeywords["functions"] {
 		item := completion.CompletionItem{
@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/febritecno/sqdesk-cli/internal/completion"
@@ -10,6 +11,9 @@ import (
 type SchemaSource struct {
 	tables  []TableInfo
 	columns map[string][]ColumnInfo // table name -> columns
+	indexes map[string][]IndexInfo  // table name -> indexes
+	// foreignKeys maps a table name to its foreign keys
+	foreignKeys map[string][]ForeignKeyInfo
 }
 
 // TableInfo holds table metadata
@@ -28,11 +32,27 @@ type ColumnInfo struct {
 	Comment   string
 }
 
+// IndexInfo holds index metadata
+type IndexInfo struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKeyInfo holds foreign key metadata
+type ForeignKeyInfo struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
 // NewSchemaSource creates a new schema source
 func NewSchemaSource() *SchemaSource {
 	return &SchemaSource{
-		tables:  make([]TableInfo, 0),
-		columns: make(map[string][]ColumnInfo),
+		tables:      make([]TableInfo, 0),
+		columns:     make(map[string][]ColumnInfo),
+		indexes:     make(map[string][]IndexInfo),
+		foreignKeys: make(map[string][]ForeignKeyInfo),
 	}
 }
 
@@ -56,6 +76,16 @@ func (s *SchemaSource) SetColumns(tableName string, columns []ColumnInfo) {
 	s.columns[tableName] = columns
 }
 
+// SetIndexes replaces the indexes of all tables
+func (s *SchemaSource) SetIndexes(indexes map[string][]IndexInfo) {
+	s.indexes = indexes
+}
+
+// SetForeignKeys replaces the foreign keys of all tables
+func (s *SchemaSource) SetForeignKeys(foreignKeys map[string][]ForeignKeyInfo) {
+	s.foreignKeys = foreignKeys
+}
+
 // LoadFromStrings loads tables from simple string slice
 func (s *SchemaSource) LoadFromStrings(tableNames []string) {
 	s.tables = make([]TableInfo, len(tableNames))
@@ -80,6 +110,14 @@ func (s *SchemaSource) Complete(ctx completion.Context) ([]completion.Completion
 		items = append(items, s.getColumnItems(ctx)...)
 	}
 	
+	if s.shouldSuggestIndexes(ctx.LinePrefix) {
+		items = append(items, s.getIndexItems(ctx)...)
+	}
+	
+	if s.shouldSuggestJoins(ctx.LinePrefix, ctx.Word) {
+		items = append(items, s.getJoinItems(ctx)...)
+	}
+	
 	return items, nil
 }
 
@@ -123,6 +161,138 @@ func (s *SchemaSource) shouldSuggestColumns(linePrefix string) bool {
 	return false
 }
 
+// shouldSuggestIndexes checks if index names should be suggested: after
+// INDEX (DROP/ALTER INDEX, MySQL index hints) and REINDEX
+func (s *SchemaSource) shouldSuggestIndexes(linePrefix string) bool {
+	linePrefix = strings.TrimRight(strings.ToUpper(linePrefix), " (")
+	// A new index needs a new name
+	if strings.HasSuffix(linePrefix, "CREATE INDEX") || strings.HasSuffix(linePrefix, "UNIQUE INDEX") {
+		return false
+	}
+	for _, trigger := range []string{"INDEX", "INDEX IF EXISTS", "REINDEX", "INDEXED BY"} {
+		if strings.HasSuffix(linePrefix, trigger) {
+			return true
+		}
+	}
+	return false
+}
+
+// getIndexItems returns completion items for indexes
+func (s *SchemaSource) getIndexItems(ctx completion.Context) []completion.CompletionItem {
+	items := make([]completion.CompletionItem, 0)
+	
+	for tableName, indexes := range s.indexes {
+		for _, idx := range indexes {
+			detail := tableName + " (" + strings.Join(idx.Columns, ", ") + ")"
+			if idx.Unique {
+				detail = "UNIQUE " + detail
+			}
+			items = append(items, completion.CompletionItem{
+				Label:      idx.Name,
+				InsertText: idx.Name,
+				Kind:       completion.KindIndex,
+				Detail:     detail,
+				Source:     s.Name(),
+				Score:      88,
+				FilterText: idx.Name,
+			})
+		}
+	}
+	
+	return items
+}
+
+// shouldSuggestJoins checks if join conditions should be suggested: right
+// after the ON of a JOIN
+func (s *SchemaSource) shouldSuggestJoins(linePrefix, word string) bool {
+	linePrefix = strings.TrimSpace(strings.TrimSuffix(linePrefix, strings.ToUpper(word)))
+	return linePrefix == "ON" || strings.HasSuffix(linePrefix, " ON")
+}
+
+// tableRefPattern matches the tables of FROM and JOIN clauses, and
+// aliasPattern the alias that may follow them
+var (
+	tableRefPattern = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+([\w."]+)`)
+	aliasPattern    = regexp.MustCompile(`(?i)^\s+(?:AS\s+)?(\w+)`)
+)
+
+// tableRef is a table named in a query and the name to qualify it by
+type tableRef struct {
+	table string
+	alias string
+}
+
+// notAlias lists keywords that can follow a table name in place of an alias
+var notAlias = map[string]bool{
+	"ON": true, "USING": true, "WHERE": true, "JOIN": true, "INNER": true, "LEFT": true,
+	"RIGHT": true, "FULL": true, "CROSS": true, "NATURAL": true, "OUTER": true,
+	"GROUP": true, "ORDER": true, "LIMIT": true, "HAVING": true, "UNION": true, "SET": true,
+}
+
+// tableRefs returns the tables of the FROM and JOIN clauses in sql
+func tableRefs(sql string) []tableRef {
+	var refs []tableRef
+	for _, m := range tableRefPattern.FindAllStringSubmatchIndex(sql, -1) {
+		name := strings.ReplaceAll(sql[m[2]:m[3]], `"`, "")
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		ref := tableRef{table: name, alias: name}
+		if a := aliasPattern.FindStringSubmatch(sql[m[1]:]); a != nil && !notAlias[strings.ToUpper(a[1])] {
+			ref.alias = a[1]
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// getJoinItems returns join conditions between the table just joined and
+// the tables before it, following the foreign keys either way
+func (s *SchemaSource) getJoinItems(ctx completion.Context) []completion.CompletionItem {
+	items := make([]completion.CompletionItem, 0)
+	
+	refs := tableRefs(ctx.Query[:ctx.Cursor])
+	if len(refs) < 2 {
+		return items
+	}
+	joined := refs[len(refs)-1]
+	
+	for _, other := range refs[:len(refs)-1] {
+		for _, fk := range s.foreignKeys[joined.table] {
+			if strings.EqualFold(fk.RefTable, other.table) {
+				detail := "Foreign key " + joined.table + " → " + other.table
+				items = append(items, joinItem(joined, fk.Columns, other, fk.RefColumns, detail, s.Name()))
+			}
+		}
+		for _, fk := range s.foreignKeys[other.table] {
+			if strings.EqualFold(fk.RefTable, joined.table) {
+				detail := "Foreign key " + other.table + " → " + joined.table
+				items = append(items, joinItem(joined, fk.RefColumns, other, fk.Columns, detail, s.Name()))
+			}
+		}
+	}
+	
+	return items
+}
+
+// joinItem builds the completion item comparing the columns of two tables
+func joinItem(left tableRef, leftCols []string, right tableRef, rightCols []string, detail, source string) completion.CompletionItem {
+	conds := make([]string, len(leftCols))
+	for i := range leftCols {
+		conds[i] = left.alias + "." + leftCols[i] + " = " + right.alias + "." + rightCols[i]
+	}
+	text := strings.Join(conds, " AND ")
+	return completion.CompletionItem{
+		Label:      text,
+		InsertText: text,
+		Kind:       completion.KindJoin,
+		Detail:     detail,
+		Source:     source,
+		Score:      95,
+		FilterText: text,
+	}
+}
+
 // getTableItems returns completion items for tables
 func (s *SchemaSource) getTableItems(ctx completion.Context) []completion.CompletionItem {
 	items := make([]completion.CompletionItem, 0, len(s.tables))
@@ -186,4 +356,6 @@ func (s *SchemaSource) getColumnItems(ctx completion.Context) []completion.Compl
 func (s *SchemaSource) Clear() {
 	s.tables = make([]TableInfo, 0)
 	s.columns = make(map[string][]ColumnInfo)
+	s.indexes = make(map[string][]IndexInfo)
+	s.foreignKeys = make(map[string][]ForeignKeyInfo)
 }
@@ -0,0 +1,81 @@
+package sources
+
+import (
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/completion"
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+)
+
+// SnippetSource provides completions from the shared snippet library, or
+// from the user's own snippets
+type SnippetSource struct {
+	snippets []shared.Snippet
+	user     bool
+	body     func(shared.Snippet) string
+}
+
+// NewSnippetSource creates a new snippet source
+func NewSnippetSource() *SnippetSource {
+	return &SnippetSource{}
+}
+
+// NewUserSnippetSource creates a source of the user's snippets; body
+// returns the text a snippet inserts
+func NewUserSnippetSource(body func(shared.Snippet) string) *SnippetSource {
+	return &SnippetSource{user: true, body: body}
+}
+
+// Name returns the source name
+func (s *SnippetSource) Name() string {
+	if s.user {
+		return "user-snippets"
+	}
+	return "snippets"
+}
+
+// Priority returns the source priority
+func (s *SnippetSource) Priority() int {
+	if s.user {
+		return 65 // Own snippets before the shared ones
+	}
+	return 60 // Above keywords, below history
+}
+
+// SetSnippets replaces the snippets offered
+func (s *SnippetSource) SetSnippets(snippets []shared.Snippet) {
+	s.snippets = snippets
+}
+
+// Complete returns the snippets; the engine filters them by name
+func (s *SnippetSource) Complete(ctx completion.Context) ([]completion.CompletionItem, error) {
+	if len(ctx.Word) < 2 {
+		return nil, nil
+	}
+
+	items := make([]completion.CompletionItem, 0, len(s.snippets))
+	for _, snippet := range s.snippets {
+		detail := snippet.Description
+		if detail == "" && s.user {
+			detail = "Snippet"
+		} else if detail == "" {
+			detail = "Shared snippet"
+		}
+		insert := snippet.SQL
+		if s.body != nil {
+			insert = s.body(snippet)
+		}
+		// Match on the file name as well as the full path
+		base := snippet.Name[strings.LastIndex(snippet.Name, "/")+1:]
+		items = append(items, completion.CompletionItem{
+			Label:      snippet.Name,
+			InsertText: insert,
+			Kind:       completion.KindSnippet,
+			Detail:     detail,
+			Source:     s.Name(),
+			Score:      40,
+			FilterText: base + " " + snippet.Name,
+		})
+	}
+	return items, nil
+}
@@ -11,6 +11,8 @@ const (
 	KindSnippet
 	KindAI
 	KindHistory
+	KindIndex
+	KindJoin
 )
 
 // CompletionItem represents a single completion suggestion
@@ -64,6 +66,10 @@ func (k ItemKind) Icon() string {
 		return "🤖"
 	case KindHistory:
 		return "📜"
+	case KindIndex:
+		return "🔑"
+	case KindJoin:
+		return "🔗"
 	default:
 		return "  "
 	}
@@ -86,6 +92,10 @@ func (k ItemKind) Name() string {
 		return "AI"
 	case KindHistory:
 		return "History"
+	case KindIndex:
+		return "Index"
+	case KindJoin:
+		return "Join"
 	default:
 		return "Unknown"
 	}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,13 +13,106 @@ import (
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
 	Name     string `yaml:"name" mapstructure:"name"`
-	Driver   string `yaml:"driver" mapstructure:"driver"` // postgres, mysql, sqlite
+	Driver   string `yaml:"driver" mapstructure:"driver"` // postgres, cockroachdb, mysql, mariadb, sqlite, bigquery, cassandra
 	Host     string `yaml:"host" mapstructure:"host"`
 	Port     int    `yaml:"port" mapstructure:"port"`
 	User     string `yaml:"user" mapstructure:"user"`
 	Password string `yaml:"password" mapstructure:"password"`
 	Database string `yaml:"database" mapstructure:"database"`
 	SSLMode  string `yaml:"sslmode" mapstructure:"sslmode"`
+	// SSLRootCert is a CA bundle for verifying the server certificate;
+	// SSLCert and SSLKey are the client certificate for mutual TLS
+	SSLRootCert string `yaml:"sslrootcert,omitempty" mapstructure:"sslrootcert"`
+	SSLCert     string `yaml:"sslcert,omitempty" mapstructure:"sslcert"`
+	SSLKey      string `yaml:"sslkey,omitempty" mapstructure:"sslkey"`
+	// Socket is a unix socket path used instead of Host (Postgres, MySQL).
+	// For Postgres it may be the socket directory or the .s.PGSQL.<port> file.
+	Socket string `yaml:"socket,omitempty" mapstructure:"socket"`
+	// ReplicaHost is a read replica that read-only queries are sent to
+	// (Postgres, MySQL); ReplicaPort defaults to Port. It uses the same
+	// credentials, TLS settings and SSH hops as the primary.
+	ReplicaHost string `yaml:"replica_host,omitempty" mapstructure:"replica_host"`
+	ReplicaPort int    `yaml:"replica_port,omitempty" mapstructure:"replica_port"`
+	// CredentialsFile is the service account JSON key path (BigQuery)
+	CredentialsFile string `yaml:"credentials_file" mapstructure:"credentials_file"`
+	// ReadOnly refuses statements that modify data and opens the session
+	// read-only where the database supports it
+	ReadOnly bool `yaml:"read_only,omitempty" mapstructure:"read_only"`
+	// PromptPassword keeps Password out of the config file; it is asked
+	// for when connecting and only held in memory for the session
+	PromptPassword bool `yaml:"prompt_password,omitempty" mapstructure:"prompt_password"`
+	// ConnectTimeout bounds connection attempts in seconds (default 10)
+	ConnectTimeout int `yaml:"connect_timeout,omitempty" mapstructure:"connect_timeout"`
+	// QueryTimeout stops statements running longer than this many seconds,
+	// overriding the global query_timeout
+	QueryTimeout int `yaml:"query_timeout,omitempty" mapstructure:"query_timeout"`
+	// SSHHops is the chain of SSH servers to tunnel through, in order
+	// (e.g. bastion, then internal jump host)
+	SSHHops []SSHHop `yaml:"ssh_hops,omitempty" mapstructure:"ssh_hops"`
+	// ProtectedTables are critical tables; statements that mention one
+	// run only after typing the table's name to confirm
+	ProtectedTables []string `yaml:"protected_tables,omitempty" mapstructure:"protected_tables"`
+	// MaskColumns hides sensitive columns in masked exports
+	MaskColumns []MaskRule `yaml:"mask_columns,omitempty" mapstructure:"mask_columns"`
+	// Production marks a production connection; the linter flags SELECT *
+	// on it
+	Production bool `yaml:"production,omitempty" mapstructure:"production"`
+	// Shared marks a connection template from the shared library; it is
+	// read-only and never written to the config file
+	Shared bool `yaml:"-" mapstructure:"-"`
+}
+
+// MaskRule masks the values of matching result columns in masked exports
+type MaskRule struct {
+	// Column is matched case-insensitively against result column names;
+	// * matches any run of characters, e.g. *email*
+	Column string `yaml:"column" mapstructure:"column"`
+	Method string `yaml:"method,omitempty" mapstructure:"method"` // hash (default), redact or fake
+}
+
+// SSHHop is one SSH server in a tunnel chain
+type SSHHop struct {
+	Host          string `yaml:"host" mapstructure:"host"`
+	Port          int    `yaml:"port" mapstructure:"port"` // default 22
+	User          string `yaml:"user" mapstructure:"user"`
+	Password      string `yaml:"password,omitempty" mapstructure:"password"`
+	KeyFile       string `yaml:"key_file,omitempty" mapstructure:"key_file"`
+	KeyPassphrase string `yaml:"key_passphrase,omitempty" mapstructure:"key_passphrase"`
+	// KnownHostsFile defaults to ~/.ssh/known_hosts
+	KnownHostsFile string `yaml:"known_hosts_file,omitempty" mapstructure:"known_hosts_file"`
+	// InsecureIgnoreHostKey skips host key verification
+	InsecureIgnoreHostKey bool `yaml:"insecure_ignore_host_key,omitempty" mapstructure:"insecure_ignore_host_key"`
+}
+
+// UsesSocket reports whether the connection goes through a unix socket
+func (c DatabaseConfig) UsesSocket() bool {
+	return c.Socket != "" && c.Host == ""
+}
+
+// NeedsPassword reports whether the password must be asked for before
+// connecting
+func (c DatabaseConfig) NeedsPassword() bool {
+	return c.PromptPassword && c.Password == ""
+}
+
+// DefaultConnectTimeout is used when a connection sets no connect_timeout
+const DefaultConnectTimeout = 10 * time.Second
+
+// ConnectTimeoutDuration returns how long a connection attempt may take
+func (c DatabaseConfig) ConnectTimeoutDuration() time.Duration {
+	if c.ConnectTimeout <= 0 {
+		return DefaultConnectTimeout
+	}
+	return time.Duration(c.ConnectTimeout) * time.Second
+}
+
+// Address returns the hop's host:port
+func (h SSHHop) Address() string {
+	port := h.Port
+	if port == 0 {
+		port = 22
+	}
+	return fmt.Sprintf("%s:%d", h.Host, port)
 }
 
 // AIConfig holds AI provider configuration
@@ -27,6 +122,164 @@ type AIConfig struct {
 	Model    string `yaml:"model" mapstructure:"model"`
 }
 
+// MetricsConfig sends query latency and error metrics to an observability
+// backend
+type MetricsConfig struct {
+	Exporter string `yaml:"exporter" mapstructure:"exporter"` // statsd, otlp or none
+	// Endpoint is the statsd host:port (default 127.0.0.1:8125) or the
+	// OTLP/HTTP collector URL (default http://localhost:4318)
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+	Prefix   string `yaml:"prefix,omitempty" mapstructure:"prefix"` // metric name prefix, default sqdesk
+	// Headers are sent with OTLP requests (e.g. authentication)
+	Headers map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+}
+
+// HooksConfig runs a command and/or posts a webhook when a query finishes,
+// e.g. to get a chat message when a long analytical query is done
+type HooksConfig struct {
+	// Command runs through the shell with the event as JSON on stdin and
+	// SQDESK_* environment variables
+	Command string `yaml:"command,omitempty" mapstructure:"command"`
+	// Webhook receives the event as a JSON POST; its text field makes it
+	// usable as a Slack incoming webhook
+	Webhook string            `yaml:"webhook,omitempty" mapstructure:"webhook"`
+	Headers map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	// MinDuration in seconds; faster queries don't run hooks
+	MinDuration int `yaml:"min_duration,omitempty" mapstructure:"min_duration"`
+}
+
+// NotifyConfig alerts when a long query finishes while the terminal is
+// unfocused
+type NotifyConfig struct {
+	// After is the query duration in seconds that triggers an alert; 0 disables
+	After int `yaml:"after" mapstructure:"after"`
+	// Method is bell (default), osc777 or desktop (notify-send / osascript)
+	Method string `yaml:"method,omitempty" mapstructure:"method"`
+}
+
+// SnapshotConfig auto-saves the results of each query as a compressed file
+// that can be re-opened offline, e.g. as evidence during an incident
+type SnapshotConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+	Dir     string `yaml:"dir,omitempty" mapstructure:"dir"` // default <config dir>/snapshots
+	// Retention; the oldest snapshots are deleted past any limit, -1 = no limit
+	MaxCount   int `yaml:"max_count,omitempty" mapstructure:"max_count"`       // default 200
+	MaxAgeDays int `yaml:"max_age_days,omitempty" mapstructure:"max_age_days"` // default 30
+	MaxMB      int `yaml:"max_mb,omitempty" mapstructure:"max_mb"`             // default 512
+}
+
+// LintConfig configures the query linter
+type LintConfig struct {
+	// OnExecute lints queries before they run; findings of error severity
+	// ask for confirmation first
+	OnExecute bool `yaml:"on_execute,omitempty" mapstructure:"on_execute"`
+	// Rules overrides rule severities: off, info, warning or error
+	Rules map[string]string `yaml:"rules,omitempty" mapstructure:"rules"`
+}
+
+// TimeFilterConfig sets the defaults of the time filter helper
+type TimeFilterConfig struct {
+	// Column is the column offered first, e.g. created_at
+	Column string `yaml:"column,omitempty" mapstructure:"column"`
+	// WeekStart is the first day of "this week": monday (default) or sunday
+	WeekStart string `yaml:"week_start,omitempty" mapstructure:"week_start"`
+}
+
+// FormatConfig sets how Format SQL lays out queries
+type FormatConfig struct {
+	// Command pipes the query through an external formatter instead, e.g.
+	// "pg_format -"; it reads the SQL on stdin and writes it to stdout
+	Command string `yaml:"command,omitempty" mapstructure:"command"`
+	// KeywordCase is upper (default), lower or preserve
+	KeywordCase string `yaml:"keyword_case,omitempty" mapstructure:"keyword_case"`
+	// Indent is the number of spaces per level (default 2)
+	Indent int `yaml:"indent,omitempty" mapstructure:"indent"`
+	// CommaFirst starts list lines with the comma instead of ending them
+	CommaFirst bool `yaml:"comma_first,omitempty" mapstructure:"comma_first"`
+}
+
+// CSVImportConfig sets how CSV files being imported write values
+type CSVImportConfig struct {
+	// Delimiter separates fields; detected from the header when empty
+	Delimiter string `yaml:"delimiter,omitempty" mapstructure:"delimiter"`
+	// DateOrder reads dates like 03/04/2024: dmy (default), mdy or ymd
+	DateOrder string `yaml:"date_order,omitempty" mapstructure:"date_order"`
+	// DecimalSeparator is "." (default) or ","
+	DecimalSeparator string `yaml:"decimal_separator,omitempty" mapstructure:"decimal_separator"`
+	// ThousandsSeparator groups digits: ",", ".", "space" or "none"; by
+	// default whichever of "." and "," isn't the decimal separator
+	ThousandsSeparator string `yaml:"thousands_separator,omitempty" mapstructure:"thousands_separator"`
+}
+
+// SharedConfig points at a git-managed directory of team-shared snippets
+// and connection templates, merged read-only over the local config
+type SharedConfig struct {
+	// Repo is cloned into Dir by "sqdesk sync" when Dir isn't a checkout yet
+	Repo string `yaml:"repo,omitempty" mapstructure:"repo"`
+	Dir  string `yaml:"dir,omitempty" mapstructure:"dir"` // default <config dir>/shared
+}
+
+// Enabled reports whether a shared library is configured
+func (s SharedConfig) Enabled() bool {
+	return s.Repo != "" || s.Dir != ""
+}
+
+// SharedDir returns the directory of the shared library
+func (s SharedConfig) SharedDir() (string, error) {
+	if s.Dir != "" {
+		return ExpandPath(s.Dir), nil
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "shared"), nil
+}
+
+// Default snapshot retention, used when the limits are unset
+const (
+	DefaultSnapshotMaxCount   = 200
+	DefaultSnapshotMaxAgeDays = 30
+	DefaultSnapshotMaxMB      = 512
+)
+
+// SnapshotDir returns the snapshot directory
+func (s SnapshotConfig) SnapshotDir() (string, error) {
+	if s.Dir != "" {
+		return ExpandPath(s.Dir), nil
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "snapshots"), nil
+}
+
+// Retention returns the snapshot limits with defaults applied; zero means
+// no limit
+func (s SnapshotConfig) Retention() (count int, age time.Duration, bytes int64) {
+	count, days, mb := s.MaxCount, s.MaxAgeDays, s.MaxMB
+	if count == 0 {
+		count = DefaultSnapshotMaxCount
+	}
+	if days == 0 {
+		days = DefaultSnapshotMaxAgeDays
+	}
+	if mb == 0 {
+		mb = DefaultSnapshotMaxMB
+	}
+	if count < 0 {
+		count = 0
+	}
+	if days > 0 {
+		age = time.Duration(days) * 24 * time.Hour
+	}
+	if mb > 0 {
+		bytes = int64(mb) << 20
+	}
+	return count, age, bytes
+}
+
 // Config is the main configuration structure
 type Config struct {
 	Theme           string           `yaml:"theme" mapstructure:"theme"`
@@ -38,8 +291,67 @@ type Config struct {
 	LastTable       string           `yaml:"last_table" mapstructure:"last_table"`
 	FirstRun        bool             `yaml:"first_run" mapstructure:"first_run"`
 	KeyMap          KeyMap           `yaml:"keymap" mapstructure:"keymap"`
+	// QueryTimeout stops statements running longer than this many seconds
+	// on connections without their own query_timeout (0 = no limit)
+	QueryTimeout int `yaml:"query_timeout,omitempty" mapstructure:"query_timeout"`
+	// MaxResultRows and MaxResultMB cap the rows and estimated memory of
+	// a result set held for display (0 = default, -1 = no limit). Larger
+	// results are truncated; export streams them to a file instead.
+	MaxResultRows int `yaml:"max_result_rows,omitempty" mapstructure:"max_result_rows"`
+	MaxResultMB   int `yaml:"max_result_mb,omitempty" mapstructure:"max_result_mb"`
+	// ResultHistory is how many past runs the results pane keeps as tabs
+	// (0 = default, -1 = off)
+	ResultHistory int `yaml:"result_history,omitempty" mapstructure:"result_history"`
+	// QueryHistory is how many past queries are saved for the history
+	// browser (0 = default, -1 = off)
+	QueryHistory int `yaml:"query_history,omitempty" mapstructure:"query_history"`
+	// UndoHistory is how many undo steps of each connection's editor text
+	// are saved across restarts (0 = default, -1 = off)
+	UndoHistory int `yaml:"undo_history,omitempty" mapstructure:"undo_history"`
+	// NotebookRows is how many rows the inline results of notebook mode
+	// show (0 = default)
+	NotebookRows int `yaml:"notebook_rows,omitempty" mapstructure:"notebook_rows"`
+	// StreamResults shows the rows of a single query as they arrive, so
+	// fetching a big result can be stopped early with Esc
+	StreamResults bool `yaml:"stream_results,omitempty" mapstructure:"stream_results"`
+	// PageRows runs a query without a LIMIT this many rows at a time,
+	// fetching the next page on demand (0 = off)
+	PageRows int `yaml:"page_rows,omitempty" mapstructure:"page_rows"`
+	// AutoClose makes the editor insert the closing bracket or quote when
+	// one is typed
+	AutoClose bool `yaml:"auto_close,omitempty" mapstructure:"auto_close"`
+	// RowNumbers shows a gutter with the row numbers in the results table
+	RowNumbers bool `yaml:"row_numbers,omitempty" mapstructure:"row_numbers"`
+	// Metrics exports query metrics; off unless an exporter is set
+	Metrics MetricsConfig `yaml:"metrics,omitempty" mapstructure:"metrics"`
+	// Hooks notify about finished queries; off unless a command or webhook is set
+	Hooks HooksConfig `yaml:"hooks,omitempty" mapstructure:"hooks"`
+	// Notify alerts about long queries finishing in the background
+	Notify NotifyConfig `yaml:"notify,omitempty" mapstructure:"notify"`
+	// Snapshots keep the results of past queries on disk
+	Snapshots SnapshotConfig `yaml:"snapshots,omitempty" mapstructure:"snapshots"`
+	// Lint flags risky queries on demand and optionally before they run
+	Lint LintConfig `yaml:"lint,omitempty" mapstructure:"lint"`
+	// Shared is the team library of snippets and connection templates
+	Shared SharedConfig `yaml:"shared,omitempty" mapstructure:"shared"`
+	// TimeFilter sets the defaults of the time filter helper
+	TimeFilter TimeFilterConfig `yaml:"time_filter,omitempty" mapstructure:"time_filter"`
+	// CSVImport sets the locale of imported CSV files
+	CSVImport CSVImportConfig `yaml:"csv_import,omitempty" mapstructure:"csv_import"`
+	// Format sets the layout of Format SQL
+	Format FormatConfig `yaml:"format,omitempty" mapstructure:"format"`
 }
 
+// Default result limits, used when max_result_rows / max_result_mb are unset
+const (
+	DefaultMaxResultRows = 100000
+	DefaultMaxResultMB   = 256
+	DefaultResultHistory = 5
+	DefaultQueryHistory  = 1000
+	DefaultUndoHistory   = 200
+	DefaultNotebookRows  = 5
+)
+
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -66,6 +378,16 @@ func GetConfigDir() (string, error) {
 	return filepath.Join(homeDir, ".config", "sqdesk"), nil
 }
 
+// ExpandPath expands a leading ~ to the user's home directory
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, path[1:])
+		}
+	}
+	return path
+}
+
 // GetConfigPath returns the full path to the config file
 func GetConfigPath() (string, error) {
 	configDir, err := GetConfigDir()
@@ -133,16 +455,138 @@ func (c *Config) Save() error {
 	viper.Set("theme", c.Theme)
 	viper.Set("editor", c.Editor)
 	viper.Set("ai", c.AI)
-	viper.Set("connections", c.Connections)
+	viper.Set("connections", c.savedConnections())
 	viper.Set("active_connection", c.ActiveConnIndex)
 	viper.Set("last_database", c.LastDatabase)
 	viper.Set("last_table", c.LastTable)
 	viper.Set("first_run", c.FirstRun)
 	viper.Set("keymap", c.KeyMap)
+	viper.Set("query_timeout", c.QueryTimeout)
+	viper.Set("max_result_rows", c.MaxResultRows)
+	viper.Set("max_result_mb", c.MaxResultMB)
+	if c.Metrics.Exporter != "" {
+		viper.Set("metrics", c.Metrics)
+	}
+	if c.Hooks.Command != "" || c.Hooks.Webhook != "" {
+		viper.Set("hooks", c.Hooks)
+	}
+	if c.Notify.After > 0 {
+		viper.Set("notify", c.Notify)
+	}
+	if c.Snapshots.Enabled || c.Snapshots.Dir != "" {
+		viper.Set("snapshots", c.Snapshots)
+	}
+	if c.Lint.OnExecute || len(c.Lint.Rules) > 0 {
+		viper.Set("lint", c.Lint)
+	}
+	if c.Shared.Enabled() {
+		viper.Set("shared", c.Shared)
+	}
+	if c.TimeFilter.Column != "" || c.TimeFilter.WeekStart != "" {
+		viper.Set("time_filter", c.TimeFilter)
+	}
+	if c.CSVImport != (CSVImportConfig{}) {
+		viper.Set("csv_import", c.CSVImport)
+	}
+	if c.Format != (FormatConfig{}) {
+		viper.Set("format", c.Format)
+	}
 
 	return viper.WriteConfigAs(configPath)
 }
 
+// savedConnections returns the connections as written to disk: shared
+// templates are left out, as are the session passwords of connections that
+// prompt for them
+func (c *Config) savedConnections() []DatabaseConfig {
+	conns := make([]DatabaseConfig, 0, len(c.Connections))
+	for _, conn := range c.Connections {
+		if conn.Shared {
+			continue
+		}
+		if conn.PromptPassword {
+			conn.Password = ""
+		}
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+// QueryTimeoutFor returns how long a statement on conn may run, or 0 for
+// no limit
+func (c *Config) QueryTimeoutFor(conn *DatabaseConfig) time.Duration {
+	seconds := c.QueryTimeout
+	if conn != nil && conn.QueryTimeout > 0 {
+		seconds = conn.QueryTimeout
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ResultLimit returns the row and byte caps for result sets, 0 meaning no
+// limit
+func (c *Config) ResultLimit() (rows int, bytes int64) {
+	rows = c.MaxResultRows
+	if rows == 0 {
+		rows = DefaultMaxResultRows
+	}
+	mb := c.MaxResultMB
+	if mb == 0 {
+		mb = DefaultMaxResultMB
+	}
+	if rows < 0 {
+		rows = 0
+	}
+	if mb > 0 {
+		bytes = int64(mb) << 20
+	}
+	return rows, bytes
+}
+
+// ResultHistorySize returns how many past runs to keep, 0 meaning none
+func (c *Config) ResultHistorySize() int {
+	switch {
+	case c.ResultHistory == 0:
+		return DefaultResultHistory
+	case c.ResultHistory < 0:
+		return 0
+	}
+	return c.ResultHistory
+}
+
+// QueryHistorySize returns how many past queries to save, 0 meaning none
+func (c *Config) QueryHistorySize() int {
+	switch {
+	case c.QueryHistory == 0:
+		return DefaultQueryHistory
+	case c.QueryHistory < 0:
+		return 0
+	}
+	return c.QueryHistory
+}
+
+// UndoHistorySize returns how many undo steps to save, 0 meaning the
+// editor text isn't saved
+func (c *Config) UndoHistorySize() int {
+	switch {
+	case c.UndoHistory == 0:
+		return DefaultUndoHistory
+	case c.UndoHistory < 0:
+		return 0
+	}
+	return c.UndoHistory
+}
+
+// NotebookPreviewRows returns how many rows an inline result shows
+func (c *Config) NotebookPreviewRows() int {
+	if c.NotebookRows <= 0 {
+		return DefaultNotebookRows
+	}
+	return c.NotebookRows
+}
+
 // GetActiveConnection returns the currently active database connection config
 func (c *Config) GetActiveConnection() *DatabaseConfig {
 	if c.ActiveConnIndex < 0 || c.ActiveConnIndex >= len(c.Connections) {
@@ -151,12 +595,71 @@ func (c *Config) GetActiveConnection() *DatabaseConfig {
 	return &c.Connections[c.ActiveConnIndex]
 }
 
-// AddConnection adds a new database connection
-func (c *Config) AddConnection(conn DatabaseConfig) {
-	c.Connections = append(c.Connections, conn)
+// AddConnection adds a new database connection and returns its index.
+// Local connections stay ahead of shared ones so their indexes survive a
+// reload.
+func (c *Config) AddConnection(conn DatabaseConfig) int {
+	at := c.localCount()
+	c.Connections = append(c.Connections, DatabaseConfig{})
+	copy(c.Connections[at+1:], c.Connections[at:])
+	c.Connections[at] = conn
+	if c.ActiveConnIndex >= at {
+		c.ActiveConnIndex++
+	}
 	if c.ActiveConnIndex < 0 {
 		c.ActiveConnIndex = 0
 	}
+	return at
+}
+
+// localCount returns the number of local connections, which come first
+func (c *Config) localCount() int {
+	for i, conn := range c.Connections {
+		if conn.Shared {
+			return i
+		}
+	}
+	return len(c.Connections)
+}
+
+// MergeShared replaces the shared connections with conns. Templates named
+// like a local connection are skipped, and the active connection is kept
+// by name.
+func (c *Config) MergeShared(conns []DatabaseConfig) {
+	active := ""
+	if conn := c.GetActiveConnection(); conn != nil {
+		active = conn.Name
+	}
+
+	local := c.Connections[:c.localCount()]
+	names := make(map[string]bool, len(local))
+	for _, conn := range local {
+		names[conn.Name] = true
+	}
+	merged := append([]DatabaseConfig{}, local...)
+	for _, conn := range conns {
+		if names[conn.Name] {
+			continue
+		}
+		names[conn.Name] = true
+		conn.Shared = true
+		merged = append(merged, conn)
+	}
+	c.Connections = merged
+
+	if active == "" {
+		if c.ActiveConnIndex >= len(c.Connections) {
+			c.ActiveConnIndex = len(c.Connections) - 1
+		}
+		return
+	}
+	c.ActiveConnIndex = -1
+	for i, conn := range c.Connections {
+		if conn.Name == active {
+			c.ActiveConnIndex = i
+			break
+		}
+	}
 }
 
 // RemoveConnection removes a database connection by index
@@ -164,6 +667,9 @@ func (c *Config) RemoveConnection(index int) error {
 	if index < 0 || index >= len(c.Connections) {
 		return fmt.Errorf("invalid connection index")
 	}
+	if c.Connections[index].Shared {
+		return fmt.Errorf("%s is a shared connection", c.Connections[index].Name)
+	}
 	c.Connections = append(c.Connections[:index], c.Connections[index+1:]...)
 	if c.ActiveConnIndex >= len(c.Connections) {
 		c.ActiveConnIndex = len(c.Connections) - 1
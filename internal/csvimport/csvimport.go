@@ -0,0 +1,204 @@
+// Package csvimport reads CSV files into new tables. Column types are
+// inferred from the values under a locale, since "03/04/2024" and
+// "1.234,5" mean different things in different countries.
+package csvimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Type is the inferred or chosen type of a column
+type Type int
+
+const (
+	Text Type = iota
+	Integer
+	Decimal
+	Boolean
+	Date
+	Timestamp
+)
+
+// Types returns every type in the order overrides cycle through
+func Types() []Type {
+	return []Type{Text, Integer, Decimal, Boolean, Date, Timestamp}
+}
+
+// String returns the name of the type
+func (t Type) String() string {
+	switch t {
+	case Integer:
+		return "integer"
+	case Decimal:
+		return "decimal"
+	case Boolean:
+		return "boolean"
+	case Date:
+		return "date"
+	case Timestamp:
+		return "timestamp"
+	}
+	return "text"
+}
+
+// File is a CSV file read into memory
+type File struct {
+	Header []string
+	Rows   [][]string // each as long as Header
+}
+
+// Column is a column of the file with the type it is imported as
+type Column struct {
+	Name     string
+	Type     Type
+	Inferred Type
+	// Override is set when the user chose Type over the inferred one
+	Override bool
+}
+
+// Read reads a CSV file whose first row names the columns. A zero
+// delimiter is detected from the header line.
+func Read(r io.Reader, delimiter rune) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	if !utf8.ValidString(text) {
+		return nil, fmt.Errorf("file is not UTF-8 text")
+	}
+	if delimiter == 0 {
+		delimiter = detectDelimiter(text)
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	f := &File{Header: headerNames(records[0])}
+	for _, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		row := make([]string, len(f.Header))
+		copy(row, record)
+		f.Rows = append(f.Rows, row)
+	}
+	return f, nil
+}
+
+// detectDelimiter picks the most frequent of comma, semicolon, tab and
+// pipe in the header line. Files with decimal commas tend to use
+// semicolons.
+func detectDelimiter(text string) rune {
+	line, _, _ := strings.Cut(text, "\n")
+	best, count := ',', 0
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		if n := strings.Count(line, string(candidate)); n > count {
+			best, count = candidate, n
+		}
+	}
+	return best
+}
+
+// headerNames trims the header, naming blank columns column_N and making
+// repeated names unique
+func headerNames(record []string) []string {
+	names := make([]string, len(record))
+	seen := make(map[string]int)
+	for i, name := range record {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		key := strings.ToLower(name)
+		if n := seen[key]; n > 0 {
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[key]++
+		names[i] = name
+	}
+	return names
+}
+
+// Values returns the values of column i
+func (f *File) Values(i int) []string {
+	values := make([]string, len(f.Rows))
+	for r, row := range f.Rows {
+		values[r] = row[i]
+	}
+	return values
+}
+
+// Columns infers the type of every column under loc
+func (f *File) Columns(loc Locale) []Column {
+	columns := make([]Column, len(f.Header))
+	for i, name := range f.Header {
+		t := Infer(f.Values(i), loc)
+		columns[i] = Column{Name: name, Type: t, Inferred: t}
+	}
+	return columns
+}
+
+// Reinfer infers the columns again under loc, keeping overrides
+func (f *File) Reinfer(columns []Column, loc Locale) {
+	for i := range columns {
+		columns[i].Inferred = Infer(f.Values(i), loc)
+		if !columns[i].Override {
+			columns[i].Type = columns[i].Inferred
+		}
+	}
+}
+
+// Infer returns the narrowest type every non-empty value parses as.
+// Columns without values are text.
+func Infer(values []string, loc Locale) Type {
+	candidates := []Type{Integer, Decimal, Boolean, Date, Timestamp}
+	seen := false
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		seen = true
+		kept := candidates[:0]
+		for _, t := range candidates {
+			if _, err := Parse(v, t, loc); err == nil {
+				kept = append(kept, t)
+			}
+		}
+		candidates = kept
+		if len(candidates) == 0 {
+			return Text
+		}
+	}
+	if !seen {
+		return Text
+	}
+	return candidates[0]
+}
+
+// Invalid counts the non-empty values of column i that don't parse as t
+func (f *File) Invalid(i int, t Type, loc Locale) int {
+	n := 0
+	for _, row := range f.Rows {
+		if strings.TrimSpace(row[i]) == "" {
+			continue
+		}
+		if _, err := Parse(row[i], t, loc); err != nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,233 @@
+package csvimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateOrder is the order of day, month and year in dates like 03/04/2024.
+// ISO dates (2024-04-03) parse under every order.
+type DateOrder int
+
+const (
+	DMY DateOrder = iota
+	MDY
+	YMD
+)
+
+// String returns the order as written in the config
+func (o DateOrder) String() string {
+	switch o {
+	case MDY:
+		return "mdy"
+	case YMD:
+		return "ymd"
+	}
+	return "dmy"
+}
+
+// Locale is how the file writes dates and numbers
+type Locale struct {
+	DateOrder DateOrder
+	// Decimal separates the fraction, '.' or ','
+	Decimal rune
+	// Thousands groups digits; 0 when numbers aren't grouped
+	Thousands rune
+}
+
+// NewLocale reads csv_import settings. The decimal separator defaults to
+// '.', and thousands to whichever of '.' and ',' isn't the decimal one.
+func NewLocale(dateOrder, decimal, thousands string) Locale {
+	loc := Locale{Decimal: '.', Thousands: ','}
+	switch strings.ToLower(strings.TrimSpace(dateOrder)) {
+	case "mdy":
+		loc.DateOrder = MDY
+	case "ymd":
+		loc.DateOrder = YMD
+	}
+	if strings.TrimSpace(decimal) == "," {
+		loc.Decimal, loc.Thousands = ',', '.'
+	}
+	switch t := strings.ToLower(thousands); t {
+	case "":
+	case "none":
+		loc.Thousands = 0
+	case "space":
+		loc.Thousands = ' '
+	default:
+		if r := []rune(t); len(r) == 1 && r[0] != loc.Decimal {
+			loc.Thousands = r[0]
+		}
+	}
+	return loc
+}
+
+// NextDateOrder returns loc with the next date order
+func (loc Locale) NextDateOrder() Locale {
+	loc.DateOrder = (loc.DateOrder + 1) % 3
+	return loc
+}
+
+// SwapDecimal returns loc with decimal comma and point swapped
+func (loc Locale) SwapDecimal() Locale {
+	if loc.Decimal == '.' {
+		loc.Decimal = ','
+		if loc.Thousands == ',' {
+			loc.Thousands = '.'
+		}
+	} else {
+		loc.Decimal = '.'
+		if loc.Thousands == '.' {
+			loc.Thousands = ','
+		}
+	}
+	return loc
+}
+
+// String describes loc, e.g. "dates dmy · numbers 1.234,5"
+func (loc Locale) String() string {
+	sample := "1234" + string(loc.Decimal) + "5"
+	if loc.Thousands != 0 {
+		sample = "1" + string(loc.Thousands) + "234" + string(loc.Decimal) + "5"
+	}
+	return "dates " + loc.DateOrder.String() + " · numbers " + sample
+}
+
+// Parse converts a value to t: int64 for integers, the decimal as text
+// with a '.' for decimals, bool, or time.Time. Empty values are nil.
+func Parse(value string, t Type, loc Locale) (interface{}, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	switch t {
+	case Integer:
+		n, ok := normalizeNumber(value, loc)
+		if ok && !strings.Contains(n, ".") {
+			if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case Decimal:
+		if n, ok := normalizeNumber(value, loc); ok {
+			return n, nil
+		}
+	case Boolean:
+		switch strings.ToLower(value) {
+		case "true", "yes":
+			return true, nil
+		case "false", "no":
+			return false, nil
+		}
+	case Date:
+		for _, layout := range dateLayouts(loc.DateOrder) {
+			if d, err := time.Parse(layout, value); err == nil {
+				return d, nil
+			}
+		}
+	case Timestamp:
+		if ts, err := time.Parse(time.RFC3339, value); err == nil {
+			return ts, nil
+		}
+		for _, layout := range dateLayouts(loc.DateOrder) {
+			for _, clock := range []string{" 15:04:05", " 15:04", "T15:04:05", "T15:04"} {
+				if ts, err := time.Parse(layout+clock, value); err == nil {
+					return ts, nil
+				}
+			}
+		}
+	default:
+		return value, nil
+	}
+	return nil, fmt.Errorf("%q is not a %s", value, t)
+}
+
+// Format shows a parsed value the way it is stored
+func Format(v interface{}, t Type) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case time.Time:
+		if t == Date {
+			return x.Format("2006-01-02")
+		}
+		return x.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(v)
+}
+
+// normalizeNumber strips thousands separators and turns the decimal
+// separator into '.'. Grouping must be in threes, so "1,5" is not 15.
+func normalizeNumber(value string, loc Locale) (string, bool) {
+	sign := ""
+	if value[0] == '-' || value[0] == '+' {
+		if value[0] == '-' {
+			sign = "-"
+		}
+		value = value[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(value, string(loc.Decimal))
+	if hasFraction && (fraction == "" || !digits(fraction)) {
+		return "", false
+	}
+	if whole == "" {
+		if !hasFraction {
+			return "", false
+		}
+		whole = "0"
+	}
+
+	if loc.Thousands != 0 && strings.ContainsRune(whole, loc.Thousands) {
+		groups := strings.Split(whole, string(loc.Thousands))
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return "", false
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return "", false
+			}
+		}
+		whole = strings.Join(groups, "")
+	}
+	// Leading zeros mark codes (zip codes, IDs) that must stay text
+	if !digits(whole) || (len(whole) > 1 && whole[0] == '0') {
+		return "", false
+	}
+	if hasFraction {
+		return sign + whole + "." + fraction, true
+	}
+	return sign + whole, true
+}
+
+// digits reports whether s is only ASCII digits
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// dateLayouts returns the date layouts of an order, ISO first. Day and
+// month take one or two digits.
+func dateLayouts(order DateOrder) []string {
+	layouts := []string{"2006-01-02"}
+	for _, sep := range []string{"/", "-", "."} {
+		switch order {
+		case DMY:
+			layouts = append(layouts, "2"+sep+"1"+sep+"2006")
+		case MDY:
+			layouts = append(layouts, "1"+sep+"2"+sep+"2006")
+		case YMD:
+			layouts = append(layouts, "2006"+sep+"1"+sep+"2")
+		}
+	}
+	return layouts
+}
@@ -0,0 +1,91 @@
+package csvimport
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLocale(t *testing.T) {
+	tests := []struct {
+		dateOrder, decimal, thousands string
+		want                          Locale
+	}{
+		{"", "", "", Locale{DMY, '.', ','}},
+		{"MDY", ".", "", Locale{MDY, '.', ','}},
+		{"ymd", ",", "", Locale{YMD, ',', '.'}},
+		{"dmy", ",", "space", Locale{DMY, ',', ' '}},
+		{"dmy", ".", "none", Locale{DMY, '.', 0}},
+		{"dmy", ".", "'", Locale{DMY, '.', '\''}},
+		// A thousands separator equal to the decimal one is ignored
+		{"dmy", ",", ",", Locale{DMY, ',', '.'}},
+	}
+	for _, tt := range tests {
+		if got := NewLocale(tt.dateOrder, tt.decimal, tt.thousands); got != tt.want {
+			t.Errorf("NewLocale(%q, %q, %q) = %+v, want %+v", tt.dateOrder, tt.decimal, tt.thousands, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumbers(t *testing.T) {
+	point := NewLocale("dmy", ".", "")
+	comma := NewLocale("dmy", ",", "")
+	tests := []struct {
+		value string
+		typ   Type
+		loc   Locale
+		want  interface{}
+	}{
+		{"1,234", Integer, point, int64(1234)},
+		{"-1,234,567", Integer, point, int64(-1234567)},
+		{"1.234", Integer, comma, int64(1234)},
+		{"1,234.5", Decimal, point, "1234.5"},
+		{"1.234,5", Decimal, comma, "1234.5"},
+		{"0,5", Decimal, comma, "0.5"},
+		{",5", Decimal, comma, "0.5"},
+		{"+12", Decimal, point, "12"},
+		{"", Integer, point, nil},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.value, tt.typ, tt.loc)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q, %s, %s) = %v, %v, want %v", tt.value, tt.typ, tt.loc, got, err, tt.want)
+		}
+	}
+
+	// Grouping must be in threes, and leading zeros keep codes as text
+	for _, value := range []string{"1,5", "12,34", "0123", "1.5"} {
+		if got, err := Parse(value, Integer, point); err == nil {
+			t.Errorf("Parse(%q, integer) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestParseDates(t *testing.T) {
+	april3 := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		order DateOrder
+		want  time.Time
+	}{
+		{"2024-04-03", DMY, april3},
+		{"2024-04-03", MDY, april3},
+		{"03/04/2024", DMY, april3},
+		{"4/3/2024", MDY, april3},
+		{"2024.4.3", YMD, april3},
+		{"3-4-2024", DMY, april3},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.value, Date, Locale{DateOrder: tt.order, Decimal: '.'})
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q, date, %s) = %v, %v, want %v", tt.value, tt.order, got, err, tt.want)
+		}
+	}
+
+	ts, err := Parse("03.04.2024 15:04", Timestamp, Locale{DateOrder: DMY, Decimal: ','})
+	if want := time.Date(2024, 4, 3, 15, 4, 0, 0, time.UTC); err != nil || ts != want {
+		t.Errorf("Parse timestamp = %v, %v, want %v", ts, err, want)
+	}
+	if _, err := Parse("13/13/2024", Date, Locale{DateOrder: DMY}); err == nil {
+		t.Error("Parse(13/13/2024) succeeded, want an error")
+	}
+}
@@ -0,0 +1,101 @@
+package csvimport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// maxBatchParams keeps multi-row INSERTs under SQLite's default limit of
+// 999 bound parameters
+const maxBatchParams = 999
+
+// Supported reports whether tables can be imported on the driver
+func Supported(driver string) bool {
+	switch driver {
+	case "postgres", "cockroachdb", "mysql", "mariadb", "sqlite3":
+		return true
+	}
+	return false
+}
+
+// SQLType returns the column type of t on the driver
+func SQLType(driver string, t Type) string {
+	switch t {
+	case Integer:
+		if driver == "sqlite3" {
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case Decimal:
+		return "NUMERIC"
+	case Boolean:
+		return "BOOLEAN"
+	case Date:
+		return "DATE"
+	case Timestamp:
+		if driver == "mysql" || driver == "mariadb" {
+			return "DATETIME"
+		}
+		return "TIMESTAMP"
+	}
+	return "TEXT"
+}
+
+// CreateTable returns the CREATE TABLE statement for the columns
+func CreateTable(driver, table string, columns []Column) string {
+	defs := make([]string, len(columns))
+	for i, col := range columns {
+		defs[i] = "  " + db.QuoteIdentifier(driver, col.Name) + " " + SQLType(driver, col.Type)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)", db.QuoteIdentifier(driver, table), strings.Join(defs, ",\n"))
+}
+
+// BatchSize returns how many rows one INSERT of the columns takes
+func BatchSize(columns []Column) int {
+	return max(1, maxBatchParams/max(1, len(columns)))
+}
+
+// Insert returns an INSERT of rows rows with the driver's placeholders
+func Insert(driver, table string, columns []Column, rows int) string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = db.QuoteIdentifier(driver, col.Name)
+	}
+
+	dollar := sqlparse.ParamStyleFor(driver) == sqlparse.ParamDollar
+	tuples := make([]string, rows)
+	n := 0
+	for r := range tuples {
+		marks := make([]string, len(columns))
+		for i := range marks {
+			n++
+			marks[i] = "?"
+			if dollar {
+				marks[i] = fmt.Sprintf("$%d", n)
+			}
+		}
+		tuples[r] = "(" + strings.Join(marks, ", ") + ")"
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", db.QuoteIdentifier(driver, table), strings.Join(names, ", "), strings.Join(tuples, ", "))
+}
+
+// Args parses a row into INSERT arguments. The error names the column.
+func Args(row []string, columns []Column, loc Locale) ([]interface{}, error) {
+	args := make([]interface{}, len(columns))
+	for i, col := range columns {
+		v, err := Parse(row[i], col.Type, loc)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		// Dates go in as ISO text; SQLite would store a time as a timestamp
+		if d, ok := v.(time.Time); ok && col.Type == Date {
+			v = d.Format("2006-01-02")
+		}
+		args[i] = v
+	}
+	return args, nil
+}
@@ -0,0 +1,232 @@
+// Package datafile loads CSV, JSON and Parquet files as result sets, for
+// viewing them in the results grid without a database.
+package datafile
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/csvimport"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// Options control how a file is read
+type Options struct {
+	// Locale and Delimiter read CSV values, see csvimport
+	Locale    csvimport.Locale
+	Delimiter rune
+	// MaxRows truncates the result (0 = no limit)
+	MaxRows int
+}
+
+// Load reads a file by its extension: .csv, .tsv, .json, .ndjson, .jsonl
+// or .parquet. Parquet is read through the duckdb command line tool.
+func Load(ctx context.Context, path string, opts Options) (db.ResultSet, error) {
+	if opts.Locale.Decimal == 0 {
+		opts.Locale = csvimport.NewLocale("", "", "")
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv", ".tsv", ".txt":
+		if ext == ".tsv" && opts.Delimiter == 0 {
+			opts.Delimiter = '\t'
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return db.ResultSet{}, err
+		}
+		defer f.Close()
+		return readCSV(f, opts)
+	case ".json", ".ndjson", ".jsonl":
+		f, err := os.Open(path)
+		if err != nil {
+			return db.ResultSet{}, err
+		}
+		defer f.Close()
+		return readJSON(bufio.NewReader(f), opts)
+	case ".parquet":
+		return readParquet(ctx, path, opts)
+	}
+	return db.ResultSet{}, fmt.Errorf("unsupported file type %q (use .csv, .tsv, .json, .ndjson or .parquet)", filepath.Ext(path))
+}
+
+// readCSV reads a CSV file with the types csvimport infers
+func readCSV(r io.Reader, opts Options) (db.ResultSet, error) {
+	file, err := csvimport.Read(r, opts.Delimiter)
+	if err != nil {
+		return db.ResultSet{}, err
+	}
+	columns := file.Columns(opts.Locale)
+
+	set := db.ResultSet{Columns: file.Header}
+	for _, col := range columns {
+		sqlType := csvimport.SQLType("postgres", col.Type)
+		set.ColumnTypes = append(set.ColumnTypes, db.ColumnType{Name: col.Name, DatabaseType: sqlType, Kind: db.ColumnKindOf(sqlType)})
+	}
+	for _, record := range file.Rows {
+		if opts.MaxRows > 0 && len(set.Rows) == opts.MaxRows {
+			set.Truncated = true
+			break
+		}
+		row := make(db.Row, len(columns))
+		for i, col := range columns {
+			// Inference guarantees every value parses as its column type
+			v, _ := csvimport.Parse(record[i], col.Type, opts.Locale)
+			if s, ok := v.(string); ok && col.Type == csvimport.Decimal {
+				v = db.Decimal(s)
+			}
+			row[col.Name] = db.NewValue(v)
+		}
+		set.Rows = append(set.Rows, row)
+	}
+	return set, nil
+}
+
+// readJSON reads an array of objects, or one object per line. Columns
+// follow the order keys first appear in; nested values show as JSON.
+func readJSON(r io.Reader, opts Options) (db.ResultSet, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var set db.ResultSet
+	seen := make(map[string]bool)
+	inArray := false
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return set, nil
+	}
+	if err != nil {
+		return set, err
+	}
+	switch tok {
+	case json.Delim('['):
+		inArray = true
+	case json.Delim('{'):
+	default:
+		return set, fmt.Errorf("expected an array of objects or one object per line")
+	}
+
+	for {
+		if inArray {
+			if !dec.More() {
+				break
+			}
+			if tok, err = dec.Token(); err != nil {
+				return set, err
+			}
+			if tok != json.Delim('{') {
+				return set, fmt.Errorf("expected an array of objects, got %v", tok)
+			}
+		}
+
+		row := make(db.Row)
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return set, err
+			}
+			key := keyTok.(string)
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return set, err
+			}
+			if !seen[key] {
+				seen[key] = true
+				set.Columns = append(set.Columns, key)
+			}
+			row[key] = jsonValue(v)
+		}
+		if _, err := dec.Token(); err != nil { // closing }
+			return set, err
+		}
+		if opts.MaxRows > 0 && len(set.Rows) == opts.MaxRows {
+			set.Truncated = true
+			break
+		}
+		set.Rows = append(set.Rows, row)
+
+		if !inArray {
+			tok, err = dec.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return set, err
+			}
+			if tok != json.Delim('{') {
+				return set, fmt.Errorf("expected one object per line, got %v", tok)
+			}
+		}
+	}
+
+	// Keys missing from a row are NULL
+	for _, row := range set.Rows {
+		for _, col := range set.Columns {
+			if _, ok := row[col]; !ok {
+				row[col] = db.Value{Null: true}
+			}
+		}
+	}
+	set.ColumnTypes = db.InferColumnTypes(set.Columns, set.Rows)
+	return set, nil
+}
+
+// jsonValue converts a decoded JSON value to a cell
+func jsonValue(v interface{}) db.Value {
+	switch x := v.(type) {
+	case nil:
+		return db.Value{Null: true}
+	case json.Number:
+		if i, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+			return db.NewValue(i)
+		}
+		return db.NewValue(db.Decimal(x))
+	case string:
+		// Timestamps keep their type so they sort and chart as times
+		if t, err := time.Parse(time.RFC3339Nano, x); err == nil {
+			return db.NewValue(t)
+		}
+		return db.NewValue(x)
+	case bool:
+		return db.NewValue(x)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return db.NewValue(fmt.Sprint(v))
+	}
+	return db.NewValue(string(b))
+}
+
+// readParquet reads a Parquet file with the duckdb CLI, as JSON
+func readParquet(ctx context.Context, path string, opts Options) (db.ResultSet, error) {
+	if _, err := exec.LookPath("duckdb"); err != nil {
+		return db.ResultSet{}, fmt.Errorf("reading Parquet needs the duckdb command line tool on PATH")
+	}
+	query := "SELECT * FROM read_parquet(" + db.QuoteLiteral("duckdb", path) + ")"
+	if opts.MaxRows > 0 {
+		// One more row tells whether the file was truncated
+		query += fmt.Sprintf(" LIMIT %d", opts.MaxRows+1)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "duckdb", "-json", "-c", query)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return db.ResultSet{}, errors.New(msg)
+		}
+		return db.ResultSet{}, err
+	}
+	return readJSON(bytes.NewReader(out), opts)
+}
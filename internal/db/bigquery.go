@@ -0,0 +1,674 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+const (
+	bigQueryAPIURL   = "https://bigquery.googleapis.com/bigquery/v2"
+	bigQueryScope    = "https://www.googleapis.com/auth/bigquery"
+	bigQueryTokenURL = "https://oauth2.googleapis.com/token"
+	// bigQueryPageRows is the number of rows requested per result page
+	bigQueryPageRows = 10000
+)
+
+// BigQueryConnector implements Connector for Google BigQuery over its REST
+// API. Datasets are exposed as databases.
+type BigQueryConnector struct {
+	config  *config.DatabaseConfig
+	client  *http.Client
+	account *serviceAccount
+	key     *rsa.PrivateKey
+	project string
+
+	// tokenMu guards the token, which queries running side by side may
+	// refresh
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
+
+	limit ResultLimit
+}
+
+// serviceAccount is the subset of a service-account JSON key we need
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewBigQueryConnector creates a new BigQuery connector
+func NewBigQueryConnector(cfg *config.DatabaseConfig) *BigQueryConnector {
+	return &BigQueryConnector{
+		config: cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Connect loads the service account key and fetches an access token
+func (c *BigQueryConnector) Connect(ctx context.Context) error {
+	if c.config.CredentialsFile == "" {
+		return fmt.Errorf("BigQuery requires credentials_file (service account JSON)")
+	}
+
+	data, err := os.ReadFile(config.ExpandPath(c.config.CredentialsFile))
+	if err != nil {
+		return fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = bigQueryTokenURL
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return fmt.Errorf("credentials file has no private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return fmt.Errorf("private key is not an RSA key")
+	}
+
+	c.account = &account
+	c.key = key
+	// Host may override the project to query other projects' data
+	c.project = c.config.Host
+	if c.project == "" {
+		c.project = account.ProjectID
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.refreshToken(ctx)
+}
+
+// accessToken returns the access token, refreshing it once expired
+func (c *BigQueryConnector) accessToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.token == "" {
+		return "", fmt.Errorf("not connected to database")
+	}
+	if time.Now().After(c.tokenExpiry) {
+		if err := c.refreshToken(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.token, nil
+}
+
+// refreshToken exchanges a signed JWT for an OAuth access token. The
+// caller holds tokenMu.
+func (c *BigQueryConnector) refreshToken(ctx context.Context) error {
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   c.account.ClientEmail,
+		"scope": bigQueryScope,
+		"aud":   c.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build token claims: %w", err)
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, c.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return fmt.Errorf("failed to sign token request: %w", err)
+	}
+	assertion := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return fmt.Errorf("failed to authenticate: %s", tokenResp.ErrorDescription)
+	}
+
+	c.token = tokenResp.AccessToken
+	// Refresh a minute early to avoid racing the expiry
+	c.tokenExpiry = now.Add(time.Duration(tokenResp.ExpiresIn)*time.Second - time.Minute)
+	return nil
+}
+
+// call performs an authenticated API request and decodes the JSON response
+func (c *BigQueryConnector) call(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, bigQueryAPIURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("BigQuery error: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("BigQuery error: %s", resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// Close drops the access token
+func (c *BigQueryConnector) Close() error {
+	c.tokenMu.Lock()
+	c.token = ""
+	c.tokenMu.Unlock()
+	return nil
+}
+
+// IsConnected reports whether an access token is held
+func (c *BigQueryConnector) IsConnected() bool {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.token != ""
+}
+
+// GetDriverName returns the driver name
+func (c *BigQueryConnector) GetDriverName() string {
+	return "bigquery"
+}
+
+// GetDatabaseName returns the current dataset
+func (c *BigQueryConnector) GetDatabaseName() string {
+	return c.config.Database
+}
+
+// bigQueryField is a column in a table or result schema
+type bigQueryField struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Mode   string          `json:"mode"`
+	Fields []bigQueryField `json:"fields"`
+	// DefaultValueExpression is only set in table schemas
+	DefaultValueExpression string `json:"defaultValueExpression"`
+}
+
+// bigQueryResult is the response of jobs.query and jobs.getQueryResults
+type bigQueryResult struct {
+	JobComplete  bool `json:"jobComplete"`
+	JobReference struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference"`
+	Schema struct {
+		Fields []bigQueryField `json:"fields"`
+	} `json:"schema"`
+	Rows []struct {
+		F []struct {
+			V interface{} `json:"v"`
+		} `json:"f"`
+	} `json:"rows"`
+	PageToken          string `json:"pageToken"`
+	NumDMLAffectedRows string `json:"numDmlAffectedRows"`
+}
+
+// bigQueryFirstWaitMs is how long jobs.query waits for the result before
+// returning the job reference. It is kept short so a cancelled query is
+// known by job ID and can be cancelled server-side.
+const bigQueryFirstWaitMs = 2000
+
+// runQuery runs standard SQL and waits for the job to finish, cancelling
+// the job when ctx is cancelled
+func (c *BigQueryConnector) runQuery(ctx context.Context, sql string) (*bigQueryResult, error) {
+	request := map[string]interface{}{
+		"query":        sql,
+		"useLegacySql": false,
+		"timeoutMs":    bigQueryFirstWaitMs,
+		"maxResults":   bigQueryPageRows,
+	}
+	if c.config.Database != "" {
+		request["defaultDataset"] = map[string]string{
+			"projectId": c.project,
+			"datasetId": c.config.Database,
+		}
+	}
+
+	var result bigQueryResult
+	if err := c.call(ctx, "POST", "/projects/"+c.project+"/queries", request, &result); err != nil {
+		return nil, err
+	}
+
+	for !result.JobComplete {
+		if err := c.getQueryResults(ctx, &result, ""); err != nil {
+			if ctx.Err() != nil {
+				c.cancelJob(result.JobReference.JobID, result.JobReference.Location)
+			}
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// cancelJob asks BigQuery to stop a running job. It is best effort: the
+// job may already have finished.
+func (c *BigQueryConnector) cancelJob(jobID, location string) {
+	if jobID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	path := fmt.Sprintf("/projects/%s/jobs/%s/cancel", c.project, jobID)
+	if location != "" {
+		path += "?location=" + url.QueryEscape(location)
+	}
+	c.call(ctx, "POST", path, nil, nil)
+}
+
+// getQueryResults polls a query job, optionally fetching a result page
+func (c *BigQueryConnector) getQueryResults(ctx context.Context, result *bigQueryResult, pageToken string) error {
+	params := url.Values{}
+	params.Set("timeoutMs", "30000")
+	params.Set("maxResults", strconv.Itoa(bigQueryPageRows))
+	if result.JobReference.Location != "" {
+		params.Set("location", result.JobReference.Location)
+	}
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	path := fmt.Sprintf("/projects/%s/queries/%s?%s", c.project, result.JobReference.JobID, params.Encode())
+	jobRef := result.JobReference
+	*result = bigQueryResult{}
+	if err := c.call(ctx, "GET", path, nil, result); err != nil {
+		return err
+	}
+	result.JobReference = jobRef
+	return nil
+}
+
+// Query runs a standard SQL query and returns results
+func (c *BigQueryConnector) Query(ctx context.Context, sql string) ([]Row, []string, error) {
+	set, err := c.queryResultSet(ctx, sql)
+	if err != nil {
+		return nil, nil, err
+	}
+	return set.Rows, set.Columns, nil
+}
+
+// QueryMulti executes a query, returning its single result set with column
+// types from the result schema
+func (c *BigQueryConnector) QueryMulti(ctx context.Context, sql string) ([]ResultSet, error) {
+	set, err := c.queryResultSet(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	return []ResultSet{set}, nil
+}
+
+// queryResultSet executes a query and reads its pages up to the result limit
+func (c *BigQueryConnector) queryResultSet(ctx context.Context, sql string) (ResultSet, error) {
+	return collectResultSet(c.limit, func(onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+		return c.StreamQuery(ctx, sql, onColumns, onRow)
+	})
+}
+
+// StreamQuery executes a query and streams its rows page by page
+func (c *BigQueryConnector) StreamQuery(ctx context.Context, sql string, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	result, err := c.runQuery(ctx, sql)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+
+	fields := result.Schema.Fields
+	types := make([]ColumnType, len(fields))
+	for i, f := range fields {
+		types[i] = ColumnType{Name: f.Name, DatabaseType: f.Type, Kind: ColumnKindOf(f.Type)}
+		if f.Mode == "REPEATED" {
+			types[i].Kind = ColumnText // shown as a JSON array
+		}
+	}
+	if err := onColumns(types); err != nil {
+		return err
+	}
+
+	values := make([]Value, len(fields))
+	for {
+		for _, r := range result.Rows {
+			for i := range values {
+				values[i] = Value{Null: true}
+				if i < len(r.F) {
+					values[i] = bigQueryValue(fields[i], r.F[i].V)
+				}
+			}
+			if err := onRow(values); err != nil {
+				return err
+			}
+		}
+
+		if result.PageToken == "" {
+			return nil
+		}
+		if err := c.getQueryResults(ctx, result, result.PageToken); err != nil {
+			return fmt.Errorf("query error: %w", err)
+		}
+	}
+}
+
+// SetResultLimit caps rows and memory kept per result
+func (c *BigQueryConnector) SetResultLimit(limit ResultLimit) {
+	c.limit = limit
+}
+
+// bigQueryValue converts a REST cell value to a typed value
+func bigQueryValue(field bigQueryField, v interface{}) Value {
+	if v == nil {
+		return Value{Null: true}
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		// RECORD and REPEATED values arrive as nested JSON
+		data, err := json.Marshal(v)
+		if err != nil {
+			return Value{Data: fmt.Sprintf("%v", v)}
+		}
+		return Value{Data: string(data)}
+	}
+
+	switch field.Type {
+	case "INTEGER", "INT64":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return Value{Data: n}
+		}
+	case "FLOAT", "FLOAT64":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return Value{Data: f}
+		}
+	case "NUMERIC", "BIGNUMERIC":
+		// Up to 76 digits; kept as text so nothing is rounded
+		return textValue(s, ColumnNumber)
+	case "BOOLEAN", "BOOL":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return Value{Data: b}
+		}
+	case "TIMESTAMP":
+		// Timestamps are seconds since the epoch as a float string
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return Value{Data: time.Unix(0, int64(f*float64(time.Second))).UTC()}
+		}
+	}
+	return Value{Data: s}
+}
+
+// Execute runs a DML/DDL statement
+func (c *BigQueryConnector) Execute(ctx context.Context, sql string) (int64, error) {
+	result, err := c.runQuery(ctx, sql)
+	if err != nil {
+		return 0, fmt.Errorf("execute error: %w", err)
+	}
+	if result.NumDMLAffectedRows == "" {
+		return 0, nil
+	}
+	affected, _ := strconv.ParseInt(result.NumDMLAffectedRows, 10, 64)
+	return affected, nil
+}
+
+// GetTables returns tables in the current dataset
+func (c *BigQueryConnector) GetTables() ([]string, error) {
+	if c.config.Database == "" {
+		return nil, nil
+	}
+
+	var tables []string
+	pageToken := ""
+	for {
+		var resp struct {
+			Tables []struct {
+				TableReference struct {
+					TableID string `json:"tableId"`
+				} `json:"tableReference"`
+			} `json:"tables"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+
+		path := fmt.Sprintf("/projects/%s/datasets/%s/tables?maxResults=1000", c.project, c.config.Database)
+		if pageToken != "" {
+			path += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+		if err := c.call(context.Background(), "GET", path, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get tables: %w", err)
+		}
+
+		for _, t := range resp.Tables {
+			tables = append(tables, t.TableReference.TableID)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return tables, nil
+}
+
+// GetColumns returns columns for a specific table
+func (c *BigQueryConnector) GetColumns(tableName string) ([]Column, error) {
+	var resp struct {
+		Schema struct {
+			Fields []bigQueryField `json:"fields"`
+		} `json:"schema"`
+	}
+
+	path := fmt.Sprintf("/projects/%s/datasets/%s/tables/%s", c.project, c.config.Database, url.PathEscape(tableName))
+	if err := c.call(context.Background(), "GET", path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	var columns []Column
+	for _, f := range resp.Schema.Fields {
+		colType := f.Type
+		if f.Mode == "REPEATED" {
+			colType = "ARRAY<" + colType + ">"
+		}
+		columns = append(columns, Column{
+			Name:     f.Name,
+			Type:     colType,
+			Nullable: f.Mode != "REQUIRED",
+			Default:  f.DefaultValueExpression,
+		})
+	}
+
+	return columns, nil
+}
+
+// GetIndexes returns no indexes; BigQuery tables have none (search
+// indexes aside)
+func (c *BigQueryConnector) GetIndexes(tableName string) ([]Index, error) {
+	return nil, nil
+}
+
+// GetTableDDL returns the CREATE statement BigQuery reports for a table
+func (c *BigQueryConnector) GetTableDDL(tableName string) (string, error) {
+	if c.config.Database == "" {
+		return "", fmt.Errorf("no dataset selected")
+	}
+
+	query := fmt.Sprintf(
+		"SELECT ddl FROM %s.INFORMATION_SCHEMA.TABLES WHERE table_name = %s",
+		QuoteIdentifier("bigquery", c.project+"."+c.config.Database),
+		QuoteLiteral("bigquery", tableName),
+	)
+	set, err := c.queryResultSet(context.Background(), query)
+	if err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	if len(set.Rows) == 0 {
+		return "", fmt.Errorf("table %s not found", tableName)
+	}
+	ddl, _ := set.Rows[0]["ddl"].Data.(string)
+	return ddl, nil
+}
+
+// GetSchema returns the complete dataset schema
+func (c *BigQueryConnector) GetSchema() (*Schema, error) {
+	tables, err := c.GetTables()
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		Tables: make(map[string]Table),
+	}
+
+	for _, tableName := range tables {
+		columns, err := c.GetColumns(tableName)
+		if err != nil {
+			continue // Skip tables we can't read
+		}
+		// Indexes are best effort; the table is still usable without them
+		indexes, _ := c.GetIndexes(tableName)
+		schema.Tables[tableName] = Table{
+			Name:    tableName,
+			Columns: columns,
+			Indexes: indexes,
+		}
+	}
+
+	return schema, nil
+}
+
+// GetDatabases returns the datasets in the project
+func (c *BigQueryConnector) GetDatabases() ([]string, error) {
+	var datasets []string
+	pageToken := ""
+	for {
+		var resp struct {
+			Datasets []struct {
+				DatasetReference struct {
+					DatasetID string `json:"datasetId"`
+				} `json:"datasetReference"`
+			} `json:"datasets"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+
+		path := fmt.Sprintf("/projects/%s/datasets?maxResults=1000", c.project)
+		if pageToken != "" {
+			path += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+		if err := c.call(context.Background(), "GET", path, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get databases: %w", err)
+		}
+
+		for _, d := range resp.Datasets {
+			datasets = append(datasets, d.DatasetReference.DatasetID)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return datasets, nil
+}
+
+// SwitchDatabase switches the default dataset
+func (c *BigQueryConnector) SwitchDatabase(dbName string) error {
+	c.config.Database = dbName
+	return nil
+}
+
+// Capabilities returns service details and supported features
+func (c *BigQueryConnector) Capabilities() Capabilities {
+	caps := Capabilities{
+		ServerVersion:          "BigQuery (standard SQL)",
+		Encoding:               "UTF-8",
+		Timezone:               "UTC",
+		SupportsSwitchDatabase: true,
+	}
+	if c.account != nil {
+		caps.CurrentUser = c.account.ClientEmail
+	}
+	return caps
+}
@@ -0,0 +1,17 @@
+package db
+
+// Capabilities describes the connected server and which SQDesk features the
+// driver supports, so the TUI can enable actions per driver instead of
+// relying on errors
+type Capabilities struct {
+	// Server details; empty when the server can't report them
+	ServerVersion string
+	Encoding      string
+	Timezone      string
+	CurrentUser   string
+
+	SupportsSwitchDatabase bool
+	SupportsCancel         bool
+	SupportsTransactions   bool
+	SupportsExplain        bool
+}
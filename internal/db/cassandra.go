@@ -0,0 +1,588 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gopkg.in/inf.v0"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// CassandraConnector implements Connector for Cassandra and ScyllaDB (CQL).
+// Keyspaces are exposed as databases.
+type CassandraConnector struct {
+	config  *config.DatabaseConfig
+	session *gocql.Session
+	limit   ResultLimit
+}
+
+// NewCassandraConnector creates a new CQL connector
+func NewCassandraConnector(cfg *config.DatabaseConfig) *CassandraConnector {
+	return &CassandraConnector{
+		config: cfg,
+	}
+}
+
+// Connect establishes a session with the cluster. Host may list several
+// contact points separated by commas.
+func (c *CassandraConnector) Connect(ctx context.Context) error {
+	var hosts []string
+	for _, h := range strings.Split(c.config.Host, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("no contact points configured")
+	}
+
+	cluster := gocql.NewCluster(hosts...)
+	if c.config.Port != 0 {
+		cluster.Port = c.config.Port
+	}
+	cluster.Keyspace = c.config.Database
+	// LOCAL_ONE keeps single-node dev clusters and RF=1 keyspaces usable
+	cluster.Consistency = gocql.LocalOne
+	cluster.Timeout = 10 * time.Second
+	cluster.ConnectTimeout = c.config.ConnectTimeoutDuration()
+
+	if c.config.User != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: c.config.User,
+			Password: c.config.Password,
+		}
+	}
+	if tlsEnabled(c.config) {
+		tlsConfig, err := clientTLSConfig(c.config)
+		if err != nil {
+			return err
+		}
+		// Host lists several contact points; gocql fills in each node's name
+		tlsConfig.ServerName = ""
+		cluster.SslOpts = &gocql.SslOptions{
+			Config:                 tlsConfig,
+			EnableHostVerification: c.config.SSLMode == "verify-full",
+		}
+	}
+
+	// CreateSession takes no context, so give up on it when ctx is done and
+	// close the session if it arrives late
+	type result struct {
+		session *gocql.Session
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		session, err := cluster.CreateSession()
+		done <- result{session, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return fmt.Errorf("failed to connect to Cassandra: %w", r.err)
+		}
+		c.session = r.session
+		return nil
+	case <-ctx.Done():
+		go func() {
+			if r := <-done; r.session != nil {
+				r.session.Close()
+			}
+		}()
+		return fmt.Errorf("failed to connect to Cassandra: %w", ctx.Err())
+	}
+}
+
+// Close closes the session
+func (c *CassandraConnector) Close() error {
+	if c.session != nil {
+		c.session.Close()
+		c.session = nil
+	}
+	return nil
+}
+
+// IsConnected checks if the session is open
+func (c *CassandraConnector) IsConnected() bool {
+	return c.session != nil && !c.session.Closed()
+}
+
+// GetDriverName returns the driver name
+func (c *CassandraConnector) GetDriverName() string {
+	return "cassandra"
+}
+
+// GetDatabaseName returns the current keyspace
+func (c *CassandraConnector) GetDatabaseName() string {
+	return c.config.Database
+}
+
+// cqlStatement trims whitespace and the trailing semicolon editors leave
+func cqlStatement(sql string) string {
+	return strings.TrimSuffix(strings.TrimSpace(sql), ";")
+}
+
+// Query executes a CQL query and returns results
+func (c *CassandraConnector) Query(ctx context.Context, sql string) ([]Row, []string, error) {
+	set, err := c.queryResultSet(ctx, sql)
+	if err != nil {
+		return nil, nil, err
+	}
+	return set.Rows, set.Columns, nil
+}
+
+// QueryMulti executes a CQL query, returning its single result set with
+// column types
+func (c *CassandraConnector) QueryMulti(ctx context.Context, sql string) ([]ResultSet, error) {
+	set, err := c.queryResultSet(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	return []ResultSet{set}, nil
+}
+
+// queryResultSet executes a CQL query with args bound to its ? markers and
+// reads rows with column types up to the result limit
+func (c *CassandraConnector) queryResultSet(ctx context.Context, sql string, args ...interface{}) (ResultSet, error) {
+	return collectResultSet(c.limit, func(onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+		return c.streamCQL(ctx, sql, args, onColumns, onRow)
+	})
+}
+
+// QueryParams executes a CQL query with bound parameters
+func (c *CassandraConnector) QueryParams(ctx context.Context, sql string, args []interface{}) ([]ResultSet, error) {
+	set, err := c.queryResultSet(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return []ResultSet{set}, nil
+}
+
+// StreamQuery executes a CQL query and streams its rows page by page.
+// Cassandra has no way to stop a running statement; cancelling ctx abandons
+// it and stops fetching pages.
+func (c *CassandraConnector) StreamQuery(ctx context.Context, sql string, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	return c.streamCQL(ctx, sql, nil, onColumns, onRow)
+}
+
+// streamCQL streams the rows of a CQL query with args bound
+func (c *CassandraConnector) streamCQL(ctx context.Context, sql string, args []interface{}, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	if c.session == nil {
+		return fmt.Errorf("not connected to database")
+	}
+
+	iter := c.session.Query(cqlStatement(sql), args...).WithContext(ctx).PageSize(1000).Iter()
+
+	// RowData splits tuple columns into name[0], name[1], ... like MapScan
+	rowData, err := iter.RowData()
+	if err != nil {
+		iter.Close()
+		return fmt.Errorf("query error: %w", err)
+	}
+	columns := rowData.Columns
+	types := make([]ColumnType, 0, len(columns))
+	for _, col := range iter.Columns() {
+		elems := []gocql.TypeInfo{col.TypeInfo}
+		if tuple, ok := col.TypeInfo.(gocql.TupleTypeInfo); ok {
+			elems = tuple.Elems
+		}
+		for _, elem := range elems {
+			dbType := elem.Type().String()
+			name := columns[len(types)]
+			types = append(types, ColumnType{Name: name, DatabaseType: dbType, Kind: ColumnKindOf(dbType)})
+		}
+	}
+	if err := onColumns(types); err != nil {
+		iter.Close()
+		return err
+	}
+
+	values := make([]Value, len(columns))
+	for {
+		// Scanning into pointers to pointers leaves nil for NULL, where
+		// MapScan would return the type's zero value
+		dest := make([]interface{}, len(rowData.Values))
+		for i, v := range rowData.Values {
+			dest[i] = reflect.New(reflect.TypeOf(v)).Interface()
+		}
+		if !iter.Scan(dest...) {
+			break
+		}
+
+		for i := range columns {
+			values[i] = cassandraValue(dest[i])
+		}
+		if err := onRow(values); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	return nil
+}
+
+// cassandraValue converts a scanned **T to a value
+func cassandraValue(dest interface{}) Value {
+	ptr := reflect.ValueOf(dest).Elem()
+	if ptr.IsNil() {
+		return Value{Null: true}
+	}
+	switch val := ptr.Elem().Interface().(type) {
+	case []byte:
+		return Value{Data: val}
+	case gocql.UUID:
+		return Value{Data: val.String()}
+	case *big.Int:
+		// varint
+		return Value{Data: Decimal(val.String())}
+	case *inf.Dec:
+		return Value{Data: Decimal(val.String())}
+	default:
+		return Value{Data: val}
+	}
+}
+
+// SetResultLimit caps rows and memory kept per result
+func (c *CassandraConnector) SetResultLimit(limit ResultLimit) {
+	c.limit = limit
+}
+
+// Execute runs a CQL statement. CQL does not report affected rows.
+func (c *CassandraConnector) Execute(ctx context.Context, sql string) (int64, error) {
+	if c.session == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	if err := c.session.Query(cqlStatement(sql)).WithContext(ctx).Exec(); err != nil {
+		return 0, fmt.Errorf("execute error: %w", err)
+	}
+	return 0, nil
+}
+
+// ExecuteParams runs a CQL statement with bound parameters
+func (c *CassandraConnector) ExecuteParams(ctx context.Context, sql string, args []interface{}) (int64, error) {
+	if c.session == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	if err := c.session.Query(cqlStatement(sql), args...).WithContext(ctx).Exec(); err != nil {
+		return 0, fmt.Errorf("execute error: %w", err)
+	}
+	return 0, nil
+}
+
+// GetTables returns tables in the current keyspace
+func (c *CassandraConnector) GetTables() ([]string, error) {
+	if c.session == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if c.config.Database == "" {
+		return nil, nil
+	}
+
+	iter := c.session.Query(
+		"SELECT table_name FROM system_schema.tables WHERE keyspace_name = ?",
+		c.config.Database,
+	).Iter()
+
+	var tables []string
+	var name string
+	for iter.Scan(&name) {
+		tables = append(tables, name)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// cqlKindOrder orders columns as CQL declares them: partition key,
+// clustering columns, then the rest
+var cqlKindOrder = map[string]int{
+	"partition_key": 0,
+	"clustering":    1,
+	"static":        2,
+	"regular":       3,
+}
+
+// GetColumns returns columns for a specific table
+func (c *CassandraConnector) GetColumns(tableName string) ([]Column, error) {
+	if c.session == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	iter := c.session.Query(
+		"SELECT column_name, type, kind, position FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?",
+		c.config.Database, tableName,
+	).Iter()
+
+	type cqlColumn struct {
+		Column
+		kind     string
+		position int
+	}
+
+	var cols []cqlColumn
+	var name, colType, kind string
+	var position int
+	for iter.Scan(&name, &colType, &kind, &position) {
+		isKey := kind == "partition_key" || kind == "clustering"
+		cols = append(cols, cqlColumn{
+			Column: Column{
+				Name:     name,
+				Type:     colType,
+				Nullable: !isKey,
+				IsPK:     isKey,
+			},
+			kind:     kind,
+			position: position,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	sort.SliceStable(cols, func(i, j int) bool {
+		if cols[i].kind != cols[j].kind {
+			return cqlKindOrder[cols[i].kind] < cqlKindOrder[cols[j].kind]
+		}
+		if cols[i].position != cols[j].position {
+			return cols[i].position < cols[j].position
+		}
+		return cols[i].Name < cols[j].Name
+	})
+
+	columns := make([]Column, len(cols))
+	for i, col := range cols {
+		columns[i] = col.Column
+	}
+	return columns, nil
+}
+
+// GetIndexes returns the primary key and the secondary indexes of a table
+func (c *CassandraConnector) GetIndexes(tableName string) ([]Index, error) {
+	if c.session == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var indexes []Index
+	// The primary key is the partition key followed by clustering columns
+	columns, err := c.GetColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	pk := Index{Name: "PRIMARY KEY", Unique: true, Primary: true}
+	for _, col := range columns {
+		if col.IsPK {
+			pk.Columns = append(pk.Columns, col.Name)
+		}
+	}
+	if len(pk.Columns) > 0 {
+		indexes = append(indexes, pk)
+	}
+
+	iter := c.session.Query(
+		"SELECT index_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? AND table_name = ?",
+		c.config.Database, tableName,
+	).Iter()
+	var name, kind string
+	var options map[string]string
+	for iter.Scan(&name, &kind, &options) {
+		idx := Index{Name: name, Method: strings.ToLower(kind)}
+		if class := options["class_name"]; class != "" {
+			// Custom indexes (SAI, SASI) name their implementation class
+			idx.Method = class[strings.LastIndex(class, ".")+1:]
+		}
+		if target := options["target"]; target != "" {
+			idx.Columns = []string{target}
+		}
+		indexes = append(indexes, idx)
+		options = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// GetTableDDL rebuilds the CREATE TABLE statement of a table from
+// system_schema, followed by its secondary indexes. Table options other
+// than the clustering order are left out.
+func (c *CassandraConnector) GetTableDDL(tableName string) (string, error) {
+	if c.session == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	iter := c.session.Query(
+		"SELECT column_name, type, kind, position, clustering_order FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?",
+		c.config.Database, tableName,
+	).Iter()
+
+	type cqlColumn struct {
+		name, colType, kind, order string
+		position                   int
+	}
+	var cols []cqlColumn
+	var col cqlColumn
+	for iter.Scan(&col.name, &col.colType, &col.kind, &col.position, &col.order) {
+		cols = append(cols, col)
+	}
+	if err := iter.Close(); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	if len(cols) == 0 {
+		return "", fmt.Errorf("table %s not found", tableName)
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		if cols[i].kind != cols[j].kind {
+			return cqlKindOrder[cols[i].kind] < cqlKindOrder[cols[j].kind]
+		}
+		if cols[i].position != cols[j].position {
+			return cols[i].position < cols[j].position
+		}
+		return cols[i].name < cols[j].name
+	})
+
+	var lines, partitionKey, clustering, order []string
+	for _, col := range cols {
+		line := QuoteIdentifier("cassandra", col.name) + " " + col.colType
+		switch col.kind {
+		case "partition_key":
+			partitionKey = append(partitionKey, QuoteIdentifier("cassandra", col.name))
+		case "clustering":
+			clustering = append(clustering, QuoteIdentifier("cassandra", col.name))
+			order = append(order, QuoteIdentifier("cassandra", col.name)+" "+strings.ToUpper(col.order))
+		case "static":
+			line += " static"
+		}
+		lines = append(lines, line)
+	}
+	key := strings.Join(partitionKey, ", ")
+	if len(partitionKey) > 1 {
+		key = "(" + key + ")"
+	}
+	lines = append(lines, "PRIMARY KEY ("+strings.Join(append([]string{key}, clustering...), ", ")+")")
+
+	table := QuoteIdentifier("cassandra", c.config.Database) + "." + QuoteIdentifier("cassandra", tableName)
+	ddl := "CREATE TABLE " + table + " (\n    " + strings.Join(lines, ",\n    ") + "\n)"
+	if len(order) > 0 {
+		ddl += " WITH CLUSTERING ORDER BY (" + strings.Join(order, ", ") + ")"
+	}
+	ddl += ";"
+
+	iter = c.session.Query(
+		"SELECT index_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? AND table_name = ?",
+		c.config.Database, tableName,
+	).Iter()
+	var name, kind string
+	var options map[string]string
+	for iter.Scan(&name, &kind, &options) {
+		stmt := "CREATE INDEX "
+		if kind == "CUSTOM" {
+			stmt = "CREATE CUSTOM INDEX "
+		}
+		stmt += QuoteIdentifier("cassandra", name) + " ON " + table + " (" + options["target"] + ")"
+		if class := options["class_name"]; class != "" {
+			stmt += " USING " + QuoteLiteral("cassandra", class)
+		}
+		ddl += "\n" + stmt + ";"
+		options = nil
+	}
+	if err := iter.Close(); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	return ddl, nil
+}
+
+// GetSchema returns the complete keyspace schema
+func (c *CassandraConnector) GetSchema() (*Schema, error) {
+	tables, err := c.GetTables()
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		Tables: make(map[string]Table),
+	}
+
+	for _, tableName := range tables {
+		columns, err := c.GetColumns(tableName)
+		if err != nil {
+			continue // Skip tables we can't read
+		}
+		// Indexes are best effort; the table is still usable without them
+		indexes, _ := c.GetIndexes(tableName)
+		schema.Tables[tableName] = Table{
+			Name:    tableName,
+			Columns: columns,
+			Indexes: indexes,
+		}
+	}
+
+	return schema, nil
+}
+
+// GetDatabases returns the keyspaces in the cluster
+func (c *CassandraConnector) GetDatabases() ([]string, error) {
+	if c.session == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	iter := c.session.Query("SELECT keyspace_name FROM system_schema.keyspaces").Iter()
+
+	var keyspaces []string
+	var name string
+	for iter.Scan(&name) {
+		keyspaces = append(keyspaces, name)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get databases: %w", err)
+	}
+
+	sort.Strings(keyspaces)
+	return keyspaces, nil
+}
+
+// SwitchDatabase switches to a different keyspace
+func (c *CassandraConnector) SwitchDatabase(dbName string) error {
+	// A session is bound to its keyspace, so reconnect
+	c.Close()
+
+	c.config.Database = dbName
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.ConnectTimeoutDuration())
+	defer cancel()
+	return c.Connect(ctx)
+}
+
+// Capabilities returns cluster details and supported features
+func (c *CassandraConnector) Capabilities() Capabilities {
+	caps := Capabilities{
+		Encoding:               "UTF-8",
+		Timezone:               "UTC",
+		CurrentUser:            c.config.User,
+		SupportsSwitchDatabase: true,
+	}
+	if c.session == nil {
+		return caps
+	}
+
+	var release, cqlVersion string
+	if err := c.session.Query("SELECT release_version, cql_version FROM system.local").Scan(&release, &cqlVersion); err == nil {
+		caps.ServerVersion = fmt.Sprintf("%s (CQL %s)", release, cqlVersion)
+	}
+	return caps
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+)
+
+// explainOptionsRe matches the parenthesised option list of an EXPLAIN
+var explainOptionsRe = regexp.MustCompile(`(?is)^(\s*explain\s*)\(([^)]*)\)`)
+
+// cockroachExplainOptions are the EXPLAIN options CockroachDB understands
+var cockroachExplainOptions = map[string]bool{
+	"ANALYZE": true,
+	"VERBOSE": true,
+	"TYPES":   true,
+	"OPT":     true,
+	"VEC":     true,
+	"DISTSQL": true,
+}
+
+// cockroachExplain rewrites a Postgres-style EXPLAIN for CockroachDB by
+// dropping options it rejects (BUFFERS, COSTS, FORMAT, TIMING...). Other
+// statements are returned unchanged.
+func cockroachExplain(sql string) string {
+	m := explainOptionsRe.FindStringSubmatch(sql)
+	if m == nil {
+		return sql
+	}
+
+	var kept []string
+	for _, opt := range strings.Split(m[2], ",") {
+		fields := strings.Fields(strings.TrimSpace(opt))
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToUpper(fields[0])
+		// "ANALYZE false" style toggles are dropped along with the option
+		if len(fields) > 1 && strings.EqualFold(fields[1], "false") {
+			continue
+		}
+		if cockroachExplainOptions[name] {
+			kept = append(kept, name)
+		}
+	}
+
+	rest := sql[len(m[0]):]
+	if len(kept) == 0 {
+		return m[1] + strings.TrimLeft(rest, " \t\n")
+	}
+	return m[1] + "(" + strings.Join(kept, ", ") + ")" + rest
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"strings"
+	"time"
+)
+
+// ColumnKind groups database types by how their values are displayed
+type ColumnKind int
+
+const (
+	ColumnText ColumnKind = iota
+	ColumnNumber
+	ColumnBool
+	ColumnDate // date without time of day
+	ColumnTime // timestamp, datetime or time of day
+	ColumnBinary
+)
+
+// ColumnType describes a result column
+type ColumnType struct {
+	Name         string
+	DatabaseType string // driver-reported type name, e.g. INT4 or VARCHAR
+	Kind         ColumnKind
+}
+
+// IsNumeric reports whether values should be right-aligned and sorted as numbers
+func (t ColumnType) IsNumeric() bool {
+	return t.Kind == ColumnNumber
+}
+
+// columnKinds maps upper-case database type names to kinds. Names cover
+// Postgres, MySQL, SQLite declared types, BigQuery and CQL.
+var columnKinds = map[string]ColumnKind{
+	"INT": ColumnNumber, "INT2": ColumnNumber, "INT4": ColumnNumber, "INT8": ColumnNumber,
+	"INTEGER": ColumnNumber, "SMALLINT": ColumnNumber, "TINYINT": ColumnNumber,
+	"MEDIUMINT": ColumnNumber, "BIGINT": ColumnNumber, "INT64": ColumnNumber,
+	"SERIAL": ColumnNumber, "BIGSERIAL": ColumnNumber, "OID": ColumnNumber,
+	"NUMERIC": ColumnNumber, "DECIMAL": ColumnNumber, "BIGNUMERIC": ColumnNumber,
+	"FLOAT": ColumnNumber, "FLOAT4": ColumnNumber, "FLOAT8": ColumnNumber,
+	"FLOAT64": ColumnNumber, "DOUBLE": ColumnNumber, "REAL": ColumnNumber,
+	"MONEY": ColumnNumber, "VARINT": ColumnNumber, "COUNTER": ColumnNumber,
+	"YEAR": ColumnNumber,
+	"BOOL": ColumnBool, "BOOLEAN": ColumnBool,
+	"DATE": ColumnDate,
+	"TIME": ColumnTime, "TIMETZ": ColumnTime, "TIMESTAMP": ColumnTime,
+	"TIMESTAMPTZ": ColumnTime, "DATETIME": ColumnTime,
+	"BYTEA": ColumnBinary, "BLOB": ColumnBinary, "TINYBLOB": ColumnBinary,
+	"MEDIUMBLOB": ColumnBinary, "LONGBLOB": ColumnBinary, "BINARY": ColumnBinary,
+	"VARBINARY": ColumnBinary, "BYTES": ColumnBinary,
+}
+
+// ColumnKindOf returns the kind for a driver-reported type name
+func ColumnKindOf(databaseType string) ColumnKind {
+	name := strings.ToUpper(strings.TrimSpace(databaseType))
+	// Drop modifiers: "UNSIGNED INT", "DECIMAL(10,2)", "TIMESTAMP WITH TIME ZONE"
+	name = strings.TrimPrefix(name, "UNSIGNED ")
+	if i := strings.IndexAny(name, "( "); i > 0 {
+		name = name[:i]
+	}
+	return columnKinds[name]
+}
+
+// InferColumnTypes builds column types from the values alone, for results
+// whose driver reports no type names
+func InferColumnTypes(columns []string, rows []Row) []ColumnType {
+	types := make([]ColumnType, len(columns))
+	for i, col := range columns {
+		types[i] = newColumnType(col, "", rows)
+	}
+	return types
+}
+
+// newColumnType builds a column type, inferring the kind from the first
+// non-NULL value when the driver reports no type name (SQLite expressions)
+func newColumnType(name, databaseType string, rows []Row) ColumnType {
+	t := ColumnType{Name: name, DatabaseType: databaseType, Kind: ColumnKindOf(databaseType)}
+	if databaseType != "" {
+		return t
+	}
+
+	for _, row := range rows {
+		v, ok := row[name]
+		if !ok || v.Null {
+			continue
+		}
+		switch v.Data.(type) {
+		case int, int32, int64, uint64, float32, float64, Decimal:
+			t.Kind = ColumnNumber
+		case bool:
+			t.Kind = ColumnBool
+		case time.Time:
+			t.Kind = ColumnTime
+		case []byte:
+			t.Kind = ColumnBinary
+		}
+		return t
+	}
+	return t
+}
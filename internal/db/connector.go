@@ -1,7 +1,10 @@
 package db
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/febritecno/sqdesk-cli/internal/config"
 	"github.com/jmoiron/sqlx"
@@ -17,12 +20,25 @@ type Column struct {
 	Type     string
 	Nullable bool
 	IsPK     bool
+	Default  string // default expression; empty when there is none
 }
 
-// Table represents a database table with its columns
-type Table struct {
+// Index represents an index of a table
+type Index struct {
 	Name    string
-	Columns []Column
+	Columns []string // column names or expressions, in key order
+	Unique  bool
+	Primary bool
+	Method  string // e.g. btree, hash, gin; empty when unknown
+}
+
+// Table represents a database table with its columns, indexes and foreign
+// keys
+type Table struct {
+	Name        string
+	Columns     []Column
+	Indexes     []Index
+	ForeignKeys []ForeignKey
 }
 
 // Schema represents the database schema
@@ -30,20 +46,59 @@ type Schema struct {
 	Tables map[string]Table
 }
 
+// ResultSet is one set of rows returned by a query
+type ResultSet struct {
+	Columns     []string
+	ColumnTypes []ColumnType // parallel to Columns
+	Rows        []Row
+	// Truncated is set when fetching stopped at the connector's ResultLimit
+	Truncated bool
+}
+
+// MultiQuerier is implemented by connectors that can return several result
+// sets from one query
+type MultiQuerier interface {
+	QueryMulti(ctx context.Context, sql string) ([]ResultSet, error)
+}
+
+// QueryMulti runs a query returning all result sets, falling back to a
+// single Query for connectors without multi-result support
+func QueryMulti(ctx context.Context, c Connector, sql string) ([]ResultSet, error) {
+	if mq, ok := c.(MultiQuerier); ok {
+		return mq.QueryMulti(ctx, sql)
+	}
+	rows, columns, err := c.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	// Without driver type names, kinds are inferred from the values
+	types := InferColumnTypes(columns, rows)
+	return []ResultSet{{Columns: columns, ColumnTypes: types, Rows: rows}}, nil
+}
+
 // Connector interface for database operations
 type Connector interface {
-	Connect() error
+	// Connect opens the connection, giving up when ctx is done
+	Connect(ctx context.Context) error
 	Close() error
 	IsConnected() bool
-	Query(sql string) ([]map[string]interface{}, []string, error)
-	Execute(sql string) (int64, error)
+	// Query and Execute stop the statement, server-side where the
+	// database allows it, when ctx is cancelled
+	Query(ctx context.Context, sql string) ([]Row, []string, error)
+	Execute(ctx context.Context, sql string) (int64, error)
 	GetTables() ([]string, error)
 	GetColumns(tableName string) ([]Column, error)
+	// GetIndexes returns the table's indexes, primary key first
+	GetIndexes(tableName string) ([]Index, error)
+	// GetTableDDL returns the statements that create the table and its
+	// indexes
+	GetTableDDL(tableName string) (string, error)
 	GetSchema() (*Schema, error)
 	GetDatabases() ([]string, error)
 	SwitchDatabase(dbName string) error
 	GetDriverName() string
 	GetDatabaseName() string
+	Capabilities() Capabilities
 }
 
 // BaseConnector implements common database operations
@@ -51,6 +106,21 @@ type BaseConnector struct {
 	config *config.DatabaseConfig
 	db     *sqlx.DB
 	driver string
+	tunnel *Tunnel
+	tx     *sqlx.Tx // open transaction, see Begin
+	limit  ResultLimit
+	// pinned is the session connection once temporary tables exist, see
+	// CreateTempTable
+	pinned     *sqlx.Conn
+	tempTables []string
+	// replica serves read-only queries when replica_host is set, see reader
+	replica       *sqlx.DB
+	replicaTunnel *Tunnel
+	primaryOnly   bool
+	// lastEndpoint is where the last query ran; endpointMu guards it since
+	// maintenance and progress polls overlap queries
+	endpointMu   sync.Mutex
+	lastEndpoint Endpoint
 }
 
 // NewConnector creates a new database connector based on driver type
@@ -60,17 +130,49 @@ func NewConnector(cfg *config.DatabaseConfig) (Connector, error) {
 	}
 
 	switch cfg.Driver {
-	case "postgres", "postgresql":
+	case "postgres", "postgresql", "cockroachdb", "cockroach":
 		return NewPostgresConnector(cfg), nil
 	case "mysql":
 		return NewMySQLConnector(cfg), nil
+	case "mariadb":
+		return NewMariaDBConnector(cfg), nil
 	case "sqlite", "sqlite3":
 		return NewSQLiteConnector(cfg), nil
+	case "bigquery":
+		return NewBigQueryConnector(cfg), nil
+	case "cassandra", "scylla", "scylladb":
+		return NewCassandraConnector(cfg), nil
 	default:
 		return nil, fmt.Errorf("unsupported driver: %s", cfg.Driver)
 	}
 }
 
+// Open connects to cfg, giving up after the connection's timeout or when
+// ctx is cancelled. The caller closes the connector.
+func Open(ctx context.Context, cfg *config.DatabaseConfig) (Connector, error) {
+	connector, err := NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	timeout := cfg.ConnectTimeoutDuration()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := connector.Connect(ctx); err != nil {
+		connector.Close()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, err
+	}
+	if !connector.IsConnected() {
+		connector.Close()
+		return nil, fmt.Errorf("connection test failed")
+	}
+	return connector, nil
+}
+
 // IsConnected checks if database is connected
 func (c *BaseConnector) IsConnected() bool {
 	if c.db == nil {
@@ -81,10 +183,26 @@ func (c *BaseConnector) IsConnected() bool {
 
 // Close closes the database connection
 func (c *BaseConnector) Close() error {
+	var err error
+	if c.tx != nil {
+		// An open transaction is rolled back, never committed implicitly
+		c.tx.Rollback()
+		c.tx = nil
+	}
+	c.unpin()
+	c.closeReplica()
 	if c.db != nil {
-		return c.db.Close()
+		err = c.db.Close()
+	}
+	if c.tunnel != nil {
+		c.tunnel.Close()
+		c.tunnel = nil
 	}
-	return nil
+	if c.replicaTunnel != nil {
+		c.replicaTunnel.Close()
+		c.replicaTunnel = nil
+	}
+	return err
 }
 
 // GetDriverName returns the driver name
@@ -98,54 +216,149 @@ func (c *BaseConnector) GetDatabaseName() string {
 }
 
 // Query executes a SELECT query and returns results
-func (c *BaseConnector) Query(sql string) ([]map[string]interface{}, []string, error) {
+func (c *BaseConnector) Query(ctx context.Context, sql string) ([]Row, []string, error) {
 	if c.db == nil {
 		return nil, nil, fmt.Errorf("not connected to database")
 	}
+	return queryRows(ctx, c.readSession(sql), sql, c.limit)
+}
 
-	rows, err := c.db.Queryx(sql)
+// queryRows runs a query on q, which is the pool or a dedicated connection
+func queryRows(ctx context.Context, q sqlx.QueryerContext, sql string, limit ResultLimit) ([]Row, []string, error) {
+	rows, err := q.QueryxContext(ctx, sql)
 	if err != nil {
 		return nil, nil, fmt.Errorf("query error: %w", err)
 	}
 	defer rows.Close()
 
-	// Get column names
+	set, err := scanResultSet(rows, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return set.Rows, set.Columns, nil
+}
+
+// QueryMulti executes a query and returns every result set it produces
+// (CALL procedures, batched statements)
+func (c *BaseConnector) QueryMulti(ctx context.Context, sql string) ([]ResultSet, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	return queryResultSets(ctx, c.readSession(sql), sql, c.limit)
+}
+
+// queryResultSets reads every result set of a query run on q with args
+// bound to its placeholders
+func queryResultSets(ctx context.Context, q sqlx.QueryerContext, sql string, limit ResultLimit, args ...interface{}) ([]ResultSet, error) {
+	rows, err := q.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var sets []ResultSet
+	for {
+		set, err := scanResultSet(rows, limit)
+		if err != nil {
+			return nil, err
+		}
+		// Statements without a result (e.g. SET in a batch) have no columns
+		if len(set.Columns) > 0 {
+			sets = append(sets, set)
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return sets, nil
+}
+
+// resultColumns returns the column names and database type names of the
+// current result set
+func resultColumns(rows *sqlx.Rows) ([]string, []string, error) {
 	columns, err := rows.Columns()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get columns: %w", err)
 	}
+	// Type names are best effort; some drivers don't report them
+	dbTypes := make([]string, len(columns))
+	if colTypes, err := rows.ColumnTypes(); err == nil {
+		for i, ct := range colTypes {
+			if i < len(dbTypes) {
+				dbTypes[i] = ct.DatabaseTypeName()
+			}
+		}
+	}
+	return columns, dbTypes, nil
+}
+
+// scanResultSet reads the current result set of rows with its column types,
+// stopping once limit is reached
+func scanResultSet(rows *sqlx.Rows, limit ResultLimit) (ResultSet, error) {
+	columns, dbTypes, err := resultColumns(rows)
+	if err != nil {
+		return ResultSet{}, err
+	}
+
+	kinds := make(map[string]ColumnKind, len(columns))
+	for i, col := range columns {
+		kinds[col] = ColumnKindOf(dbTypes[i])
+	}
 
-	var results []map[string]interface{}
+	var results []Row
+	var size int64
+	truncated := false
 	for rows.Next() {
-		row := make(map[string]interface{})
-		if err := rows.MapScan(row); err != nil {
-			return nil, nil, fmt.Errorf("scan error: %w", err)
+		if limit.reached(len(results), size) {
+			// There are more rows than the limit allows
+			truncated = true
+			break
+		}
+		scanned := make(map[string]interface{})
+		if err := rows.MapScan(scanned); err != nil {
+			return ResultSet{}, fmt.Errorf("scan error: %w", err)
 		}
 		
-		// Convert []byte to string for better display
-		for k, v := range row {
-			if b, ok := v.([]byte); ok {
-				row[k] = string(b)
-			}
+		row := make(Row, len(scanned))
+		for k, v := range scanned {
+			row[k] = scannedValue(v, kinds[k])
 		}
 		
 		results = append(results, row)
+		size += rowSize(row)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, nil, fmt.Errorf("rows error: %w", err)
+	types := make([]ColumnType, len(columns))
+	for i, col := range columns {
+		types[i] = newColumnType(col, dbTypes[i], results)
 	}
 
-	return results, columns, nil
+	return ResultSet{Columns: columns, ColumnTypes: types, Rows: results, Truncated: truncated}, nil
 }
 
 // Execute runs an INSERT/UPDATE/DELETE query
-func (c *BaseConnector) Execute(sql string) (int64, error) {
+func (c *BaseConnector) Execute(ctx context.Context, sql string) (int64, error) {
 	if c.db == nil {
 		return 0, fmt.Errorf("not connected to database")
 	}
+	c.setEndpoint(EndpointPrimary)
+	return execute(ctx, c.session(), sql)
+}
 
-	result, err := c.db.Exec(sql)
+// execute runs a statement on e with args bound to its placeholders,
+// returning the affected row count
+func execute(ctx context.Context, e sqlx.ExecerContext, sql string, args ...interface{}) (int64, error) {
+	result, err := e.ExecContext(ctx, sql, args...)
 	if err != nil {
 		return 0, fmt.Errorf("execute error: %w", err)
 	}
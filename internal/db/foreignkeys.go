@@ -0,0 +1,40 @@
+package db
+
+// ForeignKey is a foreign key constraint of a table
+type ForeignKey struct {
+	Name       string
+	Columns    []string // referencing columns, in key order
+	RefTable   string
+	RefColumns []string // referenced columns, parallel to Columns
+	OnDelete   string   // referential action, e.g. CASCADE; empty when unknown
+	OnUpdate   string
+}
+
+// ForeignKeyLister is implemented by connectors that can read foreign keys
+type ForeignKeyLister interface {
+	// GetForeignKeys returns the foreign keys of a table, by name
+	GetForeignKeys(tableName string) ([]ForeignKey, error)
+}
+
+// GetForeignKeys returns the table's foreign keys for connectors that
+// support it, or none for those that don't
+func GetForeignKeys(c Connector, tableName string) ([]ForeignKey, error) {
+	if fl, ok := c.(ForeignKeyLister); ok {
+		return fl.GetForeignKeys(tableName)
+	}
+	return nil, nil
+}
+
+// ReferencedBy returns the foreign keys of other tables in the schema that
+// reference tableName, keyed by the referencing table
+func (s *Schema) ReferencedBy(tableName string) map[string][]ForeignKey {
+	refs := make(map[string][]ForeignKey)
+	for name, table := range s.Tables {
+		for _, fk := range table.ForeignKeys {
+			if fk.RefTable == tableName {
+				refs[name] = append(refs[name], fk)
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"time"
+)
+
+// ResultLimit caps how much of a result set is held in memory. Zero fields
+// mean no limit.
+type ResultLimit struct {
+	Rows  int
+	Bytes int64
+}
+
+// ResultLimiter is implemented by connectors that stop fetching a result
+// set once it reaches a limit, marking it Truncated
+type ResultLimiter interface {
+	SetResultLimit(limit ResultLimit)
+}
+
+// SetResultLimit sets the connector's result limit if it supports one
+func SetResultLimit(c Connector, limit ResultLimit) {
+	if l, ok := c.(ResultLimiter); ok {
+		l.SetResultLimit(limit)
+	}
+}
+
+// reached reports whether a result of rows rows and about size bytes has
+// used up the limit
+func (l ResultLimit) reached(rows int, size int64) bool {
+	return (l.Rows > 0 && rows >= l.Rows) || (l.Bytes > 0 && size >= l.Bytes)
+}
+
+// rowOverhead approximates the map entry and interface cost of one cell
+const rowOverhead = 48
+
+// rowSize estimates the memory held by a row
+func rowSize(row Row) int64 {
+	var size int64
+	for col, v := range row {
+		size += rowOverhead + int64(len(col))
+		switch data := v.Data.(type) {
+		case string:
+			size += int64(len(data))
+		case Decimal:
+			size += int64(len(data))
+		case []byte:
+			size += int64(len(data))
+		case time.Time:
+			size += 24
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// SetResultLimit caps rows and memory kept per result set
+func (c *BaseConnector) SetResultLimit(limit ResultLimit) {
+	c.limit = limit
+}
@@ -0,0 +1,53 @@
+package db
+
+import "context"
+
+// AttachedDatabase is a database file attached to an SQLite session
+type AttachedDatabase struct {
+	Name   string // schema name tables are qualified with
+	File   string
+	Tables []string
+}
+
+// DatabaseAttacher is implemented by connectors that can attach other
+// database files to the session (SQLite's ATTACH DATABASE). Attachments are
+// per connection, so like temporary tables they pin the session connection.
+type DatabaseAttacher interface {
+	AttachDatabase(ctx context.Context, file, name string) error
+	DetachDatabase(ctx context.Context, name string) error
+	// AttachedDatabases returns the attached databases with their tables
+	AttachedDatabases(ctx context.Context) ([]AttachedDatabase, error)
+}
+
+// GetDatabaseAttacher returns the connector's ATTACH support, if any
+func GetDatabaseAttacher(c Connector) (DatabaseAttacher, bool) {
+	a, ok := c.(DatabaseAttacher)
+	return a, ok
+}
+
+// ForeignTable is a foreign table of a foreign data wrapper server
+type ForeignTable struct {
+	Schema  string
+	Name    string
+	Options string // e.g. schema_name=public, table_name=users
+}
+
+// ForeignServer is a server configured for a foreign data wrapper
+type ForeignServer struct {
+	Name    string
+	Wrapper string // e.g. postgres_fdw
+	Options string // e.g. host=db2, dbname=sales
+	Tables  []ForeignTable
+}
+
+// ForeignServerLister is implemented by connectors that can list foreign
+// data wrapper servers (Postgres FDW)
+type ForeignServerLister interface {
+	GetForeignServers() ([]ForeignServer, error)
+}
+
+// GetForeignServerLister returns the connector's FDW support, if any
+func GetForeignServerLister(c Connector) (ForeignServerLister, bool) {
+	l, ok := c.(ForeignServerLister)
+	return l, ok
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaintenanceAction is a table maintenance operation offered by a driver
+type MaintenanceAction struct {
+	Name        string
+	Description string
+	// Statement is a format string receiving the table name (or a
+	// database-wide statement without a %s verb)
+	Statement string
+}
+
+// SQL renders the action statement for a table
+func (a MaintenanceAction) SQL(table string) string {
+	if !strings.Contains(a.Statement, "%s") {
+		return a.Statement
+	}
+	return fmt.Sprintf(a.Statement, table)
+}
+
+// Maintainer is implemented by connectors that support table maintenance
+type Maintainer interface {
+	// MaintenanceActions lists the maintenance operations for this driver
+	MaintenanceActions() []MaintenanceAction
+	// RunMaintenance runs an action on a table and returns its output lines
+	RunMaintenance(action MaintenanceAction, table string) ([]string, error)
+}
+
+// MaintenanceProgressReporter is implemented by connectors that can report
+// progress of a running maintenance action from another session
+type MaintenanceProgressReporter interface {
+	MaintenanceProgress(table string) (string, error)
+}
+
+// GetMaintenanceActions returns the maintenance actions for a connector
+func GetMaintenanceActions(c Connector) []MaintenanceAction {
+	if mt, ok := c.(Maintainer); ok {
+		return mt.MaintenanceActions()
+	}
+	return nil
+}
+
+// runMaintenanceQuery runs a maintenance statement that returns rows (MySQL
+// ANALYZE/OPTIMIZE TABLE) and formats each row as an output line
+func (c *BaseConnector) runMaintenanceQuery(sql string) ([]string, error) {
+	rows, columns, err := c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, row := range rows {
+		values := make([]string, 0, len(columns))
+		for _, col := range columns {
+			values = append(values, row[col].String())
+		}
+		lines = append(lines, strings.Join(values, " | "))
+	}
+	return lines, nil
+}
+
+// runMaintenanceExec runs a maintenance statement that returns no rows
+func (c *BaseConnector) runMaintenanceExec(sql string) ([]string, error) {
+	if _, err := c.Execute(context.Background(), sql); err != nil {
+		return nil, err
+	}
+	return []string{"OK"}, nil
+}
@@ -1,15 +1,34 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
-// MySQLConnector implements Connector for MySQL
+// MySQLConnector implements Connector for MySQL and MariaDB
 type MySQLConnector struct {
 	BaseConnector
+	// mariadb enables MariaDB introspection (sequences, system-versioned
+	// tables); set by the mariadb driver or detected from VERSION()
+	mariadb bool
+	// txConnID is the server connection ID of the open transaction
+	txConnID int64
+	// pinnedConnID is the server connection ID of the pinned session
+	pinnedConnID int64
+	// noBackslashEscapes is set when sql_mode has NO_BACKSLASH_ESCAPES, so
+	// backslashes in string literals are plain characters
+	noBackslashEscapes bool
 }
 
 // NewMySQLConnector creates a new MySQL connector
@@ -22,26 +41,337 @@ func NewMySQLConnector(cfg *config.DatabaseConfig) *MySQLConnector {
 	}
 }
 
+// NewMariaDBConnector creates a MySQL connector with the MariaDB profile
+func NewMariaDBConnector(cfg *config.DatabaseConfig) *MySQLConnector {
+	c := NewMySQLConnector(cfg)
+	c.mariadb = true
+	c.driver = "mariadb"
+	return c
+}
+
 // Connect establishes connection to MySQL database
-func (c *MySQLConnector) Connect() error {
+func (c *MySQLConnector) Connect(ctx context.Context) error {
+	host, port, err := c.dialTarget(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open SSH tunnel: %w", err)
+	}
+
+	tlsName, err := registerMySQLTLS(c.config)
+	if err != nil {
+		return err
+	}
+
+	address := fmt.Sprintf("tcp(%s:%d)", host, port)
+	if c.config.UsesSocket() {
+		address = fmt.Sprintf("unix(%s)", config.ExpandPath(c.config.Socket))
+	}
+
+	db, err := c.open(ctx, address, tlsName)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MySQL: %w", err)
+	}
+
+	if c.config.ReplicaHost != "" {
+		host, port, err := c.replicaTarget(ctx)
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("failed to open SSH tunnel to read replica: %w", err)
+		}
+		replica, err := c.open(ctx, fmt.Sprintf("tcp(%s:%d)", host, port), tlsName)
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		c.replica = replica
+	}
+
+	c.db = db
+	c.detectMariaDB()
+	c.detectSQLMode()
+	return nil
+}
+
+// open connects to the server at address, e.g. tcp(host:3306). The DSN
+// leaves multiStatements off so a value spliced into generated SQL can't
+// start a second statement; batches are split by eachStatement instead.
+func (c *MySQLConnector) open(ctx context.Context, address, tlsName string) (*sqlx.DB, error) {
 	dsn := fmt.Sprintf(
-		"%s:%s@tcp(%s:%d)/%s?parseTime=true",
+		"%s:%s@%s/%s?parseTime=true&tls=%s",
 		c.config.User,
 		c.config.Password,
-		c.config.Host,
-		c.config.Port,
+		address,
 		c.config.Database,
+		url.QueryEscape(tlsName),
 	)
+	if c.config.ReadOnly {
+		return connectMySQLReadOnly(ctx, dsn, c.mariadb)
+	}
+	return sqlx.ConnectContext(ctx, "mysql", dsn)
+}
 
-	db, err := sqlx.Connect("mysql", dsn)
-	if err != nil {
-		return fmt.Errorf("failed to connect to MySQL: %w", err)
+// connectMySQLReadOnly connects with the session set read-only. The driver
+// sets DSN system variables on every pooled connection; MySQL 5.7.20+ names
+// the variable transaction_read_only, MariaDB before 11.1 tx_read_only.
+func connectMySQLReadOnly(ctx context.Context, dsn string, mariadb bool) (*sqlx.DB, error) {
+	vars := []string{"transaction_read_only", "tx_read_only"}
+	if mariadb {
+		vars[0], vars[1] = vars[1], vars[0]
 	}
 
-	c.db = db
+	db, err := sqlx.ConnectContext(ctx, "mysql", dsn+"&"+vars[0]+"=1")
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1193 { // unknown system variable
+		db, err = sqlx.ConnectContext(ctx, "mysql", dsn+"&"+vars[1]+"=1")
+	}
+	return db, err
+}
+
+// detectMariaDB switches to the MariaDB profile when a mysql connection
+// turns out to be a MariaDB server
+func (c *MySQLConnector) detectMariaDB() {
+	if !c.mariadb {
+		var version string
+		if err := c.db.Get(&version, "SELECT VERSION()"); err == nil {
+			c.mariadb = strings.Contains(version, "MariaDB")
+		}
+	}
+	if c.mariadb {
+		c.driver = "mariadb"
+	}
+}
+
+// detectSQLMode reads whether the server's sql_mode turns backslash
+// escapes off
+func (c *MySQLConnector) detectSQLMode() {
+	var mode string
+	if err := c.db.Get(&mode, "SELECT @@SESSION.sql_mode"); err == nil {
+		c.noBackslashEscapes = strings.Contains(mode, "NO_BACKSLASH_ESCAPES")
+	}
+}
+
+// QuoteLiteral quotes s as a string literal for the server's sql_mode
+func (c *MySQLConnector) QuoteLiteral(s string) string {
+	if c.noBackslashEscapes {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return QuoteLiteral(c.driver, s)
+}
+
+// IsMariaDB reports whether the connected server is MariaDB
+func (c *MySQLConnector) IsMariaDB() bool {
+	return c.mariadb
+}
+
+// Query executes a SELECT query, killing it server-side if ctx is cancelled
+func (c *MySQLConnector) Query(ctx context.Context, sql string) ([]Row, []string, error) {
+	var rows []Row
+	var columns []string
+	err := c.killOnCancelRead(ctx, sql, func(q queryExecer) error {
+		var err error
+		rows, columns, err = queryRows(ctx, q, sql, c.limit)
+		return err
+	})
+	return rows, columns, err
+}
+
+// QueryMulti executes a query returning all result sets, killing it
+// server-side if ctx is cancelled
+func (c *MySQLConnector) QueryMulti(ctx context.Context, sql string) ([]ResultSet, error) {
+	var sets []ResultSet
+	err := c.killOnCancelRead(ctx, sql, func(q queryExecer) error {
+		return c.eachStatement(sql, nil, func(stmt string, args []interface{}) error {
+			batch, err := queryResultSets(ctx, q, stmt, c.limit, args...)
+			sets = append(sets, batch...)
+			return err
+		})
+	})
+	return sets, err
+}
+
+// StreamQuery streams a query's first result set, killing it server-side
+// if ctx is cancelled
+func (c *MySQLConnector) StreamQuery(ctx context.Context, sql string, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	return c.killOnCancelRead(ctx, sql, func(q queryExecer) error {
+		return streamRows(ctx, q, sql, onColumns, onRow)
+	})
+}
+
+// Execute runs a statement, killing it server-side if ctx is cancelled
+func (c *MySQLConnector) Execute(ctx context.Context, sql string) (int64, error) {
+	var affected int64
+	c.setEndpoint(EndpointPrimary)
+	err := c.killOnCancel(ctx, func(q queryExecer) error {
+		return c.eachStatement(sql, nil, func(stmt string, args []interface{}) error {
+			n, err := execute(ctx, q, stmt, args...)
+			affected += n
+			return err
+		})
+	})
+	return affected, err
+}
+
+// QueryParams runs a query with bound parameters, killing it server-side if
+// ctx is cancelled
+func (c *MySQLConnector) QueryParams(ctx context.Context, sql string, args []interface{}) ([]ResultSet, error) {
+	var sets []ResultSet
+	err := c.killOnCancelRead(ctx, sql, func(q queryExecer) error {
+		return c.eachStatement(sql, args, func(stmt string, args []interface{}) error {
+			batch, err := queryResultSets(ctx, q, stmt, c.limit, args...)
+			sets = append(sets, batch...)
+			return err
+		})
+	})
+	return sets, err
+}
+
+// ExecuteParams runs a statement with bound parameters, killing it
+// server-side if ctx is cancelled
+func (c *MySQLConnector) ExecuteParams(ctx context.Context, sql string, args []interface{}) (int64, error) {
+	var affected int64
+	c.setEndpoint(EndpointPrimary)
+	err := c.killOnCancel(ctx, func(q queryExecer) error {
+		return c.eachStatement(sql, args, func(stmt string, args []interface{}) error {
+			n, err := execute(ctx, q, stmt, args...)
+			affected += n
+			return err
+		})
+	})
+	return affected, err
+}
+
+// eachStatement runs the statements of sql in turn, handing each the args
+// of its ? placeholders. Without multiStatements the server takes one
+// statement at a time; they all run on the connection fn is given, so
+// session variables carry over.
+func (c *MySQLConnector) eachStatement(sql string, args []interface{}, fn func(stmt string, args []interface{}) error) error {
+	dialect := sqlparse.DialectFor(c.driver)
+	statements := sqlparse.Split(sql, dialect)
+	if len(statements) <= 1 {
+		return fn(sql, args)
+	}
+	for _, stmt := range statements {
+		n := 0
+		for _, p := range sqlparse.Params(stmt.Text, dialect, sqlparse.ParamQuestion) {
+			if strings.HasPrefix(p.Name, "?") {
+				n++
+			}
+		}
+		n = min(n, len(args))
+		if err := fn(stmt.Text, args[:n]); err != nil {
+			return err
+		}
+		args = args[n:]
+	}
+	return nil
+}
+
+// CreateTempTable creates a temporary table, remembering the connection ID
+// of the pinned session so its statements can be killed on cancel
+func (c *MySQLConnector) CreateTempTable(ctx context.Context, name, query string) (int64, error) {
+	if c.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	if c.pinned == nil && c.tx == nil {
+		if err := c.pin(ctx); err != nil {
+			return 0, err
+		}
+		if err := c.pinned.QueryRowxContext(ctx, "SELECT CONNECTION_ID()").Scan(&c.pinnedConnID); err != nil {
+			c.unpin()
+			return 0, fmt.Errorf("failed to pin connection: %w", err)
+		}
+	}
+
+	var affected int64
+	err := c.killOnCancel(ctx, func(q queryExecer) error {
+		var err error
+		affected, err = c.BaseConnector.CreateTempTable(ctx, name, query)
+		return err
+	})
+	return affected, err
+}
+
+// Begin starts a transaction, remembering its connection ID so statements
+// in it can be killed on cancel
+func (c *MySQLConnector) Begin() error {
+	if err := c.BaseConnector.Begin(); err != nil {
+		return err
+	}
+	if err := c.tx.QueryRowx("SELECT CONNECTION_ID()").Scan(&c.txConnID); err != nil {
+		c.BaseConnector.Rollback()
+		return fmt.Errorf("begin error: %w", err)
+	}
 	return nil
 }
 
+// killOnCancel runs fn on a dedicated connection, or in the open
+// transaction. When ctx is cancelled the driver only drops its connection
+// and the statement keeps running on the server, so it is also stopped with
+// KILL QUERY from another connection.
+func (c *MySQLConnector) killOnCancel(ctx context.Context, fn func(q queryExecer) error) error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	if c.tx != nil {
+		stop := c.killQueryOnDone(ctx, c.db, c.txConnID)
+		err := fn(c.tx)
+		stop()
+		return err
+	}
+	if c.pinned != nil {
+		stop := c.killQueryOnDone(ctx, c.db, c.pinnedConnID)
+		err := fn(c.pinned)
+		stop()
+		return err
+	}
+	return c.killOnCancelIn(ctx, c.db, fn)
+}
+
+// killOnCancelRead is killOnCancel for a query, run on the read replica
+// when it may be served there
+func (c *MySQLConnector) killOnCancelRead(ctx context.Context, sql string, fn func(q queryExecer) error) error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	if replica := c.reader(sql); replica != nil {
+		return c.killOnCancelIn(ctx, replica, fn)
+	}
+	return c.killOnCancel(ctx, fn)
+}
+
+// killOnCancelIn runs fn on a dedicated connection of pool
+func (c *MySQLConnector) killOnCancelIn(ctx context.Context, pool *sqlx.DB, fn func(q queryExecer) error) error {
+	conn, err := pool.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	defer conn.Close()
+
+	var id int64
+	if err := conn.QueryRowxContext(ctx, "SELECT CONNECTION_ID()").Scan(&id); err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+
+	stop := c.killQueryOnDone(ctx, pool, id)
+	err = fn(conn)
+	if !stop() {
+		// The kill may land after fn returned; keep the connection out of
+		// the pool so it can't hit the next statement
+		conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	}
+	return err
+}
+
+// killQueryOnDone kills the statement running on connection id of pool once
+// ctx is done. The returned stop reports false if the kill was already
+// started.
+func (c *MySQLConnector) killQueryOnDone(ctx context.Context, pool *sqlx.DB, id int64) (stop func() bool) {
+	return context.AfterFunc(ctx, func() {
+		killCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		pool.ExecContext(killCtx, fmt.Sprintf("KILL QUERY %d", id))
+	})
+}
+
 // GetTables returns list of tables in the database
 func (c *MySQLConnector) GetTables() ([]string, error) {
 	if c.db == nil {
@@ -55,6 +385,16 @@ func (c *MySQLConnector) GetTables() ([]string, error) {
 		AND table_type = 'BASE TABLE'
 		ORDER BY table_name
 	`
+	if c.mariadb {
+		// MariaDB reports system-versioned tables and sequences separately
+		query = `
+			SELECT table_name
+			FROM information_schema.tables
+			WHERE table_schema = DATABASE()
+			AND table_type IN ('BASE TABLE', 'SYSTEM VERSIONED', 'SEQUENCE')
+			ORDER BY table_name
+		`
+	}
 
 	var tables []string
 	if err := c.db.Select(&tables, query); err != nil {
@@ -75,7 +415,8 @@ func (c *MySQLConnector) GetColumns(tableName string) ([]Column, error) {
 			COLUMN_NAME as name,
 			DATA_TYPE as type,
 			IS_NULLABLE = 'YES' as is_nullable,
-			COLUMN_KEY = 'PRI' as is_pk
+			COLUMN_KEY = 'PRI' as is_pk,
+			COALESCE(COLUMN_DEFAULT, '') as column_default
 		FROM information_schema.columns 
 		WHERE table_schema = DATABASE()
 		AND table_name = ?
@@ -92,7 +433,7 @@ func (c *MySQLConnector) GetColumns(tableName string) ([]Column, error) {
 	for rows.Next() {
 		var col Column
 		var isNullable, isPK bool
-		if err := rows.Scan(&col.Name, &col.Type, &isNullable, &isPK); err != nil {
+		if err := rows.Scan(&col.Name, &col.Type, &isNullable, &isPK, &col.Default); err != nil {
 			return nil, fmt.Errorf("failed to scan column: %w", err)
 		}
 		col.Nullable = isNullable
@@ -103,6 +444,191 @@ func (c *MySQLConnector) GetColumns(tableName string) ([]Column, error) {
 	return columns, nil
 }
 
+// GetIndexes returns the indexes of a table in the current database
+func (c *MySQLConnector) GetIndexes(tableName string) ([]Index, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT INDEX_NAME, NON_UNIQUE = 0, INDEX_TYPE, COLUMN_NAME, SUB_PART
+		FROM information_schema.statistics
+		WHERE table_schema = DATABASE()
+		AND table_name = ?
+		ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME, SEQ_IN_INDEX
+	`
+
+	rows, err := c.db.Queryx(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+	defer rows.Close()
+
+	// One row per key column
+	var indexes []Index
+	for rows.Next() {
+		var name, method string
+		var unique bool
+		var column sql.NullString // NULL for functional key parts
+		var subPart sql.NullInt64
+		if err := rows.Scan(&name, &unique, &method, &column, &subPart); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		key := "(expression)"
+		if column.Valid {
+			key = column.String
+		}
+		if subPart.Valid {
+			key += fmt.Sprintf("(%d)", subPart.Int64)
+		}
+
+		if n := len(indexes); n == 0 || indexes[n-1].Name != name {
+			indexes = append(indexes, Index{
+				Name:    name,
+				Unique:  unique,
+				Primary: name == "PRIMARY",
+				Method:  strings.ToLower(method),
+			})
+		}
+		last := &indexes[len(indexes)-1]
+		last.Columns = append(last.Columns, key)
+	}
+
+	return indexes, rows.Err()
+}
+
+// GetForeignKeys returns the foreign keys of a table in the current database
+func (c *MySQLConnector) GetForeignKeys(tableName string) ([]ForeignKey, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME,
+			r.DELETE_RULE, r.UPDATE_RULE
+		FROM information_schema.key_column_usage k
+		JOIN information_schema.referential_constraints r
+			ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA
+			AND r.TABLE_NAME = k.TABLE_NAME
+			AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+		WHERE k.table_schema = DATABASE()
+		AND k.table_name = ?
+		AND k.REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION
+	`
+
+	rows, err := c.db.Queryx(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	// One row per key column
+	var fks []ForeignKey
+	for rows.Next() {
+		var name, column, refTable, refColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		if n := len(fks); n == 0 || fks[n-1].Name != name {
+			fks = append(fks, ForeignKey{Name: name, RefTable: refTable, OnDelete: onDelete, OnUpdate: onUpdate})
+		}
+		last := &fks[len(fks)-1]
+		last.Columns = append(last.Columns, column)
+		last.RefColumns = append(last.RefColumns, refColumn)
+	}
+
+	return fks, rows.Err()
+}
+
+// GetViews returns the views of the current database
+func (c *MySQLConnector) GetViews() ([]View, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var names []string
+	query := `
+		SELECT table_name
+		FROM information_schema.views
+		WHERE table_schema = DATABASE()
+		ORDER BY table_name
+	`
+	if err := c.db.Select(&names, query); err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+
+	views := make([]View, len(names))
+	for i, name := range names {
+		views[i] = View{Name: name}
+	}
+	return views, nil
+}
+
+// GetViewDefinition returns the CREATE VIEW statement of a view
+func (c *MySQLConnector) GetViewDefinition(name string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	// information_schema hides the definition without SHOW VIEW privilege;
+	// SHOW CREATE VIEW reports why instead
+	row := c.db.QueryRowx("SHOW CREATE VIEW " + c.quote(name))
+	var view, definition, charset, collation string
+	if err := row.Scan(&view, &definition, &charset, &collation); err != nil {
+		return "", fmt.Errorf("failed to get view definition: %w", err)
+	}
+	return definition, nil
+}
+
+// GetTriggers returns the triggers of a table, in firing order
+func (c *MySQLConnector) GetTriggers(tableName string) ([]Trigger, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT trigger_name, action_timing, event_manipulation, action_orientation, action_statement
+		FROM information_schema.triggers
+		WHERE event_object_schema = DATABASE() AND event_object_table = ?
+		ORDER BY action_timing, event_manipulation, action_order
+	`
+
+	rows, err := c.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []Trigger
+	for rows.Next() {
+		var t Trigger
+		var event string
+		if err := rows.Scan(&t.Name, &t.Timing, &event, &t.ForEach, &t.Body); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger: %w", err)
+		}
+		// MySQL triggers fire on a single event and can't be disabled
+		t.Events = []string{event}
+		t.Enabled = true
+		triggers = append(triggers, t)
+	}
+	return triggers, rows.Err()
+}
+
+// GetTableDDL returns the table's CREATE TABLE statement, which includes
+// its indexes
+func (c *MySQLConnector) GetTableDDL(tableName string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	var name, ddl string
+	if err := c.db.QueryRowx("SHOW CREATE TABLE "+c.quote(tableName)).Scan(&name, &ddl); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	return ddl + ";", nil
+}
+
 // GetSchema returns the complete database schema
 func (c *MySQLConnector) GetSchema() (*Schema, error) {
 	tables, err := c.GetTables()
@@ -119,9 +645,15 @@ func (c *MySQLConnector) GetSchema() (*Schema, error) {
 		if err != nil {
 			continue // Skip tables we can't read
 		}
+		// Indexes and foreign keys are best effort; the table is still
+		// usable without them
+		indexes, _ := c.GetIndexes(tableName)
+		foreignKeys, _ := c.GetForeignKeys(tableName)
 		schema.Tables[tableName] = Table{
-			Name:    tableName,
-			Columns: columns,
+			Name:        tableName,
+			Columns:     columns,
+			Indexes:     indexes,
+			ForeignKeys: foreignKeys,
 		}
 	}
 
@@ -144,18 +676,206 @@ func (c *MySQLConnector) GetDatabases() ([]string, error) {
 	return databases, nil
 }
 
-// SwitchDatabase switches to a different database
+// SwitchDatabase switches to a different database by reconnecting with it
+// in the DSN; USE would only move the one pooled connection it ran on
 func (c *MySQLConnector) SwitchDatabase(dbName string) error {
 	if c.db == nil {
 		return fmt.Errorf("not connected")
 	}
+	if c.tx != nil {
+		return errTxOpen
+	}
+
+	// Close current connection; temporary tables don't survive it
+	c.unpin()
+	c.closeReplica()
+	c.db.Close()
+
+	// Update config and reconnect
+	c.config.Database = dbName
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.ConnectTimeoutDuration())
+	defer cancel()
+	return c.Connect(ctx)
+}
+
+// GetPartitions returns partitioned tables with their partitions and bounds
+func (c *MySQLConnector) GetPartitions() (map[string]*PartitionInfo, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			TABLE_NAME,
+			PARTITION_NAME,
+			COALESCE(PARTITION_METHOD, ''),
+			COALESCE(PARTITION_EXPRESSION, ''),
+			COALESCE(PARTITION_DESCRIPTION, '')
+		FROM information_schema.partitions
+		WHERE table_schema = DATABASE()
+		AND PARTITION_NAME IS NOT NULL
+		ORDER BY TABLE_NAME, PARTITION_ORDINAL_POSITION
+	`
+
+	rows, err := c.db.Queryx(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+	defer rows.Close()
+
+	partitions := make(map[string]*PartitionInfo)
+	for rows.Next() {
+		var table, name, method, expr, desc string
+		if err := rows.Scan(&table, &name, &method, &expr, &desc); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+
+		info, ok := partitions[table]
+		if !ok {
+			info = &PartitionInfo{
+				Table:    table,
+				Strategy: method,
+				Key:      strings.Trim(expr, "`"),
+			}
+			partitions[table] = info
+		}
+
+		bounds := desc
+		switch method {
+		case "RANGE", "RANGE COLUMNS":
+			bounds = "VALUES LESS THAN (" + desc + ")"
+		case "LIST", "LIST COLUMNS":
+			bounds = "VALUES IN (" + desc + ")"
+		}
+
+		// MySQL partitions are not tables, so they are read with PARTITION (...)
+		info.Partitions = append(info.Partitions, Partition{
+			Name:   name,
+			Parent: table,
+			Bounds: bounds,
+			Target: fmt.Sprintf("%s PARTITION (%s)", c.quote(table), c.quote(name)),
+		})
+	}
+
+	return partitions, nil
+}
+
+// MaintenanceActions lists MySQL maintenance operations
+func (c *MySQLConnector) MaintenanceActions() []MaintenanceAction {
+	return []MaintenanceAction{
+		{Name: "ANALYZE TABLE", Description: "Refresh key distribution statistics", Statement: "ANALYZE TABLE %s"},
+		{Name: "OPTIMIZE TABLE", Description: "Rebuild table and reclaim space", Statement: "OPTIMIZE TABLE %s"},
+		{Name: "CHECK TABLE", Description: "Check table for errors", Statement: "CHECK TABLE %s"},
+	}
+}
+
+// RunMaintenance runs a maintenance action on a table
+func (c *MySQLConnector) RunMaintenance(action MaintenanceAction, table string) ([]string, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	// These statements return a Table/Op/Msg_type/Msg_text result set
+	return c.runMaintenanceQuery(action.SQL(c.quote(table)))
+}
+
+// ReplicationStatus reports replica lag from SHOW REPLICA STATUS, falling back
+// to SHOW SLAVE STATUS on servers older than MySQL 8.0.22
+func (c *MySQLConnector) ReplicationStatus() ([]ReplicaStatus, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
 
-	// Use USE statement for MySQL
-	_, err := c.db.Exec("USE " + dbName)
+	rows, _, err := c.Query(context.Background(), "SHOW REPLICA STATUS")
 	if err != nil {
-		return fmt.Errorf("failed to switch database: %w", err)
+		rows, _, err = c.Query(context.Background(), "SHOW SLAVE STATUS")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replication status: %w", err)
+		}
 	}
 
-	c.config.Database = dbName
-	return nil
+	// pick returns the first present column among new and legacy names
+	pick := func(row Row, names ...string) string {
+		for _, name := range names {
+			if v, ok := row[name]; ok && !v.Null {
+				return v.String()
+			}
+		}
+		return ""
+	}
+
+	var statuses []ReplicaStatus
+	for _, row := range rows {
+		name := pick(row, "Source_Host", "Master_Host")
+		if channel := pick(row, "Channel_Name"); channel != "" {
+			name = channel + "@" + name
+		}
+
+		state := "running"
+		io := pick(row, "Replica_IO_Running", "Slave_IO_Running")
+		sql := pick(row, "Replica_SQL_Running", "Slave_SQL_Running")
+		if io != "Yes" || sql != "Yes" {
+			state = fmt.Sprintf("io:%s sql:%s", io, sql)
+		}
+
+		status := ReplicaStatus{Name: name, Role: "replica", State: state}
+		// Seconds_Behind_Source is NULL while replication is stopped
+		if lag := pick(row, "Seconds_Behind_Source", "Seconds_Behind_Master"); lag != "" {
+			var seconds float64
+			if _, err := fmt.Sscanf(lag, "%g", &seconds); err == nil {
+				status.Lag = secondsToDuration(seconds)
+				status.LagKnown = true
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetServerSettings returns server variables from SHOW VARIABLES
+func (c *MySQLConnector) GetServerSettings() ([]ServerSetting, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := c.db.Queryx("SHOW VARIABLES")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []ServerSetting
+	for rows.Next() {
+		var s ServerSetting
+		if err := rows.Scan(&s.Name, &s.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		settings = append(settings, s)
+	}
+
+	return settings, nil
+}
+
+// Capabilities returns server details and supported features
+func (c *MySQLConnector) Capabilities() Capabilities {
+	caps := Capabilities{
+		SupportsSwitchDatabase: true,
+		SupportsCancel:         true,
+		SupportsTransactions:   true,
+		SupportsExplain:        true,
+	}
+	if c.db == nil {
+		return caps
+	}
+
+	// @@time_zone is "SYSTEM" unless set; fall back to the host zone
+	query := `
+		SELECT
+			VERSION(),
+			@@character_set_server,
+			IF(@@time_zone = 'SYSTEM', @@system_time_zone, @@time_zone),
+			CURRENT_USER()
+	`
+	c.db.QueryRowx(query).Scan(&caps.ServerVersion, &caps.Encoding, &caps.Timezone, &caps.CurrentUser)
+	return caps
 }
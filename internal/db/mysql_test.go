@@ -0,0 +1,71 @@
+package db
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMySQLEachStatement(t *testing.T) {
+	type call struct {
+		sql  string
+		args []interface{}
+	}
+	tests := []struct {
+		sql   string
+		args  []interface{}
+		calls []call
+	}{
+		{"SELECT 1", nil, []call{{"SELECT 1", nil}}},
+		{"SELECT * FROM t WHERE id = ?;", []interface{}{1}, []call{{"SELECT * FROM t WHERE id = ?;", []interface{}{1}}}},
+		{"SELECT 1; SELECT 2", nil, []call{{"SELECT 1", nil}, {"SELECT 2", nil}}},
+		{"SELECT 'a;b'; SELECT `c;d` FROM t", nil, []call{{"SELECT 'a;b'", nil}, {"SELECT `c;d` FROM t", nil}}},
+		{
+			"INSERT INTO t VALUES (?, ?); SELECT * FROM t WHERE id = ?",
+			[]interface{}{1, 2, 3},
+			[]call{{"INSERT INTO t VALUES (?, ?)", []interface{}{1, 2}}, {"SELECT * FROM t WHERE id = ?", []interface{}{3}}},
+		},
+		{
+			"SELECT '?', ?; -- ?\nSELECT ?",
+			[]interface{}{1, 2},
+			[]call{{"SELECT '?', ?", []interface{}{1}}, {"SELECT ?", []interface{}{2}}},
+		},
+		{
+			"SET @n = 1; SELECT @n",
+			nil,
+			[]call{{"SET @n = 1", nil}, {"SELECT @n", nil}},
+		},
+	}
+
+	c := &MySQLConnector{BaseConnector: BaseConnector{driver: "mysql"}}
+	for _, tt := range tests {
+		var calls []call
+		err := c.eachStatement(tt.sql, tt.args, func(stmt string, args []interface{}) error {
+			calls = append(calls, call{stmt, args})
+			return nil
+		})
+		if err != nil {
+			t.Errorf("eachStatement(%q) error: %v", tt.sql, err)
+			continue
+		}
+		if !reflect.DeepEqual(calls, tt.calls) {
+			t.Errorf("eachStatement(%q) ran %#v, want %#v", tt.sql, calls, tt.calls)
+		}
+	}
+}
+
+func TestMySQLEachStatementStopsAtError(t *testing.T) {
+	c := &MySQLConnector{BaseConnector: BaseConnector{driver: "mysql"}}
+	boom := errors.New("boom")
+	var ran []string
+	err := c.eachStatement("DELETE FROM a; DELETE FROM b", nil, func(stmt string, _ []interface{}) error {
+		ran = append(ran, stmt)
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("error = %v, want %v", err, boom)
+	}
+	if len(ran) != 1 {
+		t.Errorf("ran %q, want only the first statement", ran)
+	}
+}
@@ -0,0 +1,19 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PageQuery appends LIMIT/OFFSET to query, a pageable SELECT, to return
+// limit of its rows from offset on. The query keeps its own columns and
+// ORDER BY, which wrapping it in a subquery would not guarantee. It
+// reports false for drivers without LIMIT/OFFSET.
+func PageQuery(driver, query string, limit, offset int) (string, bool) {
+	if driver == "cassandra" {
+		return "", false
+	}
+	query = strings.TrimRight(query, " \t\r\n;")
+	// The newline ends a trailing line comment of the query
+	return fmt.Sprintf("%s\nLIMIT %d OFFSET %d", query, limit, offset), true
+}
@@ -0,0 +1,28 @@
+package db
+
+import "testing"
+
+func TestPageQuery(t *testing.T) {
+	tests := []struct {
+		driver string
+		query  string
+		want   string
+		ok     bool
+	}{
+		{"postgres", "SELECT * FROM users", "SELECT * FROM users\nLIMIT 100 OFFSET 0", true},
+		{"mysql", "SELECT * FROM users ORDER BY id;  \n", "SELECT * FROM users ORDER BY id\nLIMIT 100 OFFSET 0", true},
+		{"sqlite3", "SELECT * FROM users -- all of them", "SELECT * FROM users -- all of them\nLIMIT 100 OFFSET 0", true},
+		{"postgres", "WITH t AS (SELECT 1 AS n) SELECT n FROM t;;", "WITH t AS (SELECT 1 AS n) SELECT n FROM t\nLIMIT 100 OFFSET 0", true},
+		{"cassandra", "SELECT * FROM users", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := PageQuery(tt.driver, tt.query, 100, 0)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("PageQuery(%q, %q) = %q, %v, want %q, %v", tt.driver, tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got, _ := PageQuery("postgres", "SELECT 1", 50, 150); got != "SELECT 1\nLIMIT 50 OFFSET 150" {
+		t.Errorf("PageQuery offset = %q", got)
+	}
+}
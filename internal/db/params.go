@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// ParamQuerier is implemented by connectors that bind query parameters
+// through the driver instead of interpolating them into the SQL. The SQL
+// uses the driver's placeholders ($1 for Postgres, ? elsewhere), see
+// sqlparse.Bind.
+type ParamQuerier interface {
+	QueryParams(ctx context.Context, sql string, args []interface{}) ([]ResultSet, error)
+	ExecuteParams(ctx context.Context, sql string, args []interface{}) (int64, error)
+}
+
+// GetParamQuerier returns the connector's parameter binding, if any
+func GetParamQuerier(c Connector) (ParamQuerier, bool) {
+	p, ok := c.(ParamQuerier)
+	return p, ok
+}
+
+// QueryParams runs a query with bound parameters, returning every result set
+func (c *BaseConnector) QueryParams(ctx context.Context, sql string, args []interface{}) ([]ResultSet, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	return queryResultSets(ctx, c.readSession(sql), sql, c.limit, args...)
+}
+
+// ExecuteParams runs a statement with bound parameters
+func (c *BaseConnector) ExecuteParams(ctx context.Context, sql string, args []interface{}) (int64, error) {
+	if c.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	c.setEndpoint(EndpointPrimary)
+	return execute(ctx, c.session(), sql, args...)
+}
@@ -0,0 +1,46 @@
+package db
+
+// Partition represents a single partition of a partitioned table
+type Partition struct {
+	Name   string
+	Parent string
+	Bounds string
+	// Target is the FROM-clause expression that reads only this partition,
+	// with identifiers already quoted
+	Target string
+}
+
+// PartitionInfo describes a partitioned table and its partitions
+type PartitionInfo struct {
+	Table      string
+	Strategy   string // RANGE, LIST, HASH, KEY...
+	Key        string
+	Partitions []Partition
+}
+
+// PartitionLister is implemented by connectors that understand table partitioning
+type PartitionLister interface {
+	// GetPartitions returns partitioning info keyed by parent table name
+	GetPartitions() (map[string]*PartitionInfo, error)
+}
+
+// GetPartitions returns partition info for connectors that support it,
+// or an empty map for those that don't
+func GetPartitions(c Connector) (map[string]*PartitionInfo, error) {
+	if pl, ok := c.(PartitionLister); ok {
+		return pl.GetPartitions()
+	}
+	return map[string]*PartitionInfo{}, nil
+}
+
+// IsPartition reports whether tableName is a partition of one of the given tables
+func IsPartition(partitions map[string]*PartitionInfo, tableName string) bool {
+	for _, info := range partitions {
+		for _, p := range info.Partitions {
+			if p.Name == tableName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,15 +1,25 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/febritecno/sqdesk-cli/internal/config"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // PostgresConnector implements Connector for PostgreSQL
 type PostgresConnector struct {
 	BaseConnector
+	// cockroach is set for CockroachDB, which speaks the Postgres wire
+	// protocol but lacks parts of pg_catalog
+	cockroach bool
 }
 
 // NewPostgresConnector creates a new PostgreSQL connector
@@ -23,29 +33,128 @@ func NewPostgresConnector(cfg *config.DatabaseConfig) *PostgresConnector {
 }
 
 // Connect establishes connection to PostgreSQL database
-func (c *PostgresConnector) Connect() error {
+func (c *PostgresConnector) Connect(ctx context.Context) error {
 	sslmode := c.config.SSLMode
 	if sslmode == "" {
 		sslmode = "disable"
 	}
 
+	host, port, err := c.dialTarget(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open SSH tunnel: %w", err)
+	}
+	if c.config.UsesSocket() {
+		host, port = postgresSocket(c.config.Socket, c.config.Port)
+	}
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", c.dsn(host, port, sslmode))
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	if c.config.ReplicaHost != "" {
+		host, port, err := c.replicaTarget(ctx)
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("failed to open SSH tunnel to read replica: %w", err)
+		}
+		replica, err := sqlx.ConnectContext(ctx, "postgres", c.dsn(host, port, sslmode))
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		c.replica = replica
+	}
+
+	c.db = db
+	c.detectCockroach()
+	return nil
+}
+
+// dsn builds the connection string for the server at host and port
+func (c *PostgresConnector) dsn(host string, port int, sslmode string) string {
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		c.config.Host,
-		c.config.Port,
+		"host='%s' port=%d user=%s password=%s dbname=%s sslmode=%s",
+		host,
+		port,
 		c.config.User,
 		c.config.Password,
 		c.config.Database,
 		sslmode,
 	)
+	// Certificate paths are quoted since they may contain spaces
+	for key, path := range map[string]string{
+		"sslrootcert": c.config.SSLRootCert,
+		"sslcert":     c.config.SSLCert,
+		"sslkey":      c.config.SSLKey,
+	} {
+		if path != "" {
+			dsn += fmt.Sprintf(" %s='%s'", key, config.ExpandPath(path))
+		}
+	}
+	if c.config.ReadOnly {
+		// Sent as a startup parameter; CockroachDB accepts it too
+		dsn += " default_transaction_read_only=on"
+	}
+	return dsn
+}
 
-	db, err := sqlx.Connect("postgres", dsn)
-	if err != nil {
-		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+// postgresSocket returns the socket directory and port lib/pq expects.
+// path may be the directory or the socket file itself (.s.PGSQL.5432).
+func postgresSocket(path string, port int) (string, int) {
+	path = config.ExpandPath(path)
+	dir, file := filepath.Split(path)
+	if suffix, ok := strings.CutPrefix(file, ".s.PGSQL."); ok {
+		if p, err := strconv.Atoi(suffix); err == nil {
+			port = p
+		}
+		path = filepath.Clean(dir)
 	}
+	if port == 0 {
+		port = 5432
+	}
+	return path, port
+}
 
-	c.db = db
-	return nil
+// detectCockroach switches to CockroachDB mode if the server identifies as
+// CockroachDB, even when the connection was configured as plain postgres
+func (c *PostgresConnector) detectCockroach() {
+	if c.config.Driver == "cockroachdb" || c.config.Driver == "cockroach" {
+		c.cockroach = true
+	} else {
+		var version string
+		if err := c.db.Get(&version, "SELECT version()"); err == nil {
+			c.cockroach = strings.Contains(version, "CockroachDB")
+		}
+	}
+
+	if c.cockroach {
+		c.driver = "cockroachdb"
+	} else {
+		c.driver = "postgres"
+	}
+}
+
+// IsCockroach reports whether the connected server is CockroachDB
+func (c *PostgresConnector) IsCockroach() bool {
+	return c.cockroach
+}
+
+// Query executes a SELECT query, adapting EXPLAIN options for CockroachDB
+func (c *PostgresConnector) Query(ctx context.Context, sql string) ([]Row, []string, error) {
+	if c.cockroach {
+		sql = cockroachExplain(sql)
+	}
+	return c.BaseConnector.Query(ctx, sql)
+}
+
+// QueryMulti executes a query returning all result sets, adapting EXPLAIN
+// options for CockroachDB
+func (c *PostgresConnector) QueryMulti(ctx context.Context, sql string) ([]ResultSet, error) {
+	if c.cockroach {
+		sql = cockroachExplain(sql)
+	}
+	return c.BaseConnector.QueryMulti(ctx, sql)
 }
 
 // GetTables returns list of tables in the database
@@ -58,7 +167,7 @@ func (c *PostgresConnector) GetTables() ([]string, error) {
 		SELECT table_name 
 		FROM information_schema.tables 
 		WHERE table_schema = 'public' 
-		AND table_type = 'BASE TABLE'
+		AND table_type IN ('BASE TABLE', 'FOREIGN')
 		ORDER BY table_name
 	`
 
@@ -81,21 +190,32 @@ func (c *PostgresConnector) GetColumns(tableName string) ([]Column, error) {
 			c.column_name,
 			c.data_type,
 			c.is_nullable = 'YES' as is_nullable,
-			COALESCE(pk.is_pk, false) as is_pk
+			COALESCE(pk.is_pk, false) as is_pk,
+			COALESCE(c.column_default, '') as column_default
 		FROM information_schema.columns c
 		LEFT JOIN (
 			SELECT kcu.column_name, true as is_pk
 			FROM information_schema.table_constraints tc
 			JOIN information_schema.key_column_usage kcu 
 				ON tc.constraint_name = kcu.constraint_name
+				AND tc.table_name = kcu.table_name
 			WHERE tc.table_name = $1 
 			AND tc.constraint_type = 'PRIMARY KEY'
 		) pk ON c.column_name = pk.column_name
 		WHERE c.table_schema = 'public' 
 		AND c.table_name = $1
+		%s
 		ORDER BY c.ordinal_position
 	`
 
+	// Cockroach names every primary key "primary", so constraint names must
+	// be matched per table, and the implicit rowid column is hidden
+	extra := ""
+	if c.cockroach {
+		extra = "AND c.is_hidden = 'NO'"
+	}
+	query = fmt.Sprintf(query, extra)
+
 	rows, err := c.db.Queryx(query, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
@@ -106,7 +226,7 @@ func (c *PostgresConnector) GetColumns(tableName string) ([]Column, error) {
 	for rows.Next() {
 		var col Column
 		var isNullable, isPK bool
-		if err := rows.Scan(&col.Name, &col.Type, &isNullable, &isPK); err != nil {
+		if err := rows.Scan(&col.Name, &col.Type, &isNullable, &isPK, &col.Default); err != nil {
 			return nil, fmt.Errorf("failed to scan column: %w", err)
 		}
 		col.Nullable = isNullable
@@ -117,6 +237,395 @@ func (c *PostgresConnector) GetColumns(tableName string) ([]Column, error) {
 	return columns, nil
 }
 
+// GetIndexes returns the indexes of a table in the public schema
+func (c *PostgresConnector) GetIndexes(tableName string) ([]Index, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// pg_indexes exists in Postgres and CockroachDB; the definition holds the
+	// method and key columns
+	query := `
+		SELECT
+			i.indexname,
+			i.indexdef,
+			EXISTS (
+				SELECT 1 FROM information_schema.table_constraints tc
+				WHERE tc.table_schema = i.schemaname
+				AND tc.table_name = i.tablename
+				AND tc.constraint_name = i.indexname
+				AND tc.constraint_type = 'PRIMARY KEY'
+			) AS is_primary
+		FROM pg_indexes i
+		WHERE i.schemaname = 'public'
+		AND i.tablename = $1
+		ORDER BY is_primary DESC, i.indexname
+	`
+
+	rows, err := c.db.Queryx(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []Index
+	for rows.Next() {
+		var idx Index
+		var def string
+		if err := rows.Scan(&idx.Name, &def, &idx.Primary); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		idx.Unique, idx.Method, idx.Columns = parseIndexDef(def)
+		indexes = append(indexes, idx)
+	}
+
+	return indexes, rows.Err()
+}
+
+// parseIndexDef reads an index definition as printed by pg_get_indexdef:
+// CREATE [UNIQUE] INDEX name ON table USING method (key, ...) [...]
+func parseIndexDef(def string) (unique bool, method string, columns []string) {
+	unique = strings.HasPrefix(strings.ToUpper(def), "CREATE UNIQUE")
+
+	i := strings.Index(def, " USING ")
+	if i < 0 {
+		return unique, "", nil
+	}
+	rest := def[i+len(" USING "):]
+	open := strings.IndexByte(rest, '(')
+	if open < 0 {
+		return unique, strings.TrimSpace(rest), nil
+	}
+	method = strings.TrimSpace(rest[:open])
+
+	// Split the key list on top-level commas; expressions nest parentheses
+	depth, start := 0, open+1
+	for j := open; j < len(rest); j++ {
+		switch rest[j] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				columns = append(columns, indexKey(rest[start:j]))
+				return unique, method, columns
+			}
+		case ',':
+			if depth == 1 {
+				columns = append(columns, indexKey(rest[start:j]))
+				start = j + 1
+			}
+		}
+	}
+	return unique, method, columns
+}
+
+// indexKey trims a key of an index definition, dropping the default order
+func indexKey(key string) string {
+	return strings.TrimSuffix(strings.TrimSpace(key), " ASC")
+}
+
+// GetForeignKeys returns the foreign keys of a table in the public schema
+func (c *PostgresConnector) GetForeignKeys(tableName string) ([]ForeignKey, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// One row per key column; conkey and confkey are parallel arrays
+	query := `
+		SELECT
+			con.conname,
+			a.attname,
+			rt.relname,
+			ra.attname,
+			con.confdeltype,
+			con.confupdtype
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class rt ON rt.oid = con.confrelid
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+		WHERE con.contype = 'f'
+		AND n.nspname = 'public'
+		AND t.relname = $1
+		ORDER BY con.conname, k.ord
+	`
+
+	rows, err := c.db.Queryx(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var fks []ForeignKey
+	for rows.Next() {
+		var name, column, refTable, refColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		if n := len(fks); n == 0 || fks[n-1].Name != name {
+			fks = append(fks, ForeignKey{
+				Name:     name,
+				RefTable: refTable,
+				OnDelete: pgRefAction(onDelete),
+				OnUpdate: pgRefAction(onUpdate),
+			})
+		}
+		last := &fks[len(fks)-1]
+		last.Columns = append(last.Columns, column)
+		last.RefColumns = append(last.RefColumns, refColumn)
+	}
+
+	return fks, rows.Err()
+}
+
+// pgRefAction names a referential action code of pg_constraint
+func pgRefAction(code string) string {
+	switch code {
+	case "a":
+		return "NO ACTION"
+	case "r":
+		return "RESTRICT"
+	case "c":
+		return "CASCADE"
+	case "n":
+		return "SET NULL"
+	case "d":
+		return "SET DEFAULT"
+	}
+	return ""
+}
+
+// GetViews returns the views and materialized views of the public schema
+func (c *PostgresConnector) GetViews() ([]View, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT viewname AS name, false AS materialized FROM pg_views WHERE schemaname = 'public'
+		UNION ALL
+		SELECT matviewname, true FROM pg_matviews WHERE schemaname = 'public'
+		ORDER BY name
+	`
+	if c.cockroach {
+		query = `
+			SELECT table_name AS name, false AS materialized
+			FROM information_schema.views
+			WHERE table_schema = 'public'
+			ORDER BY name
+		`
+	}
+
+	var views []View
+	if err := c.db.Select(&views, query); err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+	return views, nil
+}
+
+// GetViewDefinition returns the SELECT a view or materialized view runs
+func (c *PostgresConnector) GetViewDefinition(name string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT definition FROM pg_views WHERE schemaname = 'public' AND viewname = $1
+		UNION ALL
+		SELECT definition FROM pg_matviews WHERE schemaname = 'public' AND matviewname = $1
+	`
+	if c.cockroach {
+		query = `SELECT view_definition FROM information_schema.views WHERE table_schema = 'public' AND table_name = $1`
+	}
+
+	var definitions []string
+	if err := c.db.Select(&definitions, query, name); err != nil {
+		return "", fmt.Errorf("failed to get view definition: %w", err)
+	}
+	if len(definitions) == 0 {
+		return "", fmt.Errorf("view %s not found", name)
+	}
+	return strings.TrimSpace(definitions[0]), nil
+}
+
+// GetTriggers returns the user-defined triggers of a table
+func (c *PostgresConnector) GetTriggers(tableName string) ([]Trigger, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if c.cockroach {
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			t.tgname,
+			t.tgtype,
+			t.tgenabled <> 'D',
+			pg_get_triggerdef(t.oid),
+			p.prosrc
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_proc p ON p.oid = t.tgfoid
+		WHERE n.nspname = 'public' AND c.relname = $1 AND NOT t.tgisinternal
+		ORDER BY t.tgname
+	`
+
+	rows, err := c.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []Trigger
+	for rows.Next() {
+		var t Trigger
+		var tgtype int
+		if err := rows.Scan(&t.Name, &tgtype, &t.Enabled, &t.Definition, &t.Body); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger: %w", err)
+		}
+		t.Timing, t.Events, t.ForEach = pgTriggerType(tgtype)
+		t.Body = strings.TrimSpace(t.Body)
+		triggers = append(triggers, t)
+	}
+	return triggers, rows.Err()
+}
+
+// pgTriggerType decodes the tgtype bits of pg_trigger
+func pgTriggerType(tgtype int) (timing string, events []string, forEach string) {
+	forEach = "STATEMENT"
+	if tgtype&1 != 0 {
+		forEach = "ROW"
+	}
+	switch {
+	case tgtype&(1<<6) != 0:
+		timing = "INSTEAD OF"
+	case tgtype&(1<<1) != 0:
+		timing = "BEFORE"
+	default:
+		timing = "AFTER"
+	}
+	for _, e := range []struct {
+		bit  int
+		name string
+	}{{2, "INSERT"}, {4, "UPDATE"}, {3, "DELETE"}, {5, "TRUNCATE"}} {
+		if tgtype&(1<<e.bit) != 0 {
+			events = append(events, e.name)
+		}
+	}
+	return timing, events, forEach
+}
+
+// GetTableDDL reconstructs the CREATE TABLE statement of a table from
+// pg_catalog, followed by the indexes that back no constraint.
+// CockroachDB reports it with SHOW CREATE TABLE.
+func (c *PostgresConnector) GetTableDDL(tableName string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+	if c.cockroach {
+		var name, ddl string
+		if err := c.db.QueryRowx("SHOW CREATE TABLE "+c.quote(tableName)).Scan(&name, &ddl); err != nil {
+			return "", fmt.Errorf("failed to get table DDL: %w", err)
+		}
+		return ddl + ";", nil
+	}
+
+	const rel = `(quote_ident('public') || '.' || quote_ident($1))::regclass`
+
+	var columns []struct {
+		Name      string         `db:"name"`
+		Type      string         `db:"type"`
+		NotNull   bool           `db:"not_null"`
+		Default   sql.NullString `db:"default_expr"`
+		Identity  string         `db:"identity"`
+		Generated string         `db:"generated"`
+	}
+	query := `
+		SELECT
+			quote_ident(a.attname) AS name,
+			format_type(a.atttypid, a.atttypmod) AS type,
+			a.attnotnull AS not_null,
+			pg_get_expr(d.adbin, d.adrelid) AS default_expr,
+			a.attidentity::text AS identity,
+			a.attgenerated::text AS generated
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = ` + rel + ` AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`
+	if err := c.db.Select(&columns, query, tableName); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("table %s not found", tableName)
+	}
+
+	var lines []string
+	for _, col := range columns {
+		line := col.Name + " " + col.Type
+		switch {
+		case col.Generated == "s":
+			line += " GENERATED ALWAYS AS (" + col.Default.String + ") STORED"
+		case col.Identity == "a":
+			line += " GENERATED ALWAYS AS IDENTITY"
+		case col.Identity == "d":
+			line += " GENERATED BY DEFAULT AS IDENTITY"
+		case col.Default.Valid:
+			line += " DEFAULT " + col.Default.String
+		}
+		if col.NotNull {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+
+	var constraints []struct {
+		Name       string `db:"name"`
+		Definition string `db:"definition"`
+	}
+	query = `
+		SELECT quote_ident(conname) AS name, pg_get_constraintdef(oid) AS definition
+		FROM pg_constraint
+		WHERE conrelid = ` + rel + `
+		ORDER BY contype <> 'p', contype, conname
+	`
+	if err := c.db.Select(&constraints, query, tableName); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	for _, con := range constraints {
+		lines = append(lines, "CONSTRAINT "+con.Name+" "+con.Definition)
+	}
+
+	ddl := "CREATE TABLE public." + c.quote(tableName) + " (\n    " + strings.Join(lines, ",\n    ") + "\n)"
+	var partitionKey sql.NullString
+	if err := c.db.Get(&partitionKey, `SELECT pg_get_partkeydef(`+rel+`)`, tableName); err == nil && partitionKey.Valid {
+		ddl += " PARTITION BY " + partitionKey.String
+	}
+	ddl += ";"
+
+	// Indexes backing constraints were created by them
+	var indexes []string
+	query = `
+		SELECT pg_get_indexdef(i.indexrelid)
+		FROM pg_index i
+		WHERE i.indrelid = ` + rel + `
+		AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = i.indexrelid)
+		ORDER BY i.indexrelid::regclass::text
+	`
+	if err := c.db.Select(&indexes, query, tableName); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	for _, idx := range indexes {
+		ddl += "\n" + idx + ";"
+	}
+	return ddl, nil
+}
+
 // GetSchema returns the complete database schema
 func (c *PostgresConnector) GetSchema() (*Schema, error) {
 	tables, err := c.GetTables()
@@ -133,15 +642,86 @@ func (c *PostgresConnector) GetSchema() (*Schema, error) {
 		if err != nil {
 			continue // Skip tables we can't read
 		}
+		// Indexes and foreign keys are best effort; the table is still
+		// usable without them
+		indexes, _ := c.GetIndexes(tableName)
+		foreignKeys, _ := c.GetForeignKeys(tableName)
 		schema.Tables[tableName] = Table{
-			Name:    tableName,
-			Columns: columns,
+			Name:        tableName,
+			Columns:     columns,
+			Indexes:     indexes,
+			ForeignKeys: foreignKeys,
 		}
 	}
 
 	return schema, nil
 }
 
+// GetForeignServers lists the foreign servers and their foreign tables
+func (c *PostgresConnector) GetForeignServers() ([]ForeignServer, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if c.cockroach {
+		// CockroachDB has no foreign data wrappers
+		return nil, nil
+	}
+
+	// Options only; user mappings hold credentials and are left out
+	query := `
+		SELECT s.srvname, w.fdwname, COALESCE(array_to_string(s.srvoptions, ', '), '')
+		FROM pg_foreign_server s
+		JOIN pg_foreign_data_wrapper w ON w.oid = s.srvfdw
+		ORDER BY s.srvname
+	`
+	rows, err := c.db.Queryx(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign servers: %w", err)
+	}
+	defer rows.Close()
+
+	var servers []ForeignServer
+	index := make(map[string]int)
+	for rows.Next() {
+		var s ForeignServer
+		if err := rows.Scan(&s.Name, &s.Wrapper, &s.Options); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign server: %w", err)
+		}
+		index[s.Name] = len(servers)
+		servers = append(servers, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	query = `
+		SELECT s.srvname, n.nspname, t.relname, COALESCE(array_to_string(ft.ftoptions, ', '), '')
+		FROM pg_foreign_table ft
+		JOIN pg_class t ON t.oid = ft.ftrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_foreign_server s ON s.oid = ft.ftserver
+		ORDER BY s.srvname, n.nspname, t.relname
+	`
+	tableRows, err := c.db.Queryx(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign tables: %w", err)
+	}
+	defer tableRows.Close()
+
+	for tableRows.Next() {
+		var server string
+		var t ForeignTable
+		if err := tableRows.Scan(&server, &t.Schema, &t.Name, &t.Options); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign table: %w", err)
+		}
+		if i, ok := index[server]; ok {
+			servers[i].Tables = append(servers[i].Tables, t)
+		}
+	}
+	return servers, tableRows.Err()
+}
+
 // GetDatabases returns list of all databases on the server
 func (c *PostgresConnector) GetDatabases() ([]string, error) {
 	if c.db == nil {
@@ -154,6 +734,15 @@ func (c *PostgresConnector) GetDatabases() ([]string, error) {
 		WHERE datistemplate = false 
 		ORDER BY datname
 	`
+	if c.cockroach {
+		// pg_database lists Cockroach's internal system database
+		query = `
+			SELECT database_name
+			FROM [SHOW DATABASES]
+			WHERE database_name != 'system'
+			ORDER BY database_name
+		`
+	}
 
 	var databases []string
 	if err := c.db.Select(&databases, query); err != nil {
@@ -165,12 +754,324 @@ func (c *PostgresConnector) GetDatabases() ([]string, error) {
 
 // SwitchDatabase switches to a different database
 func (c *PostgresConnector) SwitchDatabase(dbName string) error {
-	// Close current connection
+	if c.tx != nil {
+		return errTxOpen
+	}
+
+	// Close current connection; temporary tables don't survive it
+	c.unpin()
+	c.closeReplica()
 	if c.db != nil {
 		c.db.Close()
 	}
 
 	// Update config and reconnect
 	c.config.Database = dbName
-	return c.Connect()
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.ConnectTimeoutDuration())
+	defer cancel()
+	return c.Connect(ctx)
+}
+
+// GetPartitions returns partitioned tables with their partitions and bounds
+func (c *PostgresConnector) GetPartitions() (map[string]*PartitionInfo, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if c.cockroach {
+		// Cockroach has no declarative partition tables (relkind 'p')
+		return map[string]*PartitionInfo{}, nil
+	}
+
+	query := `
+		SELECT
+			parent.relname,
+			pg_get_partkeydef(parent.oid),
+			child.relname,
+			COALESCE(pg_get_expr(child.relpartbound, child.oid), '')
+		FROM pg_class parent
+		JOIN pg_namespace n ON n.oid = parent.relnamespace
+		LEFT JOIN pg_inherits i ON i.inhparent = parent.oid
+		LEFT JOIN pg_class child ON child.oid = i.inhrelid
+		WHERE parent.relkind = 'p'
+		AND n.nspname = 'public'
+		ORDER BY parent.relname, child.relname
+	`
+
+	rows, err := c.db.Queryx(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+	defer rows.Close()
+
+	partitions := make(map[string]*PartitionInfo)
+	for rows.Next() {
+		var parent, keyDef string
+		var child, bounds *string
+		if err := rows.Scan(&parent, &keyDef, &child, &bounds); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+
+		info, ok := partitions[parent]
+		if !ok {
+			// pg_get_partkeydef returns e.g. "RANGE (created_at)"
+			strategy, key := keyDef, ""
+			if idx := strings.Index(keyDef, " "); idx != -1 {
+				strategy = keyDef[:idx]
+				key = strings.Trim(strings.TrimSpace(keyDef[idx:]), "()")
+			}
+			info = &PartitionInfo{Table: parent, Strategy: strategy, Key: key}
+			partitions[parent] = info
+		}
+
+		if child != nil {
+			b := ""
+			if bounds != nil {
+				b = *bounds
+			}
+			info.Partitions = append(info.Partitions, Partition{
+				Name:   *child,
+				Parent: parent,
+				Bounds: b,
+				Target: c.quote(*child),
+			})
+		}
+	}
+
+	return partitions, nil
+}
+
+// MaintenanceActions lists PostgreSQL maintenance operations
+func (c *PostgresConnector) MaintenanceActions() []MaintenanceAction {
+	if c.cockroach {
+		// Cockroach garbage-collects MVCC data itself and has no VACUUM
+		return []MaintenanceAction{
+			{Name: "ANALYZE", Description: "Refresh table statistics", Statement: "ANALYZE %s"},
+		}
+	}
+	return []MaintenanceAction{
+		{Name: "ANALYZE", Description: "Refresh planner statistics", Statement: "ANALYZE VERBOSE %s"},
+		{Name: "VACUUM", Description: "Reclaim dead tuples", Statement: "VACUUM VERBOSE %s"},
+		{Name: "VACUUM ANALYZE", Description: "Vacuum and refresh statistics", Statement: "VACUUM (VERBOSE, ANALYZE) %s"},
+		{Name: "VACUUM FULL", Description: "Rewrite table (takes an exclusive lock)", Statement: "VACUUM (FULL, VERBOSE) %s"},
+	}
+}
+
+// RunMaintenance runs a maintenance action on a table
+func (c *PostgresConnector) RunMaintenance(action MaintenanceAction, table string) ([]string, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	return c.runWithNotices(action.SQL(c.quote(table)))
+}
+
+// runWithNotices runs a statement and returns the notices the server sent
+// meanwhile, such as the report of VACUUM VERBOSE. It uses a connection of
+// its own, or the pinned session that owns temporary tables.
+func (c *PostgresConnector) runWithNotices(sql string) ([]string, error) {
+	ctx := context.Background()
+	conn := c.pinned
+	if conn == nil {
+		var err error
+		if conn, err = c.db.Connx(ctx); err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+	}
+
+	var lines []string
+	setHandler := func(handler func(*pq.Error)) error {
+		return conn.Raw(func(dc interface{}) error {
+			if pc, ok := dc.(driver.Conn); ok {
+				pq.SetNoticeHandler(pc, handler)
+			}
+			return nil
+		})
+	}
+	if err := setHandler(func(notice *pq.Error) {
+		lines = append(lines, notice.Message)
+		if notice.Detail != "" {
+			lines = append(lines, strings.Split(notice.Detail, "\n")...)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	// The connection goes back to the pool without the handler
+	defer setHandler(nil)
+
+	c.setEndpoint(EndpointPrimary)
+	if _, err := conn.ExecContext(ctx, sql); err != nil {
+		return lines, fmt.Errorf("execute error: %w", err)
+	}
+	if len(lines) == 0 {
+		lines = []string{"OK"}
+	}
+	return lines, nil
+}
+
+// MaintenanceProgress reports VACUUM progress from pg_stat_progress_vacuum
+func (c *PostgresConnector) MaintenanceProgress(table string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+	if c.cockroach {
+		return "", fmt.Errorf("progress is not available on CockroachDB")
+	}
+
+	query := `
+		SELECT phase, heap_blks_scanned, heap_blks_total
+		FROM pg_stat_progress_vacuum p
+		JOIN pg_class c ON c.oid = p.relid
+		WHERE c.relname = $1
+	`
+
+	var phase string
+	var scanned, total int64
+	if err := c.db.QueryRowx(query, table).Scan(&phase, &scanned, &total); err != nil {
+		return "", err
+	}
+	if total > 0 {
+		return fmt.Sprintf("%s: %d/%d blocks (%.0f%%)", phase, scanned, total, float64(scanned)*100/float64(total)), nil
+	}
+	return phase, nil
+}
+
+// ReplicationStatus reports replay lag from pg_stat_replication on a primary,
+// or from the last replayed transaction on a standby
+func (c *PostgresConnector) ReplicationStatus() ([]ReplicaStatus, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if c.cockroach {
+		// Cockroach replicates internally; there is no primary/standby lag
+		return nil, nil
+	}
+
+	var inRecovery bool
+	if err := c.db.Get(&inRecovery, "SELECT pg_is_in_recovery()"); err != nil {
+		return nil, fmt.Errorf("failed to get recovery state: %w", err)
+	}
+
+	if inRecovery {
+		query := `
+			SELECT
+				COALESCE(sender_host, ''),
+				COALESCE(status, 'stopped'),
+				EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+			FROM (SELECT 1) one
+			LEFT JOIN pg_stat_wal_receiver ON true
+		`
+
+		var host, state string
+		var lag *float64
+		if err := c.db.QueryRowx(query).Scan(&host, &state, &lag); err != nil {
+			return nil, fmt.Errorf("failed to get replication status: %w", err)
+		}
+
+		status := ReplicaStatus{Name: host, Role: "replica", State: state}
+		if lag != nil {
+			status.Lag = secondsToDuration(*lag)
+			status.LagKnown = true
+		}
+		return []ReplicaStatus{status}, nil
+	}
+
+	query := `
+		SELECT
+			COALESCE(application_name, ''),
+			COALESCE(host(client_addr), 'local'),
+			state,
+			EXTRACT(EPOCH FROM replay_lag)
+		FROM pg_stat_replication
+		ORDER BY application_name
+	`
+
+	rows, err := c.db.Queryx(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replication status: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []ReplicaStatus
+	for rows.Next() {
+		var app, addr, state string
+		var lag *float64
+		if err := rows.Scan(&app, &addr, &state, &lag); err != nil {
+			return nil, fmt.Errorf("failed to scan replication status: %w", err)
+		}
+
+		name := addr
+		if app != "" {
+			name = app + "@" + addr
+		}
+		status := ReplicaStatus{Name: name, Role: "primary", State: state}
+		// replay_lag is NULL once a replica has caught up and gone idle
+		status.LagKnown = true
+		if lag != nil {
+			status.Lag = secondsToDuration(*lag)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetServerSettings returns server settings from pg_settings
+func (c *PostgresConnector) GetServerSettings() ([]ServerSetting, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT name, setting || COALESCE(' ' || unit, ''), COALESCE(short_desc, '')
+		FROM pg_settings
+		ORDER BY name
+	`
+	if c.cockroach {
+		// pg_settings is incomplete on Cockroach; SHOW ALL lists session vars
+		query = `
+			SELECT variable, value, ''
+			FROM [SHOW ALL]
+			ORDER BY variable
+		`
+	}
+
+	rows, err := c.db.Queryx(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []ServerSetting
+	for rows.Next() {
+		var s ServerSetting
+		if err := rows.Scan(&s.Name, &s.Value, &s.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		settings = append(settings, s)
+	}
+
+	return settings, nil
+}
+
+// Capabilities returns server details and supported features
+func (c *PostgresConnector) Capabilities() Capabilities {
+	caps := Capabilities{
+		SupportsSwitchDatabase: true,
+		SupportsCancel:         true,
+		SupportsTransactions:   true,
+		SupportsExplain:        true,
+	}
+	if c.db == nil {
+		return caps
+	}
+
+	query := `
+		SELECT
+			version(),
+			current_setting('server_encoding'),
+			current_setting('TimeZone'),
+			current_user
+	`
+	c.db.QueryRowx(query).Scan(&caps.ServerVersion, &caps.Encoding, &caps.Timezone, &caps.CurrentUser)
+	return caps
 }
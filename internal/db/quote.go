@@ -0,0 +1,47 @@
+package db
+
+import "strings"
+
+// QuoteIdentifier quotes a table, column or database name for the given
+// driver so names from UI selections can be interpolated into SQL safely.
+// MySQL, MariaDB and BigQuery use backticks; the others use double quotes.
+func QuoteIdentifier(driver, name string) string {
+	switch driver {
+	case "mysql", "mariadb", "bigquery":
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quote quotes an identifier for the connector's driver
+func (c *BaseConnector) quote(name string) string {
+	return QuoteIdentifier(c.driver, name)
+}
+
+// QuoteLiteral quotes a string as an SQL string literal for the given
+// driver. MySQL, MariaDB and BigQuery also treat backslashes as escapes;
+// QuoteLiteralFor follows a MySQL server's NO_BACKSLASH_ESCAPES.
+func QuoteLiteral(driver, s string) string {
+	switch driver {
+	case "mysql", "mariadb", "bigquery":
+		s = strings.ReplaceAll(s, `\`, `\\`)
+		if driver == "bigquery" {
+			return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+		}
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// LiteralQuoter is implemented by connectors whose string literals depend
+// on the server's settings
+type LiteralQuoter interface {
+	QuoteLiteral(s string) string
+}
+
+// QuoteLiteralFor quotes a string as an SQL string literal for c
+func QuoteLiteralFor(c Connector, s string) string {
+	if q, ok := c.(LiteralQuoter); ok {
+		return q.QuoteLiteral(s)
+	}
+	return QuoteLiteral(c.GetDriverName(), s)
+}
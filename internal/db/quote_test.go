@@ -0,0 +1,43 @@
+package db
+
+import "testing"
+
+func TestQuoteLiteral(t *testing.T) {
+	tests := []struct {
+		driver string
+		in     string
+		want   string
+	}{
+		{"postgres", "it's", "'it''s'"},
+		{"postgres", `C:\temp`, `'C:\temp'`},
+		{"sqlite3", "a''b", "'a''''b'"},
+		{"mysql", "it's", "'it''s'"},
+		{"mysql", `C:\temp`, `'C:\\temp'`},
+		{"mariadb", `\'`, `'\\'''`},
+		{"bigquery", "it's", `'it\'s'`},
+		{"bigquery", `a\b`, `'a\\b'`},
+	}
+	for _, tt := range tests {
+		if got := QuoteLiteral(tt.driver, tt.in); got != tt.want {
+			t.Errorf("QuoteLiteral(%q, %q) = %s, want %s", tt.driver, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteLiteralFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		connector Connector
+		in        string
+		want      string
+	}{
+		{"postgres", NewPostgresConnector(nil), `a\b'c`, `'a\b''c'`},
+		{"mysql", &MySQLConnector{BaseConnector: BaseConnector{driver: "mysql"}}, `a\b'c`, `'a\\b''c'`},
+		{"mysql NO_BACKSLASH_ESCAPES", &MySQLConnector{BaseConnector: BaseConnector{driver: "mysql"}, noBackslashEscapes: true}, `a\b'c`, `'a\b''c'`},
+	}
+	for _, tt := range tests {
+		if got := QuoteLiteralFor(tt.connector, tt.in); got != tt.want {
+			t.Errorf("%s: QuoteLiteralFor(%q) = %s, want %s", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
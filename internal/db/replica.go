@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/jmoiron/sqlx"
+)
+
+// Endpoint names the server a query ran on
+type Endpoint string
+
+const (
+	EndpointPrimary Endpoint = "primary"
+	EndpointReplica Endpoint = "replica"
+)
+
+// ReplicaRouter is implemented by connectors that send read-only queries to
+// a read replica (replica_host) and everything else to the primary
+type ReplicaRouter interface {
+	// HasReplica reports whether a replica connection is open
+	HasReplica() bool
+	// SetPrimaryOnly sends every query to the primary while on, e.g. to
+	// read back a write the replica hasn't replayed yet
+	SetPrimaryOnly(on bool)
+	PrimaryOnly() bool
+	// LastEndpoint returns where the last query ran, empty before any
+	LastEndpoint() Endpoint
+}
+
+// GetReplicaRouter returns the connector's replica routing when it has a
+// replica connected
+func GetReplicaRouter(c Connector) (ReplicaRouter, bool) {
+	r, ok := c.(ReplicaRouter)
+	if !ok || !r.HasReplica() {
+		return nil, false
+	}
+	return r, true
+}
+
+// HasReplica reports whether a replica connection is open
+func (c *BaseConnector) HasReplica() bool {
+	return c.replica != nil
+}
+
+// SetPrimaryOnly turns routing of reads to the replica off or back on
+func (c *BaseConnector) SetPrimaryOnly(on bool) {
+	c.primaryOnly = on
+}
+
+// PrimaryOnly reports whether reads are kept on the primary
+func (c *BaseConnector) PrimaryOnly() bool {
+	return c.primaryOnly
+}
+
+// LastEndpoint returns where the last query ran
+func (c *BaseConnector) LastEndpoint() Endpoint {
+	c.endpointMu.Lock()
+	defer c.endpointMu.Unlock()
+	return c.lastEndpoint
+}
+
+// setEndpoint records where a query runs
+func (c *BaseConnector) setEndpoint(e Endpoint) {
+	c.endpointMu.Lock()
+	c.lastEndpoint = e
+	c.endpointMu.Unlock()
+}
+
+// reader returns the replica when sql only reads and may run there, or nil
+// for the primary, and records the choice. The open transaction and the
+// pinned session stay on the primary: the replica has neither their writes
+// nor their temporary tables and attachments.
+func (c *BaseConnector) reader(sql string) *sqlx.DB {
+	if c.replica != nil && !c.primaryOnly && c.tx == nil && c.pinned == nil && readOnlyBatch(sql, c.driver) {
+		c.setEndpoint(EndpointReplica)
+		return c.replica
+	}
+	c.setEndpoint(EndpointPrimary)
+	return nil
+}
+
+// readSession returns where a query runs: the replica when reader picks it,
+// otherwise session()
+func (c *BaseConnector) readSession(sql string) queryExecer {
+	if replica := c.reader(sql); replica != nil {
+		return replica
+	}
+	return c.session()
+}
+
+// readOnlyBatch reports whether every statement of sql only reads data
+func readOnlyBatch(sql, driver string) bool {
+	dialect := sqlparse.DialectFor(driver)
+	statements := sqlparse.Split(sql, dialect)
+	if len(statements) == 0 {
+		return false
+	}
+	for _, stmt := range statements {
+		if !sqlparse.IsReadOnly(stmt.Text, dialect) {
+			return false
+		}
+	}
+	return true
+}
+
+// replicaTarget returns the host and port of the replica, tunnelled through
+// the connection's SSH hops like the primary. The port defaults to the
+// primary's.
+func (c *BaseConnector) replicaTarget(ctx context.Context) (string, int, error) {
+	port := c.config.ReplicaPort
+	if port == 0 {
+		port = c.config.Port
+	}
+	if len(c.config.SSHHops) == 0 {
+		return c.config.ReplicaHost, port, nil
+	}
+
+	if c.replicaTunnel == nil {
+		target := net.JoinHostPort(c.config.ReplicaHost, strconv.Itoa(port))
+		tunnel, err := OpenTunnel(ctx, c.config.SSHHops, target)
+		if err != nil {
+			return "", 0, err
+		}
+		c.replicaTunnel = tunnel
+	}
+
+	host, port := c.replicaTunnel.LocalAddr()
+	return host, port, nil
+}
+
+// closeReplica closes the replica connection, keeping its tunnel for a
+// reconnect
+func (c *BaseConnector) closeReplica() {
+	if c.replica != nil {
+		c.replica.Close()
+		c.replica = nil
+	}
+}
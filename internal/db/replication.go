@@ -0,0 +1,49 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReplicaStatus describes one replication link of the connected server
+type ReplicaStatus struct {
+	// Name identifies the other end (replica address or primary host)
+	Name string
+	// Role is the connected server's role: "primary" or "replica"
+	Role  string
+	State string
+	// Lag is the replay delay; LagKnown is false when the server can't
+	// report it (e.g. replication stopped)
+	Lag      time.Duration
+	LagKnown bool
+}
+
+// LagString formats the lag for display
+func (r ReplicaStatus) LagString() string {
+	if !r.LagKnown {
+		return "?"
+	}
+	if r.Lag < time.Second {
+		return fmt.Sprintf("%dms", r.Lag.Milliseconds())
+	}
+	return r.Lag.Round(100 * time.Millisecond).String()
+}
+
+// ReplicationReporter is implemented by connectors that can report
+// replication status of the connected server
+type ReplicationReporter interface {
+	ReplicationStatus() ([]ReplicaStatus, error)
+}
+
+// GetReplicationStatus returns replication status, or nil if unsupported
+func GetReplicationStatus(c Connector) ([]ReplicaStatus, error) {
+	if r, ok := c.(ReplicationReporter); ok {
+		return r.ReplicationStatus()
+	}
+	return nil, nil
+}
+
+// secondsToDuration converts a fractional seconds value to a duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
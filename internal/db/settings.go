@@ -0,0 +1,22 @@
+package db
+
+// ServerSetting is a server configuration variable
+type ServerSetting struct {
+	Name        string
+	Value       string
+	Description string
+}
+
+// SettingsLister is implemented by connectors that can list server settings
+// (SHOW ALL / SHOW VARIABLES)
+type SettingsLister interface {
+	GetServerSettings() ([]ServerSetting, error)
+}
+
+// GetServerSettings returns server settings, or nil if unsupported
+func GetServerSettings(c Connector) ([]ServerSetting, error) {
+	if l, ok := c.(SettingsLister); ok {
+		return l.GetServerSettings()
+	}
+	return nil, nil
+}
@@ -1,9 +1,14 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -23,14 +28,22 @@ func NewSQLiteConnector(cfg *config.DatabaseConfig) *SQLiteConnector {
 }
 
 // Connect establishes connection to SQLite database
-func (c *SQLiteConnector) Connect() error {
+func (c *SQLiteConnector) Connect(ctx context.Context) error {
 	// For SQLite, the database path is stored in Host or Database field
 	dbPath := c.config.Database
 	if dbPath == "" {
 		dbPath = c.config.Host
 	}
 
-	db, err := sqlx.Connect("sqlite3", dbPath)
+	if c.config.ReadOnly {
+		sep := "?"
+		if strings.Contains(dbPath, "?") {
+			sep = "&"
+		}
+		dbPath += sep + "_query_only=1"
+	}
+
+	db, err := sqlx.ConnectContext(ctx, "sqlite3", dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to connect to SQLite: %w", err)
 	}
@@ -67,7 +80,7 @@ func (c *SQLiteConnector) GetColumns(tableName string) ([]Column, error) {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
+	query := fmt.Sprintf("PRAGMA table_info(%s)", c.quote(tableName))
 
 	rows, err := c.db.Queryx(query)
 	if err != nil {
@@ -80,7 +93,7 @@ func (c *SQLiteConnector) GetColumns(tableName string) ([]Column, error) {
 		var cid int
 		var name, colType string
 		var notNull, pk int
-		var dfltValue interface{}
+		var dfltValue sql.NullString
 
 		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
 			return nil, fmt.Errorf("failed to scan column: %w", err)
@@ -90,13 +103,249 @@ func (c *SQLiteConnector) GetColumns(tableName string) ([]Column, error) {
 			Name:     name,
 			Type:     colType,
 			Nullable: notNull == 0,
-			IsPK:     pk == 1,
+			IsPK:     pk > 0, // position in the primary key, 0 for other columns
+			Default:  dfltValue.String,
 		})
 	}
 
 	return columns, nil
 }
 
+// GetIndexes returns the indexes of a table. An INTEGER PRIMARY KEY is the
+// rowid and has no index of its own.
+func (c *SQLiteConnector) GetIndexes(tableName string) ([]Index, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	type indexEntry struct {
+		Seq     int    `db:"seq"`
+		Name    string `db:"name"`
+		Unique  bool   `db:"unique"`
+		Origin  string `db:"origin"` // c = CREATE INDEX, u = UNIQUE, pk = PRIMARY KEY
+		Partial bool   `db:"partial"`
+	}
+	var entries []indexEntry
+	if err := c.db.Select(&entries, fmt.Sprintf("PRAGMA index_list(%s)", c.quote(tableName))); err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+
+	indexes := make([]Index, 0, len(entries))
+	for _, e := range entries {
+		idx := Index{Name: e.Name, Unique: e.Unique, Primary: e.Origin == "pk"}
+
+		rows, err := c.db.Queryx(fmt.Sprintf("PRAGMA index_info(%s)", c.quote(e.Name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get index columns: %w", err)
+		}
+		for rows.Next() {
+			var seqno, cid int
+			var name sql.NullString // NULL for expressions
+			if err := rows.Scan(&seqno, &cid, &name); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan index column: %w", err)
+			}
+			key := "(expression)"
+			if name.Valid {
+				key = name.String
+			}
+			idx.Columns = append(idx.Columns, key)
+		}
+		rows.Close()
+
+		indexes = append(indexes, idx)
+	}
+
+	// Primary key first, then by name
+	sort.SliceStable(indexes, func(i, j int) bool {
+		if indexes[i].Primary != indexes[j].Primary {
+			return indexes[i].Primary
+		}
+		return indexes[i].Name < indexes[j].Name
+	})
+	return indexes, nil
+}
+
+// GetForeignKeys returns the foreign keys of a table. SQLite constraints
+// have no names, so they are named after the referenced table.
+func (c *SQLiteConnector) GetForeignKeys(tableName string) ([]ForeignKey, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := c.db.Queryx(fmt.Sprintf("PRAGMA foreign_key_list(%s)", c.quote(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	// One row per key column, grouped by id
+	var fks []ForeignKey
+	lastID := -1
+	for rows.Next() {
+		var id, seq int
+		var refTable, from, onUpdate, onDelete, match string
+		var to sql.NullString // NULL when the primary key is referenced implicitly
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		if id != lastID {
+			lastID = id
+			fks = append(fks, ForeignKey{
+				Name:     fmt.Sprintf("fk_%s_%s", tableName, refTable),
+				RefTable: refTable,
+				OnDelete: onDelete,
+				OnUpdate: onUpdate,
+			})
+		}
+		last := &fks[len(fks)-1]
+		last.Columns = append(last.Columns, from)
+		last.RefColumns = append(last.RefColumns, to.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range fks {
+		if fks[i].RefColumns[0] != "" {
+			continue
+		}
+		pk, err := c.primaryKey(fks[i].RefTable)
+		if err != nil {
+			return nil, err
+		}
+		if len(pk) == len(fks[i].Columns) {
+			fks[i].RefColumns = pk
+		}
+	}
+	return fks, nil
+}
+
+// primaryKey returns the primary key columns of a table in key order
+func (c *SQLiteConnector) primaryKey(tableName string) ([]string, error) {
+	type column struct {
+		Name string `db:"name"`
+		PK   int    `db:"pk"`
+	}
+	var columns []column
+	if err := c.db.Unsafe().Select(&columns, fmt.Sprintf("PRAGMA table_info(%s)", c.quote(tableName))); err != nil {
+		return nil, fmt.Errorf("failed to get primary key: %w", err)
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].PK < columns[j].PK })
+
+	var pk []string
+	for _, col := range columns {
+		if col.PK > 0 {
+			pk = append(pk, col.Name)
+		}
+	}
+	return pk, nil
+}
+
+// GetViews returns the views of the database
+func (c *SQLiteConnector) GetViews() ([]View, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var names []string
+	if err := c.db.Select(&names, "SELECT name FROM sqlite_master WHERE type = 'view' ORDER BY name"); err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+
+	views := make([]View, len(names))
+	for i, name := range names {
+		views[i] = View{Name: name}
+	}
+	return views, nil
+}
+
+// GetViewDefinition returns the CREATE VIEW statement of a view
+func (c *SQLiteConnector) GetViewDefinition(name string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	var definition string
+	if err := c.db.Get(&definition, "SELECT sql FROM sqlite_master WHERE type = 'view' AND name = ?", name); err != nil {
+		return "", fmt.Errorf("failed to get view definition: %w", err)
+	}
+	return definition, nil
+}
+
+// GetTriggers returns the triggers of a table, with timing and event read
+// from their definition
+func (c *SQLiteConnector) GetTriggers(tableName string) ([]Trigger, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var rows []struct {
+		Name string `db:"name"`
+		SQL  string `db:"sql"`
+	}
+	query := "SELECT name, sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? ORDER BY name"
+	if err := c.db.Select(&rows, query, tableName); err != nil {
+		return nil, fmt.Errorf("failed to get triggers: %w", err)
+	}
+
+	triggers := make([]Trigger, len(rows))
+	for i, row := range rows {
+		timing, event := sqliteTriggerType(row.SQL)
+		triggers[i] = Trigger{
+			Name:       row.Name,
+			Timing:     timing,
+			Events:     []string{event},
+			ForEach:    "ROW",
+			Enabled:    true,
+			Definition: row.SQL,
+		}
+	}
+	return triggers, nil
+}
+
+// sqliteTriggerType reads the timing and event from the head of a CREATE
+// TRIGGER statement, up to ON table. Timing defaults to BEFORE.
+func sqliteTriggerType(definition string) (timing, event string) {
+	timing = "BEFORE"
+	for _, tok := range sqlparse.Tokenize(definition, sqlparse.DialectStandard) {
+		switch kw := tok.Keyword(); kw {
+		case "before", "after":
+			timing = strings.ToUpper(kw)
+		case "instead":
+			timing = "INSTEAD OF"
+		case "insert", "update", "delete":
+			event = strings.ToUpper(kw)
+		case "on":
+			return timing, event
+		}
+	}
+	return timing, event
+}
+
+// GetTableDDL returns the statements SQLite keeps for the table and its
+// indexes. Automatic indexes (UNIQUE, PRIMARY KEY) have none.
+func (c *SQLiteConnector) GetTableDDL(tableName string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	var statements []string
+	query := `
+		SELECT sql FROM sqlite_master
+		WHERE tbl_name = ? AND type IN ('table', 'index') AND sql IS NOT NULL
+		ORDER BY type <> 'table', name
+	`
+	if err := c.db.Select(&statements, query, tableName); err != nil {
+		return "", fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	if len(statements) == 0 {
+		return "", fmt.Errorf("table %s not found", tableName)
+	}
+	return strings.Join(statements, ";\n") + ";", nil
+}
+
 // GetSchema returns the complete database schema
 func (c *SQLiteConnector) GetSchema() (*Schema, error) {
 	tables, err := c.GetTables()
@@ -113,21 +362,167 @@ func (c *SQLiteConnector) GetSchema() (*Schema, error) {
 		if err != nil {
 			continue // Skip tables we can't read
 		}
+		// Indexes and foreign keys are best effort; the table is still
+		// usable without them
+		indexes, _ := c.GetIndexes(tableName)
+		foreignKeys, _ := c.GetForeignKeys(tableName)
 		schema.Tables[tableName] = Table{
-			Name:    tableName,
-			Columns: columns,
+			Name:        tableName,
+			Columns:     columns,
+			Indexes:     indexes,
+			ForeignKeys: foreignKeys,
 		}
 	}
 
 	return schema, nil
 }
 
-// GetDatabases returns list of databases (SQLite has only one)
+// GetDatabases returns the database file followed by attached databases
 func (c *SQLiteConnector) GetDatabases() ([]string, error) {
-	return []string{c.config.Database}, nil
+	databases := []string{c.config.Database}
+	attached, err := c.AttachedDatabases(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attached {
+		databases = append(databases, a.Name)
+	}
+	return databases, nil
 }
 
 // SwitchDatabase is not supported for SQLite
 func (c *SQLiteConnector) SwitchDatabase(dbName string) error {
 	return fmt.Errorf("SQLite does not support switching databases")
 }
+
+// AttachDatabase attaches a database file under name on the pinned
+// session connection
+func (c *SQLiteConnector) AttachDatabase(ctx context.Context, file, name string) error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	if c.tx != nil && c.pinned == nil {
+		return errTxOpen
+	}
+	if err := c.pin(ctx); err != nil {
+		return err
+	}
+	if _, err := execute(ctx, c.session(), "ATTACH DATABASE ? AS "+c.quote(name), file); err != nil {
+		return fmt.Errorf("failed to attach %s: %w", file, err)
+	}
+	return nil
+}
+
+// DetachDatabase detaches an attached database
+func (c *SQLiteConnector) DetachDatabase(ctx context.Context, name string) error {
+	if c.pinned == nil {
+		return fmt.Errorf("no database %q is attached", name)
+	}
+	if _, err := execute(ctx, c.session(), "DETACH DATABASE "+c.quote(name)); err != nil {
+		return fmt.Errorf("failed to detach %s: %w", name, err)
+	}
+	return nil
+}
+
+// AttachedDatabases lists the databases attached to the pinned session
+func (c *SQLiteConnector) AttachedDatabases(ctx context.Context) ([]AttachedDatabase, error) {
+	if c.pinned == nil {
+		// Nothing was attached; pool connections only have main and temp
+		return nil, nil
+	}
+
+	type entry struct {
+		Seq  int    `db:"seq"`
+		Name string `db:"name"`
+		File string `db:"file"`
+	}
+	var entries []entry
+	if err := sqlx.SelectContext(ctx, c.session(), &entries, "PRAGMA database_list"); err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+
+	var attached []AttachedDatabase
+	for _, e := range entries {
+		if e.Name == "main" || e.Name == "temp" {
+			continue
+		}
+		a := AttachedDatabase{Name: e.Name, File: e.File}
+		query := fmt.Sprintf("SELECT name FROM %s.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%%' ORDER BY name", c.quote(e.Name))
+		if err := sqlx.SelectContext(ctx, c.session(), &a.Tables, query); err != nil {
+			return nil, fmt.Errorf("failed to get tables of %s: %w", e.Name, err)
+		}
+		attached = append(attached, a)
+	}
+	return attached, nil
+}
+
+// MaintenanceActions lists SQLite maintenance operations
+func (c *SQLiteConnector) MaintenanceActions() []MaintenanceAction {
+	return []MaintenanceAction{
+		{Name: "ANALYZE", Description: "Refresh query planner statistics", Statement: "ANALYZE %s"},
+		// VACUUM and integrity_check work on the whole database file
+		{Name: "VACUUM", Description: "Rebuild the database file", Statement: "VACUUM"},
+		{Name: "INTEGRITY CHECK", Description: "Check database integrity", Statement: "PRAGMA integrity_check"},
+	}
+}
+
+// RunMaintenance runs a maintenance action on a table
+func (c *SQLiteConnector) RunMaintenance(action MaintenanceAction, table string) ([]string, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if strings.HasPrefix(action.Statement, "PRAGMA") {
+		return c.runMaintenanceQuery(action.SQL(c.quote(table)))
+	}
+	return c.runMaintenanceExec(action.SQL(c.quote(table)))
+}
+
+// sqlitePragmas are the read-only pragmas shown as server settings
+var sqlitePragmas = []string{
+	"application_id", "auto_vacuum", "busy_timeout", "cache_size",
+	"encoding", "foreign_keys", "freelist_count", "journal_mode",
+	"journal_size_limit", "locking_mode", "page_count", "page_size",
+	"recursive_triggers", "secure_delete", "synchronous", "temp_store",
+	"user_version", "wal_autocheckpoint",
+}
+
+// GetServerSettings returns the current value of common pragmas
+func (c *SQLiteConnector) GetServerSettings() ([]ServerSetting, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var settings []ServerSetting
+	for _, pragma := range sqlitePragmas {
+		var value string
+		if err := c.db.Get(&value, "PRAGMA "+pragma); err != nil {
+			continue // Pragma not available in this build
+		}
+		settings = append(settings, ServerSetting{Name: pragma, Value: value})
+	}
+
+	return settings, nil
+}
+
+// Capabilities returns library details and supported features
+func (c *SQLiteConnector) Capabilities() Capabilities {
+	caps := Capabilities{
+		SupportsCancel:       true,
+		SupportsTransactions: true,
+		SupportsExplain:      true,
+		// SQLite has no server timezone or users
+		Timezone: "local",
+	}
+	if c.db == nil {
+		return caps
+	}
+
+	var version, encoding string
+	if err := c.db.Get(&version, "SELECT sqlite_version()"); err == nil {
+		caps.ServerVersion = "SQLite " + version
+	}
+	if err := c.db.Get(&encoding, "PRAGMA encoding"); err == nil {
+		caps.Encoding = encoding
+	}
+	return caps
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RowStreamer is implemented by connectors that can hand over a query's
+// rows one at a time, ignoring the ResultLimit. Exports use it to write
+// results too big to hold in memory.
+type RowStreamer interface {
+	// StreamQuery runs sql, calls onColumns once with the first result
+	// set's columns and then onRow for each row. values is reused between
+	// calls.
+	StreamQuery(ctx context.Context, sql string, onColumns func(columns []ColumnType) error, onRow func(values []Value) error) error
+}
+
+// GetRowStreamer returns the connector's row streaming, if any
+func GetRowStreamer(c Connector) (RowStreamer, bool) {
+	s, ok := c.(RowStreamer)
+	return s, ok
+}
+
+// errStopStream ends a stream early once enough rows were read
+var errStopStream = errors.New("stop streaming")
+
+// collectResultSet reads a stream into a result set, stopping at limit
+func collectResultSet(limit ResultLimit, stream func(onColumns func([]ColumnType) error, onRow func([]Value) error) error) (ResultSet, error) {
+	var set ResultSet
+	var size int64
+	err := stream(func(types []ColumnType) error {
+		set.ColumnTypes = types
+		set.Columns = make([]string, len(types))
+		for i, t := range types {
+			set.Columns[i] = t.Name
+		}
+		return nil
+	}, func(values []Value) error {
+		if limit.reached(len(set.Rows), size) {
+			// There are more rows than the limit allows
+			set.Truncated = true
+			return errStopStream
+		}
+		row := make(Row, len(values))
+		for i, v := range values {
+			row[set.Columns[i]] = v
+		}
+		set.Rows = append(set.Rows, row)
+		size += rowSize(row)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopStream) {
+		return ResultSet{}, err
+	}
+	return set, nil
+}
+
+// StreamBatches streams the first result set of sql into a result set,
+// stopping at limit like collectResultSet. Every interval it hands the rows
+// read since the last call to onBatch; the rows read after the last batch
+// are only in the returned set. Batches are new slices the caller may keep.
+// On an error the set holds the rows read until then.
+func StreamBatches(ctx context.Context, s RowStreamer, sql string, limit ResultLimit, interval time.Duration, onBatch func(columns []ColumnType, rows []Row) error) (ResultSet, error) {
+	var set ResultSet
+	var size int64
+	var batch []Row
+	last := time.Now()
+	err := s.StreamQuery(ctx, sql, func(types []ColumnType) error {
+		set.ColumnTypes = types
+		set.Columns = make([]string, len(types))
+		for i, t := range types {
+			set.Columns[i] = t.Name
+		}
+		return nil
+	}, func(values []Value) error {
+		if limit.reached(len(set.Rows), size) {
+			set.Truncated = true
+			return errStopStream
+		}
+		row := make(Row, len(values))
+		for i, v := range values {
+			row[set.Columns[i]] = v
+		}
+		set.Rows = append(set.Rows, row)
+		size += rowSize(row)
+
+		batch = append(batch, row)
+		if time.Since(last) < interval {
+			return nil
+		}
+		last = time.Now()
+		rows := batch
+		batch = nil
+		return onBatch(set.ColumnTypes, rows)
+	})
+	if err != nil && !errors.Is(err, errStopStream) {
+		return set, err
+	}
+	return set, nil
+}
+
+// StreamQuery streams the first result set of sql
+func (c *BaseConnector) StreamQuery(ctx context.Context, sql string, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	return streamRows(ctx, c.readSession(sql), sql, onColumns, onRow)
+}
+
+// streamRows runs a query on q and streams its first result set
+func streamRows(ctx context.Context, q queryExecer, sql string, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	rows, err := q.QueryxContext(ctx, sql)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	columns, dbTypes, err := resultColumns(rows)
+	if err != nil {
+		return err
+	}
+	types := make([]ColumnType, len(columns))
+	for i, col := range columns {
+		types[i] = ColumnType{Name: col, DatabaseType: dbTypes[i], Kind: ColumnKindOf(dbTypes[i])}
+	}
+	if err := onColumns(types); err != nil {
+		return err
+	}
+
+	values := make([]Value, len(columns))
+	for rows.Next() {
+		scanned, err := rows.SliceScan()
+		if err != nil {
+			return fmt.Errorf("scan error: %w", err)
+		}
+		for i, v := range scanned {
+			values[i] = scannedValue(v, types[i].Kind)
+		}
+		if err := onRow(values); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TempTableCreator is implemented by connectors that can materialize a
+// query into a temporary table. Temporary tables only exist in the database
+// session that created them, so from then on statements run on one pinned
+// connection instead of the pool.
+type TempTableCreator interface {
+	// CreateTempTable creates table name from the rows of query
+	CreateTempTable(ctx context.Context, name, query string) (int64, error)
+	// TempTables returns the temporary tables created in this session
+	TempTables() []string
+}
+
+// GetTempTableCreator returns the connector's temporary table support, if any
+func GetTempTableCreator(c Connector) (TempTableCreator, bool) {
+	t, ok := c.(TempTableCreator)
+	return t, ok
+}
+
+// CreateTempTable creates a temporary table from a query on the pinned
+// session connection
+func (c *BaseConnector) CreateTempTable(ctx context.Context, name, query string) (int64, error) {
+	if c.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	if c.tx != nil && c.pinned == nil {
+		// The transaction holds a pool connection the table would not be
+		// visible from afterwards
+		return 0, errTxOpen
+	}
+	if err := c.pin(ctx); err != nil {
+		return 0, err
+	}
+
+	keyword := "TEMP"
+	if c.driver == "mysql" || c.driver == "mariadb" {
+		keyword = "TEMPORARY"
+	}
+	quoted := QuoteIdentifier(c.driver, name)
+	if _, err := execute(ctx, c.session(), fmt.Sprintf("CREATE %s TABLE %s AS %s", keyword, quoted, query)); err != nil {
+		return 0, err
+	}
+	c.tempTables = append(c.tempTables, name)
+
+	// Drivers disagree on what CREATE ... AS reports as affected rows
+	// (SQLite leaves the count of the previous statement), so count them
+	var count int64
+	if err := sqlx.GetContext(ctx, c.session(), &count, "SELECT COUNT(*) FROM "+quoted); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// TempTables returns the temporary tables of the pinned session
+func (c *BaseConnector) TempTables() []string {
+	return c.tempTables
+}
+
+// pin takes a connection out of the pool for the rest of the session
+func (c *BaseConnector) pin(ctx context.Context) error {
+	if c.pinned != nil {
+		return nil
+	}
+	conn, err := c.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to pin connection: %w", err)
+	}
+	c.pinned = conn
+	return nil
+}
+
+// unpin closes the pinned connection, which drops its temporary tables and
+// detaches attached databases
+func (c *BaseConnector) unpin() {
+	if c.pinned != nil {
+		// Discard rather than return it to the pool with the tables alive
+		c.pinned.Raw(func(interface{}) error { return driver.ErrBadConn })
+		c.pinned.Close()
+		c.pinned = nil
+	}
+	c.tempTables = nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// QueryTimeoutError reports a statement stopped by the query timeout
+type QueryTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *QueryTimeoutError) Error() string {
+	return fmt.Sprintf("query timed out after %s (query_timeout)", e.Timeout)
+}
+
+// WithQueryTimeout runs fn with ctx bounded by timeout; zero means no limit.
+// Connectors stop the statement when the deadline passes like on any
+// cancellation, and the driver's error is replaced by *QueryTimeoutError.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &QueryTimeoutError{Timeout: timeout}
+	}
+	return err
+}
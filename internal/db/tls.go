@@ -0,0 +1,111 @@
+package db
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// tlsEnabled reports whether the connection's sslmode asks for TLS
+func tlsEnabled(cfg *config.DatabaseConfig) bool {
+	return cfg.SSLMode != "" && cfg.SSLMode != "disable"
+}
+
+// clientTLSConfig builds a tls.Config from the connection's sslmode and
+// certificate files, following libpq's sslmode semantics:
+// require encrypts only, verify-ca checks the chain, verify-full also
+// checks the host name
+func clientTLSConfig(cfg *config.DatabaseConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: cfg.Host,
+	}
+
+	if cfg.SSLRootCert != "" {
+		pem, err := os.ReadFile(config.ExpandPath(cfg.SSLRootCert))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sslrootcert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in sslrootcert")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.SSLCert != "" || cfg.SSLKey != "" {
+		if cfg.SSLCert == "" || cfg.SSLKey == "" {
+			return nil, fmt.Errorf("sslcert and sslkey must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(config.ExpandPath(cfg.SSLCert), config.ExpandPath(cfg.SSLKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	switch cfg.SSLMode {
+	case "verify-full":
+		// Default verification: chain and host name
+	case "verify-ca":
+		// Verify the chain ourselves, skipping the host name check
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(rawCerts, tlsConfig.RootCAs)
+		}
+	default:
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyChain verifies a peer certificate chain against roots
+// (the system pool when roots is nil) without checking the host name
+func verifyChain(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("server sent no certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse server certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// registerMySQLTLS registers the connection's TLS settings with the MySQL
+// driver and returns the name to pass as the DSN's tls parameter
+func registerMySQLTLS(cfg *config.DatabaseConfig) (string, error) {
+	if !tlsEnabled(cfg) {
+		return "false", nil
+	}
+
+	tlsConfig, err := clientTLSConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	name := "sqdesk-" + cfg.Name
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", fmt.Errorf("failed to register TLS config: %w", err)
+	}
+	return name, nil
+}
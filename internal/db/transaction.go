@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Transactor is implemented by connectors that can keep a transaction open
+// across statements. While it is open, Query and Execute run inside it.
+type Transactor interface {
+	Begin() error
+	Commit() error
+	Rollback() error
+	InTransaction() bool
+}
+
+// GetTransactor returns the connector's transaction support, if any
+func GetTransactor(c Connector) (Transactor, bool) {
+	t, ok := c.(Transactor)
+	return t, ok
+}
+
+// errTxOpen refuses operations that would leave an open transaction behind
+var errTxOpen = errors.New("commit or roll back the open transaction first")
+
+// queryExecer runs statements on the pool, a connection or a transaction
+type queryExecer interface {
+	sqlx.QueryerContext
+	sqlx.ExecerContext
+}
+
+// Begin starts a transaction that later statements run in
+func (c *BaseConnector) Begin() error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	if c.tx != nil {
+		return fmt.Errorf("a transaction is already open")
+	}
+	var tx *sqlx.Tx
+	var err error
+	if c.pinned != nil {
+		// Temporary tables are only visible on the pinned connection
+		tx, err = c.pinned.BeginTxx(context.Background(), nil)
+	} else {
+		tx, err = c.db.BeginTxx(context.Background(), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("begin error: %w", err)
+	}
+	c.tx = tx
+	return nil
+}
+
+// Commit commits the open transaction
+func (c *BaseConnector) Commit() error {
+	if c.tx == nil {
+		return fmt.Errorf("no transaction is open")
+	}
+	tx := c.tx
+	c.tx = nil
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit error: %w", err)
+	}
+	return nil
+}
+
+// Rollback rolls back the open transaction
+func (c *BaseConnector) Rollback() error {
+	if c.tx == nil {
+		return fmt.Errorf("no transaction is open")
+	}
+	tx := c.tx
+	c.tx = nil
+	if err := tx.Rollback(); err != nil {
+		return fmt.Errorf("rollback error: %w", err)
+	}
+	return nil
+}
+
+// InTransaction reports whether a transaction is open
+func (c *BaseConnector) InTransaction() bool {
+	return c.tx != nil
+}
+
+// session returns where statements run: the open transaction, the pinned
+// connection or the pool
+func (c *BaseConnector) session() queryExecer {
+	if c.tx != nil {
+		return c.tx
+	}
+	if c.pinned != nil {
+		return c.pinned
+	}
+	return c.db
+}
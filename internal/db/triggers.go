@@ -0,0 +1,30 @@
+package db
+
+// Trigger is a trigger defined on a table
+type Trigger struct {
+	Name    string
+	Timing  string   // BEFORE, AFTER or INSTEAD OF
+	Events  []string // INSERT, UPDATE, DELETE or TRUNCATE
+	ForEach string   // ROW or STATEMENT
+	Enabled bool
+	// Definition is the CREATE TRIGGER statement, when the database keeps it
+	Definition string
+	// Body is what the trigger runs: the function source for Postgres, the
+	// statement for MySQL. SQLite bodies are part of Definition.
+	Body string
+}
+
+// TriggerLister is implemented by connectors that can read triggers
+type TriggerLister interface {
+	// GetTriggers returns the triggers of a table, by name
+	GetTriggers(tableName string) ([]Trigger, error)
+}
+
+// GetTriggers returns the table's triggers for connectors that support
+// them, or none for those that don't
+func GetTriggers(c Connector, tableName string) ([]Trigger, error) {
+	if tl, ok := c.(TriggerLister); ok {
+		return tl.GetTriggers(tableName)
+	}
+	return nil, nil
+}
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// Tunnel forwards a local port to a database host through a chain of SSH hops
+type Tunnel struct {
+	clients  []*ssh.Client
+	listener net.Listener
+	target   string
+	wg       sync.WaitGroup
+}
+
+// OpenTunnel connects through each hop in order and listens on a local port
+// that forwards to target (host:port) from the last hop. ctx bounds opening
+// the tunnel, not its lifetime.
+func OpenTunnel(ctx context.Context, hops []config.SSHHop, target string) (*Tunnel, error) {
+	if len(hops) == 0 {
+		return nil, fmt.Errorf("no SSH hops configured")
+	}
+
+	t := &Tunnel{target: target}
+	for i, hop := range hops {
+		clientConfig, agentConn, err := sshClientConfig(hop)
+		if err != nil {
+			t.Close()
+			return nil, fmt.Errorf("ssh hop %d (%s): %w", i+1, hop.Host, err)
+		}
+
+		client, err := t.dialHop(ctx, hop.Address(), clientConfig)
+		// The agent is only asked for signatures during the handshake
+		if agentConn != nil {
+			agentConn.Close()
+		}
+		if err != nil {
+			t.Close()
+			return nil, fmt.Errorf("ssh hop %d (%s): %w", i+1, hop.Host, err)
+		}
+		t.clients = append(t.clients, client)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Close()
+		return nil, fmt.Errorf("failed to open local tunnel port: %w", err)
+	}
+	t.listener = listener
+
+	t.wg.Add(1)
+	go t.serve()
+	return t, nil
+}
+
+// dialHop connects to the next hop, directly for the first hop and through
+// the previous hop's connection for the rest
+func (t *Tunnel) dialHop(ctx context.Context, addr string, clientConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	var conn net.Conn
+	var err error
+	if len(t.clients) == 0 {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = t.clients[len(t.clients)-1].DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The handshake takes no context; a deadline stands in for it
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// sshClientConfig builds auth and host key checking for a hop. It also
+// returns the connection to ssh-agent when one is used, for the caller to
+// close once the hop is authenticated.
+func sshClientConfig(hop config.SSHHop) (*ssh.ClientConfig, net.Conn, error) {
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if !hop.InsecureIgnoreHostKey {
+		path := hop.KnownHostsFile
+		if path == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get home directory: %w", err)
+			}
+			path = filepath.Join(homeDir, ".ssh", "known_hosts")
+		}
+		callback, err := knownhosts.New(config.ExpandPath(path))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load known hosts: %w", err)
+		}
+		hostKeyCallback = callback
+	}
+
+	var auth []ssh.AuthMethod
+
+	if hop.KeyFile != "" {
+		key, err := os.ReadFile(config.ExpandPath(hop.KeyFile))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		var signer ssh.Signer
+		if hop.KeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(hop.KeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse key file: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	// Dialed last, so no error below leaves it open
+	var agentConn net.Conn
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			agentConn = conn
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	if hop.Password != "" {
+		auth = append(auth, ssh.Password(hop.Password))
+	}
+
+	if len(auth) == 0 {
+		return nil, nil, fmt.Errorf("no auth method (set key_file, password or run ssh-agent)")
+	}
+
+	return &ssh.ClientConfig{
+		User:            hop.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         10 * time.Second,
+	}, agentConn, nil
+}
+
+// serve accepts local connections and forwards them to the target
+func (t *Tunnel) serve() {
+	defer t.wg.Done()
+	last := t.clients[len(t.clients)-1]
+
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			return // Listener closed
+		}
+
+		go func() {
+			defer local.Close()
+			remote, err := last.Dial("tcp", t.target)
+			if err != nil {
+				return
+			}
+			defer remote.Close()
+
+			done := make(chan struct{}, 2)
+			go func() {
+				io.Copy(remote, local)
+				done <- struct{}{}
+			}()
+			go func() {
+				io.Copy(local, remote)
+				done <- struct{}{}
+			}()
+			<-done
+		}()
+	}
+}
+
+// LocalAddr returns the local host and port that forward to the target
+func (t *Tunnel) LocalAddr() (string, int) {
+	addr := t.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// Close stops forwarding and disconnects all hops, innermost first
+func (t *Tunnel) Close() error {
+	if t.listener != nil {
+		t.listener.Close()
+		t.wg.Wait()
+	}
+	for i := len(t.clients) - 1; i >= 0; i-- {
+		t.clients[i].Close()
+	}
+	t.clients = nil
+	return nil
+}
+
+// dialTarget returns the host and port to connect to, opening an SSH tunnel
+// first when the connection has hops configured
+func (c *BaseConnector) dialTarget(ctx context.Context) (string, int, error) {
+	if len(c.config.SSHHops) == 0 {
+		return c.config.Host, c.config.Port, nil
+	}
+	if c.config.UsesSocket() {
+		return "", 0, fmt.Errorf("SSH tunneling needs a TCP host, not a unix socket")
+	}
+
+	if c.tunnel == nil {
+		target := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+		tunnel, err := OpenTunnel(ctx, c.config.SSHHops, target)
+		if err != nil {
+			return "", 0, err
+		}
+		c.tunnel = tunnel
+	}
+
+	host, port := c.tunnel.LocalAddr()
+	return host, port, nil
+}
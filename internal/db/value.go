@@ -0,0 +1,103 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Value is one scanned cell. SQL NULL is kept apart from empty strings and
+// the text "NULL" so the results grid and copy/export can tell them apart.
+type Value struct {
+	Data interface{} // string, int64, uint64, float64, Decimal, bool, time.Time, []byte, ...; nil when Null
+	Null bool
+}
+
+// Decimal is the exact text of a NUMERIC/DECIMAL value. Keeping the text
+// avoids float rounding of money amounts and wide integers.
+type Decimal string
+
+// decimalRe matches plain decimal numbers as databases print them
+var decimalRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// Float converts to float64 for charts and aggregates, which may round
+func (d Decimal) Float() (float64, bool) {
+	f, err := strconv.ParseFloat(string(d), 64)
+	return f, err == nil
+}
+
+// Row is one result row keyed by column name
+type Row map[string]Value
+
+// Text formats the value by its column type as the grid, copy and export
+// show it. NULL is empty.
+func (v Value) Text(colType ColumnType) string {
+	if v.Null {
+		return ""
+	}
+	switch d := v.Data.(type) {
+	case []byte:
+		return string(d)
+	case time.Time:
+		switch {
+		case colType.Kind == ColumnDate:
+			return d.Format("2006-01-02")
+		case d.Year() == 0 && d.YearDay() == 1:
+			// TIME columns arrive as a time on year zero
+			return d.Format("15:04:05.999999")
+		case d.Location() == time.UTC:
+			return d.Format("2006-01-02 15:04:05.999999")
+		default:
+			return d.Format("2006-01-02 15:04:05.999999 -07:00")
+		}
+	case Decimal:
+		// Exact text from the database, never parsed
+		return string(d)
+	case float32:
+		return strconv.FormatFloat(float64(d), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(d, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v.Data)
+}
+
+// NewValue wraps a driver value; nil is NULL
+func NewValue(v interface{}) Value {
+	if v == nil {
+		return Value{Null: true}
+	}
+	return Value{Data: v}
+}
+
+// String formats the value as text, with NULL shown as "NULL". Callers that
+// must not confuse NULL with the text "NULL" check Null first.
+func (v Value) String() string {
+	if v.Null {
+		return "NULL"
+	}
+	if b, ok := v.Data.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v.Data)
+}
+
+// scannedValue converts a value from sqlx MapScan. Drivers return text as
+// []byte; it is kept as bytes for binary columns and for untyped values
+// that aren't valid UTF-8 (SQLite blob expressions).
+func scannedValue(v interface{}, kind ColumnKind) Value {
+	if b, ok := v.([]byte); ok && kind != ColumnBinary && utf8.Valid(b) {
+		return textValue(string(b), kind)
+	}
+	return NewValue(v)
+}
+
+// textValue wraps text, keeping numbers of numeric columns (NUMERIC,
+// DECIMAL, BIGNUMERIC) as exact decimals
+func textValue(s string, kind ColumnKind) Value {
+	if kind == ColumnNumber && decimalRe.MatchString(s) {
+		return Value{Data: Decimal(s)}
+	}
+	return Value{Data: s}
+}
@@ -0,0 +1,24 @@
+package db
+
+// View is a view or materialized view
+type View struct {
+	Name         string
+	Materialized bool
+}
+
+// ViewLister is implemented by connectors that can list views
+type ViewLister interface {
+	// GetViews returns the views of the current database, by name
+	GetViews() ([]View, error)
+	// GetViewDefinition returns the SQL a view is defined by
+	GetViewDefinition(name string) (string, error)
+}
+
+// GetViews returns the views for connectors that support them, or none for
+// those that don't
+func GetViews(c Connector) ([]View, error) {
+	if vl, ok := c.(ViewLister); ok {
+		return vl.GetViews()
+	}
+	return nil, nil
+}
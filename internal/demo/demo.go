@@ -0,0 +1,206 @@
+// Package demo creates a small SQLite database of a shop, with customers,
+// products and orders, for trying SQDesk without a server.
+package demo
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// ConnectionName is the name of the demo connection
+const ConnectionName = "SQDesk Demo"
+
+// schema creates the demo tables
+const schema = `
+CREATE TABLE customers (
+	id         INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	email      TEXT NOT NULL UNIQUE,
+	country    TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+CREATE TABLE products (
+	id       INTEGER PRIMARY KEY,
+	name     TEXT NOT NULL,
+	category TEXT NOT NULL,
+	price    DECIMAL(10,2) NOT NULL,
+	active   BOOLEAN NOT NULL DEFAULT 1
+);
+CREATE TABLE orders (
+	id          INTEGER PRIMARY KEY,
+	customer_id INTEGER NOT NULL REFERENCES customers(id),
+	status      TEXT NOT NULL,
+	note        TEXT,
+	created_at  TIMESTAMP NOT NULL
+);
+CREATE TABLE order_items (
+	order_id   INTEGER NOT NULL REFERENCES orders(id),
+	product_id INTEGER NOT NULL REFERENCES products(id),
+	quantity   INTEGER NOT NULL,
+	unit_price DECIMAL(10,2) NOT NULL,
+	PRIMARY KEY (order_id, product_id)
+);
+CREATE INDEX orders_customer_id ON orders(customer_id);
+CREATE INDEX orders_created_at ON orders(created_at);
+CREATE VIEW order_totals AS
+	SELECT o.id AS order_id, c.name AS customer, o.status, o.created_at,
+	       SUM(i.quantity * i.unit_price) AS total
+	FROM orders o
+	JOIN customers c ON c.id = o.customer_id
+	JOIN order_items i ON i.order_id = o.id
+	GROUP BY o.id;
+`
+
+// Sample values the rows are made of
+var (
+	firstNames = []string{"Ana", "Ben", "Chloé", "Dmitri", "Emma", "Farid", "Grace", "Hiro", "Ines", "Jonas", "Kai", "Lena", "Mateo", "Nora", "Omar", "Priya"}
+	lastNames  = []string{"Silva", "Novak", "Martin", "Ivanov", "Brown", "Haddad", "Lee", "Tanaka", "García", "Berg", "Müller", "Rossi"}
+	countries  = []string{"Brazil", "Czechia", "France", "Germany", "India", "Italy", "Japan", "Spain", "United Kingdom", "United States"}
+	statuses   = []string{"paid", "paid", "paid", "shipped", "shipped", "delivered", "delivered", "delivered", "cancelled", "refunded"}
+	products   = []struct {
+		name, category string
+		price          float64
+	}{
+		{"Mechanical Keyboard", "Hardware", 89.90},
+		{"Wireless Mouse", "Hardware", 24.50},
+		{"27\" Monitor", "Hardware", 279.00},
+		{"USB-C Dock", "Hardware", 129.99},
+		{"Laptop Stand", "Accessories", 39.00},
+		{"Desk Mat", "Accessories", 19.90},
+		{"Cable Kit", "Accessories", 12.00},
+		{"Noise-Cancelling Headphones", "Audio", 199.00},
+		{"USB Microphone", "Audio", 99.00},
+		{"SQL Pocket Guide", "Books", 14.99},
+		{"Database Internals", "Books", 49.95},
+		{"Team License", "Software", 480.00},
+	}
+)
+
+// Path returns where the demo database is kept
+func Path() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "demo.db"), nil
+}
+
+// Connection returns the connection to the demo database at path
+func Connection(path string) config.DatabaseConfig {
+	return config.DatabaseConfig{Name: ConnectionName, Driver: "sqlite", Database: path}
+}
+
+// Create writes the demo database to path, replacing an older one. Orders
+// span the year before now, so time filters and charts have data.
+func Create(path string, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("demo: %w", err)
+	}
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	if err := fill(tmp, now); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("demo: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("demo: %w", err)
+	}
+	return nil
+}
+
+// fill creates the tables of a new database file and inserts the rows
+func fill(path string, now time.Time) error {
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(schema); err != nil {
+		return err
+	}
+
+	// A fixed seed gives everyone the same data
+	rnd := rand.New(rand.NewSource(42))
+	start := now.AddDate(-1, 0, 0)
+	stamp := func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05") }
+	between := func(from, to time.Time) time.Time {
+		return from.Add(time.Duration(rnd.Int63n(int64(to.Sub(from)))))
+	}
+
+	for i, p := range products {
+		active := i != 6 // one retired product
+		if _, err := tx.Exec("INSERT INTO products (id, name, category, price, active) VALUES (?, ?, ?, ?, ?)",
+			i+1, p.name, p.category, p.price, active); err != nil {
+			return err
+		}
+	}
+
+	const customerCount = 60
+	joined := make([]time.Time, customerCount)
+	for i := range joined {
+		first := firstNames[rnd.Intn(len(firstNames))]
+		last := lastNames[rnd.Intn(len(lastNames))]
+		email := fmt.Sprintf("%s.%s%d@example.com", asciiLower(first), asciiLower(last), i+1)
+		joined[i] = between(start, now.AddDate(0, -1, 0))
+		if _, err := tx.Exec("INSERT INTO customers (id, name, email, country, created_at) VALUES (?, ?, ?, ?, ?)",
+			i+1, first+" "+last, email, countries[rnd.Intn(len(countries))], stamp(joined[i])); err != nil {
+			return err
+		}
+	}
+
+	const orderCount = 400
+	for id := 1; id <= orderCount; id++ {
+		customer := rnd.Intn(customerCount)
+		at := between(joined[customer], now)
+		var note interface{}
+		if rnd.Intn(8) == 0 {
+			note = []string{"gift wrap", "leave at the door", "invoice to company", "call before delivery"}[rnd.Intn(4)]
+		}
+		if _, err := tx.Exec("INSERT INTO orders (id, customer_id, status, note, created_at) VALUES (?, ?, ?, ?, ?)",
+			id, customer+1, statuses[rnd.Intn(len(statuses))], note, stamp(at)); err != nil {
+			return err
+		}
+
+		for _, p := range rnd.Perm(len(products))[:1+rnd.Intn(3)] {
+			if _, err := tx.Exec("INSERT INTO order_items (order_id, product_id, quantity, unit_price) VALUES (?, ?, ?, ?)",
+				id, p+1, 1+rnd.Intn(3), products[p].price); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// asciiLower lowercases a name for an email address, dropping accents
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'é':
+			return 'e'
+		case 'í':
+			return 'i'
+		case 'ü':
+			return 'u'
+		}
+		if r > 127 {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
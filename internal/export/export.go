@@ -0,0 +1,308 @@
+// Package export streams every row of a query to CSV, JSON or a Markdown
+// table, optionally masking sensitive columns, without holding the result
+// in memory.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/mask"
+)
+
+// Format is the file format of an export
+type Format string
+
+const (
+	// CSV writes a header and one record per row; NULL is empty
+	CSV Format = "csv"
+	// JSON writes an array with one object per row, keys in column order
+	JSON Format = "json"
+	// Markdown writes a GitHub-flavored Markdown table; numeric columns
+	// are right-aligned and NULL is written as NULL
+	Markdown Format = "md"
+)
+
+// MaskRule masks the columns whose name matches its pattern
+type MaskRule = config.MaskRule
+
+// Options configure an export
+type Options struct {
+	Format Format
+	// Mask lists the rules for columns to mask; the first matching rule
+	// applies
+	Mask []MaskRule
+}
+
+// Result sums up a finished export
+type Result struct {
+	Rows          int64
+	MaskedColumns int
+}
+
+// ValidateMask reports the first invalid rule of rules
+func ValidateMask(rules []MaskRule) error {
+	_, err := mask.Columns(rules, nil)
+	return err
+}
+
+// Write runs sql on streamer and writes every row to w. Values are
+// formatted like copied cells; NULL is never masked.
+func Write(ctx context.Context, streamer db.RowStreamer, sql string, w io.Writer, opts Options) (Result, error) {
+	enc, err := newEncoder(w, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	err = streamer.StreamQuery(ctx, sql, enc.header, enc.row)
+	return enc.close(err)
+}
+
+// WriteResultSet writes the rows of a result set already in memory to w,
+// like Write
+func WriteResultSet(w io.Writer, set db.ResultSet, opts Options) (Result, error) {
+	enc, err := newEncoder(w, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	types := make([]db.ColumnType, len(set.Columns))
+	for i, name := range set.Columns {
+		if i < len(set.ColumnTypes) {
+			types[i] = set.ColumnTypes[i]
+		}
+		types[i].Name = name
+	}
+	err = enc.header(types)
+	values := make([]db.Value, len(set.Columns))
+	for _, row := range set.Rows {
+		if err != nil {
+			break
+		}
+		for i, name := range set.Columns {
+			values[i] = row[name]
+		}
+		err = enc.row(values)
+	}
+	return enc.close(err)
+}
+
+// encoder formats and masks rows for a rowWriter
+type encoder struct {
+	rw     rowWriter
+	rules  []MaskRule
+	types  []db.ColumnType
+	masks  []mask.Func
+	texts  []string
+	masked []bool
+	result Result
+}
+
+func newEncoder(w io.Writer, opts Options) (*encoder, error) {
+	enc := &encoder{rules: opts.Mask}
+	switch opts.Format {
+	case JSON:
+		enc.rw = &jsonWriter{w: bufio.NewWriter(w)}
+	case Markdown:
+		enc.rw = &markdownWriter{w: bufio.NewWriter(w)}
+	case CSV, "":
+		enc.rw = &csvWriter{w: csv.NewWriter(w)}
+	default:
+		return nil, fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	return enc, nil
+}
+
+// header picks the mask of each column and writes the header
+func (e *encoder) header(columns []db.ColumnType) error {
+	e.types = columns
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	var err error
+	if e.masks, err = mask.Columns(e.rules, names); err != nil {
+		return err
+	}
+	for _, fn := range e.masks {
+		if fn != nil {
+			e.result.MaskedColumns++
+		}
+	}
+	e.texts = make([]string, len(columns))
+	e.masked = make([]bool, len(columns))
+	return e.rw.header(columns)
+}
+
+// row formats, masks and writes one row
+func (e *encoder) row(values []db.Value) error {
+	for i, v := range values {
+		e.texts[i] = v.Text(e.types[i])
+		// NULL stays NULL: it gives nothing away
+		e.masked[i] = e.masks[i] != nil && !v.Null
+		if e.masked[i] {
+			e.texts[i] = e.masks[i](e.texts[i])
+		}
+	}
+	e.result.Rows++
+	return e.rw.row(values, e.texts, e.masked)
+}
+
+// close finishes the output, returning err or else the error of the
+// final write
+func (e *encoder) close(err error) (Result, error) {
+	if closeErr := e.rw.close(); err == nil {
+		err = closeErr
+	}
+	return e.result, err
+}
+
+// rowWriter writes exported rows in one file format. Values arrive
+// formatted like copied cells, masked where a rule applies.
+type rowWriter interface {
+	header(columns []db.ColumnType) error
+	row(values []db.Value, texts []string, masked []bool) error
+	close() error
+}
+
+// csvWriter writes a header and one record per row (NULL is empty)
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) header(columns []db.ColumnType) error {
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = col.Name
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) row(_ []db.Value, texts []string, _ []bool) error {
+	return c.w.Write(texts)
+}
+
+func (c *csvWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter writes an array with one object per row, keys in column
+// order. NULL is null, booleans and numbers stay unquoted unless masked.
+type jsonWriter struct {
+	w       *bufio.Writer
+	keys    [][]byte
+	types   []db.ColumnType
+	started bool
+}
+
+func (j *jsonWriter) header(columns []db.ColumnType) error {
+	j.types = columns
+	j.keys = make([][]byte, len(columns))
+	for i, col := range columns {
+		key, err := json.Marshal(col.Name)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = key
+	}
+	_, err := j.w.WriteString("[")
+	return err
+}
+
+func (j *jsonWriter) row(values []db.Value, texts []string, masked []bool) error {
+	if j.started {
+		j.w.WriteString(",")
+	}
+	j.started = true
+	j.w.WriteString("\n  {")
+	for i, v := range values {
+		if i > 0 {
+			j.w.WriteString(", ")
+		}
+		j.w.Write(j.keys[i])
+		j.w.WriteString(": ")
+		value, err := j.value(v, texts[i], masked[i], j.types[i])
+		if err != nil {
+			return err
+		}
+		j.w.Write(value)
+	}
+	_, err := j.w.WriteString("}")
+	return err
+}
+
+// value encodes one value
+func (j *jsonWriter) value(v db.Value, text string, masked bool, colType db.ColumnType) ([]byte, error) {
+	switch {
+	case v.Null:
+		return []byte("null"), nil
+	case masked:
+		return json.Marshal(text)
+	}
+	if b, ok := v.Data.(bool); ok {
+		return json.Marshal(b)
+	}
+	if colType.IsNumeric() && json.Valid([]byte(text)) {
+		return []byte(text), nil
+	}
+	return json.Marshal(text)
+}
+
+func (j *jsonWriter) close() error {
+	// Bufio errors stick, so Flush reports any failed write
+	if j.started {
+		j.w.WriteString("\n")
+	}
+	j.w.WriteString("]\n")
+	return j.w.Flush()
+}
+
+// markdownWriter writes a GitHub-flavored Markdown table
+type markdownWriter struct {
+	w *bufio.Writer
+}
+
+// markdownEscaper keeps values on one line and inside their cell
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func (m *markdownWriter) header(columns []db.ColumnType) error {
+	names := make([]string, len(columns))
+	rule := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = markdownEscaper.Replace(col.Name)
+		rule[i] = "---"
+		if col.IsNumeric() {
+			rule[i] = "---:"
+		}
+	}
+	m.line(names)
+	return m.line(rule)
+}
+
+func (m *markdownWriter) row(values []db.Value, texts []string, _ []bool) error {
+	cells := make([]string, len(values))
+	for i, v := range values {
+		if v.Null {
+			cells[i] = "NULL"
+			continue
+		}
+		cells[i] = markdownEscaper.Replace(texts[i])
+	}
+	return m.line(cells)
+}
+
+// line writes one table row
+func (m *markdownWriter) line(cells []string) error {
+	_, err := m.w.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	return err
+}
+
+func (m *markdownWriter) close() error {
+	return m.w.Flush()
+}
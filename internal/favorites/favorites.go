@@ -0,0 +1,166 @@
+// Package favorites keeps named queries saved from the editor in a JSON file
+// in the config directory, so they can be loaded and run again later.
+package favorites
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// fileName is the favorites file in the config directory
+const fileName = "favorites.json"
+
+// Favorite is a saved query. A favorite with a Connection is only offered
+// while that connection is active; one without is offered everywhere.
+type Favorite struct {
+	Name       string    `json:"name"`
+	Query      string    `json:"query"`
+	Tags       []string  `json:"tags,omitempty"`
+	Connection string    `json:"connection,omitempty"`
+	Saved      time.Time `json:"saved"`
+}
+
+// Store is the favorites file
+type Store struct {
+	path string
+}
+
+// NewStore opens the favorites file in the config directory
+func NewStore() (*Store, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: filepath.Join(dir, fileName)}, nil
+}
+
+// Path returns the favorites file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// List returns the favorites offered for connection, sorted by name; all of
+// them when connection is ""
+func (s *Store) List(connection string) ([]Favorite, error) {
+	favs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var out []Favorite
+	for _, f := range favs {
+		if connection == "" || f.Connection == "" || f.Connection == connection {
+			out = append(out, f)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out, nil
+}
+
+// Save adds f, replacing the favorite of the same name and connection
+func (s *Store) Save(f Favorite) error {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" {
+		return fmt.Errorf("favorites: a favorite needs a name")
+	}
+	favs, err := s.load()
+	if err != nil {
+		return err
+	}
+	if i := find(favs, f.Name, f.Connection); i >= 0 {
+		favs[i] = f
+	} else {
+		favs = append(favs, f)
+	}
+	return s.write(favs)
+}
+
+// Delete removes the favorite of name and connection
+func (s *Store) Delete(name, connection string) error {
+	favs, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := find(favs, name, connection)
+	if i < 0 {
+		return fmt.Errorf("favorites: %q not found", name)
+	}
+	return s.write(append(favs[:i], favs[i+1:]...))
+}
+
+// Rescope moves the favorite of name and connection to the connection to,
+// "" for all connections, replacing a favorite of the same name there
+func (s *Store) Rescope(name, connection, to string) error {
+	favs, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := find(favs, name, connection)
+	if i < 0 {
+		return fmt.Errorf("favorites: %q not found", name)
+	}
+	f := favs[i]
+	f.Connection = to
+	favs = append(favs[:i], favs[i+1:]...)
+	if j := find(favs, name, to); j >= 0 {
+		favs[j] = f
+	} else {
+		favs = append(favs, f)
+	}
+	return s.write(favs)
+}
+
+// find returns the index of the favorite of name and connection, -1 if none
+func find(favs []Favorite, name, connection string) int {
+	for i, f := range favs {
+		if strings.EqualFold(f.Name, name) && f.Connection == connection {
+			return i
+		}
+	}
+	return -1
+}
+
+// load reads the file; a missing file holds no favorites
+func (s *Store) load() ([]Favorite, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("favorites: %w", err)
+	}
+	var favs []Favorite
+	if err := json.Unmarshal(data, &favs); err != nil {
+		return nil, fmt.Errorf("favorites: %s: %w", s.path, err)
+	}
+	return favs, nil
+}
+
+// write replaces the file. It is readable by the owner only since queries
+// may hold data.
+func (s *Store) write(favs []Favorite) error {
+	data, err := json.MarshalIndent(favs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("favorites: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("favorites: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("favorites: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("favorites: %w", err)
+	}
+	return nil
+}
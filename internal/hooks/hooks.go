@@ -0,0 +1,170 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// hookTimeout bounds how long a command or webhook may take
+const hookTimeout = 30 * time.Second
+
+// Event describes a finished query. It is the JSON body sent to hooks.
+type Event struct {
+	Connection string `json:"connection"`
+	Driver     string `json:"driver"`
+	Query      string `json:"query"`
+	Status     string `json:"status"` // ok, error or timeout
+	// ExitStatus is 0 when the query succeeded and 1 otherwise
+	ExitStatus int    `json:"exit_status"`
+	DurationMs int64  `json:"duration_ms"`
+	Rows       int64  `json:"rows"` // rows returned or affected
+	Error      string `json:"error,omitempty"`
+	// Text is a one-line summary for chat webhooks
+	Text string `json:"text"`
+}
+
+// NewEvent builds an event and its summary text
+func NewEvent(connection, driver, query, status string, duration time.Duration, rows int64, err error) Event {
+	ev := Event{
+		Connection: connection,
+		Driver:     driver,
+		Query:      query,
+		Status:     status,
+		DurationMs: duration.Milliseconds(),
+		Rows:       rows,
+	}
+	if err != nil {
+		ev.ExitStatus = 1
+		ev.Error = err.Error()
+	}
+
+	elapsed := duration.Round(time.Second)
+	if duration < time.Second {
+		elapsed = duration.Round(time.Millisecond)
+	}
+	if err != nil {
+		ev.Text = fmt.Sprintf("SQDesk: query on %s failed after %s: %s", connection, elapsed, ev.Error)
+	} else {
+		ev.Text = fmt.Sprintf("SQDesk: query on %s finished in %s (%d rows)", connection, elapsed, rows)
+	}
+	return ev
+}
+
+// Runner runs the configured hooks
+type Runner struct {
+	cfg    config.HooksConfig
+	client *http.Client
+}
+
+// NewRunner creates a runner for the hooks in cfg
+func NewRunner(cfg config.HooksConfig) *Runner {
+	return &Runner{
+		cfg:    cfg,
+		client: &http.Client{Timeout: hookTimeout},
+	}
+}
+
+// Wants reports whether a query that took duration should run the hooks
+func (r *Runner) Wants(duration time.Duration) bool {
+	if r.cfg.Command == "" && r.cfg.Webhook == "" {
+		return false
+	}
+	return duration >= time.Duration(r.cfg.MinDuration)*time.Second
+}
+
+// Run runs the command and posts the webhook, returning the errors of both
+func (r *Runner) Run(ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("hook: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	var errs []error
+	if r.cfg.Command != "" {
+		if err := r.runCommand(ctx, ev, body); err != nil {
+			errs = append(errs, fmt.Errorf("hook command: %w", err))
+		}
+	}
+	if r.cfg.Webhook != "" {
+		if err := r.postWebhook(ctx, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runCommand runs the hook command with the event on stdin and in the
+// environment
+func (r *Runner) runCommand(ctx context.Context, ev Event, body []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", r.cfg.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", r.cfg.Command)
+	}
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"SQDESK_CONNECTION="+ev.Connection,
+		"SQDESK_DRIVER="+ev.Driver,
+		"SQDESK_STATUS="+ev.Status,
+		"SQDESK_EXIT_STATUS="+strconv.Itoa(ev.ExitStatus),
+		"SQDESK_DURATION_MS="+strconv.FormatInt(ev.DurationMs, 10),
+		"SQDESK_ROWS="+strconv.FormatInt(ev.Rows, 10),
+		"SQDESK_ERROR="+ev.Error,
+		"SQDESK_TEXT="+ev.Text,
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, firstLine(msg))
+		}
+		return err
+	}
+	return nil
+}
+
+// postWebhook posts the event to the webhook URL
+func (r *Runner) postWebhook(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range r.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,499 @@
+// Package lint flags SQL anti-patterns before they run: SELECT * on
+// production connections, UPDATE/DELETE without WHERE, predicates that
+// can't use an index, implicit casts and cartesian joins. It also catches
+// queries bound to fail: unbalanced parentheses and quotes, tables and
+// columns missing from the schema and SELECT lists without FROM.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// Severity ranks findings; Off disables a rule
+type Severity int
+
+const (
+	Off Severity = iota
+	Info
+	Warning
+	Error
+)
+
+// String returns the name used in the config
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return "off"
+}
+
+// Icon returns the marker shown for findings of this severity
+func (s Severity) Icon() string {
+	switch s {
+	case Error:
+		return "✖"
+	case Warning:
+		return "▲"
+	}
+	return "●"
+}
+
+// ParseSeverity parses a severity name from the config
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "off", "none":
+		return Off, nil
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	}
+	return Off, fmt.Errorf("unknown severity %q (use off, info, warning or error)", name)
+}
+
+// Rule names, as used in the config
+const (
+	RuleSelectStar    = "select_star"
+	RuleMissingWhere  = "missing_where"
+	RuleNonSargable   = "non_sargable"
+	RuleImplicitCast  = "implicit_cast"
+	RuleCartesianJoin = "cartesian_join"
+	RuleSyntax        = "syntax"
+	RuleUnknownName   = "unknown_name"
+	RuleMissingFrom   = "missing_from"
+)
+
+// ValidationRules catch queries the server would reject; they run before
+// every query, see Only
+var ValidationRules = []string{RuleSyntax, RuleUnknownName, RuleMissingFrom}
+
+// defaultSeverities are the severities of rules the config doesn't set
+var defaultSeverities = map[string]Severity{
+	RuleSelectStar:    Warning,
+	RuleMissingWhere:  Error,
+	RuleNonSargable:   Warning,
+	RuleImplicitCast:  Info,
+	RuleCartesianJoin: Warning,
+	RuleSyntax:        Error,
+	RuleUnknownName:   Warning,
+	RuleMissingFrom:   Warning,
+}
+
+// Rules returns the names of all rules
+func Rules() []string {
+	names := make([]string, 0, len(defaultSeverities))
+	for name := range defaultSeverities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Severities returns the default severities with the config's overrides
+// applied
+func Severities(overrides map[string]string) (map[string]Severity, error) {
+	severities := make(map[string]Severity, len(defaultSeverities))
+	for name, sev := range defaultSeverities {
+		severities[name] = sev
+	}
+	for name, value := range overrides {
+		name = strings.ToLower(name)
+		if _, ok := defaultSeverities[name]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		sev, err := ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("lint rule %s: %w", name, err)
+		}
+		severities[name] = sev
+	}
+	return severities, nil
+}
+
+// Only returns severities with all rules but names turned off
+func Only(severities map[string]Severity, names ...string) map[string]Severity {
+	only := make(map[string]Severity, len(severities))
+	for name := range severities {
+		only[name] = Off
+	}
+	for _, name := range names {
+		only[name] = severities[name]
+	}
+	return only
+}
+
+// Finding is one flagged pattern
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	Offset   int // byte offset into the linted SQL
+}
+
+// Options configure a lint pass
+type Options struct {
+	// Severities of the rules, from Severities; nil uses the defaults
+	Severities map[string]Severity
+	// Production enables the SELECT * rule
+	Production bool
+	// Columns maps lower-case column names to their kind for the implicit
+	// cast rule; names with different kinds across tables are left out
+	Columns map[string]db.ColumnKind
+	// Tables maps lower-case table names to their lower-case column names
+	// for the unknown name rule; nil skips it
+	Tables map[string]map[string]bool
+}
+
+// Check lints every statement of sql, returning findings in order
+func Check(sql string, dialect sqlparse.Dialect, opts Options) []Finding {
+	if opts.Severities == nil {
+		opts.Severities, _ = Severities(nil)
+	}
+	var findings []Finding
+	for _, stmt := range sqlparse.Split(sql, dialect) {
+		l := &linter{opts: opts, base: stmt.Start}
+		for _, tok := range sqlparse.Tokenize(stmt.Text, dialect) {
+			if sqlparse.Unterminated(tok, dialect) {
+				l.unterminated(tok)
+			}
+			if tok.Kind == sqlparse.TokenSpace || tok.Kind == sqlparse.TokenComment {
+				continue
+			}
+			depth := 0
+			if n := len(l.toks); n > 0 {
+				depth = l.toks[n-1].depth
+				if l.toks[n-1].Text == "(" {
+					depth++
+				}
+			}
+			if tok.Text == ")" {
+				depth--
+			}
+			l.toks = append(l.toks, token{Token: tok, depth: depth})
+		}
+		l.selectStar()
+		l.missingWhere()
+		l.joins()
+		l.predicates()
+		l.parens()
+		l.missingFrom()
+		l.unknownNames()
+		sort.SliceStable(l.findings, func(i, j int) bool { return l.findings[i].Offset < l.findings[j].Offset })
+		findings = append(findings, l.findings...)
+	}
+	return findings
+}
+
+// token is a significant token with its parenthesis depth
+type token struct {
+	sqlparse.Token
+	depth int
+}
+
+// linter checks the tokens of one statement
+type linter struct {
+	opts     Options
+	base     int // offset of the statement in the linted SQL
+	toks     []token
+	findings []Finding
+}
+
+// report adds a finding at tok unless its rule is off
+func (l *linter) report(rule string, tok token, format string, args ...interface{}) {
+	l.reportAt(rule, tok.Token, format, args...)
+}
+
+// reportAt adds a finding at a token that isn't in toks, e.g. a comment
+func (l *linter) reportAt(rule string, tok sqlparse.Token, format string, args ...interface{}) {
+	sev := l.opts.Severities[rule]
+	if sev == Off {
+		return
+	}
+	l.findings = append(l.findings, Finding{Rule: rule, Severity: sev, Message: fmt.Sprintf(format, args...), Offset: l.base + tok.Start})
+}
+
+// kw returns the keyword of the token at i, or "" out of range
+func (l *linter) kw(i int) string {
+	if i < 0 || i >= len(l.toks) {
+		return ""
+	}
+	return l.toks[i].Keyword()
+}
+
+// text returns the text of the token at i, or "" out of range
+func (l *linter) text(i int) string {
+	if i < 0 || i >= len(l.toks) {
+		return ""
+	}
+	return l.toks[i].Text
+}
+
+// clauseEnd are keywords that end a FROM list or a WHERE/ON condition
+var clauseEnd = map[string]bool{
+	"where": true, "group": true, "order": true, "limit": true, "having": true,
+	"union": true, "intersect": true, "except": true, "window": true,
+	"returning": true, "offset": true, "fetch": true, "set": true,
+}
+
+// joinWords start or qualify a join and end an ON condition
+var joinWords = map[string]bool{
+	"join": true, "left": true, "right": true, "inner": true, "full": true,
+	"cross": true, "natural": true, "outer": true, "straight_join": true,
+}
+
+// selectStar flags SELECT * and SELECT t.* on production connections
+func (l *linter) selectStar() {
+	if !l.opts.Production {
+		return
+	}
+	for i, tok := range l.toks {
+		if tok.Text != "*" {
+			continue
+		}
+		prev := i - 1
+		if l.text(prev) == "." {
+			// t.*
+			prev -= 2
+		}
+		switch l.kw(prev) {
+		case "select", "distinct", "all":
+		default:
+			if l.text(prev) != "," {
+				continue
+			}
+		}
+		l.report(RuleSelectStar, tok, "SELECT * on a production connection; list the columns you need")
+		return
+	}
+}
+
+// missingWhere flags UPDATE and DELETE statements without WHERE
+func (l *linter) missingWhere() {
+	start := -1
+	for i, tok := range l.toks {
+		if tok.depth != 0 {
+			continue
+		}
+		if kw := tok.Keyword(); kw == "update" || kw == "delete" {
+			start = i
+			break
+		}
+		if i == 0 && tok.Keyword() != "with" && tok.Text != "(" {
+			return
+		}
+	}
+	if start < 0 {
+		return
+	}
+	for _, tok := range l.toks[start:] {
+		if tok.depth == 0 && tok.Keyword() == "where" {
+			return
+		}
+	}
+	verb := strings.ToUpper(l.kw(start))
+	effect := "changes"
+	if verb == "DELETE" {
+		effect = "deletes"
+	}
+	l.report(RuleMissingWhere, l.toks[start], "%s without WHERE %s every row of the table", verb, effect)
+}
+
+// joins flags comma-joined tables without WHERE and JOINs without ON or
+// USING; CROSS and NATURAL joins are taken as intended
+func (l *linter) joins() {
+	for i, tok := range l.toks {
+		switch tok.Keyword() {
+		case "from":
+			commas, where := 0, false
+			for j := i + 1; j < len(l.toks) && l.toks[j].depth >= tok.depth; j++ {
+				t := l.toks[j]
+				if t.depth != tok.depth {
+					continue
+				}
+				if t.Text == "," {
+					commas++
+				}
+				if kw := t.Keyword(); clauseEnd[kw] {
+					where = kw == "where"
+					break
+				}
+			}
+			if commas > 0 && !where {
+				l.report(RuleCartesianJoin, tok, "Tables listed in FROM without a WHERE condition form a cartesian product")
+			}
+
+		case "join", "straight_join":
+			if q := l.kw(i - 1); q == "cross" || q == "natural" || l.kw(i-2) == "natural" || l.kw(i-3) == "natural" {
+				continue
+			}
+			joined := false
+			for j := i + 1; j < len(l.toks) && l.toks[j].depth >= tok.depth; j++ {
+				t := l.toks[j]
+				if t.depth != tok.depth {
+					continue
+				}
+				kw := t.Keyword()
+				if kw == "on" || kw == "using" {
+					joined = true
+					break
+				}
+				if clauseEnd[kw] || joinWords[kw] || t.Text == "," {
+					break
+				}
+			}
+			if !joined {
+				l.report(RuleCartesianJoin, tok, "JOIN without ON or USING forms a cartesian product")
+			}
+		}
+	}
+}
+
+// notFunctions are words followed by a parenthesis that aren't function
+// calls on a column
+var notFunctions = map[string]bool{
+	"in": true, "exists": true, "not": true, "and": true, "or": true,
+	"any": true, "all": true, "some": true, "select": true, "values": true,
+	"where": true, "on": true, "when": true, "then": true, "else": true,
+	"between": true, "is": true, "like": true, "ilike": true, "as": true,
+}
+
+// predicates flags functions on columns, leading wildcards and implicit
+// casts in WHERE and ON conditions
+func (l *linter) predicates() {
+	for i, tok := range l.toks {
+		if kw := tok.Keyword(); kw != "where" && kw != "on" {
+			continue
+		}
+		end := i + 1
+		for ; end < len(l.toks) && l.toks[end].depth >= tok.depth; end++ {
+			t := l.toks[end]
+			if t.depth != tok.depth {
+				continue
+			}
+			if kw := t.Keyword(); clauseEnd[kw] || (tok.Keyword() == "on" && (joinWords[kw] || t.Text == ",")) {
+				break
+			}
+		}
+		l.condition(i+1, end)
+	}
+}
+
+// condition checks the tokens of one condition in [start, end)
+func (l *linter) condition(start, end int) {
+	for i := start; i < end; i++ {
+		tok := l.toks[i]
+
+		// LOWER(email) = ..., YEAR(created_at) > ...
+		if tok.Kind == sqlparse.TokenWord && !notFunctions[tok.Keyword()] && l.text(i+1) == "(" {
+			closing := l.matching(i + 1)
+			if closing > 0 && closing < end && isComparison(l, closing+1) && l.hasColumn(i+2, closing) {
+				l.report(RuleNonSargable, tok, "%s() on a column in a condition can't use an index on it", strings.ToUpper(tok.Text))
+			}
+		}
+
+		// LIKE '%term'
+		if kw := tok.Keyword(); (kw == "like" || kw == "ilike") && i+1 < end {
+			next := l.toks[i+1]
+			if next.Kind == sqlparse.TokenString && (strings.HasPrefix(next.Text, "'%") || strings.HasPrefix(next.Text, "'_")) {
+				l.report(RuleNonSargable, next, "LIKE with a leading wildcard can't use an index")
+			}
+		}
+
+		// id = '42', code = 42
+		if isColumn(tok) && isComparison(l, i+1) {
+			value := i + 1
+			for value < end && strings.ContainsAny(l.text(value), "=<>!") {
+				value++
+			}
+			if value < end {
+				l.implicitCast(tok, l.toks[value])
+			}
+		}
+	}
+}
+
+// implicitCast flags a column compared to a literal of another type
+func (l *linter) implicitCast(col, value token) {
+	kind, ok := l.opts.Columns[strings.ToLower(strings.Trim(col.Text, "\"`[]"))]
+	if !ok {
+		return
+	}
+	switch {
+	case kind == db.ColumnNumber && value.Kind == sqlparse.TokenString:
+		l.report(RuleImplicitCast, col, "Numeric column %s is compared to the string %s; the implicit cast can skip its index", col.Text, value.Text)
+	case kind == db.ColumnText && value.Kind == sqlparse.TokenNumber:
+		l.report(RuleImplicitCast, col, "Text column %s is compared to the number %s; the implicit cast can skip its index", col.Text, value.Text)
+	}
+}
+
+// matching returns the index of the parenthesis closing the one at open
+func (l *linter) matching(open int) int {
+	for j := open + 1; j < len(l.toks); j++ {
+		if l.toks[j].Text == ")" && l.toks[j].depth == l.toks[open].depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// hasColumn reports whether [start, end) mentions a column
+func (l *linter) hasColumn(start, end int) bool {
+	for i := start; i < end; i++ {
+		if isColumn(l.toks[i]) && l.text(i+1) != "(" {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlWords are words that are never column names in a condition
+var sqlWords = map[string]bool{
+	"and": true, "or": true, "not": true, "null": true, "true": true,
+	"false": true, "is": true, "in": true, "as": true, "like": true,
+	"ilike": true, "between": true, "case": true, "when": true, "then": true,
+	"else": true, "end": true, "select": true, "from": true, "exists": true,
+	"interval": true, "distinct": true, "current_date": true,
+	"current_timestamp": true, "day": true, "month": true, "year": true,
+	"hour": true, "minute": true, "second": true, "int": true, "integer": true,
+	"text": true, "date": true, "varchar": true, "char": true,
+}
+
+// isColumn reports whether tok can name a column
+func isColumn(tok token) bool {
+	if tok.Kind == sqlparse.TokenQuotedIdent {
+		return true
+	}
+	if tok.Kind != sqlparse.TokenWord || sqlWords[tok.Keyword()] {
+		return false
+	}
+	_, err := strconv.ParseFloat(tok.Text, 64)
+	return err != nil
+}
+
+// isComparison reports whether a comparison starts at token i
+func isComparison(l *linter, i int) bool {
+	switch text := l.text(i); {
+	case text == "=" || text == "<" || text == ">":
+		return true
+	case text == "!" && l.text(i+1) == "=":
+		return true
+	}
+	switch l.kw(i) {
+	case "like", "ilike", "between", "in":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,432 @@
+package lint
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// unterminated flags a string, quoted identifier or comment left open
+func (l *linter) unterminated(tok sqlparse.Token) {
+	what := "String"
+	switch tok.Kind {
+	case sqlparse.TokenQuotedIdent:
+		what = "Quoted identifier"
+	case sqlparse.TokenComment:
+		what = "Comment"
+	}
+	l.reportAt(RuleSyntax, tok, "%s is never closed and runs to the end of the query", what)
+}
+
+// parens flags parentheses without a partner
+func (l *linter) parens() {
+	var open []token
+	for _, tok := range l.toks {
+		switch tok.Text {
+		case "(":
+			open = append(open, tok)
+		case ")":
+			if len(open) == 0 {
+				l.report(RuleSyntax, tok, "Unmatched ) without an opening (")
+				continue
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	for _, tok := range open {
+		l.report(RuleSyntax, tok, "Unclosed ( is missing its )")
+	}
+}
+
+// valueWords are words that are values of their own in a SELECT list
+var valueWords = map[string]bool{
+	"current_user": true, "session_user": true, "current_role": true,
+	"current_schema": true, "current_catalog": true, "user": true,
+	"current_time": true, "localtime": true, "localtimestamp": true,
+	"sysdate": true, "systimestamp": true, "array": true, "not": true,
+}
+
+// missingFrom flags SELECT lists naming a column without a FROM clause
+func (l *linter) missingFrom() {
+	for i, tok := range l.toks {
+		if tok.Keyword() != "select" {
+			continue
+		}
+		column, from := -1, false
+		for j := i + 1; j < len(l.toks) && l.toks[j].depth >= tok.depth; j++ {
+			t := l.toks[j]
+			if t.depth != tok.depth {
+				continue
+			}
+			if kw := t.Keyword(); kw == "from" || clauseEnd[kw] {
+				from = kw == "from"
+				break
+			}
+			if column < 0 && l.namesColumn(j) {
+				column = j
+			}
+		}
+		if !from && column >= 0 {
+			l.report(RuleMissingFrom, tok, "SELECT names %s but has no FROM clause", l.toks[column].Text)
+		}
+	}
+}
+
+// namesColumn reports whether the token at i of a SELECT list refers to a
+// column or a table rather than being a value, function or alias
+func (l *linter) namesColumn(i int) bool {
+	tok := l.toks[i]
+	if tok.Text == "*" {
+		kw := l.kw(i - 1)
+		return kw == "select" || kw == "distinct" || kw == "all" || l.text(i-1) == ","
+	}
+	if !isColumn(tok) || valueWords[tok.Keyword()] || l.text(i+1) == "(" {
+		return false
+	}
+	if i+1 < len(l.toks) && l.toks[i+1].Kind == sqlparse.TokenString {
+		// Typed literals: TIMESTAMP '2024-01-01'
+		return false
+	}
+	switch l.text(i - 1) {
+	case ".", "@", ":", "$":
+		return false
+	}
+	if i > 0 {
+		// Aliases: 1 AS one, 1 one, count(*) n
+		prev := l.toks[i-1]
+		if prev.Keyword() == "as" || prev.Kind == sqlparse.TokenNumber || prev.Kind == sqlparse.TokenString || prev.Text == ")" {
+			return false
+		}
+	}
+	return true
+}
+
+// tableRef is a table a statement reads or writes
+type tableRef struct {
+	tok     token
+	name    string          // lower-case last part of the name
+	alias   string          // lower-case alias, or the name
+	columns map[string]bool // nil unless the table is in the schema
+}
+
+// tableStarts are keywords followed by table names
+var tableStarts = map[string]bool{
+	"from": true, "join": true, "straight_join": true, "update": true, "into": true,
+}
+
+// aliasStops are words after a table name that aren't its alias
+var aliasStops = map[string]bool{
+	"on": true, "using": true, "select": true, "values": true, "default": true,
+	"lateral": true, "tablesample": true, "for": true, "with": true, "as": true,
+	"partition": true, "use": true, "force": true, "ignore": true,
+}
+
+// unknownNames flags tables missing from the schema and columns missing
+// from the schema tables a statement uses
+func (l *linter) unknownNames() {
+	if l.opts.Tables == nil {
+		return
+	}
+	ctes := l.cteNames()
+	refs, complete := l.tableRefs(ctes)
+
+	byAlias := make(map[string][]tableRef)
+	for _, ref := range refs {
+		if ref.columns == nil {
+			continue
+		}
+		byAlias[ref.alias] = append(byAlias[ref.alias], ref)
+		if ref.alias != ref.name {
+			byAlias[ref.name] = append(byAlias[ref.name], ref)
+		}
+	}
+
+	// t.column, for tables of the schema
+	for i, tok := range l.toks {
+		if !isName(tok) || l.text(i+1) != "." || l.text(i-1) == "." || i+2 >= len(l.toks) {
+			continue
+		}
+		col := l.toks[i+2]
+		if !isName(col) || l.text(i+3) == "." || l.text(i+3) == "(" {
+			continue
+		}
+		tables, ok := byAlias[unquote(tok.Text)]
+		if !ok || hasColumn(tables, unquote(col.Text)) {
+			continue
+		}
+		l.report(RuleUnknownName, col, "Column %s is not in table %s", col.Text, tables[0].name)
+	}
+
+	if !complete || len(byAlias) == 0 {
+		return
+	}
+
+	// Unqualified columns in conditions and SET, when every table of the
+	// statement is known
+	aliases := l.outputAliases()
+	var all []tableRef
+	var names []string
+	for _, ref := range refs {
+		all = append(all, ref)
+		names = append(names, ref.name)
+	}
+	sort.Strings(names)
+	for i, tok := range l.toks {
+		if !isColumn(tok) || !isComparison(l, i+1) || l.text(i+1) == "." {
+			continue
+		}
+		switch l.text(i - 1) {
+		case ".", "@", ":", "$":
+			continue
+		}
+		name := unquote(tok.Text)
+		if aliases[name] || byAlias[name] != nil || windowWords[name] || hasColumn(all, name) {
+			continue
+		}
+		l.report(RuleUnknownName, tok, "Column %s is not in %s", tok.Text, strings.Join(dedupe(names), ", "))
+	}
+}
+
+// windowWords are words of window frames that look like columns
+var windowWords = map[string]bool{
+	"rows": true, "range": true, "groups": true, "unbounded": true,
+	"preceding": true, "following": true, "current": true,
+}
+
+// tableRefs returns the tables the statement names after FROM, JOIN,
+// UPDATE and INSERT INTO, reporting those missing from the schema.
+// complete is false when some are subqueries, functions, CTEs or not in
+// the schema, so the columns the statement can use aren't all known.
+func (l *linter) tableRefs(ctes map[string]bool) (refs []tableRef, complete bool) {
+	complete = true
+	reported := make(map[string]bool)
+	for i, tok := range l.toks {
+		kw := tok.Keyword()
+		if !tableStarts[kw] {
+			continue
+		}
+		switch prev := l.kw(i - 1); kw {
+		case "into":
+			// SELECT ... INTO creates the table
+			if prev != "insert" && prev != "ignore" && prev != "replace" {
+				continue
+			}
+		case "from":
+			// EXTRACT(YEAR FROM d), a IS DISTINCT FROM b
+			if prev == "distinct" || !l.inStatement(i) {
+				continue
+			}
+		case "update":
+			// FOR UPDATE, DO UPDATE SET, ON DUPLICATE KEY UPDATE
+			if prev == "for" || prev == "do" || prev == "key" {
+				continue
+			}
+		}
+		for j := i + 1; j < len(l.toks); {
+			switch l.kw(j) {
+			case "only", "lateral", "ignore", "low_priority":
+				j++
+				continue
+			}
+			start := j
+			if l.text(j) == "(" {
+				// Subquery
+				complete = false
+				if j = l.matching(j); j < 0 {
+					return refs, false
+				}
+				j = l.skipAlias(j + 1)
+			} else {
+				name, qualified, next := l.tableName(j)
+				if name == "" {
+					break
+				}
+				j = next
+				if l.text(j) == "(" && kw != "into" {
+					// Table function: generate_series(1, 10)
+					complete = false
+					if j = l.matching(j); j < 0 {
+						return refs, false
+					}
+					j = l.skipAlias(j + 1)
+				} else {
+					ref := tableRef{tok: l.toks[start], name: name, alias: name, columns: l.opts.Tables[name]}
+					switch {
+					case ctes[name]:
+						complete = false
+					case ref.columns == nil:
+						complete = false
+						if !qualified && !systemTable(name) && !reported[name] {
+							reported[name] = true
+							l.report(RuleUnknownName, ref.tok, "Table %s is not in the schema", ref.tok.Text)
+						}
+					}
+					if alias, after := l.alias(j); alias != "" {
+						ref.alias, j = alias, after
+					}
+					if kw == "into" && l.text(j) == "(" {
+						l.insertColumns(ref, j)
+					}
+					refs = append(refs, ref)
+				}
+			}
+			// FROM a, b and MySQL's UPDATE a, b
+			if l.text(j) != "," || (kw != "from" && kw != "update") {
+				break
+			}
+			j++
+		}
+	}
+	return refs, complete
+}
+
+// inStatement reports whether the FROM at i belongs to a SELECT or
+// DELETE rather than to a function's arguments
+func (l *linter) inStatement(i int) bool {
+	depth := l.toks[i].depth
+	for j := i - 1; j >= 0 && l.toks[j].depth >= depth; j-- {
+		if kw := l.kw(j); l.toks[j].depth == depth && (kw == "select" || kw == "delete") {
+			return true
+		}
+	}
+	return false
+}
+
+// tableName reads a possibly qualified name at i, returning its
+// lower-case last part and the index after it
+func (l *linter) tableName(i int) (name string, qualified bool, next int) {
+	if i >= len(l.toks) || !isName(l.toks[i]) || clauseEnd[l.kw(i)] || joinWords[l.kw(i)] || aliasStops[l.kw(i)] {
+		return "", false, i
+	}
+	name, next = unquote(l.toks[i].Text), i+1
+	for l.text(next) == "." && next+1 < len(l.toks) && isName(l.toks[next+1]) {
+		name, qualified, next = unquote(l.toks[next+1].Text), true, next+2
+	}
+	return name, qualified, next
+}
+
+// alias reads the alias of a table at i, with or without AS
+func (l *linter) alias(i int) (string, int) {
+	if l.kw(i) == "as" {
+		i++
+	}
+	if i >= len(l.toks) || !isName(l.toks[i]) {
+		return "", i
+	}
+	if kw := l.kw(i); clauseEnd[kw] || joinWords[kw] || aliasStops[kw] || kw == "where" {
+		return "", i
+	}
+	return unquote(l.toks[i].Text), i + 1
+}
+
+// skipAlias skips the alias of a subquery at i, and its column list
+func (l *linter) skipAlias(i int) int {
+	if _, next := l.alias(i); next != i {
+		i = next
+		if l.text(i) == "(" {
+			if end := l.matching(i); end > 0 {
+				return end + 1
+			}
+		}
+	}
+	return i
+}
+
+// insertColumns flags the columns of INSERT INTO t (...) missing from t
+func (l *linter) insertColumns(ref tableRef, open int) {
+	if ref.columns == nil {
+		return
+	}
+	end := l.matching(open)
+	for j := open + 1; j < end; j++ {
+		if tok := l.toks[j]; isName(tok) && !ref.columns[unquote(tok.Text)] {
+			l.report(RuleUnknownName, tok, "Column %s is not in table %s", tok.Text, ref.name)
+		}
+	}
+}
+
+// cteNames returns the lower-case names of the statement's CTEs:
+// name AS (...) and name (columns) AS (...)
+func (l *linter) cteNames() map[string]bool {
+	names := make(map[string]bool)
+	for i, tok := range l.toks {
+		if !isName(tok) {
+			continue
+		}
+		next := i + 1
+		if l.text(next) == "(" {
+			if next = l.matching(next); next < 0 {
+				continue
+			}
+			next++
+		}
+		if l.kw(next) != "as" {
+			continue
+		}
+		body := next + 1
+		for l.kw(body) == "not" || l.kw(body) == "materialized" {
+			body++
+		}
+		if l.text(body) == "(" {
+			names[unquote(tok.Text)] = true
+		}
+	}
+	return names
+}
+
+// outputAliases returns the lower-case aliases the statement gives its
+// columns, which ORDER BY and HAVING can use
+func (l *linter) outputAliases() map[string]bool {
+	aliases := make(map[string]bool)
+	for i, tok := range l.toks {
+		if !isName(tok) || i == 0 {
+			continue
+		}
+		prev := l.toks[i-1]
+		explicit := prev.Keyword() == "as"
+		implicit := (prev.Text == ")" || prev.Kind == sqlparse.TokenNumber || prev.Kind == sqlparse.TokenString || isName(prev)) &&
+			(l.text(i+1) == "," || l.kw(i+1) == "from")
+		if explicit || implicit {
+			aliases[unquote(tok.Text)] = true
+		}
+	}
+	return aliases
+}
+
+// systemTable reports whether name is a catalog table that schemas leave
+// out
+func systemTable(name string) bool {
+	return name == "dual" || strings.HasPrefix(name, "pg_") || strings.HasPrefix(name, "sqlite_")
+}
+
+// hasColumn reports whether one of tables has the lower-case column
+func hasColumn(tables []tableRef, column string) bool {
+	for _, t := range tables {
+		if t.columns[column] {
+			return true
+		}
+	}
+	return false
+}
+
+// isName reports whether tok is an identifier, quoted or not
+func isName(tok token) bool {
+	return tok.Kind == sqlparse.TokenWord || tok.Kind == sqlparse.TokenQuotedIdent
+}
+
+// unquote returns an identifier without its quotes, lower-cased
+func unquote(name string) string {
+	return strings.ToLower(strings.Trim(name, "\"`[]"))
+}
+
+// dedupe removes repeats from sorted names
+func dedupe(names []string) []string {
+	out := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+// Package mask hides sensitive values in exported rows. Masking is
+// deterministic: the same value always masks the same way, so masked
+// columns can still be joined and grouped on.
+package mask
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// Masking methods of a rule
+const (
+	MethodHash   = "hash"
+	MethodRedact = "redact"
+	MethodFake   = "fake"
+)
+
+// redacted replaces values masked with MethodRedact
+const redacted = "REDACTED"
+
+// Func masks the text of a value
+type Func func(string) string
+
+// Columns returns the mask of each result column: that of the first rule
+// matching its name, or nil when no rule does
+func Columns(rules []config.MaskRule, columns []string) ([]Func, error) {
+	type compiled struct {
+		pattern *regexp.Regexp
+		mask    Func
+	}
+	var compiledRules []compiled
+	for _, rule := range rules {
+		mask, err := method(rule.Method)
+		if err != nil {
+			return nil, fmt.Errorf("mask rule for %s: %w", rule.Column, err)
+		}
+		compiledRules = append(compiledRules, compiled{pattern: wildcard(rule.Column), mask: mask})
+	}
+
+	masks := make([]Func, len(columns))
+	for i, col := range columns {
+		for _, rule := range compiledRules {
+			if rule.pattern.MatchString(col) {
+				masks[i] = rule.mask
+				break
+			}
+		}
+	}
+	return masks, nil
+}
+
+// method returns the mask of a method name
+func method(name string) (Func, error) {
+	switch strings.ToLower(name) {
+	case "", MethodHash:
+		return Hash, nil
+	case MethodRedact:
+		return Redact, nil
+	case MethodFake:
+		return Fake, nil
+	}
+	return nil, fmt.Errorf("unknown method %q (use hash, redact or fake)", name)
+}
+
+// wildcard compiles a column pattern where * matches any run of characters
+func wildcard(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
+
+// Hash replaces a value with the first 16 hex digits of its SHA-256
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Redact replaces a value with a fixed placeholder
+func Redact(string) string {
+	return redacted
+}
+
+// Fake replaces a value with a made-up one of the same shape: letters
+// become other letters of the same case and digits other digits, while
+// spaces and punctuation stay. E-mail addresses keep their form with the
+// domain example.com.
+func Fake(s string) string {
+	if at := strings.LastIndex(s, "@"); at > 0 {
+		return scramble(s[:at], s) + "@example.com"
+	}
+	return scramble(s, s)
+}
+
+// scramble replaces the letters and digits of s with ones drawn from a
+// hash of seed
+func scramble(s, seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		n := int(sum[i%len(sum)]) + i/len(sum)
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteByte(byte('0' + n%10))
+		case unicode.IsUpper(r):
+			b.WriteByte(byte('A' + n%26))
+		case unicode.IsLetter(r):
+			b.WriteByte(byte('a' + n%26))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
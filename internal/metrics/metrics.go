@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// Query statuses reported with each query
+const (
+	StatusOK        = "ok"
+	StatusError     = "error"
+	StatusCancelled = "cancelled"
+	StatusTimeout   = "timeout"
+)
+
+// Query is one finished query
+type Query struct {
+	Connection string
+	Driver     string
+	Duration   time.Duration
+	Status     string
+}
+
+// Recorder sends query metrics to an observability backend
+type Recorder interface {
+	// RecordQuery records a finished query; it must not block on the network
+	RecordQuery(q Query)
+	// Close flushes pending metrics
+	Close() error
+}
+
+// NewRecorder creates the recorder configured in cfg. With no exporter set
+// it returns a no-op recorder.
+func NewRecorder(cfg config.MetricsConfig) (Recorder, error) {
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = "sqdesk"
+	}
+
+	switch cfg.Exporter {
+	case "statsd":
+		return NewStatsdRecorder(cfg.Endpoint, prefix)
+	case "otlp":
+		return NewOTLPRecorder(cfg.Endpoint, prefix, cfg.Headers)
+	case "", "none":
+		return NewNoopRecorder(), nil
+	default:
+		return nil, fmt.Errorf("unknown metrics exporter %q (use statsd or otlp)", cfg.Exporter)
+	}
+}
+
+// NoopRecorder drops all metrics
+type NoopRecorder struct{}
+
+// NewNoopRecorder creates a no-op recorder
+func NewNoopRecorder() *NoopRecorder {
+	return &NoopRecorder{}
+}
+
+func (r *NoopRecorder) RecordQuery(q Query) {}
+
+func (r *NoopRecorder) Close() error {
+	return nil
+}
@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// otlpFlushInterval is how often aggregated metrics are sent
+const otlpFlushInterval = 10 * time.Second
+
+// otlpBoundsMs are the latency histogram bucket bounds in milliseconds
+var otlpBoundsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+// otlpKey identifies one series
+type otlpKey struct {
+	connection string
+	driver     string
+	status     string
+}
+
+// otlpPoint aggregates the queries of one series since the last flush
+type otlpPoint struct {
+	count   uint64
+	sumMs   float64
+	buckets []uint64 // len(otlpBoundsMs)+1
+}
+
+// OTLPRecorder aggregates queries and sends them periodically as delta
+// metrics over OTLP/HTTP (JSON encoding): a query count and a latency
+// histogram, both with connection, driver and status attributes
+type OTLPRecorder struct {
+	url     string
+	prefix  string
+	headers map[string]string
+	client  *http.Client
+
+	mu     sync.Mutex
+	points map[otlpKey]*otlpPoint
+	since  time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewOTLPRecorder creates a recorder sending to an OTLP/HTTP collector,
+// e.g. http://localhost:4318
+func NewOTLPRecorder(endpoint, prefix string, headers map[string]string) (*OTLPRecorder, error) {
+	if endpoint == "" {
+		endpoint = "http://localhost:4318"
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("otlp: endpoint must be an http(s) URL, got %q", endpoint)
+	}
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/metrics") {
+		url += "/v1/metrics"
+	}
+
+	r := &OTLPRecorder{
+		url:     url,
+		prefix:  prefix,
+		headers: headers,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		points:  make(map[otlpKey]*otlpPoint),
+		since:   time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.loop()
+	return r, nil
+}
+
+// RecordQuery adds a query to the current interval
+func (r *OTLPRecorder) RecordQuery(q Query) {
+	key := otlpKey{connection: q.Connection, driver: q.Driver, status: q.Status}
+	ms := float64(q.Duration) / float64(time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.points[key]
+	if !ok {
+		p = &otlpPoint{buckets: make([]uint64, len(otlpBoundsMs)+1)}
+		r.points[key] = p
+	}
+	p.count++
+	p.sumMs += ms
+	bucket := len(otlpBoundsMs)
+	for i, bound := range otlpBoundsMs {
+		if ms <= bound {
+			bucket = i
+			break
+		}
+	}
+	p.buckets[bucket]++
+}
+
+// Close stops the flush loop and sends what is left
+func (r *OTLPRecorder) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		err = r.flush()
+	})
+	return err
+}
+
+// loop flushes on every interval until Close
+func (r *OTLPRecorder) loop() {
+	defer close(r.done)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Failures drop the interval; metrics must not affect queries
+			r.flush()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// flush sends and resets the aggregated points
+func (r *OTLPRecorder) flush() error {
+	r.mu.Lock()
+	points, start := r.points, r.since
+	r.points = make(map[otlpKey]*otlpPoint)
+	r.since = time.Now()
+	r.mu.Unlock()
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(r.payload(points, start, time.Now()))
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range r.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp: collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// payload builds an ExportMetricsServiceRequest in OTLP's JSON mapping,
+// where 64-bit integers are strings
+func (r *OTLPRecorder) payload(points map[otlpKey]*otlpPoint, start, end time.Time) map[string]interface{} {
+	startNano := strconv.FormatInt(start.UnixNano(), 10)
+	endNano := strconv.FormatInt(end.UnixNano(), 10)
+
+	var counts, histograms []interface{}
+	for key, p := range points {
+		attrs := []interface{}{
+			otlpAttr("connection", key.connection),
+			otlpAttr("driver", key.driver),
+			otlpAttr("status", key.status),
+		}
+		buckets := make([]string, len(p.buckets))
+		for i, n := range p.buckets {
+			buckets[i] = strconv.FormatUint(n, 10)
+		}
+		counts = append(counts, map[string]interface{}{
+			"attributes":        attrs,
+			"startTimeUnixNano": startNano,
+			"timeUnixNano":      endNano,
+			"asInt":             strconv.FormatUint(p.count, 10),
+		})
+		histograms = append(histograms, map[string]interface{}{
+			"attributes":        attrs,
+			"startTimeUnixNano": startNano,
+			"timeUnixNano":      endNano,
+			"count":             strconv.FormatUint(p.count, 10),
+			"sum":               p.sumMs,
+			"bucketCounts":      buckets,
+			"explicitBounds":    otlpBoundsMs,
+		})
+	}
+
+	const deltaTemporality = 1
+	return map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []interface{}{otlpAttr("service.name", "sqdesk")},
+			},
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "sqdesk"},
+				"metrics": []interface{}{
+					map[string]interface{}{
+						"name": r.prefix + ".query.count",
+						"unit": "{query}",
+						"sum": map[string]interface{}{
+							"aggregationTemporality": deltaTemporality,
+							"isMonotonic":            true,
+							"dataPoints":             counts,
+						},
+					},
+					map[string]interface{}{
+						"name": r.prefix + ".query.duration",
+						"unit": "ms",
+						"histogram": map[string]interface{}{
+							"aggregationTemporality": deltaTemporality,
+							"dataPoints":             histograms,
+						},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+// otlpAttr builds a string KeyValue attribute
+func otlpAttr(key, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"key":   key,
+		"value": map[string]interface{}{"stringValue": value},
+	}
+}
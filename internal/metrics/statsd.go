@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// StatsdRecorder sends each query as statsd packets over UDP, with
+// connection, driver and status as DogStatsD tags
+type StatsdRecorder struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsdRecorder creates a recorder sending to a statsd host:port
+func NewStatsdRecorder(address, prefix string) (*StatsdRecorder, error) {
+	if address == "" {
+		address = "127.0.0.1:8125"
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("statsd: %w", err)
+	}
+	return &StatsdRecorder{conn: conn, prefix: prefix}, nil
+}
+
+// statsdTagReplacer strips characters with a meaning in the statsd format
+var statsdTagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", ":", "_", "\n", " ")
+
+// RecordQuery sends the query latency as a timer and counts it
+func (r *StatsdRecorder) RecordQuery(q Query) {
+	tags := fmt.Sprintf("#connection:%s,driver:%s,status:%s",
+		statsdTagReplacer.Replace(q.Connection), statsdTagReplacer.Replace(q.Driver), q.Status)
+	packet := fmt.Sprintf("%s.query.duration:%d|ms|%s\n%s.query.count:1|c|%s",
+		r.prefix, q.Duration.Milliseconds(), tags, r.prefix, tags)
+	// UDP is fire and forget; a missing agent must not affect queries
+	r.conn.Write([]byte(packet))
+}
+
+// Close closes the UDP socket
+func (r *StatsdRecorder) Close() error {
+	return r.conn.Close()
+}
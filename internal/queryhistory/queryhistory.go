@@ -0,0 +1,172 @@
+// Package queryhistory keeps the queries run in SQDesk in a JSON lines file
+// so they can be searched and run again in later sessions.
+package queryhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// fileName is the history file in the config directory
+const fileName = "history.jsonl"
+
+// Entry is a query run, one line of the history file
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Connection string    `json:"connection,omitempty"`
+	Driver     string    `json:"driver,omitempty"`
+	Query      string    `json:"query"`
+	DurationMs int64     `json:"duration_ms"`
+	Rows       int       `json:"rows,omitempty"`
+	Affected   int64     `json:"affected,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Store is the history file, holding at most limit entries
+type Store struct {
+	path  string
+	limit int
+	count int // entries in the file, -1 until read
+}
+
+// NewStore opens the history file in the config directory, keeping at
+// most limit entries
+func NewStore(limit int) (*Store, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: filepath.Join(dir, fileName), limit: limit, count: -1}, nil
+}
+
+// Path returns the history file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds an entry to the end of the file. Once the file holds twice
+// the limit it is rewritten with the newest entries, so appending stays
+// cheap. The file is readable by the owner only since queries may hold
+// data.
+func (s *Store) Append(e Entry) error {
+	if s.count < 0 {
+		entries, err := s.read()
+		if err != nil {
+			return err
+		}
+		s.count = len(entries)
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	s.count++
+	if s.count > 2*s.limit {
+		return s.compact()
+	}
+	return nil
+}
+
+// List returns the entries, newest first
+func (s *Store) List() ([]Entry, error) {
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.count = len(entries)
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if len(entries) > s.limit {
+		entries = entries[:s.limit]
+	}
+	return entries, nil
+}
+
+// read returns the entries of the file in the order they were run.
+// Lines that don't parse are skipped; one bad line must not hide the rest.
+func (s *Store) read() ([]Entry, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.Query != "" {
+			entries = append(entries, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return entries, nil
+}
+
+// compact rewrites the file with the newest limit entries
+func (s *Store) compact() error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	if len(entries) > s.limit {
+		entries = entries[len(entries)-s.limit:]
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err = enc.Encode(e); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("history: %w", err)
+	}
+	s.count = len(entries)
+	return nil
+}
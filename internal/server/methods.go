@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// methods maps JSON-RPC method names to their handlers
+var methods = map[string]func(s *Server, ctx context.Context, params json.RawMessage) (interface{}, error){
+	"connections": (*Server).listConnections,
+	"connect":     (*Server).connect,
+	"disconnect":  (*Server).disconnectMethod,
+	"query":       (*Server).query,
+	"schema":      (*Server).schema,
+	"complete":    (*Server).complete,
+}
+
+// connectionParams name a configured connection; empty is the active one
+type connectionParams struct {
+	Connection string `json:"connection"`
+}
+
+// connectionInfo describes a configured connection, without credentials
+type connectionInfo struct {
+	Name      string `json:"name"`
+	Driver    string `json:"driver"`
+	Database  string `json:"database,omitempty"`
+	ReadOnly  bool   `json:"readOnly"`
+	Active    bool   `json:"active"`
+	Connected bool   `json:"connected"`
+}
+
+// listConnections returns the configured connections
+func (s *Server) listConnections(context.Context, json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conns := make([]connectionInfo, len(s.cfg.Connections))
+	for i, conn := range s.cfg.Connections {
+		sess := s.sessions[conn.Name]
+		conns[i] = connectionInfo{
+			Name:      conn.Name,
+			Driver:    conn.Driver,
+			Database:  conn.Database,
+			ReadOnly:  conn.ReadOnly,
+			Active:    i == s.cfg.ActiveConnIndex,
+			Connected: sess != nil && sess.connected.Load(),
+		}
+	}
+	return conns, nil
+}
+
+// connect opens a connection ahead of use; connections that don't store
+// their password need it here
+func (s *Server) connect(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		Connection string `json:"connection"`
+		Password   string `json:"password"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	sess, err := s.session(ctx, params.Connection, params.Password)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"connection": sess.cfg.Name,
+		"driver":     sess.connector.GetDriverName(),
+		"database":   sess.connector.GetDatabaseName(),
+	}, nil
+}
+
+// disconnectMethod closes a connection
+func (s *Server) disconnectMethod(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var params connectionParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	closed, err := s.disconnect(params.Connection)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]bool{"closed": closed}, nil
+}
+
+// resultSet is one result set of a query. Values are formatted as the
+// results grid shows them; NULL is null.
+type resultSet struct {
+	Columns   []string    `json:"columns"`
+	Types     []string    `json:"types"`
+	Rows      [][]*string `json:"rows"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+// queryResult is the outcome of a query: result sets for statements that
+// return rows, otherwise the number of affected rows
+type queryResult struct {
+	Sets      []resultSet `json:"sets,omitempty"`
+	Affected  int64       `json:"affected"`
+	ElapsedMS int64       `json:"elapsedMs"`
+}
+
+// query runs SQL on a connection with the same read-only and protected
+// table checks as the editor. Statements touching protected tables need
+// confirmTable set to the first protected table they mention.
+func (s *Server) query(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		Connection   string `json:"connection"`
+		SQL          string `json:"sql"`
+		ConfirmTable string `json:"confirmTable"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(params.SQL) == "" {
+		return nil, invalidParams("sql is empty")
+	}
+	sess, err := s.session(ctx, params.Connection, "")
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := sqlparse.DialectFor(sess.connector.GetDriverName())
+	statements := sqlparse.Split(params.SQL, dialect)
+	if len(statements) == 0 {
+		return nil, invalidParams("sql has no statements")
+	}
+	isSelect := false
+	for _, stmt := range statements {
+		if sess.cfg.ReadOnly && !sqlparse.IsReadOnly(stmt.Text, dialect) {
+			return nil, fmt.Errorf("connection is read-only, refusing: %s", strings.TrimSpace(stmt.Text))
+		}
+		if sqlparse.Classify(stmt.Text, dialect) == sqlparse.KindQuery {
+			isSelect = true
+		}
+	}
+	if protected := sqlparse.MentionedTables(params.SQL, dialect, sess.cfg.ProtectedTables); len(protected) > 0 && strings.TrimSpace(params.ConfirmTable) != protected[0] {
+		return nil, fmt.Errorf("statement touches the protected table %s; set confirmTable to %s to run it", protected[0], protected[0])
+	}
+
+	var result queryResult
+	var sets []db.ResultSet
+	if err := sess.lockDB(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	err = db.WithQueryTimeout(ctx, s.cfg.QueryTimeoutFor(&sess.cfg), func(ctx context.Context) error {
+		var err error
+		if isSelect {
+			sets, err = db.QueryMulti(ctx, sess.connector, params.SQL)
+		} else {
+			result.Affected, err = sess.connector.Execute(ctx, params.SQL)
+		}
+		return err
+	})
+	result.ElapsedMS = time.Since(start).Milliseconds()
+	sess.dbMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range sets {
+		result.Sets = append(result.Sets, encodeResultSet(set))
+	}
+	sess.compMu.Lock()
+	sess.completer.AddHistory(params.SQL)
+	sess.compMu.Unlock()
+	return result, nil
+}
+
+// encodeResultSet formats the values of a result set for JSON
+func encodeResultSet(set db.ResultSet) resultSet {
+	out := resultSet{
+		Columns:   set.Columns,
+		Types:     make([]string, len(set.Columns)),
+		Rows:      make([][]*string, len(set.Rows)),
+		Truncated: set.Truncated,
+	}
+	types := make([]db.ColumnType, len(set.Columns))
+	for i := range set.Columns {
+		if i < len(set.ColumnTypes) {
+			types[i] = set.ColumnTypes[i]
+			out.Types[i] = types[i].DatabaseType
+		}
+	}
+	for r, row := range set.Rows {
+		values := make([]*string, len(set.Columns))
+		for i, col := range set.Columns {
+			v := row[col]
+			if v.Null {
+				continue
+			}
+			text := v.Text(types[i])
+			values[i] = &text
+		}
+		out.Rows[r] = values
+	}
+	return out
+}
+
+// tableInfo describes a table of the schema
+type tableInfo struct {
+	Name    string       `json:"name"`
+	Columns []columnInfo `json:"columns"`
+}
+
+// columnInfo describes a column of a table
+type columnInfo struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primaryKey"`
+}
+
+// schema returns the tables and columns of a connection's database,
+// reloading them for completion too
+func (s *Server) schema(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var params connectionParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	sess, err := s.session(ctx, params.Connection, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := sess.lockDB(); err != nil {
+		return nil, err
+	}
+	schema, err := sess.connector.GetSchema()
+	sess.dbMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	sess.compMu.Lock()
+	sess.completer.SetSchema(schema)
+	sess.compMu.Unlock()
+
+	tables := make([]tableInfo, 0, len(schema.Tables))
+	for name, table := range schema.Tables {
+		info := tableInfo{Name: name, Columns: make([]columnInfo, len(table.Columns))}
+		for i, col := range table.Columns {
+			info.Columns[i] = columnInfo{Name: col.Name, Type: col.Type, Nullable: col.Nullable, PrimaryKey: col.IsPK}
+		}
+		tables = append(tables, info)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return map[string]interface{}{"tables": tables}, nil
+}
+
+// completionItem is one suggestion for the cursor position
+type completionItem struct {
+	Label      string `json:"label"`
+	InsertText string `json:"insertText"`
+	Kind       string `json:"kind"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// complete returns suggestions for the cursor, a byte offset into sql
+func (s *Server) complete(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		Connection string `json:"connection"`
+		SQL        string `json:"sql"`
+		Cursor     int    `json:"cursor"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Cursor < 0 || params.Cursor > len(params.SQL) {
+		return nil, invalidParams("cursor %d is outside sql", params.Cursor)
+	}
+	sess, err := s.session(ctx, params.Connection, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sess.compMu.Lock()
+	items := sess.completer.Complete(params.SQL, params.Cursor, sess.connector.GetDatabaseName())
+	sess.compMu.Unlock()
+
+	out := make([]completionItem, len(items))
+	for i, item := range items {
+		out[i] = completionItem{Label: item.Label, InsertText: item.InsertText, Kind: item.Kind.Name(), Detail: item.Detail}
+	}
+	return out, nil
+}
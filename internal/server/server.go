@@ -0,0 +1,315 @@
+// Package server runs SQDesk headless: editor plugins send JSON-RPC 2.0
+// requests, one JSON object per line, over a local Unix socket or stdio
+// and reuse the configured connections, schema and completion engine.
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/febritecno/sqdesk-cli/internal/completion/sources"
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeServerError    = -32000
+)
+
+// maxMessage caps the size of one request line
+const maxMessage = 16 << 20
+
+// request is a JSON-RPC request; notifications have no ID
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response carrying either Result or Error
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error object of a response
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// invalidParams reports params that don't fit the method
+func invalidParams(format string, args ...interface{}) error {
+	return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// Server answers requests for the connections of a config. Connections
+// open on first use and stay open, shared by all clients, until Close.
+type Server struct {
+	cfg *config.Config
+
+	mu       sync.Mutex
+	sessions map[string]*session // by connection name
+}
+
+// session is one open connection with the completion state for it. Once
+// connected, connector stays set; disconnecting drops the whole session.
+type session struct {
+	mu        sync.Mutex // held while connecting
+	cfg       config.DatabaseConfig
+	connector db.Connector
+	connected atomic.Bool
+	// dbMu runs the statements of all clients one at a time: a connector
+	// holds per-connection state and isn't safe for concurrent use
+	dbMu sync.Mutex
+
+	compMu    sync.Mutex // guards completer
+	completer *sources.Completer
+}
+
+// New returns a server for the connections of cfg
+func New(cfg *config.Config) *Server {
+	return &Server{cfg: cfg, sessions: make(map[string]*session)}
+}
+
+// Serve answers clients connecting to l until l is closed
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			s.ServeConn(conn, conn)
+		}()
+	}
+}
+
+// ServeConn answers the requests read from r on w until r ends. Requests
+// run concurrently, so a slow query doesn't hold up completion, though the
+// queries of one connection wait for each other; responses are written as
+// they finish and matched by ID. Once r ends the client is gone and its
+// running queries are cancelled.
+func (s *Server) ServeConn(r io.Reader, w io.Writer) error {
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	enc := json.NewEncoder(w)
+	write := func(resp response) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		enc.Encode(resp)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessage)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			write(response{JSONRPC: "2.0", ID: idOrNull(req.ID), Error: &rpcError{Code: codeInvalidRequest, Message: "not a JSON-RPC 2.0 request"}})
+			continue
+		}
+
+		wg.Add(1)
+		go func(req request) {
+			defer wg.Done()
+			result, err := s.call(ctx, req.Method, req.Params)
+			if len(req.ID) == 0 {
+				// Notification: no response
+				return
+			}
+			resp := response{JSONRPC: "2.0", ID: req.ID, Result: result}
+			if err != nil {
+				var rerr *rpcError
+				if !errors.As(err, &rerr) {
+					rerr = &rpcError{Code: codeServerError, Message: err.Error()}
+				}
+				resp.Result, resp.Error = nil, rerr
+			}
+			write(resp)
+		}(req)
+	}
+	return scanner.Err()
+}
+
+// idOrNull returns id, or null when the request had none
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+// call runs one method; ctx is cancelled when the client goes away
+func (s *Server) call(ctx context.Context, method string, raw json.RawMessage) (interface{}, error) {
+	handler, ok := methods[method]
+	if !ok {
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "unknown method " + method}
+	}
+	return handler(s, ctx, raw)
+}
+
+// decodeParams unmarshals the params of a request into v
+func decodeParams(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return invalidParams("invalid params: %v", err)
+	}
+	return nil
+}
+
+// findConnection returns the configured connection called name, or the
+// active one when name is empty
+func (s *Server) findConnection(name string) (config.DatabaseConfig, error) {
+	if name == "" {
+		if conn := s.cfg.GetActiveConnection(); conn != nil {
+			return *conn, nil
+		}
+		return config.DatabaseConfig{}, invalidParams("no connection given and none is active")
+	}
+	for _, conn := range s.cfg.Connections {
+		if conn.Name == name {
+			return conn, nil
+		}
+	}
+	return config.DatabaseConfig{}, invalidParams("unknown connection %q", name)
+}
+
+// session returns the open session of a connection, connecting first when
+// needed. password is used for connections that don't store theirs.
+func (s *Server) session(ctx context.Context, name, password string) (*session, error) {
+	cfg, err := s.findConnection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	sess, ok := s.sessions[cfg.Name]
+	if !ok {
+		sess = &session{cfg: cfg}
+		s.sessions[cfg.Name] = sess
+	}
+	s.mu.Unlock()
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.connector != nil {
+		return sess, nil
+	}
+
+	if cfg.NeedsPassword() {
+		if password == "" {
+			return nil, invalidParams("connection %q needs a password; pass it to connect", cfg.Name)
+		}
+		cfg.Password = password
+	}
+	connector, err := db.Open(ctx, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.Name, err)
+	}
+	rows, bytes := s.cfg.ResultLimit()
+	db.SetResultLimit(connector, db.ResultLimit{Rows: rows, Bytes: bytes})
+
+	completer := sources.NewCompleter()
+	completer.SetDriver(connector.GetDriverName())
+	if schema, err := connector.GetSchema(); err == nil {
+		completer.SetSchema(schema)
+	}
+	sess.connector = connector
+	sess.completer = completer
+	sess.connected.Store(true)
+	return sess, nil
+}
+
+// disconnect closes the session of a connection, reporting whether it was
+// open
+func (s *Server) disconnect(name string) (bool, error) {
+	cfg, err := s.findConnection(name)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	sess, ok := s.sessions[cfg.Name]
+	delete(s.sessions, cfg.Name)
+	s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+
+	return sess.close(), nil
+}
+
+// close closes the connection of a session dropped from the server once
+// its running statement finishes, reporting whether it was open
+func (sess *session) close() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if !sess.connected.Load() {
+		return false
+	}
+	sess.dbMu.Lock()
+	defer sess.dbMu.Unlock()
+	sess.connected.Store(false)
+	sess.connector.Close()
+	return true
+}
+
+// lockDB takes dbMu for a statement, failing when the session was closed
+// while the caller waited for it
+func (sess *session) lockDB() error {
+	sess.dbMu.Lock()
+	if !sess.connected.Load() {
+		sess.dbMu.Unlock()
+		return fmt.Errorf("connection %s was closed", sess.cfg.Name)
+	}
+	return nil
+}
+
+// Close closes every open connection
+func (s *Server) Close() error {
+	s.mu.Lock()
+	sessions := s.sessions
+	s.sessions = make(map[string]*session)
+	s.mu.Unlock()
+
+	for _, sess := range sessions {
+		sess.close()
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// client talks to ServeConn over pipes, one request at a time
+type client struct {
+	t   *testing.T
+	in  *io.PipeWriter
+	out *bufio.Scanner
+	id  int
+}
+
+func newClient(t *testing.T, s *Server) *client {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.ServeConn(inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		<-done
+	})
+	return &client{t: t, in: inW, out: bufio.NewScanner(outR)}
+}
+
+// send writes a raw request line
+func (c *client) send(line string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, line+"\n"); err != nil {
+		c.t.Fatalf("write request: %v", err)
+	}
+}
+
+// read reads the next response
+func (c *client) read() response {
+	c.t.Helper()
+	if !c.out.Scan() {
+		c.t.Fatalf("no response: %v", c.out.Err())
+	}
+	var resp response
+	if err := json.Unmarshal(c.out.Bytes(), &resp); err != nil {
+		c.t.Fatalf("bad response %s: %v", c.out.Bytes(), err)
+	}
+	return resp
+}
+
+// call sends a request and decodes its result into result, returning the
+// error of the response, if any
+func (c *client) call(method string, params interface{}, result interface{}) *rpcError {
+	c.t.Helper()
+	c.id++
+	raw, _ := json.Marshal(params)
+	c.send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":%q,"params":%s}`, c.id, method, raw))
+	resp := c.read()
+	if string(resp.ID) != fmt.Sprint(c.id) {
+		c.t.Fatalf("response id = %s, want %d", resp.ID, c.id)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result != nil {
+		raw, _ := json.Marshal(resp.Result)
+		if err := json.Unmarshal(raw, result); err != nil {
+			c.t.Fatalf("decode result %s: %v", raw, err)
+		}
+	}
+	return nil
+}
+
+// newServer returns a server for a SQLite database, read-write as "main"
+// and read-only as "reports", with the orders table protected
+func newServer(t *testing.T) *Server {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s := New(&config.Config{Connections: []config.DatabaseConfig{
+		{Name: "main", Driver: "sqlite", Host: path, ProtectedTables: []string{"orders"}},
+		{Name: "reports", Driver: "sqlite", Host: path, ReadOnly: true},
+	}})
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestServeConnQuery(t *testing.T) {
+	c := newClient(t, newServer(t))
+
+	var affected queryResult
+	if err := c.call("query", map[string]string{"sql": "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"}, &affected); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := c.call("query", map[string]string{"sql": "INSERT INTO users (name) VALUES ('ada'), (NULL)"}, &affected); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if affected.Affected != 2 {
+		t.Errorf("insert affected %d rows, want 2", affected.Affected)
+	}
+
+	var result queryResult
+	if err := c.call("query", map[string]string{"connection": "main", "sql": "SELECT id, name FROM users ORDER BY id"}, &result); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if len(result.Sets) != 1 {
+		t.Fatalf("got %d result sets, want 1", len(result.Sets))
+	}
+	set := result.Sets[0]
+	if strings.Join(set.Columns, ",") != "id,name" || len(set.Rows) != 2 {
+		t.Fatalf("got columns %v and %d rows", set.Columns, len(set.Rows))
+	}
+	if set.Rows[0][1] == nil || *set.Rows[0][1] != "ada" || set.Rows[1][1] != nil {
+		t.Errorf("names = %v, %v; want ada, null", set.Rows[0][1], set.Rows[1][1])
+	}
+}
+
+func TestServeConnChecks(t *testing.T) {
+	c := newClient(t, newServer(t))
+	if err := c.call("query", map[string]string{"sql": "CREATE TABLE orders (id INTEGER)", "confirmTable": "orders"}, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   string // part of the error; empty when it succeeds
+	}{
+		{"empty sql", map[string]string{"sql": "  "}, "sql is empty"},
+		{"unknown connection", map[string]string{"connection": "nope", "sql": "SELECT 1"}, `unknown connection "nope"`},
+		{"read-only write", map[string]string{"connection": "reports", "sql": "DELETE FROM orders"}, "read-only"},
+		{"read-only select", map[string]string{"connection": "reports", "sql": "SELECT 1"}, ""},
+		{"protected", map[string]string{"sql": "DELETE FROM orders"}, "protected table orders"},
+		{"protected confirmed", map[string]string{"sql": "DELETE FROM orders", "confirmTable": "orders"}, ""},
+	}
+	for _, tt := range tests {
+		err := c.call("query", tt.params, nil)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Message, tt.want)):
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestServeConnProtocolErrors(t *testing.T) {
+	c := newClient(t, newServer(t))
+
+	c.send(`{not json`)
+	if resp := c.read(); resp.Error == nil || resp.Error.Code != codeParseError || string(resp.ID) != "null" {
+		t.Errorf("bad JSON: got %+v, want a parse error with id null", resp)
+	}
+	c.send(`{"id":1,"method":"connections"}`)
+	if resp := c.read(); resp.Error == nil || resp.Error.Code != codeInvalidRequest {
+		t.Errorf("missing jsonrpc: got %+v, want an invalid request error", resp)
+	}
+	if err := c.call("nope", nil, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("unknown method: got %v, want method not found", err)
+	}
+	if err := c.call("complete", map[string]interface{}{"sql": "SELECT", "cursor": 7}, nil); err == nil || err.Code != codeInvalidParams {
+		t.Errorf("cursor outside sql: got %v, want invalid params", err)
+	}
+}
+
+func TestServeConnSchemaAndCompletion(t *testing.T) {
+	c := newClient(t, newServer(t))
+	if err := c.call("query", map[string]string{"sql": "CREATE TABLE customers (id INTEGER PRIMARY KEY, email TEXT NOT NULL)"}, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var schema struct {
+		Tables []tableInfo `json:"tables"`
+	}
+	if err := c.call("schema", nil, &schema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	if len(schema.Tables) != 1 || schema.Tables[0].Name != "customers" || len(schema.Tables[0].Columns) != 2 {
+		t.Fatalf("schema = %+v, want the customers table with 2 columns", schema.Tables)
+	}
+	if col := schema.Tables[0].Columns[0]; col.Name != "id" || !col.PrimaryKey {
+		t.Errorf("first column = %+v, want primary key id", col)
+	}
+
+	var items []completionItem
+	sql := "SELECT * FROM cust"
+	if err := c.call("complete", map[string]interface{}{"sql": sql, "cursor": len(sql)}, &items); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	found := false
+	for _, item := range items {
+		found = found || item.Label == "customers"
+	}
+	if !found {
+		t.Errorf("completions of %q = %+v, want customers", sql, items)
+	}
+}
+
+func TestServeConnDisconnect(t *testing.T) {
+	s := newServer(t)
+	c := newClient(t, s)
+	if err := c.call("connect", map[string]string{"connection": "main"}, nil); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	var conns []connectionInfo
+	c.call("connections", nil, &conns)
+	if len(conns) != 2 || !conns[0].Connected || conns[1].Connected {
+		t.Fatalf("connections = %+v, want only main connected", conns)
+	}
+
+	var closed map[string]bool
+	if err := c.call("disconnect", map[string]string{"connection": "main"}, &closed); err != nil || !closed["closed"] {
+		t.Fatalf("disconnect = %v, %v; want closed", closed, err)
+	}
+	if err := c.call("disconnect", map[string]string{"connection": "main"}, &closed); err != nil || closed["closed"] {
+		t.Errorf("second disconnect = %v, %v; want not closed", closed, err)
+	}
+}
+
+// A client going away cancels the queries it left running
+func TestServeConnCancelsOnEOF(t *testing.T) {
+	s := newServer(t)
+	c := newClient(t, s)
+	if err := c.call("connect", nil, nil); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	slow := `WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) SELECT count(*) FROM n`
+	c.send(fmt.Sprintf(`{"jsonrpc":"2.0","id":99,"method":"query","params":{"sql":%q}}`, slow))
+	time.Sleep(100 * time.Millisecond)
+	c.in.Close()
+
+	done := make(chan response, 1)
+	go func() { done <- c.read() }()
+	select {
+	case resp := <-done:
+		if resp.Error == nil {
+			t.Errorf("cancelled query returned %+v, want an error", resp.Result)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("query kept running after the client went away")
+	}
+}
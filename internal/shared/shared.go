@@ -0,0 +1,198 @@
+// Package shared loads a team's library of SQL snippets and connection
+// templates from a git-managed directory and keeps it in sync. The layout:
+//
+//	connections.yaml   connections: [...] in the config file format
+//	snippets/**/*.sql  one snippet per file; leading -- comments describe it
+package shared
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+)
+
+// Snippet is a saved query of the shared library
+type Snippet struct {
+	Name        string // path below snippets/ without .sql, e.g. billing/refunds
+	Description string
+	SQL         string
+}
+
+// Library is the content of a shared directory
+type Library struct {
+	Dir         string
+	Snippets    []Snippet
+	Connections []config.DatabaseConfig
+}
+
+// Load reads the library in dir. A missing directory is an empty library,
+// so SQDesk starts before the first sync.
+func Load(dir string) (*Library, error) {
+	lib := &Library{Dir: dir}
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return lib, nil
+	}
+
+	conns, err := loadConnections(filepath.Join(dir, "connections.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	lib.Connections = conns
+
+	snippets, err := LoadSnippets(filepath.Join(dir, "snippets"))
+	if err != nil {
+		return nil, err
+	}
+	lib.Snippets = snippets
+	return lib, nil
+}
+
+// loadConnections reads the connection templates. Passwords never come
+// from the shared file: connections with a user prompt for theirs.
+func loadConnections(path string) ([]config.DatabaseConfig, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var file struct {
+		Connections []config.DatabaseConfig `mapstructure:"connections"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	conns := make([]config.DatabaseConfig, 0, len(file.Connections))
+	for _, conn := range file.Connections {
+		if conn.Name == "" {
+			continue
+		}
+		conn.Password = ""
+		if conn.User != "" {
+			conn.PromptPassword = true
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// LoadSnippets reads every .sql file below dir, sorted by name
+func LoadSnippets(dir string) ([]Snippet, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	var snippets []Snippet
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sql") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		snippets = append(snippets, parseSnippet(name, string(data)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippets: %w", err)
+	}
+	sort.Slice(snippets, func(i, j int) bool { return snippets[i].Name < snippets[j].Name })
+	return snippets, nil
+}
+
+// parseSnippet takes the leading -- comment lines of a file as the
+// description and keeps the whole file as the SQL
+func parseSnippet(name, text string) Snippet {
+	var desc []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		if d := strings.TrimSpace(strings.TrimPrefix(line, "--")); d != "" {
+			desc = append(desc, d)
+		}
+	}
+	return Snippet{
+		Name:        name,
+		Description: strings.Join(desc, " "),
+		SQL:         strings.TrimSpace(text),
+	}
+}
+
+// Apply loads the library configured in cfg and merges its connections
+// into cfg. It returns nil without a shared library.
+func Apply(cfg *config.Config) (*Library, error) {
+	if !cfg.Shared.Enabled() {
+		return nil, nil
+	}
+	dir, err := cfg.Shared.SharedDir()
+	if err != nil {
+		return nil, err
+	}
+	lib, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MergeShared(lib.Connections)
+	return lib, nil
+}
+
+// Sync brings the shared directory up to date: it clones the repo when
+// the directory isn't a checkout yet and fast-forwards it otherwise. It
+// returns git's output.
+func Sync(ctx context.Context, cfg config.SharedConfig) (string, error) {
+	dir, err := cfg.SharedDir()
+	if err != nil {
+		return "", err
+	}
+
+	op, args := "pull", []string{"-C", dir, "pull", "--ff-only"}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if cfg.Repo == "" {
+			return "", fmt.Errorf("%s is not a git checkout and no shared repo is configured", dir)
+		}
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return "", err
+		}
+		op, args = "clone", []string{"clone", cfg.Repo, dir}
+	}
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// Fail instead of asking for credentials, which the TUI couldn't answer
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(out.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", op, err)
+		}
+		return "", fmt.Errorf("git %s: %s", op, msg)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
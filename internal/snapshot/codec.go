@@ -0,0 +1,154 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// resultSet is a db.ResultSet as stored on disk, with rows in column order
+type resultSet struct {
+	Columns     []string        `json:"columns"`
+	ColumnTypes []db.ColumnType `json:"column_types"`
+	Rows        [][]cell        `json:"rows"`
+	Truncated   bool            `json:"truncated,omitempty"`
+}
+
+// cell is a tagged db.Value so its Go type survives the round trip. Text is
+// the untagged default; types without a tag are stored as their text.
+type cell struct {
+	T string          `json:"t,omitempty"`
+	V json.RawMessage `json:"v,omitempty"`
+}
+
+// Cell type tags
+const (
+	tagText    = ""
+	tagNull    = "null"
+	tagInt     = "int"
+	tagUint    = "uint"
+	tagFloat   = "float"
+	tagDecimal = "decimal"
+	tagBool    = "bool"
+	tagTime    = "time"
+	tagBytes   = "bytes"
+)
+
+func encodeSet(set db.ResultSet) resultSet {
+	out := resultSet{
+		Columns:     set.Columns,
+		ColumnTypes: set.ColumnTypes,
+		Rows:        make([][]cell, len(set.Rows)),
+		Truncated:   set.Truncated,
+	}
+	for i, row := range set.Rows {
+		cells := make([]cell, len(set.Columns))
+		for j, col := range set.Columns {
+			cells[j] = encodeValue(row[col])
+		}
+		out.Rows[i] = cells
+	}
+	return out
+}
+
+func encodeValue(v db.Value) cell {
+	if v.Null {
+		return cell{T: tagNull}
+	}
+	tag := tagText
+	var data interface{}
+	switch d := v.Data.(type) {
+	case string:
+		data = d
+	case db.Decimal:
+		tag, data = tagDecimal, string(d)
+	case int64, int32, int16, int8, int:
+		tag, data = tagInt, d
+	case uint64, uint32, uint16, uint8, uint:
+		tag, data = tagUint, d
+	case float64, float32:
+		tag, data = tagFloat, d
+	case bool:
+		tag, data = tagBool, d
+	case time.Time:
+		tag, data = tagTime, d.Format(time.RFC3339Nano)
+	case []byte:
+		tag, data = tagBytes, d // base64
+	default:
+		data = v.String()
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		// NaN and Inf floats have no JSON form
+		tag = tagText
+		raw, _ = json.Marshal(v.String())
+	}
+	return cell{T: tag, V: raw}
+}
+
+func decodeSet(set resultSet) (db.ResultSet, error) {
+	out := db.ResultSet{
+		Columns:     set.Columns,
+		ColumnTypes: set.ColumnTypes,
+		Rows:        make([]db.Row, len(set.Rows)),
+		Truncated:   set.Truncated,
+	}
+	for i, cells := range set.Rows {
+		if len(cells) != len(set.Columns) {
+			return out, fmt.Errorf("row %d has %d cells for %d columns", i+1, len(cells), len(set.Columns))
+		}
+		row := make(db.Row, len(cells))
+		for j, c := range cells {
+			v, err := decodeValue(c)
+			if err != nil {
+				return out, fmt.Errorf("row %d, column %s: %w", i+1, set.Columns[j], err)
+			}
+			row[set.Columns[j]] = v
+		}
+		out.Rows[i] = row
+	}
+	return out, nil
+}
+
+func decodeValue(c cell) (db.Value, error) {
+	var err error
+	switch c.T {
+	case tagNull:
+		return db.Value{Null: true}, nil
+	case tagInt:
+		var n int64
+		err = json.Unmarshal(c.V, &n)
+		return db.Value{Data: n}, err
+	case tagUint:
+		var n uint64
+		err = json.Unmarshal(c.V, &n)
+		return db.Value{Data: n}, err
+	case tagFloat:
+		var f float64
+		err = json.Unmarshal(c.V, &f)
+		return db.Value{Data: f}, err
+	case tagBool:
+		var b bool
+		err = json.Unmarshal(c.V, &b)
+		return db.Value{Data: b}, err
+	case tagBytes:
+		var b []byte
+		err = json.Unmarshal(c.V, &b)
+		return db.Value{Data: b}, err
+	}
+
+	var s string
+	if err = json.Unmarshal(c.V, &s); err != nil {
+		return db.Value{}, err
+	}
+	switch c.T {
+	case tagDecimal:
+		return db.Value{Data: db.Decimal(s)}, nil
+	case tagTime:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return db.Value{Data: t}, err
+	}
+	return db.Value{Data: s}, nil
+}
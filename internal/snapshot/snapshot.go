@@ -0,0 +1,269 @@
+// Package snapshot saves query results to compressed files so they can be
+// re-opened offline later.
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// fileExt is the extension of snapshot files
+const fileExt = ".json.gz"
+
+// Meta describes a snapshot. It is the first line of the file, so listing
+// snapshots doesn't decompress their results.
+type Meta struct {
+	ID         string    `json:"-"` // file name without extension
+	Time       time.Time `json:"time"`
+	Connection string    `json:"connection"`
+	Driver     string    `json:"driver"`
+	Database   string    `json:"database,omitempty"`
+	Query      string    `json:"query"`
+	DurationMs int64     `json:"duration_ms"`
+	Rows       int       `json:"rows"`
+	Truncated  bool      `json:"truncated,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+}
+
+// Store is a directory of snapshots with retention limits
+type Store struct {
+	dir      string
+	maxCount int
+	maxAge   time.Duration
+	maxBytes int64
+}
+
+// NewStore opens the snapshot directory configured in cfg
+func NewStore(cfg config.SnapshotConfig) (*Store, error) {
+	dir, err := cfg.SnapshotDir()
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir}
+	s.maxCount, s.maxAge, s.maxBytes = cfg.Retention()
+	return s, nil
+}
+
+// Dir returns the snapshot directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes the result sets of a query and then applies the retention
+// limits. Files are readable by the owner only since they hold query data.
+func (s *Store) Save(meta Meta, sets []db.ResultSet) (Meta, error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return meta, fmt.Errorf("snapshot: %w", err)
+	}
+
+	meta.Rows = 0
+	for _, set := range sets {
+		meta.Rows += len(set.Rows)
+		meta.Truncated = meta.Truncated || set.Truncated
+	}
+	name := meta.Time.Format("20060102-150405.000")
+	if meta.Connection != "" {
+		name += "-" + fileSafe(meta.Connection)
+	}
+	meta.ID = name
+
+	path := filepath.Join(s.dir, name+fileExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return meta, fmt.Errorf("snapshot: %w", err)
+	}
+	err = write(f, meta, sets)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return meta, fmt.Errorf("snapshot: %w", err)
+	}
+
+	return meta, s.prune()
+}
+
+// write encodes the metadata line and the result sets as gzipped JSON
+func write(f *os.File, meta Meta, sets []db.ResultSet) error {
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(meta); err != nil {
+		return err
+	}
+	encoded := make([]resultSet, len(sets))
+	for i, set := range sets {
+		encoded[i] = encodeSet(set)
+	}
+	if err := enc.Encode(encoded); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// List returns the snapshots, newest first
+func (s *Store) List() ([]Meta, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+
+	var metas []Meta
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		id := strings.TrimSuffix(e.Name(), fileExt)
+		meta, _, err := s.read(id, false)
+		if err != nil {
+			// Unreadable files are skipped; one bad file must not hide the rest
+			continue
+		}
+		metas = append(metas, meta)
+	}
+	sort.Slice(metas, func(i, j int) bool { return metas[i].Time.After(metas[j].Time) })
+	return metas, nil
+}
+
+// Load reads a snapshot with its result sets
+func (s *Store) Load(id string) (Meta, []db.ResultSet, error) {
+	return s.read(id, true)
+}
+
+// read decodes a snapshot file, with the result sets only when withSets
+func (s *Store) read(id string, withSets bool) (Meta, []db.ResultSet, error) {
+	var meta Meta
+	f, err := os.Open(filepath.Join(s.dir, id+fileExt))
+	if err != nil {
+		return meta, nil, fmt.Errorf("snapshot: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return meta, nil, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	defer zr.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(zr))
+	if err := dec.Decode(&meta); err != nil {
+		return meta, nil, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	meta.ID = id
+	if !withSets {
+		return meta, nil, nil
+	}
+
+	var encoded []resultSet
+	if err := dec.Decode(&encoded); err != nil {
+		return meta, nil, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	sets := make([]db.ResultSet, len(encoded))
+	for i, set := range encoded {
+		if sets[i], err = decodeSet(set); err != nil {
+			return meta, nil, fmt.Errorf("snapshot %s: %w", id, err)
+		}
+	}
+	return meta, sets, nil
+}
+
+// SetTags replaces the tags of a snapshot. The file is rewritten with its
+// modification time kept, so retention still counts from when it was saved.
+func (s *Store) SetTags(id string, tags []string) (Meta, error) {
+	meta, sets, err := s.read(id, true)
+	if err != nil {
+		return meta, err
+	}
+	meta.Tags = tags
+
+	path := filepath.Join(s.dir, id+fileExt)
+	info, err := os.Stat(path)
+	if err != nil {
+		return meta, fmt.Errorf("snapshot: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, id+".*.tmp")
+	if err != nil {
+		return meta, fmt.Errorf("snapshot: %w", err)
+	}
+	err = write(tmp, meta, sets)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chtimes(tmp.Name(), info.ModTime(), info.ModTime())
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return meta, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	return meta, nil
+}
+
+// prune deletes the oldest snapshots past the count, age and size limits
+func (s *Store) prune() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+
+	type file struct {
+		name    string
+		modTime time.Time
+		size    int64
+	}
+	var files []file
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{e.Name(), info.ModTime(), info.Size()})
+	}
+	// Newest first; names start with the timestamp
+	sort.Slice(files, func(i, j int) bool { return files[i].name > files[j].name })
+
+	var total int64
+	for i, f := range files {
+		total += f.size
+		expired := (s.maxCount > 0 && i >= s.maxCount) ||
+			(s.maxAge > 0 && time.Since(f.modTime) > s.maxAge) ||
+			// The newest snapshot is always kept, even when it alone is too big
+			(s.maxBytes > 0 && total > s.maxBytes && i > 0)
+		if expired {
+			if err := os.Remove(filepath.Join(s.dir, f.name)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("snapshot: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// fileSafe replaces characters that don't belong in file names
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,116 @@
+// Package snippets keeps the user's own SQL snippets, one .sql file each
+// in the snippets directory of the config directory, in the same format as
+// the shared library. Snippets may hold tab stops: $1, ${2:placeholder}
+// and $0 for the final cursor position.
+package snippets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+)
+
+// defaults are written when the snippets directory doesn't exist yet, so
+// there is something to start from and to edit
+var defaults = map[string]string{
+	"selcount": "-- Count the rows of a table matching a condition\nSELECT COUNT(*) FROM ${1:table} WHERE ${2:condition};$0\n",
+	"selwhere": "-- Select the rows of a table matching a condition\nSELECT ${3:*} FROM ${1:table} WHERE ${2:condition} LIMIT ${4:100};$0\n",
+	"groupby":  "-- Count the rows of a table per value of a column\nSELECT ${2:column}, COUNT(*) AS n\nFROM ${1:table}\nGROUP BY ${2:column}\nORDER BY n DESC;$0\n",
+	"upd":      "-- Update the rows of a table matching a condition\nUPDATE ${1:table} SET ${2:column} = ${3:value} WHERE ${4:condition};$0\n",
+	"cte":      "-- Query through a common table expression\nWITH ${1:name} AS (\n    ${2:SELECT 1}\n)\nSELECT * FROM ${1:name};$0\n",
+}
+
+// Dir returns the snippets directory
+func Dir() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snippets"), nil
+}
+
+// Load reads the snippets in dir, sorted by name. The first time, when dir
+// doesn't exist, it is created with a few default snippets.
+func Load(dir string) ([]shared.Snippet, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if err := writeDefaults(dir); err != nil {
+			return nil, err
+		}
+	}
+	return shared.LoadSnippets(dir)
+}
+
+// writeDefaults creates dir with the default snippets
+func writeDefaults(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("snippets: %w", err)
+	}
+	for name, text := range defaults {
+		if err := os.WriteFile(filepath.Join(dir, name+".sql"), []byte(text), 0600); err != nil {
+			return fmt.Errorf("snippets: %w", err)
+		}
+	}
+	return nil
+}
+
+// Path returns the file of the snippet name in dir. Names may have
+// directories, e.g. billing/refunds, but must stay inside dir.
+func Path(dir, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if name == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("snippets: invalid name %q", name)
+	}
+	return filepath.Join(dir, clean+".sql"), nil
+}
+
+// Save writes the snippet name with the given SQL, replacing it if it
+// exists. A description becomes its leading -- comment.
+func Save(dir, name, description, sql string) error {
+	path, err := Path(dir, name)
+	if err != nil {
+		return err
+	}
+	var text strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			text.WriteString("-- " + line + "\n")
+		}
+	}
+	text.WriteString(strings.TrimSpace(sql) + "\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("snippets: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(text.String()), 0600); err != nil {
+		return fmt.Errorf("snippets: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the snippet name
+func Delete(dir, name string) error {
+	path, err := Path(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("snippets: %w", err)
+	}
+	return nil
+}
+
+// Body returns the SQL of a snippet without the leading comment lines that
+// describe it, as it is inserted into the editor
+func Body(s shared.Snippet) string {
+	lines := strings.Split(s.SQL, "\n")
+	for len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "--") {
+		lines = lines[1:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,472 @@
+package sqlparse
+
+import (
+	"strings"
+)
+
+// KeywordCase is how Format writes keywords
+type KeywordCase int
+
+const (
+	KeywordsUpper KeywordCase = iota
+	KeywordsLower
+	KeywordsPreserve
+)
+
+// FormatOptions sets the layout Format writes
+type FormatOptions struct {
+	KeywordCase KeywordCase
+	Indent      string // one level of indentation; two spaces when empty
+	CommaFirst  bool   // start list lines with the comma instead of ending them
+}
+
+// clauseKeywords start a clause on a line of its own
+var clauseKeywords = map[string]bool{
+	"select": true, "from": true, "where": true, "having": true, "limit": true,
+	"offset": true, "values": true, "set": true, "returning": true, "window": true,
+	"union": true, "intersect": true, "except": true, "insert": true, "update": true,
+	"delete": true, "group": true, "order": true, "create": true, "alter": true,
+	"drop": true, "truncate": true, "qualify": true,
+}
+
+// joinKeywords start a join; the first of them starts its line
+var joinKeywords = map[string]bool{
+	"join": true, "left": true, "right": true, "full": true, "inner": true,
+	"cross": true, "natural": true, "outer": true, "lateral": true,
+}
+
+// listClauses put each item of their list on its own line
+var listClauses = map[string]bool{
+	"select": true, "set": true, "returning": true, "values": true, "with": true,
+}
+
+// formatKeywords are the words Format writes in the keyword case; other
+// words are identifiers and keep their case
+var formatKeywords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		add all alter and any array as asc between by case cast check
+		collate column conflict constraint create cross current_date
+		current_time current_timestamp database default delete desc distinct
+		do drop else end except exists explain false fetch filter first
+		following for foreign from full group having if ilike in index inner
+		insert intersect interval into is join key lateral left like limit
+		materialized natural not nothing null nulls offset on or order outer
+		over partition preceding primary qualify range recursive references
+		replace returning right row rows select set similar table then ties
+		to top true truncate unbounded union unique update using values view
+		when where window with within
+		bigint bigserial boolean char decimal double float int integer jsonb
+		numeric precision real serial smallint text timestamp timestamptz
+		varchar
+		avg coalesce count greatest least lower max min nullif sum upper`) {
+		formatKeywords[w] = true
+	}
+}
+
+// parenKind is how a parenthesis lays out its content
+type parenKind int
+
+const (
+	parenInline parenKind = iota // function calls, IN lists: on one line
+	parenBlock                   // subqueries: indented on lines of their own
+	parenList                    // column definitions: one per line
+)
+
+// level is a statement or parenthesis being formatted
+type level struct {
+	kind       parenKind
+	depth      int    // indentation of its clauses
+	openIndent int    // indentation of the line with its (
+	clause     string // the clause being written
+	split      bool   // the clause puts each list item on its own line
+	caseDepth  int    // open CASE expressions
+	between    bool   // BETWEEN waits for its AND
+	create     bool   // CREATE TABLE waits for its column list
+}
+
+// formatter writes the tokens of Format
+type formatter struct {
+	opts    FormatOptions
+	src     string
+	tokens  []Token
+	out     strings.Builder
+	line    strings.Builder
+	indent  int // indentation of line
+	levels  []*level
+	prev    *Token // last token written on any line
+	newline bool   // the next token starts a line, after a line comment
+	blank   bool   // a blank line goes before the next token
+	skip    int    // tokens the current one already wrote
+}
+
+// Format lays out sql: each clause starts a line, the items of SELECT
+// lists and the conditions of WHERE are indented on lines of their own,
+// subqueries are indented and keywords are cased as opts says. Comments
+// and the text of strings and identifiers are kept.
+func Format(sql string, dialect Dialect, opts FormatOptions) string {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	f := &formatter{opts: opts, src: sql, levels: []*level{{kind: parenBlock}}}
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Kind != TokenSpace {
+			f.tokens = append(f.tokens, tok)
+		}
+	}
+	for i := 0; i < len(f.tokens); i++ {
+		f.token(i)
+		i += f.skip
+		f.skip = 0
+	}
+	f.flush()
+	return strings.TrimRight(f.out.String(), "\n")
+}
+
+// top returns the innermost level
+func (f *formatter) top() *level {
+	return f.levels[len(f.levels)-1]
+}
+
+// flush ends the current line
+func (f *formatter) flush() {
+	if f.line.Len() == 0 {
+		return
+	}
+	f.out.WriteString(strings.TrimRight(f.line.String(), " "))
+	f.out.WriteString("\n")
+	f.line.Reset()
+}
+
+// breakLine starts a new line at indent, unless the current one is empty
+func (f *formatter) breakLine(indent int) {
+	f.flush()
+	f.indent = indent
+	f.newline = false
+}
+
+// write adds text to the line, after a space when space is set
+func (f *formatter) write(text string, space bool) {
+	if f.newline {
+		f.breakLine(f.indent)
+	}
+	if f.line.Len() == 0 {
+		if f.blank && f.out.Len() > 0 {
+			f.out.WriteString("\n")
+		}
+		f.blank = false
+		f.line.WriteString(strings.Repeat(f.opts.Indent, f.indent))
+	} else if space {
+		f.line.WriteString(" ")
+	}
+	f.line.WriteString(text)
+}
+
+// word returns the lowercased word of token i, "" past the end or for
+// other tokens
+func (f *formatter) word(i int) string {
+	if i < 0 || i >= len(f.tokens) {
+		return ""
+	}
+	return f.tokens[i].Keyword()
+}
+
+// text returns token i in the keyword case when it is a keyword
+func (f *formatter) text(i int) string {
+	tok := f.tokens[i]
+	if tok.Kind != TokenWord || !formatKeywords[tok.Keyword()] {
+		return tok.Text
+	}
+	switch f.opts.KeywordCase {
+	case KeywordsUpper:
+		return strings.ToUpper(tok.Text)
+	case KeywordsLower:
+		return strings.ToLower(tok.Text)
+	}
+	return tok.Text
+}
+
+// startsClause reports whether token i starts a clause
+func (f *formatter) startsClause(i int) bool {
+	w := f.word(i)
+	switch {
+	case w == "":
+		return false
+	case clauseKeywords[w]:
+		// ORDER/GROUP only as ORDER BY; SET after DO UPDATE too
+		if w == "group" || w == "order" {
+			return f.word(i+1) == "by"
+		}
+		return true
+	case w == "with":
+		// WITH opens a statement; elsewhere it is e.g. WITH TIES
+		return i == 0 || f.tokens[i-1].Text == "(" || f.tokens[i-1].Text == ";"
+	case w == "join":
+		return !joinKeywords[f.word(i-1)]
+	case joinKeywords[w] && w != "outer" && w != "lateral":
+		if joinKeywords[f.word(i-1)] {
+			return false
+		}
+		for j := i + 1; j < len(f.tokens) && joinKeywords[f.word(j)]; j++ {
+			if f.word(j) == "join" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listHasMore reports whether the list starting at token i has more than
+// one item: a comma before the clause or parenthesis ends
+func (f *formatter) listHasMore(i int) bool {
+	depth := 0
+	for j := i; j < len(f.tokens); j++ {
+		switch f.tokens[j].Text {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				return true
+			}
+		case ";":
+			return false
+		}
+		if depth == 0 && f.tokens[j].Kind == TokenWord && f.startsClause(j) {
+			return false
+		}
+	}
+	return false
+}
+
+// space reports whether a space goes between the last token and token i
+func (f *formatter) space(i int) bool {
+	if f.prev == nil {
+		return false
+	}
+	tok, prev := f.tokens[i], *f.prev
+	adjacent := prev.End == tok.Start
+	switch {
+	case tok.Text == "," || tok.Text == ";" || tok.Text == ")" || tok.Text == "]":
+		return false
+	case tok.Text == "." || prev.Text == "." || prev.Text == "(" || prev.Text == "[":
+		return false
+	case tok.Text == "[":
+		return !adjacent
+	case tok.Text == "(":
+		// Calls keep the name and ( together; keywords and tables don't
+		if prev.Kind != TokenWord && prev.Kind != TokenQuotedIdent {
+			return true
+		}
+		w := prev.Keyword()
+		if formatKeywords[w] && !isFunction(w) {
+			return true
+		}
+		before := f.word(i - 2)
+		return before == "into" || before == "table" || before == "as"
+	case prev.Kind == TokenPunct && tok.Kind == TokenPunct && adjacent:
+		// Operators are tokenized a rune at a time: >=, ||, ::, ->>
+		return false
+	case tok.Text == ":" && i+1 < len(f.tokens) && f.tokens[i+1].Text == ":":
+		return false // x::int
+	case prev.Text == ":" && i >= 2 && f.tokens[i-2].Text == ":":
+		return false
+	case (prev.Text == ":" || prev.Text == "@") && adjacent:
+		return false // :name parameters, @variables
+	case (prev.Text == "-" || prev.Text == "+") && tok.Kind == TokenNumber && adjacent:
+		// A sign rather than an operator after another operator or keyword
+		if i < 2 {
+			return false
+		}
+		before := f.tokens[i-2]
+		return !(before.Kind == TokenPunct && before.Text != ")" || before.Kind == TokenWord && formatKeywords[before.Keyword()])
+	}
+	return true
+}
+
+// isFunction reports whether a keyword is a function called with (
+func isFunction(w string) bool {
+	switch w {
+	case "any", "array", "avg", "cast", "coalesce", "count", "greatest", "least",
+		"left", "lower", "max", "min", "nullif", "replace", "right", "row", "sum", "upper",
+		"char", "decimal", "float", "numeric", "timestamp", "timestamptz", "varchar":
+		return true
+	}
+	return false
+}
+
+// token writes token i
+func (f *formatter) token(i int) {
+	tok := f.tokens[i]
+	lvl := f.top()
+
+	switch {
+	case tok.Kind == TokenComment:
+		f.comment(i)
+		return
+
+	case tok.Text == ";":
+		f.write(";", false)
+		f.levels = f.levels[:1]
+		*f.levels[0] = level{kind: parenBlock}
+		f.breakLine(0)
+		f.blank = true
+		f.prev = nil
+		return
+
+	case tok.Text == "(":
+		f.open(i)
+		return
+
+	case tok.Text == ")":
+		f.close(i)
+		return
+
+	case tok.Text == ",":
+		f.comma(lvl)
+		f.prev = &f.tokens[i]
+		return
+	}
+
+	if tok.Kind == TokenWord && lvl.kind != parenInline && lvl.caseDepth == 0 && f.startsClause(i) {
+		f.clause(i, lvl)
+		return
+	}
+
+	w := tok.Keyword()
+	switch w {
+	case "case":
+		lvl.caseDepth++
+	case "end":
+		lvl.caseDepth = max(lvl.caseDepth-1, 0)
+	case "between":
+		lvl.between = true
+	case "table":
+		if lvl.clause == "create" {
+			lvl.create = true
+		}
+	case "and", "or":
+		if w == "and" && lvl.between {
+			lvl.between = false
+			break
+		}
+		if lvl.kind != parenInline && lvl.caseDepth == 0 {
+			f.breakLine(lvl.depth + 1)
+		}
+	}
+	f.write(f.text(i), f.space(i))
+	f.prev = &f.tokens[i]
+}
+
+// clause starts the clause of token i on a new line
+func (f *formatter) clause(i int, lvl *level) {
+	w := f.word(i)
+	f.breakLine(lvl.depth)
+	lvl.clause = w
+	lvl.between = false
+	f.write(f.text(i), false)
+	f.prev = &f.tokens[i]
+
+	// Multi-word keywords stay on the clause line: GROUP BY, UNION ALL,
+	// SELECT DISTINCT, INSERT INTO, LEFT OUTER JOIN
+	next := i + 1
+	for ; next < len(f.tokens); next++ {
+		nw := f.word(next)
+		if !(nw == "by" || nw == "all" || nw == "distinct" || nw == "into" ||
+			(joinKeywords[nw] && joinKeywords[w]) || (nw == "recursive" && w == "with")) {
+			break
+		}
+		f.write(f.text(next), true)
+		f.prev = &f.tokens[next]
+	}
+	f.skip = next - i - 1
+
+	lvl.split = listClauses[w] && w != "with" && f.listHasMore(next)
+	if lvl.split {
+		f.breakLine(lvl.depth + 1)
+	}
+}
+
+// comment writes the comment token i, after the code it trails when it was
+// on the same line
+func (f *formatter) comment(i int) {
+	tok := f.tokens[i]
+	trailing := f.prev != nil && f.line.Len() > 0 &&
+		!strings.Contains(f.src[f.prev.End:tok.Start], "\n")
+	if !trailing {
+		f.breakLine(f.indent)
+	}
+	f.write(tok.Text, trailing)
+	// Line comments run to the end of their line; block comments keep
+	// their own line when they had one
+	if !strings.HasPrefix(tok.Text, "/*") ||
+		i+1 < len(f.tokens) && strings.Contains(f.src[tok.End:f.tokens[i+1].Start], "\n") {
+		f.newline = true
+	}
+}
+
+// open writes the ( of token i and starts its level
+func (f *formatter) open(i int) {
+	lvl := f.top()
+	kind := parenInline
+	next := i + 1
+	for next < len(f.tokens) && f.tokens[next].Kind == TokenComment {
+		next++
+	}
+	if w := f.word(next); w == "select" || w == "with" {
+		kind = parenBlock
+	} else if lvl.create && lvl.kind != parenInline {
+		kind = parenList
+		lvl.create = false
+	}
+
+	f.write("(", f.space(i))
+	f.prev = &f.tokens[i]
+	if kind == parenInline {
+		f.levels = append(f.levels, &level{kind: kind, depth: lvl.depth, openIndent: f.indent})
+		return
+	}
+	f.levels = append(f.levels, &level{kind: kind, depth: f.indent + 1, openIndent: f.indent})
+	f.breakLine(f.indent + 1)
+}
+
+// close writes the ) of token i and ends its level; subqueries and column
+// lists close on a line of their own
+func (f *formatter) close(i int) {
+	if len(f.levels) > 1 {
+		lvl := f.top()
+		f.levels = f.levels[:len(f.levels)-1]
+		if lvl.kind != parenInline {
+			f.breakLine(lvl.openIndent)
+		}
+	}
+	f.write(")", false)
+	f.prev = &f.tokens[i]
+}
+
+// comma writes a comma of lvl, ending the line in lists laid out one
+// item per line
+func (f *formatter) comma(lvl *level) {
+	lines := lvl.kind == parenList ||
+		lvl.kind == parenBlock && lvl.caseDepth == 0 && (lvl.split || lvl.clause == "with")
+	if !lines {
+		f.write(",", false)
+		return
+	}
+	indent := lvl.depth + 1
+	if lvl.kind == parenList || lvl.clause == "with" {
+		indent = lvl.depth
+	}
+	if f.opts.CommaFirst {
+		f.breakLine(indent)
+		f.write(",", false)
+		return
+	}
+	f.write(",", false)
+	f.breakLine(indent)
+}
@@ -0,0 +1,46 @@
+package sqlparse
+
+// pagingKeywords are top-level clauses a query can't be paged past: it
+// limits its own rows, locks them or writes them somewhere
+var pagingKeywords = map[string]bool{
+	"limit":  true,
+	"offset": true,
+	"fetch":  true,
+	"top":    true,
+	"into":   true,
+	"for":    true,
+}
+
+// Pageable reports whether a single statement is a plain query that can
+// be run a page at a time by appending LIMIT/OFFSET: a read-only
+// SELECT, WITH or VALUES without a LIMIT or locking clause of its own
+func Pageable(sql string, dialect Dialect) bool {
+	var tokens []Token
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Kind != TokenSpace && tok.Kind != TokenComment {
+			tokens = append(tokens, tok)
+		}
+	}
+	if len(tokens) == 0 || classifyTokens(tokens) != KindQuery || !readOnlyTokens(tokens) {
+		return false
+	}
+	switch tokens[0].Keyword() {
+	case "select", "with", "values":
+	default:
+		return false
+	}
+
+	depth := 0
+	for _, tok := range tokens {
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 && pagingKeywords[tok.Keyword()] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package sqlparse
+
+import "testing"
+
+func TestPageable(t *testing.T) {
+	tests := []struct {
+		sql      string
+		dialect  Dialect
+		pageable bool
+	}{
+		{"SELECT * FROM users", DialectStandard, true},
+		{"select id, name from users order by name", DialectStandard, true},
+		{"WITH t AS (SELECT 1 AS n) SELECT n FROM t", DialectStandard, true},
+		{"VALUES (1), (2)", DialectStandard, true},
+		{"SELECT * FROM (SELECT * FROM users LIMIT 5) u", DialectStandard, true},
+		{"SELECT * FROM users WHERE id IN (SELECT id FROM admins FETCH FIRST 1 ROWS ONLY)", DialectStandard, true},
+		{"SELECT 'limit 5' AS s FROM users", DialectStandard, true},
+
+		{"SELECT * FROM users LIMIT 10", DialectStandard, false},
+		{"SELECT * FROM users OFFSET 10", DialectStandard, false},
+		{"SELECT * FROM users FETCH FIRST 10 ROWS ONLY", DialectStandard, false},
+		{"SELECT * FROM users FOR UPDATE", DialectStandard, false},
+		{"SELECT * INTO copy FROM users", DialectStandard, false},
+		{"INSERT INTO users VALUES (1)", DialectStandard, false},
+		{"UPDATE users SET name = 'x' RETURNING *", DialectStandard, false},
+		{"WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", DialectStandard, false},
+		{"EXPLAIN SELECT * FROM users", DialectStandard, false},
+		{"SHOW TABLES", DialectMySQL, false},
+		{"TABLE users", DialectStandard, false},
+		{"", DialectStandard, false},
+	}
+	for _, tt := range tests {
+		if got := Pageable(tt.sql, tt.dialect); got != tt.pageable {
+			t.Errorf("Pageable(%q) = %v, want %v", tt.sql, got, tt.pageable)
+		}
+	}
+}
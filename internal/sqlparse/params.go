@@ -0,0 +1,110 @@
+package sqlparse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParamStyle is the placeholder syntax a driver binds
+type ParamStyle int
+
+const (
+	// ParamDollar binds $1, $2, ... (Postgres, CockroachDB)
+	ParamDollar ParamStyle = iota
+	// ParamQuestion binds ? in order of appearance (MySQL, SQLite, Cassandra)
+	ParamQuestion
+)
+
+// ParamStyleFor returns the placeholder style for a connector driver name
+func ParamStyleFor(driver string) ParamStyle {
+	switch driver {
+	case "postgres", "postgresql", "cockroachdb", "cockroach":
+		return ParamDollar
+	}
+	return ParamQuestion
+}
+
+// Param is one placeholder occurrence in a statement
+type Param struct {
+	// Name is :name, $N, or ?N for the Nth question mark
+	Name  string
+	Start int
+	End   int
+}
+
+// Params finds the placeholders of sql outside strings and comments: :name
+// in every style, plus $N for ParamDollar or ? for ParamQuestion. Postgres
+// casts (::type) and slices (a[1:n]) are not placeholders.
+func Params(sql string, dialect Dialect, style ParamStyle) []Param {
+	var params []Param
+	tokens := Tokenize(sql, dialect)
+	questions := 0
+
+	for i, tok := range tokens {
+		if tok.Kind != TokenPunct {
+			continue
+		}
+		switch {
+		case tok.Text == ":":
+			if i+1 >= len(tokens) || tokens[i+1].Kind != TokenWord || tokens[i+1].Start != tok.End {
+				continue
+			}
+			if i > 0 && tokens[i-1].End == tok.Start {
+				switch prev := tokens[i-1]; {
+				case prev.Text == ":", prev.Kind == TokenWord, prev.Kind == TokenNumber, prev.Kind == TokenQuotedIdent:
+					continue
+				}
+			}
+			next := tokens[i+1]
+			params = append(params, Param{Name: ":" + next.Text, Start: tok.Start, End: next.End})
+		case style == ParamDollar && len(tok.Text) > 1 && tok.Text[0] == '$':
+			params = append(params, Param{Name: tok.Text, Start: tok.Start, End: tok.End})
+		case style == ParamQuestion && tok.Text == "?":
+			questions++
+			params = append(params, Param{Name: "?" + strconv.Itoa(questions), Start: tok.Start, End: tok.End})
+		}
+	}
+	return params
+}
+
+// ParamNames returns the distinct names of params in order of appearance
+func ParamNames(params []Param) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, p := range params {
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// Bind rewrites the placeholders of sql to style and returns the parameter
+// name bound to each driver argument. ParamDollar numbers each distinct
+// name once; ParamQuestion takes one argument per occurrence.
+func Bind(sql string, params []Param, style ParamStyle) (string, []string) {
+	var b strings.Builder
+	var args []string
+	numbers := make(map[string]int)
+	last := 0
+
+	for _, p := range params {
+		b.WriteString(sql[last:p.Start])
+		last = p.End
+		if style == ParamQuestion {
+			b.WriteString("?")
+			args = append(args, p.Name)
+			continue
+		}
+		n, ok := numbers[p.Name]
+		if !ok {
+			args = append(args, p.Name)
+			n = len(args)
+			numbers[p.Name] = n
+		}
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	b.WriteString(sql[last:])
+	return b.String(), args
+}
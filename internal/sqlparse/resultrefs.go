@@ -0,0 +1,80 @@
+package sqlparse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// resultRefPattern matches {{result.column}}, {{result.column[N]}} and
+// {{result.column[*]}}; quoted column names may hold any character but ".
+var resultRefPattern = regexp.MustCompile(`\{\{\s*result\.(\w+|"[^"]+")\s*(?:\[\s*(\d+|\*)\s*\])?\s*\}\}`)
+
+// ResultRef is a reference to values of the previous result in a query
+type ResultRef struct {
+	Column string
+	// Row is the zero-based row index, or -1 for the selected row
+	Row int
+	// All is set for column[*], every value of the column
+	All   bool
+	Start int
+	End   int
+}
+
+// ResultRefs finds the result references of sql outside strings, quoted
+// identifiers and comments
+func ResultRefs(sql string, dialect Dialect) []ResultRef {
+	matches := resultRefPattern.FindAllStringSubmatchIndex(sql, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	tokens := Tokenize(sql, dialect)
+
+	var refs []ResultRef
+	for _, m := range matches {
+		if inLiteral(tokens, m[0]) {
+			continue
+		}
+		ref := ResultRef{Column: strings.Trim(sql[m[2]:m[3]], `"`), Row: -1, Start: m[0], End: m[1]}
+		if m[4] >= 0 {
+			if index := sql[m[4]:m[5]]; index == "*" {
+				ref.All = true
+			} else if n, err := strconv.Atoi(index); err == nil {
+				ref.Row = n
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// inLiteral reports whether offset falls in a string, quoted identifier or
+// comment token
+func inLiteral(tokens []Token, offset int) bool {
+	for _, tok := range tokens {
+		if offset < tok.Start {
+			return false
+		}
+		if offset < tok.End {
+			switch tok.Kind {
+			case TokenString, TokenQuotedIdent, TokenComment:
+				return true
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// ReplaceRefs replaces each reference with the value at the same index
+func ReplaceRefs(sql string, refs []ResultRef, values []string) string {
+	var b strings.Builder
+	last := 0
+	for i, ref := range refs {
+		b.WriteString(sql[last:ref.Start])
+		b.WriteString(values[i])
+		last = ref.End
+	}
+	b.WriteString(sql[last:])
+	return b.String()
+}
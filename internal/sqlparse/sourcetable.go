@@ -0,0 +1,159 @@
+package sqlparse
+
+import "strings"
+
+// TableRef is a table named in a statement
+type TableRef struct {
+	Text string // as written, possibly schema-qualified and quoted
+	Name string // the table name without schema and quotes
+}
+
+// sourceClauses may follow the table of a single-table SELECT
+var sourceClauses = map[string]bool{
+	"where":  true,
+	"order":  true,
+	"limit":  true,
+	"offset": true,
+	"fetch":  true,
+	"for":    true,
+}
+
+// sourceRejects anywhere at the top level mean rows don't map one to one
+// onto rows of the table
+var sourceRejects = map[string]bool{
+	"join":      true,
+	"union":     true,
+	"intersect": true,
+	"except":    true,
+	"group":     true,
+	"having":    true,
+	"window":    true,
+}
+
+// SourceTable returns the table a query reads when each result row is one
+// row of that table with its columns unrenamed: a single SELECT of * or
+// plain column names from one table, without DISTINCT, joins, grouping or
+// set operations.
+func SourceTable(sql string, dialect Dialect) (TableRef, bool) {
+	var tokens []Token
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Kind != TokenSpace && tok.Kind != TokenComment {
+			tokens = append(tokens, tok)
+		}
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].Text == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 || tokens[0].Keyword() != "select" {
+		return TableRef{}, false
+	}
+
+	// Select list: plain, possibly qualified, columns or *
+	i := 1
+	from := -1
+	for start := i; i < len(tokens); i++ {
+		kw := tokens[i].Keyword()
+		if kw != "from" && tokens[i].Text != "," {
+			continue
+		}
+		if !plainColumn(tokens[start:i]) {
+			return TableRef{}, false
+		}
+		if kw == "from" {
+			from = i
+			break
+		}
+		start = i + 1
+	}
+	if from < 0 {
+		return TableRef{}, false
+	}
+
+	// The table, then an optional alias
+	i = from + 1
+	start := i
+	for i < len(tokens) && isIdent(tokens[i]) && !sourceClauses[tokens[i].Keyword()] {
+		i++
+		if i < len(tokens) && tokens[i].Text == "." {
+			i++
+			continue
+		}
+		break
+	}
+	if i == start || tokens[i-1].Text == "." {
+		return TableRef{}, false
+	}
+	ref := TableRef{
+		Text: sql[tokens[start].Start:tokens[i-1].End],
+		Name: unquoteIdent(tokens[i-1]),
+	}
+	if i < len(tokens) && tokens[i].Keyword() == "as" {
+		i++
+	}
+	if i < len(tokens) && isIdent(tokens[i]) && !sourceClauses[tokens[i].Keyword()] && !sourceRejects[tokens[i].Keyword()] {
+		i++
+	}
+	if i < len(tokens) && !sourceClauses[tokens[i].Keyword()] {
+		return TableRef{}, false
+	}
+
+	// The remaining clauses, outside subqueries
+	depth := 0
+	for _, tok := range tokens[i:] {
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",", ";":
+			if depth == 0 {
+				return TableRef{}, false
+			}
+		}
+		if depth == 0 && sourceRejects[tok.Keyword()] {
+			return TableRef{}, false
+		}
+	}
+	return ref, true
+}
+
+// plainColumn reports whether a select list item is *, a column or a
+// qualified column or *
+func plainColumn(item []Token) bool {
+	if len(item) == 0 || len(item)%2 == 0 {
+		return false
+	}
+	for i, tok := range item {
+		switch {
+		case i%2 == 1:
+			if tok.Text != "." {
+				return false
+			}
+		case tok.Text == "*":
+			if i != len(item)-1 {
+				return false
+			}
+		case !isIdent(tok) || tok.Keyword() == "distinct":
+			return false
+		}
+	}
+	return true
+}
+
+// isIdent reports whether tok is a bare or quoted identifier
+func isIdent(tok Token) bool {
+	return tok.Kind == TokenWord || tok.Kind == TokenQuotedIdent
+}
+
+// unquoteIdent returns an identifier's name without its quotes
+func unquoteIdent(tok Token) string {
+	if tok.Kind != TokenQuotedIdent || len(tok.Text) < 2 {
+		return tok.Text
+	}
+	open, close := tok.Text[0], tok.Text[len(tok.Text)-1]
+	name := tok.Text[1 : len(tok.Text)-1]
+	if open == '[' {
+		return name
+	}
+	return strings.ReplaceAll(name, string(close)+string(close), string(close))
+}
@@ -0,0 +1,356 @@
+package sqlparse
+
+import "strings"
+
+// Statement is one statement of a script with its byte offsets. Start is
+// the first significant token, so leading comments are not included.
+type Statement struct {
+	Text  string // without leading comments and the trailing semicolon
+	Start int
+	End   int
+}
+
+// Kind tells how a statement must be run
+type Kind int
+
+const (
+	// KindExec statements are run with Execute and report affected rows
+	KindExec Kind = iota
+	// KindQuery statements return rows and are run with Query
+	KindQuery
+)
+
+// Split splits a script into statements on semicolons outside strings,
+// comments and routine bodies (CREATE TRIGGER ... BEGIN ... END). Statements
+// holding only whitespace and comments are dropped.
+func Split(sql string, dialect Dialect) []Statement {
+	var statements []Statement
+	tokens := Tokenize(sql, dialect)
+
+	start := 0
+	depth := 0       // open BEGIN/CASE blocks in a routine body
+	routine := false // statement creates a trigger, procedure or function
+	meaningful := false
+	var words []string // leading keywords, to detect routines
+
+	flush := func(end int) {
+		if meaningful {
+			text := strings.TrimSpace(sql[start:end])
+			statements = append(statements, Statement{Text: text, Start: start, End: end})
+		}
+		depth, routine, meaningful, words = 0, false, false, words[:0]
+	}
+
+	for i, tok := range tokens {
+		switch tok.Kind {
+		case TokenSpace, TokenComment:
+			continue
+		case TokenPunct:
+			if tok.Text == ";" && depth == 0 {
+				flush(tok.Start)
+				start = tok.End
+				continue
+			}
+		case TokenWord:
+			kw := tok.Keyword()
+			if len(words) < 6 {
+				words = append(words, kw)
+				routine = routine || isRoutine(words)
+			}
+			if routine {
+				switch kw {
+				case "begin", "case":
+					depth++
+				case "end":
+					// END IF/LOOP/WHILE/REPEAT close blocks we don't count
+					switch nextKeyword(tokens, i) {
+					case "if", "loop", "while", "repeat":
+					default:
+						if depth > 0 {
+							depth--
+						}
+					}
+				}
+			}
+		}
+		if !meaningful {
+			start = tok.Start
+		}
+		meaningful = true
+	}
+	flush(len(sql))
+
+	return statements
+}
+
+// StatementAt returns the statement of a script under the byte offset. A
+// cursor between statements picks the previous one when it is still on the
+// line of its semicolon, otherwise the next one.
+func StatementAt(sql string, offset int, dialect Dialect) (Statement, bool) {
+	statements := Split(sql, dialect)
+	if len(statements) == 0 {
+		return Statement{}, false
+	}
+	for i, stmt := range statements {
+		if offset > stmt.End {
+			continue
+		}
+		if offset >= stmt.Start || i == 0 {
+			return stmt, true
+		}
+		prev := statements[i-1]
+		if !strings.Contains(sql[prev.End:offset], "\n") {
+			return prev, true
+		}
+		return stmt, true
+	}
+	return statements[len(statements)-1], true
+}
+
+// isRoutine reports whether leading keywords start a CREATE TRIGGER,
+// PROCEDURE, FUNCTION or EVENT statement, whose body may contain semicolons
+func isRoutine(words []string) bool {
+	if len(words) == 0 || words[0] != "create" {
+		return false
+	}
+	definer := false
+	for _, w := range words[1:] {
+		switch w {
+		case "trigger", "procedure", "function", "event":
+			return true
+		case "or", "replace", "temp", "temporary", "aggregate", "constraint":
+		case "definer":
+			// MySQL DEFINER = user@host precedes the routine kind
+			definer = true
+		default:
+			if !definer {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// nextKeyword returns the keyword of the next significant token after i
+func nextKeyword(tokens []Token, i int) string {
+	for _, tok := range tokens[i+1:] {
+		if tok.Kind == TokenSpace || tok.Kind == TokenComment {
+			continue
+		}
+		return tok.Keyword()
+	}
+	return ""
+}
+
+// queryKeywords start statements that return rows
+var queryKeywords = map[string]bool{
+	"select":   true,
+	"values":   true,
+	"table":    true, // Postgres TABLE name
+	"show":     true,
+	"describe": true,
+	"desc":     true,
+	"explain":  true,
+	"pragma":   true, // SQLite pragmas usually return data
+	"call":     true, // procedures may return result sets
+	"fetch":    true,
+	"check":    true, // MySQL CHECK/CHECKSUM/OPTIMIZE/REPAIR TABLE report
+	"checksum": true, // a result set
+	"optimize": true,
+	"repair":   true,
+}
+
+// dmlKeywords start statements that modify rows
+var dmlKeywords = map[string]bool{
+	"insert":  true,
+	"update":  true,
+	"delete":  true,
+	"merge":   true,
+	"replace": true,
+	"upsert":  true,
+}
+
+// Classify tells whether a single statement returns rows. CTEs are
+// classified by their main statement and DML by its RETURNING/OUTPUT clause.
+func Classify(sql string, dialect Dialect) Kind {
+	var tokens []Token
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Kind != TokenSpace && tok.Kind != TokenComment {
+			tokens = append(tokens, tok)
+		}
+	}
+	return classifyTokens(tokens)
+}
+
+// classifyTokens classifies significant (non-space, non-comment) tokens
+func classifyTokens(tokens []Token) Kind {
+	// Skip wrapping parentheses: (SELECT ...) UNION (SELECT ...)
+	for len(tokens) > 0 && tokens[0].Text == "(" {
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return KindExec
+	}
+
+	kw := tokens[0].Keyword()
+	switch {
+	case kw == "with":
+		if main := mainStatement(tokens); main > 0 {
+			return classifyTokens(tokens[main:])
+		}
+		return KindQuery
+	case kw == "analyze":
+		// MySQL ANALYZE TABLE returns a result set; Postgres ANALYZE doesn't
+		for _, tok := range tokens[1:] {
+			if k := tok.Keyword(); k == "table" {
+				return KindQuery
+			} else if k != "local" && k != "no_write_to_binlog" {
+				break
+			}
+		}
+		return KindExec
+	case queryKeywords[kw]:
+		return KindQuery
+	case dmlKeywords[kw]:
+		if hasReturning(tokens) {
+			return KindQuery
+		}
+	}
+	return KindExec
+}
+
+// mainStatement returns the index of the statement following a WITH clause:
+// the first SELECT/DML keyword outside the CTE bodies' parentheses
+func mainStatement(tokens []Token) int {
+	depth := 0
+	for i, tok := range tokens {
+		switch tok.Text {
+		case "(":
+			depth++
+			continue
+		case ")":
+			depth--
+			continue
+		}
+		if depth != 0 || i == 0 {
+			continue
+		}
+		if kw := tok.Keyword(); kw == "select" || kw == "values" || kw == "table" || dmlKeywords[kw] {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasReturning reports whether DML has a top-level RETURNING clause
+// (Postgres, SQLite, MariaDB) or OUTPUT inserted/deleted (SQL Server)
+func hasReturning(tokens []Token) bool {
+	depth := 0
+	for i, tok := range tokens {
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth != 0 {
+			continue
+		}
+		switch tok.Keyword() {
+		case "returning":
+			return true
+		case "output":
+			if i+1 < len(tokens) {
+				if next := tokens[i+1].Keyword(); next == "inserted" || next == "deleted" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// readOnlyKeywords start statements that never modify data
+var readOnlyKeywords = map[string]bool{
+	"show":      true,
+	"describe":  true,
+	"desc":      true,
+	"use":       true,
+	"commit":    true,
+	"end":       true,
+	"rollback":  true,
+	"savepoint": true,
+	"release":   true,
+}
+
+// IsReadOnly reports whether a single statement only reads data. It errs
+// on the side of writes: anything not known to be read-only is a write.
+func IsReadOnly(sql string, dialect Dialect) bool {
+	var tokens []Token
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Kind != TokenSpace && tok.Kind != TokenComment {
+			tokens = append(tokens, tok)
+		}
+	}
+	return readOnlyTokens(tokens)
+}
+
+// readOnlyTokens checks significant (non-space, non-comment) tokens
+func readOnlyTokens(tokens []Token) bool {
+	for len(tokens) > 0 && tokens[0].Text == "(" {
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return true
+	}
+
+	kw := tokens[0].Keyword()
+	switch kw {
+	case "select", "values", "table", "with":
+		// Data-modifying CTEs and SELECT ... INTO write. replace( and
+		// friends followed by a parenthesis are function calls.
+		for i, tok := range tokens {
+			k := tok.Keyword()
+			if k == "into" {
+				return false
+			}
+			if dmlKeywords[k] && (i+1 == len(tokens) || tokens[i+1].Text != "(") {
+				return false
+			}
+		}
+		return true
+	case "explain":
+		// EXPLAIN ANALYZE runs the statement it explains
+		for i, tok := range tokens[1:] {
+			if tok.Keyword() == "analyze" {
+				for j, inner := range tokens[i+2:] {
+					if k := inner.Keyword(); k == "select" || k == "with" || k == "values" || k == "table" || dmlKeywords[k] {
+						return readOnlyTokens(tokens[i+2+j:])
+					}
+				}
+				return false
+			}
+		}
+		return true
+	case "begin", "start":
+		// START TRANSACTION, not START REPLICA; neither may ask for READ WRITE
+		if kw == "start" && (len(tokens) < 2 || tokens[1].Keyword() != "transaction") {
+			return false
+		}
+		for i := 1; i+1 < len(tokens); i++ {
+			if tokens[i].Keyword() == "read" && tokens[i+1].Keyword() == "write" {
+				return false
+			}
+		}
+		return true
+	case "pragma":
+		// PRAGMA name = value changes settings; PRAGMA name(arg) reads
+		for _, tok := range tokens {
+			if tok.Text == "=" {
+				return false
+			}
+		}
+		return true
+	}
+	return readOnlyKeywords[kw]
+}
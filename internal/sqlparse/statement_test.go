@@ -0,0 +1,141 @@
+package sqlparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsReadOnly(t *testing.T) {
+	tests := []struct {
+		sql      string
+		dialect  Dialect
+		readOnly bool
+	}{
+		{"SELECT * FROM users", DialectStandard, true},
+		{"  -- leading comment\nSELECT 1", DialectStandard, true},
+		{"(SELECT 1) UNION (SELECT 2)", DialectStandard, true},
+		{"VALUES (1), (2)", DialectStandard, true},
+		{"TABLE users", DialectStandard, true},
+		{"WITH t AS (SELECT 1) SELECT * FROM t", DialectStandard, true},
+		{"SELECT replace(name, 'a', 'b') FROM users", DialectStandard, true},
+		{"SELECT 'delete from users' AS s", DialectStandard, true},
+		{"SELECT $$update t$$", DialectStandard, true},
+		{"SHOW TABLES", DialectMySQL, true},
+		{"DESCRIBE users", DialectMySQL, true},
+		{"EXPLAIN SELECT * FROM users", DialectStandard, true},
+		{"EXPLAIN ANALYZE SELECT * FROM users", DialectStandard, true},
+		{"PRAGMA table_info(users)", DialectStandard, true},
+		{"", DialectStandard, true},
+
+		{"BEGIN", DialectStandard, true},
+		{"BEGIN TRANSACTION READ ONLY", DialectStandard, true},
+		{"BEGIN ISOLATION LEVEL SERIALIZABLE, READ WRITE", DialectStandard, false},
+		{"START TRANSACTION", DialectMySQL, true},
+		{"START TRANSACTION READ ONLY", DialectMySQL, true},
+		{"START TRANSACTION READ WRITE", DialectMySQL, false},
+		{"START REPLICA", DialectMySQL, false},
+		{"START", DialectMySQL, false},
+		{"COMMIT", DialectStandard, true},
+		{"ROLLBACK", DialectStandard, true},
+
+		{"INSERT INTO users VALUES (1)", DialectStandard, false},
+		{"UPDATE users SET name = 'x'", DialectStandard, false},
+		{"DELETE FROM users", DialectStandard, false},
+		{"DROP TABLE users", DialectStandard, false},
+		{"SELECT * INTO copy FROM users", DialectStandard, false},
+		{"WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", DialectStandard, false},
+		{"EXPLAIN ANALYZE DELETE FROM users", DialectStandard, false},
+		{"PRAGMA journal_mode = WAL", DialectStandard, false},
+		{"SET search_path = x", DialectStandard, false},
+		{"CALL refresh()", DialectStandard, false},
+	}
+	for _, tt := range tests {
+		if got := IsReadOnly(tt.sql, tt.dialect); got != tt.readOnly {
+			t.Errorf("IsReadOnly(%q) = %v, want %v", tt.sql, got, tt.readOnly)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		dialect Dialect
+		want    []string
+	}{
+		{
+			name: "semicolons",
+			sql:  "SELECT 1; SELECT 2;",
+			want: []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name: "semicolons in strings and comments",
+			sql:  "SELECT ';'; -- a; b\nSELECT /* ; */ 2",
+			want: []string{"SELECT ';'", "SELECT /* ; */ 2"},
+		},
+		{
+			name: "dollar-quoted function body",
+			sql:  "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql; SELECT f()",
+			want: []string{"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql", "SELECT f()"},
+		},
+		{
+			name: "trigger body",
+			sql:  "CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = 1; END; SELECT 1",
+			want: []string{"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = 1; END", "SELECT 1"},
+		},
+		{
+			name: "comment-only statements are dropped",
+			sql:  "-- nothing\n;\n/* still nothing */",
+			want: nil,
+		},
+		{
+			name:    "MySQL backslash escape",
+			sql:     `SELECT 'a\';b'; SELECT 2`,
+			dialect: DialectMySQL,
+			want:    []string{`SELECT 'a\';b'`, "SELECT 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, st := range Split(tt.sql, tt.dialect) {
+				got = append(got, st.Text)
+				if tt.sql[st.Start:st.End] != st.Text {
+					t.Errorf("statement %q has offsets %d-%d", st.Text, st.Start, st.End)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want Kind
+	}{
+		{"SELECT 1", KindQuery},
+		{"(SELECT 1) UNION (SELECT 2)", KindQuery},
+		{"VALUES (1)", KindQuery},
+		{"SHOW TABLES", KindQuery},
+		{"WITH t AS (SELECT 1) SELECT * FROM t", KindQuery},
+		{"WITH t AS (SELECT 1) DELETE FROM a WHERE id IN (SELECT * FROM t)", KindExec},
+		{"WITH t AS (SELECT 1) DELETE FROM a RETURNING id", KindQuery},
+		{"INSERT INTO a VALUES (1)", KindExec},
+		{"INSERT INTO a VALUES (1) RETURNING id", KindQuery},
+		{"UPDATE a SET n = 1 WHERE id IN (SELECT id FROM b) RETURNING *", KindQuery},
+		{"INSERT INTO a SELECT 'returning' FROM b", KindExec},
+		{"DELETE FROM a -- returning\n", KindExec},
+		{"ANALYZE TABLE a", KindQuery},
+		{"ANALYZE a", KindExec},
+		{"CREATE TABLE a (id int)", KindExec},
+		{"", KindExec},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.sql, DialectStandard); got != tt.want {
+			t.Errorf("Classify(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
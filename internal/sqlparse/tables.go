@@ -0,0 +1,57 @@
+package sqlparse
+
+import "strings"
+
+// MentionedTables returns the names of tables that sql mentions as an
+// identifier, outside strings and comments, ignoring case. Schemas are
+// matched loosely: users matches public.users and the other way round.
+// Columns that share a table's name match too, which errs on the side of
+// guarding the table.
+func MentionedTables(sql string, dialect Dialect, tables []string) []string {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	// Identifiers, with qualified names joined by dots
+	var names []string
+	var parts []string
+	flush := func() {
+		if len(parts) > 0 {
+			names = append(names, strings.Join(parts, "."))
+			if len(parts) > 1 {
+				names = append(names, parts[len(parts)-1])
+			}
+		}
+		parts = nil
+	}
+	dotted := false
+	for _, tok := range Tokenize(sql, dialect) {
+		switch {
+		case isIdent(tok):
+			if !dotted {
+				flush()
+			}
+			parts = append(parts, unquoteIdent(tok))
+			dotted = false
+		case tok.Text == "." && len(parts) > 0:
+			dotted = true
+		case tok.Kind == TokenSpace || tok.Kind == TokenComment:
+		default:
+			flush()
+			dotted = false
+		}
+	}
+	flush()
+
+	var found []string
+	for _, table := range tables {
+		bare := table[strings.LastIndex(table, ".")+1:]
+		for _, name := range names {
+			if strings.EqualFold(name, table) || strings.EqualFold(name, bare) {
+				found = append(found, table)
+				break
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,71 @@
+package sqlparse
+
+import "strings"
+
+// Tags returns the #tags written in the comments of sql, e.g.
+// "-- #billing #incident-1234", lowercased and without duplicates
+func Tags(sql string, dialect Dialect) []string {
+	var tags []string
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Kind != TokenComment {
+			continue
+		}
+		// MySQL's # comments start with the tag itself
+		text := tok.Text
+		if strings.HasPrefix(text, "#") && !strings.HasPrefix(text, "#!") {
+			text = " " + text
+		}
+		for _, word := range strings.Fields(text) {
+			if strings.HasPrefix(word, "#") {
+				tags = addTag(tags, word)
+			}
+		}
+	}
+	return tags
+}
+
+// ParseTags reads a list of tags as typed, separated by spaces or commas,
+// with or without the leading #
+func ParseTags(text string) []string {
+	var tags []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		tags = addTag(tags, word)
+	}
+	return tags
+}
+
+// FormatTags writes tags as "#billing #incident-1234"
+func FormatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(tags, " #")
+}
+
+// addTag appends the tag in word, if any, unless tags already has it
+func addTag(tags []string, word string) []string {
+	tag := strings.ToLower(strings.TrimLeft(word, "#"))
+	tag = strings.TrimRight(tag, ".,;:!?)")
+	if tag == "" || !isTag(tag) {
+		return tags
+	}
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// isTag reports whether tag holds only letters, digits and - _ . / :
+func isTag(tag string) bool {
+	for _, r := range tag {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r > 127:
+		case strings.ContainsRune("-_./:", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
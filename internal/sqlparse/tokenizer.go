@@ -0,0 +1,243 @@
+// Package sqlparse provides a lightweight SQL tokenizer used to split
+// scripts into statements and to decide how each statement is executed.
+package sqlparse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dialect selects lexical rules that differ between databases
+type Dialect int
+
+const (
+	// DialectStandard follows Postgres/SQLite rules: backslashes are only
+	// escapes inside E'...' strings
+	DialectStandard Dialect = iota
+	// DialectMySQL treats backslashes as escapes in all strings and # as a
+	// line comment
+	DialectMySQL
+)
+
+// DialectFor returns the dialect for a connector driver name
+func DialectFor(driver string) Dialect {
+	switch driver {
+	case "mysql", "mariadb":
+		return DialectMySQL
+	}
+	return DialectStandard
+}
+
+// TokenKind is the kind of a lexical token
+type TokenKind int
+
+const (
+	TokenWord        TokenKind = iota // keyword or bare identifier
+	TokenQuotedIdent                  // "ident", `ident` or [ident]
+	TokenString                       // 'string', E'string' or $tag$string$tag$
+	TokenNumber
+	TokenComment // -- line or /* block */ comment
+	TokenPunct   // operators, parentheses, commas, semicolons
+	TokenSpace
+)
+
+// Token is a lexical token with its byte offsets in the source
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Start int
+	End   int
+}
+
+// Keyword returns the lowercased text for words and "" otherwise
+func (t Token) Keyword() string {
+	if t.Kind != TokenWord {
+		return ""
+	}
+	return strings.ToLower(t.Text)
+}
+
+// Tokenize splits sql into tokens. Unterminated strings, identifiers and
+// comments run to the end of the input.
+func Tokenize(sql string, dialect Dialect) []Token {
+	var tokens []Token
+	for i := 0; i < len(sql); {
+		kind, end := scanToken(sql, i, dialect)
+		tokens = append(tokens, Token{Kind: kind, Text: sql[i:end], Start: i, End: end})
+		i = end
+	}
+	return tokens
+}
+
+// scanToken scans one token starting at i and returns its kind and end offset
+func scanToken(sql string, i int, dialect Dialect) (TokenKind, int) {
+	c := sql[i]
+	switch {
+	case isSpace(c):
+		j := i + 1
+		for j < len(sql) && isSpace(sql[j]) {
+			j++
+		}
+		return TokenSpace, j
+
+	case strings.HasPrefix(sql[i:], "--"), c == '#' && dialect == DialectMySQL:
+		if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+			return TokenComment, i + j
+		}
+		return TokenComment, len(sql)
+
+	case strings.HasPrefix(sql[i:], "/*"):
+		if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+			return TokenComment, i + 2 + j + 2
+		}
+		return TokenComment, len(sql)
+
+	case c == '\'':
+		return TokenString, scanQuoted(sql, i, '\'', dialect == DialectMySQL)
+
+	case c == '"':
+		return TokenQuotedIdent, scanQuoted(sql, i, '"', dialect == DialectMySQL)
+
+	case c == '`':
+		return TokenQuotedIdent, scanQuoted(sql, i, '`', false)
+
+	case c == '[' && dialect == DialectStandard:
+		// SQLite accepts [ident]; MySQL and Postgres use [ for arrays
+		if j := strings.IndexByte(sql[i:], ']'); j >= 0 && isWordStart(sql, i+1) {
+			return TokenQuotedIdent, i + j + 1
+		}
+
+	case c == '$':
+		if tag, ok := dollarTag(sql, i); ok {
+			if j := strings.Index(sql[i+len(tag):], tag); j >= 0 {
+				return TokenString, i + len(tag) + j + len(tag)
+			}
+			return TokenString, len(sql)
+		}
+		// Positional parameter ($1) or stray dollar
+		j := i + 1
+		for j < len(sql) && sql[j] >= '0' && sql[j] <= '9' {
+			j++
+		}
+		return TokenPunct, j
+
+	case c >= '0' && c <= '9', c == '.' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+		j := i + 1
+		for j < len(sql) && (isWordByte(sql[j]) || sql[j] == '.') {
+			j++
+		}
+		return TokenNumber, j
+
+	case isWordStart(sql, i):
+		// E'...' (backslash escapes), N'...', X'...' and B'...' strings
+		if i+1 < len(sql) && sql[i+1] == '\'' && strings.IndexByte("eEnNxXbB", c) >= 0 {
+			escapes := c == 'e' || c == 'E' || dialect == DialectMySQL
+			return TokenString, scanQuoted(sql, i+1, '\'', escapes)
+		}
+		j := i
+		for j < len(sql) {
+			r, size := utf8.DecodeRuneInString(sql[j:])
+			if r != '_' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			j += size
+		}
+		return TokenWord, j
+	}
+
+	// Operators are emitted one rune at a time; splitting doesn't need more
+	_, size := utf8.DecodeRuneInString(sql[i:])
+	return TokenPunct, i + size
+}
+
+// scanQuoted scans a quoted token starting at i where a doubled quote is an
+// escape, and optionally a backslash escapes the next byte
+func scanQuoted(sql string, i int, quote byte, backslash bool) int {
+	end, _ := scanQuotedEnd(sql, i, quote, backslash)
+	return end
+}
+
+// scanQuotedEnd is scanQuoted, also reporting whether the closing quote
+// was found
+func scanQuotedEnd(sql string, i int, quote byte, backslash bool) (int, bool) {
+	for j := i + 1; j < len(sql); j++ {
+		switch {
+		case backslash && sql[j] == '\\':
+			j++
+		case sql[j] == quote:
+			if j+1 < len(sql) && sql[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1, true
+		}
+	}
+	return len(sql), false
+}
+
+// Unterminated reports whether a string, quoted identifier or block
+// comment runs to the end of the input without being closed
+func Unterminated(tok Token, dialect Dialect) bool {
+	text := tok.Text
+	switch tok.Kind {
+	case TokenComment:
+		return strings.HasPrefix(text, "/*") && (len(text) < 4 || !strings.HasSuffix(text, "*/"))
+
+	case TokenString:
+		if tag, ok := dollarTag(text, 0); ok && text[0] == '$' {
+			return len(text) < 2*len(tag) || !strings.HasSuffix(text, tag)
+		}
+		start, escapes := 0, dialect == DialectMySQL
+		if text[0] != '\'' {
+			// E'...', N'...', X'...', B'...'
+			start, escapes = 1, escapes || text[0] == 'e' || text[0] == 'E'
+		}
+		_, closed := scanQuotedEnd(text, start, '\'', escapes)
+		return !closed
+
+	case TokenQuotedIdent:
+		switch text[0] {
+		case '[':
+			return !strings.HasSuffix(text, "]")
+		case '"':
+			_, closed := scanQuotedEnd(text, 0, '"', dialect == DialectMySQL)
+			return !closed
+		}
+		_, closed := scanQuotedEnd(text, 0, text[0], false)
+		return !closed
+	}
+	return false
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string at i
+func dollarTag(sql string, i int) (string, bool) {
+	j := i + 1
+	for j < len(sql) && sql[j] != '$' {
+		if !isWordByte(sql[j]) || (j == i+1 && sql[j] >= '0' && sql[j] <= '9') {
+			return "", false
+		}
+		j++
+	}
+	if j >= len(sql) {
+		return "", false
+	}
+	return sql[i : j+1], true
+}
+
+// isWordStart reports whether a word (keyword or identifier) starts at i
+func isWordStart(sql string, i int) bool {
+	if i >= len(sql) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(sql[i:])
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,126 @@
+package sqlparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		dialect Dialect
+		want    []Token
+	}{
+		{
+			name: "words and punctuation",
+			sql:  "SELECT a,1",
+			want: []Token{
+				{TokenWord, "SELECT", 0, 6},
+				{TokenSpace, " ", 6, 7},
+				{TokenWord, "a", 7, 8},
+				{TokenPunct, ",", 8, 9},
+				{TokenNumber, "1", 9, 10},
+			},
+		},
+		{
+			name: "doubled quote inside a string",
+			sql:  "'it''s';",
+			want: []Token{
+				{TokenString, "'it''s'", 0, 7},
+				{TokenPunct, ";", 7, 8},
+			},
+		},
+		{
+			name: "backslash is literal in standard strings",
+			sql:  `'a\' x`,
+			want: []Token{
+				{TokenString, `'a\'`, 0, 4},
+				{TokenSpace, " ", 4, 5},
+				{TokenWord, "x", 5, 6},
+			},
+		},
+		{
+			name:    "backslash escapes in MySQL strings",
+			sql:     `'a\'b'`,
+			dialect: DialectMySQL,
+			want:    []Token{{TokenString, `'a\'b'`, 0, 6}},
+		},
+		{
+			name: "E string escapes",
+			sql:  `E'a\'b'`,
+			want: []Token{{TokenString, `E'a\'b'`, 0, 7}},
+		},
+		{
+			name: "dollar quotes hide semicolons and quotes",
+			sql:  "$fn$ a; 'b $fn$",
+			want: []Token{{TokenString, "$fn$ a; 'b $fn$", 0, 15}},
+		},
+		{
+			name: "positional parameter",
+			sql:  "$1",
+			want: []Token{{TokenPunct, "$1", 0, 2}},
+		},
+		{
+			name: "quoted identifiers",
+			sql:  "\"a b\" `c`",
+			want: []Token{
+				{TokenQuotedIdent, `"a b"`, 0, 5},
+				{TokenSpace, " ", 5, 6},
+				{TokenQuotedIdent, "`c`", 6, 9},
+			},
+		},
+		{
+			name: "line and block comments",
+			sql:  "-- x;\n/* y; */",
+			want: []Token{
+				{TokenComment, "-- x;", 0, 5},
+				{TokenSpace, "\n", 5, 6},
+				{TokenComment, "/* y; */", 6, 14},
+			},
+		},
+		{
+			name:    "hash comment in MySQL",
+			sql:     "# x\n1",
+			dialect: DialectMySQL,
+			want: []Token{
+				{TokenComment, "# x", 0, 3},
+				{TokenSpace, "\n", 3, 4},
+				{TokenNumber, "1", 4, 5},
+			},
+		},
+		{
+			name: "unterminated string runs to the end",
+			sql:  "'abc",
+			want: []Token{{TokenString, "'abc", 0, 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.sql, tt.dialect); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %v, want %v", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnterminated(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"'abc'", false},
+		{"'abc", true},
+		{"$$ body", true},
+		{"$$ body $$", false},
+		{"/* open", true},
+		{"/* closed */", false},
+		{`"ident`, true},
+	}
+	for _, tt := range tests {
+		tokens := Tokenize(tt.sql, DialectStandard)
+		if got := Unterminated(tokens[0], DialectStandard); got != tt.want {
+			t.Errorf("Unterminated(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Package timefilter renders common time predicates ("last 24 hours",
+// "this week", a range of days) in the SQL of each driver, since the
+// syntax for timestamps and date arithmetic differs between engines.
+package timefilter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Preset is a kind of time filter
+type Preset int
+
+const (
+	Last24Hours Preset = iota
+	Last7Days
+	Today
+	Yesterday
+	ThisWeek
+	LastWeek
+	ThisMonth
+	LastMonth
+	// Between covers the days From to To of a Range, both included
+	Between
+)
+
+// Presets returns every preset in menu order
+func Presets() []Preset {
+	return []Preset{Last24Hours, Last7Days, Today, Yesterday, ThisWeek, LastWeek, ThisMonth, LastMonth, Between}
+}
+
+// String returns the label of the preset
+func (p Preset) String() string {
+	switch p {
+	case Last24Hours:
+		return "Last 24 hours"
+	case Last7Days:
+		return "Last 7 days"
+	case Today:
+		return "Today"
+	case Yesterday:
+		return "Yesterday"
+	case ThisWeek:
+		return "This week"
+	case LastWeek:
+		return "Last week"
+	case ThisMonth:
+		return "This month"
+	case LastMonth:
+		return "Last month"
+	case Between:
+		return "Between two dates"
+	}
+	return "Unknown"
+}
+
+// Range is a preset, with the days to cover for Between
+type Range struct {
+	Preset Preset
+	From   time.Time // Between: first day
+	To     time.Time // Between: last day, included
+	// WeekStart is the first day of a week, Monday or Sunday
+	WeekStart time.Weekday
+}
+
+// ParseWeekStart reads a week_start setting; anything but "sunday" is
+// Monday
+func ParseWeekStart(s string) time.Weekday {
+	if strings.EqualFold(strings.TrimSpace(s), "sunday") {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// Predicate renders a filter of column, an SQL expression, to r for the
+// driver. Relative presets use the server's clock where the dialect has
+// date arithmetic; other drivers get literal bounds computed from now.
+func Predicate(driver, column string, r Range, now time.Time) string {
+	d, ok := dialects[driver]
+	if !ok || r.Preset == Between {
+		from, to := r.Bounds(now)
+		lit := literalFor(driver)
+		return halfOpen(column, lit(from), lit(to))
+	}
+
+	switch r.Preset {
+	case Last24Hours:
+		return fmt.Sprintf("%s >= %s", column, d.hoursAgo(24))
+	case Last7Days:
+		return fmt.Sprintf("%s >= %s", column, d.hoursAgo(7*24))
+	case Today:
+		return fmt.Sprintf("%s >= %s", column, d.day(0))
+	case Yesterday:
+		return halfOpen(column, d.day(-1), d.day(0))
+	case ThisWeek:
+		return fmt.Sprintf("%s >= %s", column, d.week(0, r.WeekStart))
+	case LastWeek:
+		return halfOpen(column, d.week(-1, r.WeekStart), d.week(0, r.WeekStart))
+	case ThisMonth:
+		return fmt.Sprintf("%s >= %s", column, d.month(0))
+	case LastMonth:
+		return halfOpen(column, d.month(-1), d.month(0))
+	}
+	return ""
+}
+
+// halfOpen renders from <= column < to
+func halfOpen(column, from, to string) string {
+	return fmt.Sprintf("%s >= %s AND %s < %s", column, from, column, to)
+}
+
+// Bounds returns the start and the exclusive end of r in now's location.
+// Relative presets without an end run until the next midnight.
+func (r Range) Bounds(now time.Time) (from, to time.Time) {
+	today := startOfDay(now)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) - int(r.WeekStart) + 7) % 7))
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+
+	switch r.Preset {
+	case Last24Hours:
+		return now.Add(-24 * time.Hour), tomorrow
+	case Last7Days:
+		return now.Add(-7 * 24 * time.Hour), tomorrow
+	case Today:
+		return today, tomorrow
+	case Yesterday:
+		return today.AddDate(0, 0, -1), today
+	case ThisWeek:
+		return weekStart, tomorrow
+	case LastWeek:
+		return weekStart.AddDate(0, 0, -7), weekStart
+	case ThisMonth:
+		return monthStart, tomorrow
+	case LastMonth:
+		return monthStart.AddDate(0, -1, 0), monthStart
+	}
+
+	from, to = startOfDay(r.From), startOfDay(r.To)
+	if to.Before(from) {
+		from, to = to, from
+	}
+	return from, to.AddDate(0, 0, 1)
+}
+
+// startOfDay returns midnight of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dialect renders the building blocks of relative presets
+type dialect struct {
+	hoursAgo func(n int) string                     // now minus n hours
+	day      func(n int) string                     // midnight n days from today
+	week     func(n int, start time.Weekday) string // start of the week n weeks from this one
+	month    func(n int) string                     // start of the month n months from this one
+}
+
+// dialects by driver; drivers missing here get literal bounds
+var dialects = map[string]dialect{
+	"postgres":    postgres,
+	"cockroachdb": postgres,
+	"mysql":       mysql,
+	"mariadb":     mysql,
+	"sqlite3":     sqlite,
+	"bigquery":    bigquery,
+}
+
+// offset renders " - interval" style suffixes for a negative n
+func offset(n int, format string) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(format, -n)
+}
+
+var postgres = dialect{
+	hoursAgo: func(n int) string { return fmt.Sprintf("now() - interval '%d hours'", n) },
+	day:      func(n int) string { return "current_date" + offset(n, " - %d") },
+	week: func(n int, start time.Weekday) string {
+		// date_trunc weeks start on Monday
+		base := "date_trunc('week', now())"
+		if start == time.Sunday {
+			base = "(date_trunc('week', now() + interval '1 day') - interval '1 day')"
+		}
+		return base + offset(n, " - interval '%d week'")
+	},
+	month: func(n int) string { return "date_trunc('month', now())" + offset(n, " - interval '%d month'") },
+}
+
+var mysql = dialect{
+	hoursAgo: func(n int) string { return fmt.Sprintf("NOW() - INTERVAL %d HOUR", n) },
+	day:      func(n int) string { return "CURDATE()" + offset(n, " - INTERVAL %d DAY") },
+	week: func(n int, start time.Weekday) string {
+		base := "CURDATE() - INTERVAL WEEKDAY(CURDATE()) DAY"
+		if start == time.Sunday {
+			base = "CURDATE() - INTERVAL (DAYOFWEEK(CURDATE()) - 1) DAY"
+		}
+		return base + offset(n, " - INTERVAL %d WEEK")
+	},
+	month: func(n int) string {
+		return "CURDATE() - INTERVAL (DAYOFMONTH(CURDATE()) - 1) DAY" + offset(n, " - INTERVAL %d MONTH")
+	},
+}
+
+// SQLite works in UTC on text timestamps, which compare as strings
+var sqlite = dialect{
+	hoursAgo: func(n int) string { return fmt.Sprintf("datetime('now', '-%d hours')", n) },
+	day:      func(n int) string { return "date('now'" + offset(n, ", '-%d days'") + ")" },
+	week: func(n int, start time.Weekday) string {
+		return fmt.Sprintf("date('now', '-6 days', 'weekday %d'", int(start)) + offset(7*n, ", '-%d days'") + ")"
+	},
+	month: func(n int) string { return "date('now', 'start of month'" + offset(n, ", '-%d months'") + ")" },
+}
+
+// BigQuery compares TIMESTAMP columns; dates are converted with TIMESTAMP()
+var bigquery = dialect{
+	hoursAgo: func(n int) string { return fmt.Sprintf("TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL %d HOUR)", n) },
+	day: func(n int) string {
+		if n == 0 {
+			return "TIMESTAMP(CURRENT_DATE())"
+		}
+		return fmt.Sprintf("TIMESTAMP(DATE_SUB(CURRENT_DATE(), INTERVAL %d DAY))", -n)
+	},
+	week: func(n int, start time.Weekday) string {
+		trunc := "DATE_TRUNC(CURRENT_DATE(), WEEK(MONDAY))"
+		if start == time.Sunday {
+			trunc = "DATE_TRUNC(CURRENT_DATE(), WEEK(SUNDAY))"
+		}
+		if n == 0 {
+			return "TIMESTAMP(" + trunc + ")"
+		}
+		return fmt.Sprintf("TIMESTAMP(DATE_SUB(%s, INTERVAL %d WEEK))", trunc, -n)
+	},
+	month: func(n int) string {
+		if n == 0 {
+			return "TIMESTAMP(DATE_TRUNC(CURRENT_DATE(), MONTH))"
+		}
+		return fmt.Sprintf("TIMESTAMP(DATE_SUB(DATE_TRUNC(CURRENT_DATE(), MONTH), INTERVAL %d MONTH))", -n)
+	},
+}
+
+// literalFor returns how the driver writes a timestamp literal
+func literalFor(driver string) func(time.Time) string {
+	switch driver {
+	case "postgres", "cockroachdb", "mysql", "mariadb", "bigquery":
+		return func(t time.Time) string { return "TIMESTAMP '" + t.Format("2006-01-02 15:04:05") + "'" }
+	case "cassandra":
+		return func(t time.Time) string { return "'" + t.Format("2006-01-02 15:04:05-0700") + "'" }
+	}
+	return func(t time.Time) string { return "'" + t.Format("2006-01-02 15:04:05") + "'" }
+}
@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/ai"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// aiResultMsg carries the SQL an AI request returned in the background
+type aiResultMsg struct {
+	id          int
+	mode        components.AIPromptMode
+	sql         string
+	isSelection bool
+	elapsed     time.Duration
+	err         error
+}
+
+// aiDoneMsg reports an AI request that was not cancelled
+type aiDoneMsg aiResultMsg
+
+// aiController runs AI requests in the background, one at a time. Its
+// Update drops the results of cancelled requests and passes the rest on to
+// the model as aiDoneMsg.
+type aiController struct {
+	provider ai.Provider
+	running  bool
+	// id tells cancelled results apart
+	id int
+}
+
+// Update handles the results of AI requests
+func (c aiController) Update(msg tea.Msg) (aiController, tea.Cmd) {
+	if msg, ok := msg.(aiResultMsg); ok {
+		if msg.id != c.id || !c.running {
+			// Cancelled
+			return c, nil
+		}
+		c.running = false
+		return c, func() tea.Msg { return aiDoneMsg(msg) }
+	}
+	return c, nil
+}
+
+// configured reports whether an AI provider is set up
+func (c aiController) configured() bool {
+	return c.provider != nil && c.provider.IsConfigured()
+}
+
+// start runs request in the background; the request itself runs to its
+// end even when cancelled
+func (c *aiController) start(mode components.AIPromptMode, isSelection bool, request func() (string, error)) tea.Cmd {
+	c.running = true
+	c.id++
+	id := c.id
+
+	return func() tea.Msg {
+		start := time.Now()
+		sql, err := request()
+		return aiResultMsg{id: id, mode: mode, sql: sql, isSelection: isSelection, elapsed: time.Since(start), err: err}
+	}
+}
+
+// cancel drops the running request, reporting whether there was one
+func (c *aiController) cancel() bool {
+	if !c.running {
+		return false
+	}
+	c.running = false
+	return true
+}
+
+// info describes the provider and model for the status bar
+func (c aiController) info() string {
+	if !c.configured() {
+		return "AI: Disabled"
+	}
+	return fmt.Sprintf("%s: %s", c.provider.GetProviderName(), c.provider.GetModelName())
+}
+
+// aiReady reports whether an AI provider is configured and free
+func (m *Model) aiReady() bool {
+	if !m.ai.configured() {
+		m.statusMessage = "AI not configured"
+		m.isError = true
+		return false
+	}
+	if m.ai.running {
+		m.statusMessage = "An AI request is already running"
+		m.isError = true
+		return false
+	}
+	return true
+}
+
+// GenerateSQL uses AI to generate SQL from natural language
+func (m *Model) GenerateSQL(prompt string) tea.Cmd {
+	if !m.aiReady() {
+		return nil
+	}
+
+	provider, schema := m.ai.provider, m.schema
+	return m.startAI(components.AIPromptModeNL2SQL, false, func() (string, error) {
+		return provider.NL2SQL(prompt, schema)
+	})
+}
+
+// RefactorSQL uses AI to refactor SQL
+func (m *Model) RefactorSQL(instruction string) tea.Cmd {
+	if !m.aiReady() {
+		return nil
+	}
+
+	// Use selected text if available, otherwise full content
+	currentSQL := m.editor.GetSelectedText()
+	isSelection := currentSQL != m.editor.GetValue()
+	if currentSQL == "" {
+		m.statusMessage = "No SQL to refactor"
+		m.isError = true
+		return nil
+	}
+
+	provider, schema := m.ai.provider, m.schema
+	return m.startAI(components.AIPromptModeRefactor, isSelection, func() (string, error) {
+		return provider.RefactorSQL(currentSQL, instruction, schema)
+	})
+}
+
+// startAI runs an AI request in the background. Esc drops its result
+// through cancelAI.
+func (m *Model) startAI(mode components.AIPromptMode, isSelection bool, request func() (string, error)) tea.Cmd {
+	m.statusMessage = "Asking " + m.ai.provider.GetProviderName() + "... (Esc to cancel)"
+	m.isError = false
+	return m.ai.start(mode, isSelection, request)
+}
+
+// cancelAI drops the running AI request, reporting whether there was one
+func (m *Model) cancelAI() bool {
+	if !m.ai.cancel() {
+		return false
+	}
+	m.statusMessage = "AI request cancelled"
+	m.isError = false
+	return true
+}
+
+// handleAIDone puts the SQL of a finished AI request in the editor
+func (m *Model) handleAIDone(msg aiDoneMsg) {
+	if msg.err != nil {
+		m.statusMessage = "AI error: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+
+	m.editor.SetValue(msg.sql)
+	switch {
+	case msg.mode == components.AIPromptModeNL2SQL:
+		m.statusMessage = "SQL generated by AI"
+	case msg.isSelection:
+		m.statusMessage = "Selected SQL refactored by AI"
+	default:
+		m.statusMessage = "SQL refactored by AI"
+	}
+	m.statusMessage += " in " + msg.elapsed.Round(time.Millisecond).String()
+	m.isError = false
+}
+
+// GetAIInfo returns the AI provider info string
+func (m *Model) GetAIInfo() string {
+	return m.ai.info()
+}
@@ -0,0 +1,36 @@
+package tui
+
+import "testing"
+
+func TestAIControllerDropsCancelledResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		msgID    int
+		running  bool
+		wantDone bool
+	}{
+		{"current request", 2, true, true},
+		{"earlier request", 1, true, false},
+		{"cancelled request", 2, false, false},
+	}
+	for _, tt := range tests {
+		c := aiController{id: 2, running: tt.running}
+		c, cmd := c.Update(aiResultMsg{id: tt.msgID, sql: "SELECT 1"})
+		// A dropped result leaves the current request running
+		if want := tt.running && !tt.wantDone; c.running != want {
+			t.Errorf("%s: running = %v, want %v", tt.name, c.running, want)
+		}
+		if !tt.wantDone {
+			if cmd != nil {
+				t.Errorf("%s: passed on a dropped result", tt.name)
+			}
+			continue
+		}
+		if cmd == nil {
+			t.Fatalf("%s: dropped the result", tt.name)
+		}
+		if done, ok := cmd().(aiDoneMsg); !ok || done.sql != "SELECT 1" {
+			t.Errorf("%s: got %#v, want an aiDoneMsg with the SQL", tt.name, cmd())
+		}
+	}
+}
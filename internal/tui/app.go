@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/csvimport"
+	"github.com/febritecno/sqdesk-cli/internal/datafile"
 )
 
 // App represents the SQDesk TUI application
@@ -29,6 +32,8 @@ func New() (*App, error) {
 		model,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
+		tea.WithReportFocus(),
+		tea.WithOutput(terminal),
 	)
 
 	return &App{
@@ -37,18 +42,45 @@ func New() (*App, error) {
 	}, nil
 }
 
-// Run starts the TUI application
-func (a *App) Run() error {
-	// Try to connect to database if configured
-	if !a.model.config.FirstRun {
-		if err := a.model.Connect(); err != nil {
-			a.model.statusMessage = "Connection failed: " + err.Error()
-			a.model.isError = true
-			// Continue anyway, user can reconfigure
-		}
+// NewViewer creates an application that shows the rows of a CSV, JSON or
+// Parquet file read-only, without connecting to a database
+func NewViewer(path string) (*App, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Run the program
+	// Read the file before the screen switches, so errors print plainly
+	opts := datafile.Options{
+		Locale:    csvimport.NewLocale(cfg.CSVImport.DateOrder, cfg.CSVImport.DecimalSeparator, cfg.CSVImport.ThousandsSeparator),
+		Delimiter: csvDelimiter(cfg.CSVImport),
+	}
+	opts.MaxRows, _ = cfg.ResultLimit()
+	set, err := datafile.Load(context.Background(), path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	model := NewModel(cfg)
+	model.startViewer(path, set)
+
+	program := tea.NewProgram(
+		model,
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+		tea.WithReportFocus(),
+		tea.WithOutput(terminal),
+	)
+
+	return &App{
+		model:   model,
+		program: program,
+	}, nil
+}
+
+// Run starts the TUI application
+func (a *App) Run() error {
+	// Run the program; Init connects to the configured database
 	if _, err := a.program.Run(); err != nil {
 		return fmt.Errorf("application error: %w", err)
 	}
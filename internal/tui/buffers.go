@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"github.com/febritecno/sqdesk-cli/internal/buffers"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// saveBuffer saves the editor text and undo history under the connection
+// the editor holds. Saving is best effort: the session goes on without it.
+func (m *Model) saveBuffer() {
+	if m.buffers == nil || m.bufferConn == "" {
+		return
+	}
+	eb := m.editor.Buffer()
+	b := buffers.Buffer{
+		Connection: m.bufferConn,
+		Text:       eb.Text,
+		Cursor:     eb.Cursor,
+		History:    make([]buffers.State, len(eb.History)),
+		Index:      max(eb.Index, 0),
+	}
+	for i, s := range eb.History {
+		b.History[i] = buffers.State{Text: s.Text, Cursor: s.Cursor}
+	}
+	m.buffers.Save(b)
+}
+
+// switchBuffer saves the editor under the connection it holds and loads
+// the text and undo history saved for connection. Without one the editor
+// keeps its text.
+func (m *Model) switchBuffer(connection string) {
+	if m.buffers == nil || connection == m.bufferConn {
+		return
+	}
+	m.saveBuffer()
+	m.bufferConn = connection
+
+	b, ok, err := m.buffers.Load(connection)
+	if err != nil || !ok {
+		return
+	}
+	eb := components.EditorBuffer{
+		Text:    b.Text,
+		Cursor:  b.Cursor,
+		History: make([]components.EditorState, len(b.History)),
+		Index:   b.Index,
+	}
+	for i, s := range b.History {
+		eb.History[i] = components.EditorState{Text: s.Text, Cursor: s.Cursor}
+	}
+	if len(eb.History) == 0 {
+		eb.Index = -1
+	}
+	m.editor.SetBuffer(eb)
+}
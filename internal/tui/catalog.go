@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// catalog is what the sidebar and completion show of a database: loaded in
+// the background so a slow server doesn't freeze the UI
+type catalog struct {
+	tables     []string
+	tablesErr  error
+	partitions map[string]*db.PartitionInfo
+	views      []db.View
+	schema     *db.Schema // nil when it failed to load
+	databases  []string   // nil when they failed to load
+	currentDB  string
+}
+
+// loadCatalog reads the tables, partitions, views, schema and databases of
+// the current database. It runs off the event loop.
+func loadCatalog(connector db.Connector) catalog {
+	var c catalog
+	c.tables, c.tablesErr = connector.GetTables()
+	if c.tablesErr == nil {
+		partitions, err := db.GetPartitions(connector)
+		if err != nil {
+			// Partitioning is optional metadata, show tables flat
+			partitions = map[string]*db.PartitionInfo{}
+		}
+		c.partitions = partitions
+		// Views are optional metadata; the tables are still usable
+		c.views, _ = db.GetViews(connector)
+	}
+	if schema, err := connector.GetSchema(); err == nil {
+		c.schema = schema
+	}
+	if databases, err := connector.GetDatabases(); err == nil {
+		c.databases = databases
+		c.currentDB = connector.GetDatabaseName()
+	}
+	return c
+}
+
+// applyCatalog shows a loaded catalog in the sidebar and completion,
+// reporting whether its tables loaded
+func (m *Model) applyCatalog(c catalog) bool {
+	if c.tablesErr == nil {
+		m.tables = c.tables
+		m.sidebar.SetTables(c.tables)
+		m.setPartitions(c.partitions)
+		m.setViews(c.views)
+	}
+	if c.schema != nil {
+		m.applySchema(c.schema)
+	}
+	if c.databases != nil {
+		m.sidebar.SetDatabases(c.databases, c.currentDB)
+	}
+	return c.tablesErr == nil
+}
+
+// databaseSwitchedMsg carries the outcome of a background database switch
+type databaseSwitchedMsg struct {
+	name    string
+	catalog catalog
+	err     error
+}
+
+// SwitchDatabase switches to a different database in the background and
+// reloads the catalog; the result arrives as a databaseSwitchedMsg. The
+// switch counts as a running query so nothing else uses the connection
+// while it is replaced.
+func (m *Model) SwitchDatabase(dbName string) tea.Cmd {
+	if m.conn.connector == nil {
+		m.statusMessage = "Not connected"
+		m.isError = true
+		return nil
+	}
+	if !m.conn.capabilities.SupportsSwitchDatabase {
+		m.statusMessage = fmt.Sprintf("%s does not support switching databases", m.conn.connector.GetDriverName())
+		m.isError = true
+		return nil
+	}
+	if m.queryRunning {
+		// The switch would close the connection under the query
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return nil
+	}
+	connector := m.conn.connector
+	m.queryRunning = true
+	m.statusMessage = "Switching to database " + dbName + "..."
+	m.isError = false
+
+	return func() tea.Msg {
+		msg := databaseSwitchedMsg{name: dbName}
+		if msg.err = connector.SwitchDatabase(dbName); msg.err == nil {
+			msg.catalog = loadCatalog(connector)
+		}
+		return msg
+	}
+}
+
+// handleDatabaseSwitched applies a finished database switch and remembers
+// the database for the next start
+func (m *Model) handleDatabaseSwitched(msg databaseSwitchedMsg) {
+	m.queryRunning = false
+	if msg.err != nil {
+		m.statusMessage = "Failed to switch: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+	m.applyCatalog(msg.catalog)
+
+	m.config.LastDatabase = msg.name
+	m.config.Save()
+
+	m.statusMessage = "Switched to database: " + msg.name
+	m.isError = false
+}
+
+// setPartitions keeps the partitioning info and nests partitions in the
+// sidebar
+func (m *Model) setPartitions(partitions map[string]*db.PartitionInfo) {
+	m.partitions = partitions
+
+	sidebarParts := make(map[string][]components.TablePartition)
+	for table, info := range partitions {
+		for _, p := range info.Partitions {
+			sidebarParts[table] = append(sidebarParts[table], components.TablePartition{
+				Name:   p.Name,
+				Target: p.Target,
+			})
+		}
+	}
+	m.sidebar.SetPartitions(sidebarParts)
+}
+
+// setViews keeps the views of the current database and lists them in the
+// sidebar
+func (m *Model) setViews(views []db.View) {
+	m.views = views
+
+	names := make([]string, len(views))
+	materialized := make(map[string]bool)
+	for i, v := range views {
+		names[i] = v.Name
+		materialized[v.Name] = v.Materialized
+	}
+	m.sidebar.SetViews(names, materialized)
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// cellDetail is the value shown by the cell detail modal
+type cellDetail struct {
+	column string
+	dbType string
+	text   string
+	null   bool
+	pretty string // text indented as JSON, "" when it isn't JSON
+	raw    bool   // show text even when pretty is set
+}
+
+// ShowCellDetail shows the selected cell's full value, wrapped, with JSON
+// pretty-printed
+func (m *Model) ShowCellDetail() {
+	text, null, ok := m.results.SelectedCell()
+	if !ok {
+		m.statusMessage = "No cell selected"
+		m.isError = true
+		return
+	}
+	set := m.results.ActiveResult()
+	col := m.results.SelectedColumn()
+	m.cellDetail = &cellDetail{
+		column: set.Columns[col],
+		dbType: set.ColumnTypes[col].DatabaseType,
+		text:   text,
+		null:   null,
+	}
+	if !null {
+		m.cellDetail.pretty = prettyJSON(text)
+	}
+	m.refreshCellDetail()
+	m.state = StateCellDetail
+}
+
+// prettyJSON indents text when it holds a JSON object or array
+func prettyJSON(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(trimmed), "", "  "); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// shown returns the value as the modal shows it
+func (d *cellDetail) shown() string {
+	if d.pretty != "" && !d.raw {
+		return d.pretty
+	}
+	return d.text
+}
+
+// refreshCellDetail renders cellDetail into the info panel
+func (m *Model) refreshCellDetail() {
+	d := m.cellDetail
+	rows := []components.InfoRow{{Label: "Column", Value: d.column}}
+	if d.dbType != "" {
+		rows = append(rows, components.InfoRow{Label: "Type", Value: d.dbType})
+	}
+
+	value := d.shown()
+	keys := "y: copy"
+	switch {
+	case d.null:
+		rows = append(rows, components.InfoRow{Label: "Value", Value: "NULL"})
+		value = ""
+	case d.pretty != "":
+		format := "JSON, pretty-printed"
+		if d.raw {
+			format = "JSON, as stored"
+		}
+		rows = append(rows, components.InfoRow{Label: "Format", Value: format})
+		keys += " • p: pretty/raw"
+	}
+	if !d.null {
+		length := fmt.Sprintf("%d chars", len([]rune(d.text)))
+		if lines := strings.Count(d.text, "\n") + 1; lines > 1 {
+			length += fmt.Sprintf(", %d lines", lines)
+		}
+		rows = append(rows, components.InfoRow{Label: "Length", Value: length})
+	}
+
+	sections := []components.InfoSection{{Rows: rows}}
+	if value != "" {
+		sections = append(sections, components.InfoSection{Title: "Value", Text: value})
+	}
+	m.infoPanel.Show("🔎 "+d.column, sections)
+	m.infoPanel.SetKeys(keys)
+}
+
+// updateCellDetail handles the cell detail modal
+func (m *Model) updateCellDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.cellDetail
+	switch msg.String() {
+	case "y":
+		if err := clipboard.WriteAll(d.shown()); err != nil {
+			m.statusMessage = "Copy failed: " + err.Error()
+			m.isError = true
+			return m, nil
+		}
+		m.statusMessage = "Value of " + d.column + " copied to clipboard"
+		if d.null {
+			m.statusMessage = "Cell is NULL; copied an empty value"
+		}
+		m.isError = false
+		return m, nil
+	case "p":
+		if d.pretty != "" {
+			d.raw = !d.raw
+			m.refreshCellDetail()
+		}
+		return m, nil
+	case "esc", "q", "enter":
+		m.cellDetail = nil
+	}
+	return m.updateInfo(msg)
+}
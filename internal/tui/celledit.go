@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// rowChangeDoneMsg carries the outcome of the UPDATE of an edited cell or
+// the DELETE of a row
+type rowChangeDoneMsg struct {
+	row       db.Row
+	column    string   // the edited column; empty for a delete
+	value     db.Value // the new value of column
+	affected  int64
+	elapsed   time.Duration
+	cancelled bool
+	err       error
+}
+
+// rowTarget is the table row behind the selected result row
+type rowTarget struct {
+	table     sqlparse.TableRef
+	tableName string // as listed, for GetColumns and messages
+	where     string // matches the row by its primary key
+	row       db.Row
+}
+
+// withSelectedRow finds the table row of the selected result row and passes
+// it to use. Only results of a single-table SELECT whose rows include the
+// table's primary key map back to table rows; the table's columns are read
+// in the background.
+func (m *Model) withSelectedRow(use func(target rowTarget) tea.Cmd) tea.Cmd {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return nil
+	}
+	if m.readOnly() {
+		m.statusMessage = "Connection is read-only"
+		m.isError = true
+		return nil
+	}
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil
+	}
+	set := m.results.ActiveResult()
+	rowIdx := m.results.SelectedRowIndex()
+	if rowIdx < 0 {
+		m.statusMessage = "No row selected"
+		m.isError = true
+		return nil
+	}
+
+	driver := m.conn.connector.GetDriverName()
+	ref, ok := sqlparse.SourceTable(set.Query, sqlparse.DialectFor(driver))
+	if !ok {
+		m.statusMessage = "Only results of a single-table SELECT of plain columns can be changed"
+		m.isError = true
+		return nil
+	}
+	tableName := m.resolveTable(ref.Name)
+	var columns []db.Column
+	read := func(connector db.Connector) error {
+		var err error
+		columns, err = connector.GetColumns(tableName)
+		return err
+	}
+	return m.introspect("Reading columns of "+tableName, read, func(err error) tea.Cmd {
+		if err != nil {
+			m.statusMessage = "Failed to read columns: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		target, ok := m.findRowTarget(set, rowIdx, driver, ref, tableName, columns)
+		if !ok {
+			return nil
+		}
+		return use(target)
+	})
+}
+
+// findRowTarget maps a result row back to its table row by the table's primary
+// key
+func (m *Model) findRowTarget(set components.ResultSet, rowIdx int, driver string, ref sqlparse.TableRef, tableName string, columns []db.Column) (rowTarget, bool) {
+	// Every result column must be a column of the table, and the primary
+	// key must be among them to find the row
+	known := make(map[string]bool, len(columns))
+	var pk []string
+	for _, col := range columns {
+		known[col.Name] = true
+		if col.IsPK {
+			pk = append(pk, col.Name)
+		}
+	}
+	if len(pk) == 0 {
+		m.statusMessage = "Table " + tableName + " has no primary key"
+		m.isError = true
+		return rowTarget{}, false
+	}
+	shown := make(map[string]int, len(set.Columns))
+	for i, col := range set.Columns {
+		if !known[col] {
+			m.statusMessage = fmt.Sprintf("Column %s is not a column of %s", col, tableName)
+			m.isError = true
+			return rowTarget{}, false
+		}
+		shown[col] = i
+	}
+	row := set.Rows[rowIdx]
+	var where []string
+	for _, col := range pk {
+		i, ok := shown[col]
+		if !ok {
+			m.statusMessage = "Include the primary key (" + strings.Join(pk, ", ") + ") in the results to change them"
+			m.isError = true
+			return rowTarget{}, false
+		}
+		where = append(where, db.QuoteIdentifier(driver, col)+" = "+sqlLiteral(m.conn.connector, row[col], set.ColumnTypes[i]))
+	}
+	return rowTarget{table: ref, tableName: tableName, where: strings.Join(where, " AND "), row: row}, true
+}
+
+// EditCell asks for a new value of the selected cell and, after confirming
+// the UPDATE, writes it
+func (m *Model) EditCell() tea.Cmd {
+	// The selection may move while the columns are read
+	set := m.results.ActiveResult()
+	colIdx := m.results.SelectedColumn()
+	current, null, _ := m.results.SelectedCell()
+	if null {
+		current = "NULL"
+	}
+	return m.withSelectedRow(func(target rowTarget) tea.Cmd {
+		m.editCell(target, set, colIdx, current, null)
+		return nil
+	})
+}
+
+// editCell asks for the new value of a cell of a table row
+func (m *Model) editCell(target rowTarget, set components.ResultSet, colIdx int, current string, null bool) {
+	driver := m.conn.connector.GetDriverName()
+	column := set.Columns[colIdx]
+	colType := set.ColumnTypes[colIdx]
+
+	message := fmt.Sprintf("%s.%s (NULL sets NULL)", target.tableName, column)
+	m.askInput("✏️ Edit Cell", message, current, func(text string) tea.Cmd {
+		value := db.Value{Data: text}
+		if strings.EqualFold(text, "null") {
+			value = db.Value{Null: true}
+		}
+		if value.Null == null && (null || text == current) {
+			m.statusMessage = "Value unchanged"
+			m.isError = false
+			return nil
+		}
+
+		sql := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s",
+			target.table.Text, db.QuoteIdentifier(driver, column), sqlLiteral(m.conn.connector, value, colType), target.where)
+		m.askConfirm("✏️ Update Row", sql, func() tea.Cmd {
+			return m.guardProtected(m.protectedTablesIn(sql), func() tea.Cmd {
+				return m.runRowChange(sql, "Updating row", rowChangeDoneMsg{row: target.row, column: column, value: value})
+			})
+		})
+		return nil
+	})
+}
+
+// DeleteRow deletes the table row behind the selected result row after
+// confirming the DELETE
+func (m *Model) DeleteRow() tea.Cmd {
+	return m.withSelectedRow(func(target rowTarget) tea.Cmd {
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s", target.table.Text, target.where)
+		m.askConfirm("🗑 Delete Row", sql, func() tea.Cmd {
+			return m.guardProtected(m.protectedTablesIn(sql), func() tea.Cmd {
+				return m.runRowChange(sql, "Deleting row", rowChangeDoneMsg{row: target.row})
+			})
+		})
+		return nil
+	})
+}
+
+// resolveTable returns the listed table matching name, ignoring case as
+// unquoted names do, or name itself
+func (m *Model) resolveTable(name string) string {
+	for _, t := range m.tables {
+		if t == name {
+			return t
+		}
+	}
+	for _, t := range m.tables {
+		if strings.EqualFold(t, name) {
+			return t
+		}
+	}
+	return name
+}
+
+// runRowChange runs the UPDATE or DELETE of a result row in the background,
+// reporting it with done
+func (m *Model) runRowChange(sql, label string, done rowChangeDoneMsg) tea.Cmd {
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil
+	}
+	connector := m.conn.connector
+	timeout := m.config.QueryTimeoutFor(m.config.GetActiveConnection())
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	m.statusMessage = label + "... (Esc to cancel)"
+	m.isError = false
+
+	run := func() tea.Msg {
+		msg := done
+		start := time.Now()
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			var err error
+			msg.affected, err = connector.Execute(ctx, sql)
+			return err
+		})
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+	return tea.Batch(run, m.results.StartRunning(label))
+}
+
+// handleRowChangeDone shows the new value once the row was updated, or
+// drops the row once deleted
+func (m *Model) handleRowChangeDone(msg rowChangeDoneMsg) {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+
+	switch {
+	case msg.cancelled:
+		m.statusMessage = "Cancelled"
+		m.isError = true
+		return
+	case msg.err != nil:
+		m.statusMessage = "Failed: " + msg.err.Error()
+		m.isError = true
+		return
+	case msg.affected == 0:
+		// The row changed or went away since it was fetched
+		m.statusMessage = "No row changed: the row no longer matches its primary key"
+		m.isError = true
+		return
+	}
+
+	if msg.column == "" {
+		m.results.RemoveRow(msg.row)
+		m.statusMessage = fmt.Sprintf("Deleted row (%d rows affected) in %s", msg.affected, msg.elapsed.Round(time.Millisecond))
+		m.isError = false
+		return
+	}
+	m.results.UpdateCell(msg.row, msg.column, msg.value)
+	m.statusMessage = fmt.Sprintf("Updated %s (%d rows affected) in %s", msg.column, msg.affected, msg.elapsed.Round(time.Millisecond))
+	m.isError = false
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// expandResultRefs replaces the {{result.column}} references of sql with
+// literals from the result shown in the results pane, so a query can use
+// the values of the one before it
+func (m *Model) expandResultRefs(sql string) (string, error) {
+	driver := m.conn.connector.GetDriverName()
+	refs := sqlparse.ResultRefs(sql, sqlparse.DialectFor(driver))
+	if len(refs) == 0 {
+		return sql, nil
+	}
+
+	set := m.results.ActiveResult()
+	if len(set.Columns) == 0 {
+		return "", fmt.Errorf("no result to take {{result...}} values from")
+	}
+	values := make([]string, len(refs))
+	for i, ref := range refs {
+		col, ok := resultColumn(set, ref.Column)
+		if !ok {
+			return "", fmt.Errorf("result has no column %q", ref.Column)
+		}
+		if ref.All {
+			list := columnLiterals(m.conn.connector, set, col)
+			if len(list) == 0 {
+				return "", fmt.Errorf("column %q has no values", ref.Column)
+			}
+			values[i] = strings.Join(list, ", ")
+			continue
+		}
+
+		row := ref.Row
+		if row < 0 {
+			if row = m.results.SelectedRowIndex(); row < 0 {
+				return "", fmt.Errorf("no row selected for {{result.%s}}", ref.Column)
+			}
+		}
+		if row >= len(set.Rows) {
+			return "", fmt.Errorf("result has %d rows, no row %d", len(set.Rows), row)
+		}
+		values[i] = sqlLiteral(m.conn.connector, set.Rows[row][set.Columns[col]], set.ColumnTypes[col])
+	}
+	return sqlparse.ReplaceRefs(sql, refs, values), nil
+}
+
+// resultColumn finds a column by name, exactly or else ignoring case
+func resultColumn(set components.ResultSet, name string) (int, bool) {
+	for i, col := range set.Columns {
+		if col == name {
+			return i, true
+		}
+	}
+	for i, col := range set.Columns {
+		if strings.EqualFold(col, name) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// columnLiterals returns the distinct non-NULL values of a column as SQL
+// literals, in row order
+func columnLiterals(c db.Connector, set components.ResultSet, col int) []string {
+	seen := make(map[string]bool)
+	var list []string
+	for _, row := range set.Rows {
+		v := row[set.Columns[col]]
+		if v.Null {
+			// NULL never matches IN
+			continue
+		}
+		lit := sqlLiteral(c, v, set.ColumnTypes[col])
+		if !seen[lit] {
+			seen[lit] = true
+			list = append(list, lit)
+		}
+	}
+	return list
+}
+
+// sqlLiteral formats a result value as an SQL literal: numbers and
+// booleans bare, everything else as a string quoted for c
+func sqlLiteral(c db.Connector, v db.Value, colType db.ColumnType) string {
+	if v.Null {
+		return "NULL"
+	}
+	if b, ok := v.Data.(bool); ok {
+		if b {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	text := components.CellText(v, colType)
+	if colType.IsNumeric() {
+		// Only well-formed numbers go in unquoted
+		if _, err := strconv.ParseFloat(text, 64); err == nil {
+			return text
+		}
+	}
+	return db.QuoteLiteralFor(c, text)
+}
+
+// ShowInListPicker opens the column picker that inserts the values of a
+// result column into the editor as an IN list
+func (m *Model) ShowInListPicker() {
+	set := m.results.ActiveResult()
+	if len(set.Columns) == 0 || len(set.Rows) == 0 {
+		m.statusMessage = "No result values to insert"
+		m.isError = true
+		return
+	}
+	if m.conn.connector == nil {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+
+	items := make([]components.VariableItem, len(set.Columns))
+	for i, col := range set.Columns {
+		list := columnLiterals(m.conn.connector, set, i)
+		preview := list
+		if len(preview) > 10 {
+			preview = append(preview[:10:10], "…")
+		}
+		items[i] = components.VariableItem{
+			Name:        col,
+			Value:       fmt.Sprintf("%d distinct values", len(list)),
+			Description: "(" + strings.Join(preview, ", ") + ")",
+			Key:         col,
+		}
+	}
+	m.columnPicker.Show("📋 Insert Values as IN List", items)
+	m.state = StateColumnPicker
+}
+
+// insertInList inserts the distinct values of a result column at the editor
+// cursor, e.g. (1, 2, 3)
+func (m *Model) insertInList(column string) {
+	set := m.results.ActiveResult()
+	col, ok := resultColumn(set, column)
+	if !ok || m.conn.connector == nil {
+		return
+	}
+	list := columnLiterals(m.conn.connector, set, col)
+	if len(list) == 0 {
+		m.statusMessage = "Column " + column + " has only NULL values"
+		m.isError = true
+		return
+	}
+
+	m.editor.InsertText("(" + strings.Join(list, ", ") + ")")
+	m.FocusEditor()
+	m.statusMessage = fmt.Sprintf("Inserted %d values of %s", len(list), column)
+	if set.Truncated {
+		m.statusMessage += " (result was truncated)"
+	}
+	m.isError = false
+}
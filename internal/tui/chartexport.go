@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/chartimage"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// chartExportOptions are the export menu entries of the chart shown in
+// results, if any
+func (m *Model) chartExportOptions() []exportOption {
+	if !m.results.IsChart() {
+		return nil
+	}
+	return []exportOption{
+		{label: "📈 Chart as text", chart: "txt"},
+		{label: "🖼  Chart as PNG image", chart: "png"},
+	}
+}
+
+// ExportChart writes the chart shown in results to a file in the current
+// directory: as text like in the pane, or as a PNG image of its shapes
+func (m *Model) ExportChart(format string) {
+	path := fmt.Sprintf("sqdesk-chart-%s.%s", time.Now().Format("20060102-150405"), format)
+	f, err := os.Create(path)
+	if err != nil {
+		m.statusMessage = "Export failed: " + err.Error()
+		m.isError = true
+		return
+	}
+	if format == "png" {
+		kind := chartimage.Bar
+		switch m.results.GetViewMode() {
+		case components.ViewChartLine:
+			kind = chartimage.Line
+		case components.ViewChartPie:
+			kind = chartimage.Pie
+		}
+		err = chartimage.WritePNG(f, kind, m.results.ChartValues())
+	} else {
+		_, err = f.WriteString(m.results.ChartText() + "\n")
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		m.statusMessage = "Export failed: " + err.Error()
+		m.isError = true
+		return
+	}
+	m.statusMessage = "Exported the chart to " + path
+	m.isError = false
+}
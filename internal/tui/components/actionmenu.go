@@ -0,0 +1,113 @@
+package components
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ActionMenu component for a pop-up list of actions (e.g. table actions)
+type ActionMenu struct {
+	visible  bool
+	width    int
+	height   int
+	title    string
+	items    []string
+	selected int
+	styles   ActionMenuStyles
+}
+
+// ActionMenuStyles holds styling for the action menu
+type ActionMenuStyles struct {
+	Modal    lipgloss.Style
+	Title    lipgloss.Style
+	Item     lipgloss.Style
+	Selected lipgloss.Style
+	Hint     lipgloss.Style
+}
+
+// NewActionMenu creates a new action menu
+func NewActionMenu(styles ActionMenuStyles) ActionMenu {
+	return ActionMenu{
+		visible: false,
+		styles:  styles,
+	}
+}
+
+// Show shows the menu with the given items
+func (m *ActionMenu) Show(title string, items []string) {
+	m.visible = true
+	m.title = title
+	m.items = items
+	m.selected = 0
+}
+
+// Hide hides the menu
+func (m *ActionMenu) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns if the menu is visible
+func (m ActionMenu) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the menu dimensions
+func (m *ActionMenu) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp moves selection up
+func (m *ActionMenu) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+}
+
+// MoveDown moves selection down
+func (m *ActionMenu) MoveDown() {
+	if m.selected < len(m.items)-1 {
+		m.selected++
+	}
+}
+
+// Selected returns the selected item index
+func (m ActionMenu) Selected() int {
+	return m.selected
+}
+
+// SelectedItem returns the selected item label
+func (m ActionMenu) SelectedItem() string {
+	if m.selected >= 0 && m.selected < len(m.items) {
+		return m.items[m.selected]
+	}
+	return ""
+}
+
+// View renders the menu
+func (m ActionMenu) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	content := m.styles.Title.Render(m.title) + "\n\n"
+
+	for i, item := range m.items {
+		style := m.styles.Item
+		if i == m.selected {
+			style = m.styles.Selected
+		}
+		content += style.Render(item) + "\n"
+	}
+
+	content += "\n" + m.styles.Hint.Render("↑↓: navigate • Enter: select • Esc: cancel")
+
+	width := m.width
+	if width < 40 {
+		width = 40
+	}
+
+	return m.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
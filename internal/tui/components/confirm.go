@@ -0,0 +1,75 @@
+package components
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConfirmModal component asking the user to confirm a risky action
+type ConfirmModal struct {
+	visible bool
+	width   int
+	height  int
+	title   string
+	message string
+	styles  ConfirmModalStyles
+}
+
+// ConfirmModalStyles holds styling for the confirm modal
+type ConfirmModalStyles struct {
+	Modal   lipgloss.Style
+	Title   lipgloss.Style
+	Message lipgloss.Style
+	Hint    lipgloss.Style
+}
+
+// NewConfirmModal creates a new confirm modal
+func NewConfirmModal(styles ConfirmModalStyles) ConfirmModal {
+	return ConfirmModal{
+		visible: false,
+		styles:  styles,
+	}
+}
+
+// Show shows the modal with a title and message
+func (c *ConfirmModal) Show(title, message string) {
+	c.visible = true
+	c.title = title
+	c.message = message
+}
+
+// Hide hides the modal
+func (c *ConfirmModal) Hide() {
+	c.visible = false
+}
+
+// IsVisible returns if the modal is visible
+func (c ConfirmModal) IsVisible() bool {
+	return c.visible
+}
+
+// SetSize sets the modal dimensions
+func (c *ConfirmModal) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// View renders the modal
+func (c ConfirmModal) View() string {
+	if !c.visible {
+		return ""
+	}
+
+	content := c.styles.Title.Render(c.title) + "\n\n"
+	content += c.styles.Message.Render(c.message) + "\n\n"
+	content += c.styles.Hint.Render("y/Enter: confirm • n/Esc: cancel")
+
+	width := c.width
+	if width < 40 {
+		width = 40
+	}
+
+	return c.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
@@ -0,0 +1,126 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DatePicker picks a day from a month calendar
+type DatePicker struct {
+	visible   bool
+	width     int
+	title     string
+	message   string
+	date      time.Time
+	weekStart time.Weekday
+	styles    ConfirmModalStyles
+}
+
+// NewDatePicker creates a new date picker
+func NewDatePicker(styles ConfirmModalStyles) DatePicker {
+	return DatePicker{styles: styles, weekStart: time.Monday}
+}
+
+// Show opens the picker on date
+func (d *DatePicker) Show(title, message string, date time.Time) {
+	d.visible = true
+	d.title = title
+	d.message = message
+	d.date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// Hide hides the picker
+func (d *DatePicker) Hide() {
+	d.visible = false
+}
+
+// IsVisible returns if the picker is visible
+func (d DatePicker) IsVisible() bool {
+	return d.visible
+}
+
+// SetSize sets the picker width
+func (d *DatePicker) SetSize(width, height int) {
+	d.width = width
+}
+
+// SetWeekStart sets the first column of the calendar
+func (d *DatePicker) SetWeekStart(day time.Weekday) {
+	d.weekStart = day
+}
+
+// Selected returns the picked day
+func (d DatePicker) Selected() time.Time {
+	return d.date
+}
+
+// MoveDays moves the selection by n days
+func (d *DatePicker) MoveDays(n int) {
+	d.date = d.date.AddDate(0, 0, n)
+}
+
+// MoveMonths moves the selection by n months, staying within the month
+// when the day doesn't exist there
+func (d *DatePicker) MoveMonths(n int) {
+	first := time.Date(d.date.Year(), d.date.Month()+time.Month(n), 1, 0, 0, 0, 0, d.date.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	day := d.date.Day()
+	if day > last {
+		day = last
+	}
+	d.date = first.AddDate(0, 0, day-1)
+}
+
+// Today selects the current day
+func (d *DatePicker) Today() {
+	now := time.Now()
+	d.date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// View renders the picker
+func (d DatePicker) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	var cal strings.Builder
+	cal.WriteString(d.date.Format("January 2006") + "\n")
+	for i := 0; i < 7; i++ {
+		cal.WriteString(time.Weekday((int(d.weekStart) + i) % 7).String()[:2] + " ")
+	}
+	cal.WriteString("\n")
+
+	first := time.Date(d.date.Year(), d.date.Month(), 1, 0, 0, 0, 0, d.date.Location())
+	lead := (int(first.Weekday()) - int(d.weekStart) + 7) % 7
+	cal.WriteString(strings.Repeat("   ", lead))
+	selected := lipgloss.NewStyle().Reverse(true)
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%2d", day.Day())
+		if day.Day() == d.date.Day() {
+			cell = selected.Render(cell)
+		}
+		cal.WriteString(cell + " ")
+		if (lead+day.Day())%7 == 0 {
+			cal.WriteString("\n")
+		}
+	}
+
+	content := d.styles.Title.Render(d.title) + "\n\n"
+	content += cal.String() + "\n\n"
+	if d.message != "" {
+		content += d.styles.Message.Render(d.message) + "\n"
+	}
+	content += d.styles.Hint.Render("←→↑↓: day • PgUp/PgDn: month • t: today • Enter: pick • Esc: cancel")
+
+	width := d.width
+	if width < 40 {
+		width = 40
+	}
+	return d.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
@@ -3,16 +3,19 @@ package components
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
 )
 
 // EditorMode represents the current mode of the editor
@@ -35,17 +38,34 @@ type EditorState struct {
 	Cursor int
 }
 
+// EditorBuffer is the text of the editor with its undo history, as kept
+// across sessions
+type EditorBuffer struct {
+	Text    string
+	Cursor  int
+	History []EditorState
+	Index   int // Current state in History, -1 when it's empty
+}
+
 // Editor component for SQL editing (Sublime-like)
 type Editor struct {
 	textarea    textarea.Model
 	width       int
 	height      int
 	focused     bool
+	state       PaneState
 	styles      EditorStyles
 	
 	// Suggestions
 	suggestion  string
 	schema      map[string][]string
+	dialect     []string
+
+	// Highlighting: word classes, the dialect of quotes and comments and
+	// the tokens of the rendered text
+	words      map[string]wordClass
+	sqlDialect sqlparse.Dialect
+	highlight  *highlightCache
 	
 	// Selection
 	selectionStart int
@@ -59,6 +79,9 @@ type Editor struct {
 	
 	// KeyMap
 	keyMap config.KeyMap
+
+	// Typing an opening bracket or quote inserts its closer
+	autoClose bool
 	
 	// Mode
 	mode EditorMode
@@ -75,15 +98,51 @@ type Editor struct {
 	// Search
 	searchMode    bool
 	searchQuery   string
-	searchMatches []int // Indices of matches
-	searchIndex   int   // Current match index
+	searchMatches [][]int // Submatch offsets of each match
+	searchIndex   int     // Current match index
+	searchRegex   bool    // The query is a regular expression
+	searchNoCase  bool    // Ignore case
+	searchErr     string
 	replaceMode   bool
 	replaceQuery  string
+	replaceFocus  bool // Typing goes to the replace field
 	
 	// Go to Line
 	gotoLineMode  bool
 	gotoLineInput string
 
+	// Line markers (lint findings) for the text in markersFor; editing
+	// the text hides them
+	markers    map[int]LineMarker
+	markersFor string
+
+	// Inline blocks (notebook results) shown under lines of the text
+	blocks map[int][]string
+
+	// Folded subqueries and CTE bodies by the offset of their opening
+	// parenthesis, for the text in foldsFor
+	folds    map[int]bool
+	foldsFor string
+
+	// Vim normal mode: the keys of an unfinished command such as "d" or
+	// "ci", the count typed before it and the one before its operator,
+	// and the text last deleted or yanked, whole lines when yankLines
+	pending   string
+	count     string
+	opCount   int
+	yanked    string
+	yankLines bool
+
+	// Tab stops of the expanded snippet, see InsertSnippet
+	snippetStops []tabStop
+	snippetStop  int
+
+	// Multi-cursor editing: cursors besides the textarea's, added with
+	// Ctrl+D or Alt+Click; multiWord matches whole words only
+	cursors   []extraCursor
+	multi     bool
+	multiWord bool
+
 	// Mouse
 	mouseDown  bool
 	mouseStart int
@@ -91,6 +150,14 @@ type Editor struct {
 	posY       int
 }
 
+// LineMarker flags a line in the gutter, with a message shown while the
+// cursor is on it
+type LineMarker struct {
+	Icon    string
+	Style   lipgloss.Style
+	Message string
+}
+
 // EditorStyles holds styling for the editor
 type EditorStyles struct {
 	Normal     lipgloss.Style
@@ -99,13 +166,19 @@ type EditorStyles struct {
 	LineNum    lipgloss.Style
 	Keyword    lipgloss.Style
 	String     lipgloss.Style
+	Number     lipgloss.Style
+	Comment    lipgloss.Style
 	GhostText  lipgloss.Style
 	Selection  lipgloss.Style
+	Match      lipgloss.Style // Search matches
+	CurMatch   lipgloss.Style // The current search match
+	Bracket    lipgloss.Style // The bracket at the cursor and its pair
 	Suggestion lipgloss.Style
 	Type       lipgloss.Style
 	Function   lipgloss.Style
 	Operator   lipgloss.Style
 	Mode       lipgloss.Style
+	Borders    PaneBorders
 }
 
 // SQL keywords for highlighting and suggestion
@@ -131,34 +204,6 @@ var sqlFunctions = []string{
 
 // ... NewEditor ...
 
-// HighlightSQL applies basic syntax highlighting to SQL
-func (e Editor) HighlightSQL(sql string) string {
-	result := sql
-	
-	// Helper to highlight words
-	highlight := func(words []string, style lipgloss.Style) {
-		for _, w := range words {
-			re := regexp.MustCompile(`(?i)\b` + w + `\b`)
-			result = re.ReplaceAllStringFunc(result, func(match string) string {
-				return style.Render(strings.ToUpper(match))
-			})
-		}
-	}
-	
-	highlight(sqlKeywords, e.styles.Keyword)
-	highlight(sqlOperators, e.styles.Operator)
-	highlight(sqlTypes, e.styles.Type)
-	highlight(sqlFunctions, e.styles.Function)
-	
-	// Highlight strings
-	strRe := regexp.MustCompile(`'[^']*'`)
-	result = strRe.ReplaceAllStringFunc(result, func(match string) string {
-		return e.styles.String.Render(match)
-	})
-	
-	return result
-}
-
 // NewEditor creates a new editor component
 func NewEditor(styles EditorStyles) Editor {
 	ta := textarea.New()
@@ -182,6 +227,8 @@ func NewEditor(styles EditorStyles) Editor {
 		showLineNumbers: true,
 		softWrap:        false,
 		rulerColumn:     80,
+		words:           wordClasses(nil),
+		highlight:       &highlightCache{},
 	}
 	e.snapshot()
 	return e
@@ -192,6 +239,25 @@ func (e *Editor) SetKeyMap(km config.KeyMap) {
 	e.keyMap = km
 }
 
+// SetDialectKeywords sets extra keywords for the active driver's dialect
+func (e *Editor) SetDialectKeywords(keywords []string) {
+	known := make(map[string]bool)
+	for _, list := range [][]string{sqlKeywords, sqlOperators, sqlTypes, sqlFunctions} {
+		for _, w := range list {
+			known[w] = true
+		}
+	}
+
+	// Skip words already highlighted so they aren't styled twice
+	e.dialect = nil
+	for _, w := range keywords {
+		if !known[w] {
+			e.dialect = append(e.dialect, w)
+		}
+	}
+	e.words = wordClasses(e.dialect)
+}
+
 // SetSchema sets the database schema for suggestions
 func (e *Editor) SetSchema(schema map[string][]string) {
 	e.schema = schema
@@ -221,11 +287,22 @@ func (e *Editor) SetFocused(focused bool) {
 	}
 }
 
+// SetState sets the state the border shows
+func (e *Editor) SetState(state PaneState) {
+	e.state = state
+}
+
 // IsFocused returns if editor is focused
 func (e Editor) IsFocused() bool {
 	return e.focused
 }
 
+// InNormalMode reports whether the editor is in normal mode, where keys
+// such as Ctrl+R (redo) are its own
+func (e Editor) InNormalMode() bool {
+	return e.mode == ModeNormal
+}
+
 // GetValue returns the current SQL text
 func (e Editor) GetValue() string {
 	return e.textarea.Value()
@@ -233,9 +310,48 @@ func (e Editor) GetValue() string {
 
 // SetValue sets the SQL text
 func (e *Editor) SetValue(value string) {
+	e.endSnippet()
+	e.clearCursors()
 	e.textarea.SetValue(value)
 }
 
+// SetMarkers flags lines (0-based) of the current text until it changes
+func (e *Editor) SetMarkers(markers map[int]LineMarker) {
+	e.markers = markers
+	e.markersFor = e.textarea.Value()
+}
+
+// SetInlineBlocks shows read-only lines of text under lines (0-based) of
+// the text, e.g. the results of the statement ending there
+func (e *Editor) SetInlineBlocks(blocks map[int][]string) {
+	e.blocks = blocks
+}
+
+// blockRows returns how many screen rows the block under line takes
+func (e Editor) blockRows(line int) int {
+	return len(e.blocks[line])
+}
+
+// gutterWidth returns the width of the line number and marker gutter
+func (e Editor) gutterWidth() int {
+	switch {
+	case e.showLineNumbers:
+		return 5
+	case len(e.activeMarkers()) > 0:
+		return 2
+	}
+	return 0
+}
+
+// activeMarkers returns the markers when the text is unchanged since
+// SetMarkers
+func (e Editor) activeMarkers() map[int]LineMarker {
+	if len(e.markers) == 0 || e.textarea.Value() != e.markersFor {
+		return nil
+	}
+	return e.markers
+}
+
 // GetCursorPosition returns the cursor position as character offset
 func (e Editor) GetCursorPosition() int {
 	// Get current position from textarea
@@ -254,6 +370,27 @@ func (e Editor) GetCursorPosition() int {
 	return offset
 }
 
+// CursorOffset returns the cursor position as a byte offset into GetValue,
+// also on wrapped and non-ASCII lines
+func (e Editor) CursorOffset() int {
+	lines := strings.Split(e.textarea.Value(), "\n")
+	line := e.textarea.Line()
+	offset := 0
+	for i := 0; i < line && i < len(lines); i++ {
+		offset += len(lines[i]) + 1 // +1 for newline
+	}
+	if line >= len(lines) {
+		return offset
+	}
+	info := e.textarea.LineInfo()
+	runes := []rune(lines[line])
+	col := info.StartColumn + info.ColumnOffset
+	if col > len(runes) {
+		col = len(runes)
+	}
+	return offset + len(string(runes[:col]))
+}
+
 // InsertText inserts text at the current cursor position
 func (e *Editor) InsertText(text string) {
 	// Get current value and cursor
@@ -271,6 +408,34 @@ func (e *Editor) InsertText(text string) {
 	e.textarea.SetCursor(pos + len(text))
 }
 
+// ReplaceSelection replaces the selected text, or the whole text when
+// nothing is selected, as one undoable edit
+func (e *Editor) ReplaceSelection(text string) {
+	e.endSnippet()
+	e.snapshot()
+	if e.hasActiveSelection() {
+		start, end := e.selectionStart, e.selectionEnd
+		if start > end {
+			start, end = end, start
+		}
+		val := e.textarea.Value()
+		start, end = min(max(start, 0), len(val)), min(end, len(val))
+		e.textarea.SetValue(val[:start] + text + val[end:])
+		e.setCursorIndex(start + len(text))
+		e.clearSelection()
+		e.mode = ModeNormal
+	} else {
+		e.textarea.SetValue(text)
+	}
+	e.snapshot()
+}
+
+// HasSelection reports whether some text other than a snippet
+// placeholder is selected
+func (e Editor) HasSelection() bool {
+	return e.hasActiveSelection() && !e.placeholderSelected()
+}
+
 // ReplaceCurrentWord replaces the word being typed with the given text
 func (e *Editor) ReplaceCurrentWord(text string) {
 	value := e.textarea.Value()
@@ -343,14 +508,21 @@ func (e Editor) Update(msg tea.Msg) (Editor, tea.Cmd) {
 	case tea.MouseMsg:
 		return e.handleMouse(msg)
 	case tea.KeyMsg:
+		// Jumping to a line or match opens the folds hiding it
+		prevLine := e.textarea.Line()
+		
 		// Go to Line mode handling
 		if e.gotoLineMode {
-			return e.updateGotoLineInput(msg)
+			e, cmd = e.updateGotoLineInput(msg)
+			e.skipFolds(prevLine, false)
+			return e, cmd
 		}
 		
 		// Search mode handling
 		if e.searchMode {
-			return e.updateSearchInput(msg)
+			e, cmd = e.updateSearchInput(msg)
+			e.skipFolds(prevLine, false)
+			return e, cmd
 		}
 		
 		// Global shortcuts (work in all modes)
@@ -365,25 +537,46 @@ func (e Editor) Update(msg tea.Msg) (Editor, tea.Cmd) {
 		}
 		if key == "f3" {
 			e.findNext()
+			e.skipFolds(prevLine, false)
 			return e, nil
 		}
 		if key == "shift+f3" {
 			e.findPrev()
+			e.skipFolds(prevLine, false)
 			return e, nil
 		}
 		if key == "ctrl+g" {
 			e.startGotoLine()
 			return e, nil
 		}
+
+		// Multiple cursors; in normal mode Ctrl+D duplicates the line
+		if key == "ctrl+d" && e.mode != ModeNormal {
+			e.addNextOccurrence()
+			return e, nil
+		}
+		if e.multi {
+			var handled bool
+			if e, cmd, handled = e.updateMulti(msg); handled {
+				return e, cmd
+			}
+		}
 		
 		switch e.mode {
 		case ModeNormal:
-			return e.updateNormal(msg)
+			e, cmd = e.updateNormal(msg)
 		case ModeVisual:
-			return e.updateVisual(msg)
+			e, cmd = e.updateVisual(msg)
 		case ModeInsert:
-			return e.updateInsert(msg)
+			e, cmd = e.updateInsert(msg)
+		}
+		switch key {
+		case "up", "down", "j", "k":
+			e.skipFolds(prevLine, e.mode != ModeInsert || key == "up" || key == "down")
+		default:
+			e.skipFolds(prevLine, false)
 		}
+		return e, cmd
 	}
 
 	e.textarea, cmd = e.textarea.Update(msg)
@@ -400,6 +593,10 @@ func (e Editor) updateNormal(msg tea.KeyMsg) (Editor, tea.Cmd) {
 		return e, nil
 	}
 
+	if e.vimKey(key) {
+		return e, nil
+	}
+
 	switch key {
 	case "i":
 		e.mode = ModeInsert
@@ -500,7 +697,8 @@ func (e Editor) updateVisual(msg tea.KeyMsg) (Editor, tea.Cmd) {
 	return e, cmd
 }
 
-func (e Editor) updateInsert(msg tea.KeyMsg) (Editor, tea.Cmd) {
+// updateInsertKey handles a key in insert mode, see updateInsert
+func (e Editor) updateInsertKey(msg tea.KeyMsg) (Editor, tea.Cmd) {
 	var cmd tea.Cmd
 	key := msg.String()
 
@@ -589,6 +787,12 @@ func (e Editor) updateInsert(msg tea.KeyMsg) (Editor, tea.Cmd) {
 		}
 	}
 
+	// Brackets and quotes
+	if e.autoClose && e.autoPair(msg) {
+		e.updateSuggestion()
+		return e, nil
+	}
+
 	// Snapshot triggers
 	if msg.Type == tea.KeySpace || msg.Type == tea.KeyEnter {
 		e.snapshot()
@@ -663,6 +867,7 @@ func (e *Editor) updateSuggestion() {
 	if checkList(sqlOperators) { return }
 	if checkList(sqlTypes) { return }
 	if checkList(sqlFunctions) { return }
+	if checkList(e.dialect) { return }
 	
 	// Check tables
 	for table := range e.schema {
@@ -682,6 +887,7 @@ func (e Editor) View() string {
 	if e.focused {
 		style = e.styles.Focused
 	}
+	style = e.styles.Borders.style(style, e.state)
 
 	title := e.styles.Title.Render("SQL EDITOR")
 	
@@ -697,6 +903,9 @@ func (e Editor) View() string {
 		modeStyle = lipgloss.NewStyle().Background(lipgloss.Color("5")).Foreground(lipgloss.Color("15")) // Purple for Visual
 	}
 	modeIndicator := modeStyle.Bold(true).Render(modeStr)
+	if e.multi && len(e.cursors) > 0 {
+		modeIndicator += e.styles.Suggestion.Render(fmt.Sprintf(" %d cursors", len(e.cursors)+1))
+	}
 
 	// Suggestion bar
 	suggestionText := ""
@@ -704,6 +913,9 @@ func (e Editor) View() string {
 		suggestionText = "Suggest: " + e.suggestion + " (Tab)"
 	}
 	suggestionBar := e.styles.Suggestion.Render(suggestionText)
+	if marker, ok := e.activeMarkers()[e.textarea.Line()]; ok && suggestionText == "" {
+		suggestionBar = marker.Style.Render(marker.Icon + " " + marker.Message)
+	}
 	
 	header := lipgloss.JoinHorizontal(lipgloss.Center, title, "  ", modeIndicator, "  ", suggestionBar)
 	
@@ -713,21 +925,37 @@ func (e Editor) View() string {
 	searchBar := ""
 	if e.searchMode {
 		matchInfo := ""
-		if len(e.searchMatches) > 0 {
+		switch {
+		case e.searchErr != "":
+			matchInfo = " (" + e.searchErr + ")"
+		case len(e.searchMatches) > 0:
 			matchInfo = fmt.Sprintf(" (%d/%d)", e.searchIndex+1, len(e.searchMatches))
+		case e.searchQuery != "":
+			matchInfo = " (no matches)"
+		}
+		toggle := func(label string, on bool) string {
+			if on {
+				return "[" + label + "]"
+			}
+			return " " + label + " "
+		}
+		findCaret, replaceCaret := "▏", ""
+		if e.replaceFocus {
+			findCaret, replaceCaret = "", "▏"
 		}
 		searchBar = lipgloss.NewStyle().
 			Background(lipgloss.Color("8")).
 			Foreground(lipgloss.Color("15")).
 			Padding(0, 1).
-			Render(fmt.Sprintf("Find: %s%s", e.searchQuery, matchInfo))
+			Render(fmt.Sprintf("Find: %s%s%s  %s%s  Alt+R regex, Alt+C case",
+				e.searchQuery, findCaret, matchInfo, toggle(".*", e.searchRegex), toggle("Aa", !e.searchNoCase)))
 		
 		if e.replaceMode {
 			replaceBar := lipgloss.NewStyle().
 				Background(lipgloss.Color("8")).
 				Foreground(lipgloss.Color("15")).
 				Padding(0, 1).
-				Render(fmt.Sprintf("Replace: %s", e.replaceQuery))
+				Render(fmt.Sprintf("Replace: %s%s  Tab switch field, Enter replace, Alt+Enter all", e.replaceQuery, replaceCaret))
 			searchBar += "\n" + replaceBar
 		}
 		searchBar += "\n"
@@ -755,7 +983,7 @@ func (e Editor) View() string {
 
 // GetSelectedText returns the text to execute
 func (e Editor) GetSelectedText() string {
-	if e.hasSelection {
+	if e.hasSelection && !e.placeholderSelected() {
 		start, end := e.selectionStart, e.selectionEnd
 		// Ensure start is before end
 		if start > end {
@@ -799,6 +1027,8 @@ func (e *Editor) snapshot() {
 
 // undo reverts to previous state
 func (e *Editor) undo() {
+	e.endSnippet()
+	e.clearCursors()
 	if e.historyIndex > 0 {
 		e.historyIndex--
 		state := e.history[e.historyIndex]
@@ -809,6 +1039,8 @@ func (e *Editor) undo() {
 
 // redo reverts to next state
 func (e *Editor) redo() {
+	e.endSnippet()
+	e.clearCursors()
 	if e.historyIndex < len(e.history)-1 {
 		e.historyIndex++
 		state := e.history[e.historyIndex]
@@ -817,16 +1049,45 @@ func (e *Editor) redo() {
 	}
 }
 
+// Buffer returns the text, cursor and undo history
+func (e Editor) Buffer() EditorBuffer {
+	return EditorBuffer{
+		Text:    e.textarea.Value(),
+		Cursor:  e.getCursorIndex(),
+		History: slices.Clone(e.history),
+		Index:   e.historyIndex,
+	}
+}
+
+// SetBuffer replaces the text, cursor and undo history, e.g. with those of
+// an earlier session
+func (e *Editor) SetBuffer(b EditorBuffer) {
+	e.SetValue(b.Text)
+	e.clearSelection()
+	e.setCursorIndex(b.Cursor)
+	e.history, e.historyIndex = slices.Clone(b.History), b.Index
+	if e.historyIndex < 0 || e.historyIndex >= len(e.history) {
+		e.history, e.historyIndex = nil, -1
+	}
+	// The text may be newer than the last state
+	e.snapshot()
+	e.updateViewport()
+}
+
 // setCursorIndex sets cursor position from linear index
 func (e *Editor) setCursorIndex(idx int) {
 	val := e.textarea.Value()
 	if idx > len(val) { idx = len(val) }
+	if idx < 0 { idx = 0 }
 	
 	// Count newlines before idx
 	lines := strings.Split(val[:idx], "\n")
 	line := len(lines) - 1
 	col := len(lines[line])
 	
+	for e.textarea.Line() > 0 {
+		e.textarea.CursorUp()
+	}
 	e.textarea.CursorStart()
 	for i := 0; i < line; i++ {
 		e.textarea.CursorDown()
@@ -1056,25 +1317,26 @@ func (e *Editor) toggleLineNumbers() {
 // startSearch enters search mode
 func (e *Editor) startSearch() {
 	e.searchMode = true
+	e.replaceMode = false
+	e.replaceFocus = false
 	e.searchQuery = ""
 	e.searchMatches = nil
 	e.searchIndex = 0
+	e.searchErr = ""
 }
 
 // startReplace enters replace mode
 func (e *Editor) startReplace() {
-	e.searchMode = true
+	e.startSearch()
 	e.replaceMode = true
-	e.searchQuery = ""
 	e.replaceQuery = ""
-	e.searchMatches = nil
-	e.searchIndex = 0
 }
 
 // cancelSearch exits search mode
 func (e *Editor) cancelSearch() {
 	e.searchMode = false
 	e.replaceMode = false
+	e.replaceFocus = false
 }
 
 // updateSearchInput handles key input in search mode
@@ -1086,7 +1348,7 @@ func (e Editor) updateSearchInput(msg tea.KeyMsg) (Editor, tea.Cmd) {
 		e.cancelSearch()
 		return e, nil
 	case "enter":
-		if e.replaceMode {
+		if e.replaceMode && e.replaceFocus {
 			e.doReplace()
 		} else {
 			e.findNext()
@@ -1098,50 +1360,80 @@ func (e Editor) updateSearchInput(msg tea.KeyMsg) (Editor, tea.Cmd) {
 	case "shift+f3":
 		e.findPrev()
 		return e, nil
-	case "ctrl+enter":
+	case "ctrl+enter", "alt+enter":
 		if e.replaceMode {
 			e.replaceAll()
 		}
 		return e, nil
-	case "backspace":
-		if len(e.searchQuery) > 0 {
-			e.searchQuery = e.searchQuery[:len(e.searchQuery)-1]
-			e.refreshSearchMatches()
-		}
+	case "alt+r":
+		e.searchRegex = !e.searchRegex
+		e.refreshSearchMatches()
 		return e, nil
-	case "tab":
-		// Switch focus between search and replace fields (if in replace mode)
-		// For simplicity, we handle this by just moving between fields conceptually
+	case "alt+c":
+		e.searchNoCase = !e.searchNoCase
+		e.refreshSearchMatches()
 		return e, nil
-	default:
-		// Add character to search query
-		if len(key) == 1 && key >= " " && key <= "~" {
-			e.searchQuery += key
-			e.refreshSearchMatches()
+	case "tab", "shift+tab":
+		// Switch between the find and replace fields
+		if e.replaceMode {
+			e.replaceFocus = !e.replaceFocus
 		}
 		return e, nil
 	}
+
+	field := &e.searchQuery
+	if e.replaceFocus {
+		field = &e.replaceQuery
+	}
+	switch {
+	case key == "backspace":
+		if _, size := utf8.DecodeLastRuneInString(*field); size > 0 {
+			*field = (*field)[:len(*field)-size]
+		}
+	case msg.Type == tea.KeyRunes && !msg.Alt:
+		*field += string(msg.Runes)
+	case msg.Type == tea.KeySpace:
+		*field += " "
+	default:
+		return e, nil
+	}
+	if !e.replaceFocus {
+		e.refreshSearchMatches()
+	}
+	return e, nil
+}
+
+// searchPattern compiles the search query: a regular expression in regex
+// mode, else the literal text; case-insensitive when toggled
+func (e Editor) searchPattern() (*regexp.Regexp, error) {
+	pattern := e.searchQuery
+	if !e.searchRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if e.searchNoCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
 }
 
 // refreshSearchMatches updates search matches
 func (e *Editor) refreshSearchMatches() {
+	e.searchMatches = nil
+	e.searchErr = ""
 	if e.searchQuery == "" {
-		e.searchMatches = nil
+		return
+	}
+	re, err := e.searchPattern()
+	if err != nil {
+		e.searchErr = "invalid regex"
 		return
 	}
 	
-	text := e.textarea.Value()
-	query := e.searchQuery
-	e.searchMatches = nil
-	
-	idx := 0
-	for {
-		found := strings.Index(text[idx:], query)
-		if found == -1 {
-			break
+	// Empty matches (a*, ^) can't be stepped through or replaced
+	for _, m := range re.FindAllStringSubmatchIndex(e.textarea.Value(), -1) {
+		if m[1] > m[0] {
+			e.searchMatches = append(e.searchMatches, m)
 		}
-		e.searchMatches = append(e.searchMatches, idx+found)
-		idx += found + 1
 	}
 	
 	if e.searchIndex >= len(e.searchMatches) {
@@ -1149,13 +1441,36 @@ func (e *Editor) refreshSearchMatches() {
 	}
 }
 
+// searchMarks returns the column ranges of the search matches on the line
+// from lineStart to lineEnd, and which of them is the current match (-1
+// when it isn't on the line)
+func (e Editor) searchMarks(lineStart, lineEnd int) (spans [][2]int, current int) {
+	current = -1
+	if !e.searchMode {
+		return nil, current
+	}
+	for i, m := range e.searchMatches {
+		if m[0] >= lineEnd {
+			break
+		}
+		if m[1] <= lineStart {
+			continue
+		}
+		if i == e.searchIndex {
+			current = len(spans)
+		}
+		spans = append(spans, [2]int{max(m[0], lineStart) - lineStart, min(m[1], lineEnd) - lineStart})
+	}
+	return spans, current
+}
+
 // findNext moves to next match
 func (e *Editor) findNext() {
 	if len(e.searchMatches) == 0 {
 		return
 	}
 	e.searchIndex = (e.searchIndex + 1) % len(e.searchMatches)
-	e.setCursorIndex(e.searchMatches[e.searchIndex])
+	e.setCursorIndex(e.searchMatches[e.searchIndex][0])
 	e.updateViewport()
 }
 
@@ -1168,33 +1483,63 @@ func (e *Editor) findPrev() {
 	if e.searchIndex < 0 {
 		e.searchIndex = len(e.searchMatches) - 1
 	}
-	e.setCursorIndex(e.searchMatches[e.searchIndex])
+	e.setCursorIndex(e.searchMatches[e.searchIndex][0])
 	e.updateViewport()
 }
 
+// replacement returns the text replacing match m of text: the replace
+// field with $1 and ${name} expanded in regex mode, else as typed
+func (e Editor) replacement(re *regexp.Regexp, text string, m []int) string {
+	if !e.searchRegex {
+		return e.replaceQuery
+	}
+	return string(re.ExpandString(nil, e.replaceQuery, text, m))
+}
+
 // doReplace replaces current match
 func (e *Editor) doReplace() {
-	if len(e.searchMatches) == 0 || e.replaceQuery == "" {
+	re, err := e.searchPattern()
+	if len(e.searchMatches) == 0 || err != nil {
 		return
 	}
 	
-	matchIdx := e.searchMatches[e.searchIndex]
+	e.snapshot()
+	m := e.searchMatches[e.searchIndex]
 	text := e.textarea.Value()
-	newText := text[:matchIdx] + e.replaceQuery + text[matchIdx+len(e.searchQuery):]
-	e.textarea.SetValue(newText)
+	with := e.replacement(re, text, m)
+	e.textarea.SetValue(text[:m[0]] + with + text[m[1]:])
 	e.snapshot()
 	e.refreshSearchMatches()
+	
+	// Stay on the match after the replaced one
+	for i, next := range e.searchMatches {
+		if next[0] >= m[0]+len(with) {
+			e.searchIndex = i
+			break
+		}
+	}
+	e.setCursorIndex(m[0] + len(with))
+	e.updateViewport()
 }
 
 // replaceAll replaces all matches
 func (e *Editor) replaceAll() {
-	if e.searchQuery == "" || e.replaceQuery == "" {
+	re, err := e.searchPattern()
+	if len(e.searchMatches) == 0 || err != nil {
 		return
 	}
 	
+	e.snapshot()
 	text := e.textarea.Value()
-	newText := strings.ReplaceAll(text, e.searchQuery, e.replaceQuery)
-	e.textarea.SetValue(newText)
+	var b strings.Builder
+	last := 0
+	for _, m := range e.searchMatches {
+		b.WriteString(text[last:m[0]])
+		b.WriteString(e.replacement(re, text, m))
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	e.textarea.SetValue(b.String())
 	e.snapshot()
 	e.refreshSearchMatches()
 }
@@ -1288,6 +1633,10 @@ func (e Editor) handleMouse(msg tea.MouseMsg) (Editor, tea.Cmd) {
 	
 	switch msg.Type {
 	case tea.MouseLeft:
+		if msg.Alt && !e.mouseDown {
+			e.toggleCursor(idx)
+			return e, nil
+		}
 		if !e.mouseDown {
 			e.mouseDown = true
 			e.mouseStart = idx
@@ -1312,10 +1661,14 @@ func (e Editor) handleMouse(msg tea.MouseMsg) (Editor, tea.Cmd) {
 			// Keep selection
 		}
 	case tea.MouseWheelUp:
+		prevLine := e.textarea.Line()
 		e.textarea.CursorUp()
+		e.skipFolds(prevLine, true)
 		e.updateViewport()
 	case tea.MouseWheelDown:
+		prevLine := e.textarea.Line()
 		e.textarea.CursorDown()
+		e.skipFolds(prevLine, true)
 		e.updateViewport()
 	}
 	
@@ -1326,10 +1679,25 @@ func (e Editor) handleMouse(msg tea.MouseMsg) (Editor, tea.Cmd) {
 func (e Editor) getIndexFromCoords(x, y, headerHeight int) int {
 	contentY := e.posY + headerHeight
 	relY := y - contentY
-	lineIdx := e.offsetY + relY
 	
 	lines := strings.Split(e.textarea.Value(), "\n")
-	if lineIdx < 0 || lineIdx >= len(lines) {
+	hidden, _ := e.foldedLines()
+	// Walk the rows, skipping inline blocks and folded lines, to the
+	// clicked line
+	lineIdx := e.offsetY
+	for row := 0; lineIdx < len(lines); lineIdx++ {
+		if hidden[lineIdx] {
+			continue
+		}
+		if row == relY {
+			break
+		}
+		row += 1 + e.blockRows(lineIdx)
+		if row > relY {
+			return -1 // inside a block
+		}
+	}
+	if relY < 0 || lineIdx >= len(lines) {
 		return -1
 	}
 	
@@ -1374,13 +1742,30 @@ func (e *Editor) updateViewport() {
 		e.offsetY = cursorLine - viewportHeight + 1
 	}
 	
+	// Inline blocks above the cursor take rows too, folded lines none
+	hidden, _ := e.foldedLines()
+	for e.offsetY < cursorLine {
+		rows := 1
+		for i := e.offsetY; i < cursorLine; i++ {
+			if !hidden[i] {
+				rows += 1 + e.blockRows(i)
+			}
+		}
+		if rows <= viewportHeight {
+			break
+		}
+		e.offsetY++
+	}
+	
 	if e.offsetY < 0 { e.offsetY = 0 }
 }
 
 // render renders the editor content with custom highlighting
 func (e Editor) render() string {
 	var view strings.Builder
-	lines := strings.Split(e.textarea.Value(), "\n")
+	text := e.textarea.Value()
+	lines := strings.Split(text, "\n")
+	tokens := e.highlight.tokensFor(text, e.sqlDialect)
 	viewportHeight := e.height - 2
 	
 	// Ensure offsets are valid
@@ -1388,8 +1773,8 @@ func (e Editor) render() string {
 	if e.offsetY < 0 { e.offsetY = 0 }
 	
 	startLine := e.offsetY
-	endLine := startLine + viewportHeight
-	if endLine > len(lines) { endLine = len(lines) }
+	endLine := len(lines)
+	hidden, foldCounts := e.foldedLines()
 	
 	// Calculate global index for startLine
 	currentIdx := 0
@@ -1402,11 +1787,21 @@ func (e Editor) render() string {
 	
 	cursorLine := e.textarea.Line()
 	cursorCol := e.textarea.LineInfo().ColumnOffset
+	markers := e.activeMarkers()
+	var brackets []int
+	if at, match, ok := matchingBracket(tokens, e.getCursorIndex()); ok {
+		brackets = []int{at, match}
+	}
+	rows := 0
 	
-	for i := startLine; i < endLine; i++ {
+	for i := startLine; i < endLine && rows < viewportHeight; i++ {
 		line := lines[i]
 		lineLen := len(line)
 		lineEndIdx := currentIdx + lineLen
+		if hidden[i] {
+			currentIdx += lineLen + 1
+			continue
+		}
 		
 		// Line Number (conditional)
 		if e.showLineNumbers {
@@ -1414,7 +1809,17 @@ func (e Editor) render() string {
 			if i == cursorLine {
 				lineNumStyle = e.styles.LineNum.Copy().Foreground(lipgloss.Color("15")).Bold(true)
 			}
-			view.WriteString(lineNumStyle.Render(fmt.Sprintf("%4d ", i+1)))
+			if marker, ok := markers[i]; ok {
+				view.WriteString(lineNumStyle.Render(fmt.Sprintf("%3d", i+1)) + marker.Style.Render(marker.Icon) + " ")
+			} else {
+				view.WriteString(lineNumStyle.Render(fmt.Sprintf("%4d ", i+1)))
+			}
+		} else if len(markers) > 0 {
+			if marker, ok := markers[i]; ok {
+				view.WriteString(marker.Style.Render(marker.Icon) + " ")
+			} else {
+				view.WriteString("  ")
+			}
 		}
 		
 		// Determine cuts for segments
@@ -1439,6 +1844,24 @@ func (e Editor) render() string {
 		if cCol != -1 {
 			cuts = append(cuts, cCol, cCol+1)
 		}
+		extraCols, extraSpans := e.cursorMarks(currentIdx, lineEndIdx)
+		for _, col := range extraCols {
+			cuts = append(cuts, col, col+1)
+		}
+		for _, span := range extraSpans {
+			cuts = append(cuts, span[0], span[1])
+		}
+		matchSpans, curMatch := e.searchMarks(currentIdx, lineEndIdx)
+		for _, span := range matchSpans {
+			cuts = append(cuts, span[0], span[1])
+		}
+		var bracketCols []int
+		for _, b := range brackets {
+			if b >= currentIdx && b < lineEndIdx {
+				bracketCols = append(bracketCols, b-currentIdx)
+				cuts = append(cuts, b-currentIdx, b-currentIdx+1)
+			}
+		}
 		
 		// Sort and unique
 		sort.Ints(cuts)
@@ -1457,26 +1880,41 @@ func (e Editor) render() string {
 		for k := 0; k < len(cuts)-1; k++ {
 			p1, p2 := cuts[k], cuts[k+1]
 			if p1 >= p2 { continue }
-			if p1 >= lineLen && cCol != p1 { continue }
+			isCur := (cCol != -1 && p1 == cCol) || slices.Contains(extraCols, p1)
+			if p1 >= lineLen && !isCur { continue }
 			
 			segText := ""
 			if p1 < lineLen {
 				end := p2
 				if end > lineLen { end = lineLen }
 				segText = line[p1:end]
-			} else if p1 == lineLen && cCol == p1 {
+			} else if p1 == lineLen && isCur {
 				segText = " "
 			}
 			
 			isSel := (sStart != -1 && p1 >= sStart && p1 < sEnd)
-			isCur := (cCol != -1 && p1 == cCol)
+			for _, span := range extraSpans {
+				isSel = isSel || (p1 >= span[0] && p1 < span[1])
+			}
+			match := -1
+			for j, span := range matchSpans {
+				if p1 >= span[0] && p1 < span[1] {
+					match = j
+				}
+			}
 			
 			if isCur {
 				view.WriteString(lipgloss.NewStyle().Reverse(true).Render(segText))
 			} else if isSel {
 				view.WriteString(e.styles.Selection.Render(segText))
+			} else if slices.Contains(bracketCols, p1) {
+				view.WriteString(e.styles.Bracket.Render(segText))
+			} else if match != -1 && match == curMatch {
+				view.WriteString(e.styles.CurMatch.Render(segText))
+			} else if match != -1 {
+				view.WriteString(e.styles.Match.Render(segText))
 			} else {
-				view.WriteString(e.HighlightSQL(segText))
+				view.WriteString(e.highlightRange(tokens, text, currentIdx+p1, currentIdx+p1+len(segText)))
 			}
 		}
 		
@@ -1484,13 +1922,31 @@ func (e Editor) render() string {
 		if cCol == lineLen && lineLen == 0 {
              view.WriteString(lipgloss.NewStyle().Reverse(true).Render(" "))
         }
+		if n := foldCounts[i]; n > 0 {
+			view.WriteString(e.styles.GhostText.Render(fmt.Sprintf(" (+ %d lines)", n)))
+		}
 		
 		view.WriteString("\n")
 		currentIdx += lineLen + 1
+		rows++
+		
+		// Inline block under the line, cut to the pane
+		gutter := strings.Repeat(" ", e.gutterWidth())
+		width := e.width - e.gutterWidth() - 2
+		for _, blockLine := range e.blocks[i] {
+			if rows >= viewportHeight {
+				break
+			}
+			if runes := []rune(blockLine); width > 0 && len(runes) > width {
+				blockLine = string(runes[:width])
+			}
+			view.WriteString(gutter + e.styles.GhostText.Render(blockLine) + "\n")
+			rows++
+		}
 	}
 	
 	// Fill empty lines
-	for i := endLine; i < startLine + viewportHeight; i++ {
+	for ; rows < viewportHeight; rows++ {
 		view.WriteString(e.styles.LineNum.Render("~") + "\n")
 	}
 	
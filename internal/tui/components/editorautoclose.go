@@ -0,0 +1,109 @@
+package components
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// closers maps the brackets and quotes that are closed automatically to
+// their closers
+var closers = map[byte]byte{
+	'(':  ')',
+	'[':  ']',
+	'\'': '\'',
+	'"':  '"',
+	'`':  '`',
+}
+
+// SetAutoClose sets whether typing an opening bracket or quote inserts its
+// closer
+func (e *Editor) SetAutoClose(on bool) {
+	e.autoClose = on
+}
+
+// autoPair handles a key typed in insert mode with auto-close on: an
+// opener gets its closer after the cursor, a closer already there is
+// stepped over and Backspace between an empty pair removes both. It
+// reports whether it handled the key.
+func (e *Editor) autoPair(msg tea.KeyMsg) bool {
+	if e.hasActiveSelection() || msg.Alt || msg.Paste {
+		return false
+	}
+	val := e.textarea.Value()
+	idx := min(e.getCursorIndex(), len(val))
+	var prev, next byte
+	if idx > 0 {
+		prev = val[idx-1]
+	}
+	if idx < len(val) {
+		next = val[idx]
+	}
+
+	if msg.Type == tea.KeyBackspace {
+		if c, ok := closers[prev]; !ok || next != c || e.inLiteral(val, idx-1) {
+			return false
+		}
+		e.textarea.SetValue(val[:idx-1] + val[idx+1:])
+		e.setCursorIndex(idx - 1)
+		return true
+	}
+
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 || msg.Runes[0] > 0x7f {
+		return false
+	}
+	r := byte(msg.Runes[0])
+
+	// Typing the closer that follows the cursor steps over it
+	if (r == ')' || r == ']' || isQuote(r)) && next == r {
+		e.setCursorIndex(idx + 1)
+		return true
+	}
+
+	c, ok := closers[r]
+	if !ok || e.inLiteral(val, idx) {
+		return false
+	}
+	// Only before the end of a word, so "(" in front of "x" stays alone,
+	// and for quotes not after one either, as in "it's"
+	if next != 0 && !strings.ContainsRune(" \t\n),;]", rune(next)) {
+		return false
+	}
+	if isQuote(r) && (isWordChar(prev) || prev == r) {
+		return false
+	}
+	e.textarea.SetValue(val[:idx] + string(r) + string(c) + val[idx:])
+	e.setCursorIndex(idx + 1)
+	return true
+}
+
+// isQuote reports whether c is a quote that auto-close pairs
+func isQuote(c byte) bool {
+	return c == '\'' || c == '"' || c == '`'
+}
+
+// inLiteral reports whether the offset idx of val is inside a string,
+// quoted identifier or comment, where brackets and quotes aren't paired
+func (e *Editor) inLiteral(val string, idx int) bool {
+	for _, tok := range e.highlight.tokensFor(val, e.sqlDialect) {
+		if tok.Start >= idx {
+			break
+		}
+		switch tok.Kind {
+		case sqlparse.TokenString, sqlparse.TokenQuotedIdent, sqlparse.TokenComment:
+		default:
+			continue
+		}
+		if idx < tok.End {
+			return true
+		}
+		// An open literal or a line comment goes on at its end
+		lineComment := tok.Kind == sqlparse.TokenComment && !strings.HasPrefix(tok.Text, "/*")
+		if idx == tok.End && (lineComment || sqlparse.Unterminated(tok, e.sqlDialect)) {
+			return true
+		}
+	}
+	return false
+}
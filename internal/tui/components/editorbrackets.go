@@ -0,0 +1,52 @@
+package components
+
+import (
+	"sort"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// bracketPairs maps each bracket to its counterpart
+var bracketPairs = map[string]string{
+	"(": ")", ")": "(",
+	"[": "]", "]": "[",
+}
+
+// matchingBracket returns the offset of the bracket at the cursor, or
+// else just before it, and of its counterpart. Brackets in strings and
+// comments don't count. ok is false when there is no bracket or it isn't
+// matched.
+func matchingBracket(tokens []sqlparse.Token, cursor int) (at, match int, ok bool) {
+	for _, pos := range []int{cursor, cursor - 1} {
+		i := sort.Search(len(tokens), func(i int) bool { return tokens[i].Start >= pos })
+		if i == len(tokens) || tokens[i].Start != pos || tokens[i].Kind != sqlparse.TokenPunct {
+			continue
+		}
+		open := tokens[i].Text
+		pair, isBracket := bracketPairs[open]
+		if !isBracket {
+			continue
+		}
+		step := 1
+		if open == ")" || open == "]" {
+			step = -1
+		}
+		depth := 0
+		for j := i; j >= 0 && j < len(tokens); j += step {
+			if tokens[j].Kind != sqlparse.TokenPunct {
+				continue
+			}
+			switch tokens[j].Text {
+			case open:
+				depth++
+			case pair:
+				depth--
+			}
+			if depth == 0 {
+				return pos, tokens[j].Start, true
+			}
+		}
+		return 0, 0, false
+	}
+	return 0, 0, false
+}
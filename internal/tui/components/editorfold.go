@@ -0,0 +1,200 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// foldRegion is a subquery or CTE body spanning lines: the offset of its
+// opening parenthesis and the lines of both parentheses. Folding hides the
+// lines between them.
+type foldRegion struct {
+	open                int
+	openLine, closeLine int
+}
+
+// foldRegions returns the parenthesized subqueries and CTE bodies of text
+// that have lines to hide, outermost first
+func foldRegions(text string, tokens []sqlparse.Token) []foldRegion {
+	var lineStarts []int
+	lineStarts = append(lineStarts, 0)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	lineOf := func(offset int) int {
+		return sort.SearchInts(lineStarts, offset+1) - 1
+	}
+
+	// Words only; spaces and comments don't separate a parenthesis from
+	// what it belongs to
+	var code []sqlparse.Token
+	for _, tok := range tokens {
+		if tok.Kind != sqlparse.TokenSpace && tok.Kind != sqlparse.TokenComment {
+			code = append(code, tok)
+		}
+	}
+
+	var regions []foldRegion
+	var stack []int
+	for i, tok := range code {
+		if tok.Kind != sqlparse.TokenPunct {
+			continue
+		}
+		switch tok.Text {
+		case "(":
+			stack = append(stack, i)
+		case ")":
+			if len(stack) == 0 {
+				continue
+			}
+			o := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			openLine, closeLine := lineOf(code[o].Start), lineOf(tok.Start)
+			if closeLine-openLine >= 2 && foldable(code, o) {
+				regions = append(regions, foldRegion{open: code[o].Start, openLine: openLine, closeLine: closeLine})
+			}
+		}
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i].open < regions[j].open })
+	return regions
+}
+
+// foldable reports whether the parenthesis at code[i] opens a subquery or
+// the body of a CTE
+func foldable(code []sqlparse.Token, i int) bool {
+	if i+1 < len(code) {
+		switch code[i+1].Keyword() {
+		case "select", "with", "values":
+			return true
+		}
+	}
+	if i > 0 {
+		switch code[i-1].Keyword() {
+		case "as", "materialized":
+			// "name AS (" starts a CTE, but "CAST(x AS (" doesn't occur
+			return true
+		}
+	}
+	return false
+}
+
+// activeFolds returns the opening offsets of the folded regions, none once
+// the text changed since they were folded
+func (e Editor) activeFolds() map[int]bool {
+	if len(e.folds) == 0 || e.foldsFor != e.textarea.Value() {
+		return nil
+	}
+	return e.folds
+}
+
+// foldedLines returns the lines hidden by folds, and for each line ending
+// in a folded parenthesis how many lines it hides
+func (e Editor) foldedLines() (hidden map[int]bool, counts map[int]int) {
+	folds := e.activeFolds()
+	if len(folds) == 0 {
+		return nil, nil
+	}
+	text := e.textarea.Value()
+	hidden, counts = make(map[int]bool), make(map[int]int)
+	for _, r := range foldRegions(text, e.highlight.tokensFor(text, e.sqlDialect)) {
+		if !folds[r.open] || hidden[r.openLine] {
+			continue
+		}
+		counts[r.openLine] = r.closeLine - r.openLine - 1
+		for line := r.openLine + 1; line < r.closeLine; line++ {
+			hidden[line] = true
+		}
+	}
+	return hidden, counts
+}
+
+// toggleFold unfolds the folded region starting on the cursor line, or
+// else folds the innermost subquery or CTE body around the cursor
+func (e *Editor) toggleFold() {
+	text := e.textarea.Value()
+	regions := foldRegions(text, e.highlight.tokensFor(text, e.sqlDialect))
+	line := e.textarea.Line()
+	folds := e.activeFolds()
+
+	target := -1
+	for i, r := range regions {
+		if folds[r.open] && r.openLine == line {
+			target = i
+			break
+		}
+		if r.openLine <= line && line <= r.closeLine {
+			target = i // later regions are nested deeper
+		}
+	}
+	if target < 0 {
+		return
+	}
+
+	// Copied so other copies of the editor keep theirs
+	next := make(map[int]bool, len(folds)+1)
+	for open := range folds {
+		next[open] = true
+	}
+	r := regions[target]
+	if next[r.open] {
+		delete(next, r.open)
+	} else {
+		next[r.open] = true
+		if line > r.openLine && line < r.closeLine {
+			e.setCursorIndex(r.open)
+		}
+	}
+	e.folds, e.foldsFor = next, text
+	e.updateViewport()
+}
+
+// skipFolds keeps the cursor off hidden lines: moving up or down from
+// prevLine it jumps over the fold, other moves open the folds around it
+func (e *Editor) skipFolds(prevLine int, vertical bool) {
+	hidden, _ := e.foldedLines()
+	line := e.textarea.Line()
+	if !hidden[line] {
+		return
+	}
+	if !vertical {
+		e.unfoldAt(line)
+		return
+	}
+
+	lines := strings.Split(e.textarea.Value(), "\n")
+	target := line
+	if line > prevLine {
+		for target < len(lines)-1 && hidden[target] {
+			target++
+		}
+	} else {
+		for target > 0 && hidden[target] {
+			target--
+		}
+	}
+	col := e.textarea.LineInfo().ColumnOffset
+	idx := 0
+	for i := 0; i < target; i++ {
+		idx += len(lines[i]) + 1
+	}
+	e.setCursorIndex(idx + min(col, len(lines[target])))
+	e.updateViewport()
+}
+
+// unfoldAt opens the folds hiding line
+func (e *Editor) unfoldAt(line int) {
+	text := e.textarea.Value()
+	folds := e.activeFolds()
+	next := make(map[int]bool, len(folds))
+	for _, r := range foldRegions(text, e.highlight.tokensFor(text, e.sqlDialect)) {
+		if folds[r.open] && (line <= r.openLine || line >= r.closeLine) {
+			next[r.open] = true
+		}
+	}
+	e.folds, e.foldsFor = next, text
+	e.updateViewport()
+}
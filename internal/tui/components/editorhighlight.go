@@ -0,0 +1,109 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// wordClass is how the highlighter styles a word
+type wordClass int
+
+const (
+	wordPlain wordClass = iota
+	wordKeyword
+	wordOperator
+	wordType
+	wordFunction
+)
+
+// highlightCache keeps the tokens of the last text the editor rendered;
+// it is shared by the copies of an Editor so View can fill it
+type highlightCache struct {
+	text    string
+	dialect sqlparse.Dialect
+	tokens  []sqlparse.Token
+	valid   bool
+}
+
+// tokens returns the tokens of text, tokenizing it only when it changed
+func (c *highlightCache) tokensFor(text string, dialect sqlparse.Dialect) []sqlparse.Token {
+	if !c.valid || c.text != text || c.dialect != dialect {
+		c.text, c.dialect, c.valid = text, dialect, true
+		c.tokens = sqlparse.Tokenize(text, dialect)
+	}
+	return c.tokens
+}
+
+// wordClasses maps upper-case words to their class; a word in several
+// lists keeps the first
+func wordClasses(dialect []string) map[string]wordClass {
+	classes := make(map[string]wordClass)
+	for _, list := range []struct {
+		words []string
+		class wordClass
+	}{
+		{sqlKeywords, wordKeyword},
+		{sqlOperators, wordOperator},
+		{sqlTypes, wordType},
+		{sqlFunctions, wordFunction},
+		{dialect, wordKeyword},
+	} {
+		for _, w := range list.words {
+			w = strings.ToUpper(w)
+			if _, ok := classes[w]; !ok {
+				classes[w] = list.class
+			}
+		}
+	}
+	return classes
+}
+
+// SetDialect sets the SQL dialect the highlighter reads quotes and
+// comments in
+func (e *Editor) SetDialect(dialect sqlparse.Dialect) {
+	e.sqlDialect = dialect
+}
+
+// HighlightSQL applies syntax highlighting to a piece of SQL
+func (e Editor) HighlightSQL(sql string) string {
+	return e.highlightRange(sqlparse.Tokenize(sql, e.sqlDialect), sql, 0, len(sql))
+}
+
+// highlightRange renders text[start:end] with the styles of the tokens of
+// text covering it. Tokens are cut at the range ends, so a string or
+// comment spanning lines is styled on each of them.
+func (e Editor) highlightRange(tokens []sqlparse.Token, text string, start, end int) string {
+	var b strings.Builder
+	i := sort.Search(len(tokens), func(i int) bool { return tokens[i].End > start })
+	for ; i < len(tokens) && tokens[i].Start < end; i++ {
+		tok := tokens[i]
+		b.WriteString(e.renderToken(tok, text[max(tok.Start, start):min(tok.End, end)]))
+	}
+	return b.String()
+}
+
+// renderToken styles part, all or some of the text of tok
+func (e Editor) renderToken(tok sqlparse.Token, part string) string {
+	switch tok.Kind {
+	case sqlparse.TokenString:
+		return e.styles.String.Render(part)
+	case sqlparse.TokenNumber:
+		return e.styles.Number.Render(part)
+	case sqlparse.TokenComment:
+		return e.styles.Comment.Render(part)
+	case sqlparse.TokenWord:
+		switch e.words[strings.ToUpper(tok.Text)] {
+		case wordKeyword:
+			return e.styles.Keyword.Render(strings.ToUpper(part))
+		case wordOperator:
+			return e.styles.Operator.Render(strings.ToUpper(part))
+		case wordType:
+			return e.styles.Type.Render(strings.ToUpper(part))
+		case wordFunction:
+			return e.styles.Function.Render(strings.ToUpper(part))
+		}
+	}
+	return part
+}
@@ -0,0 +1,279 @@
+package components
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// extraCursor is a cursor besides the textarea's own, selecting the text
+// between anchor and pos when they differ
+type extraCursor struct {
+	anchor, pos int
+}
+
+// span returns the selected range of the cursor in order
+func (c extraCursor) span() (int, int) {
+	return min(c.anchor, c.pos), max(c.anchor, c.pos)
+}
+
+// clearCursors leaves multi-cursor editing, keeping the main cursor
+func (e *Editor) clearCursors() {
+	e.multi = false
+	e.multiWord = false
+	e.cursors = nil
+}
+
+// wordAt returns the range of the word around pos in val
+func wordAt(val string, pos int) (int, int) {
+	start, end := min(pos, len(val)), min(pos, len(val))
+	for start > 0 && isWordChar(val[start-1]) {
+		start--
+	}
+	for end < len(val) && isWordChar(val[end]) {
+		end++
+	}
+	return start, end
+}
+
+// addNextOccurrence selects the word at the cursor or, with text
+// selected, adds a cursor selecting its next occurrence, wrapping around
+func (e *Editor) addNextOccurrence() {
+	val := e.textarea.Value()
+	if !e.hasActiveSelection() {
+		start, end := wordAt(val, e.getCursorIndex())
+		if start == end {
+			return
+		}
+		e.selectionStart, e.selectionEnd, e.hasSelection = start, end, true
+		e.setCursorIndex(end)
+		e.mode = ModeInsert
+		e.multi, e.multiWord = true, true
+		return
+	}
+
+	start, end := min(e.selectionStart, e.selectionEnd), max(e.selectionStart, e.selectionEnd)
+	needle := val[start:end]
+	taken := map[int]bool{start: true}
+	from := end
+	for _, c := range e.cursors {
+		s, t := c.span()
+		taken[s] = true
+		from = max(from, t)
+	}
+
+	for _, offset := range []int{from, 0} {
+		for i := offset; i <= len(val)-len(needle); {
+			j := strings.Index(val[i:], needle)
+			if j < 0 {
+				break
+			}
+			at := i + j
+			i = at + 1
+			if taken[at] || e.multiWord && !wholeWord(val, at, at+len(needle)) {
+				continue
+			}
+			e.cursors = append(e.cursors[:len(e.cursors):len(e.cursors)], extraCursor{anchor: at, pos: at + len(needle)})
+			e.mode = ModeInsert
+			e.multi = true
+			return
+		}
+	}
+}
+
+// wholeWord reports whether val[start:end] isn't part of a longer word
+func wholeWord(val string, start, end int) bool {
+	return (start == 0 || !isWordChar(val[start-1])) && (end == len(val) || !isWordChar(val[end]))
+}
+
+// toggleCursor adds a cursor at idx, or removes the one already there
+func (e *Editor) toggleCursor(idx int) {
+	if idx == e.getCursorIndex() {
+		return
+	}
+	cursors := make([]extraCursor, 0, len(e.cursors)+1)
+	removed := false
+	for _, c := range e.cursors {
+		if c.pos == idx {
+			removed = true
+			continue
+		}
+		cursors = append(cursors, c)
+	}
+	if !removed {
+		cursors = append(cursors, extraCursor{anchor: idx, pos: idx})
+	}
+	e.cursors = cursors
+	e.mode = ModeInsert
+	e.multi = len(cursors) > 0 || e.hasActiveSelection()
+}
+
+// allCursors returns the main and extra cursors ordered by position and
+// the index of the main one among them
+func (e Editor) allCursors() ([]extraCursor, int) {
+	main := extraCursor{anchor: e.getCursorIndex(), pos: e.getCursorIndex()}
+	if e.hasActiveSelection() {
+		main = extraCursor{anchor: e.selectionStart, pos: e.selectionEnd}
+	}
+	cursors := append([]extraCursor{main}, e.cursors...)
+	order := make([]int, len(cursors))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, _ := cursors[order[a]].span()
+		sb, _ := cursors[order[b]].span()
+		return sa < sb
+	})
+	sorted := make([]extraCursor, len(cursors))
+	primary := 0
+	for i, j := range order {
+		sorted[i] = cursors[j]
+		if j == 0 {
+			primary = i
+		}
+	}
+	return sorted, primary
+}
+
+// setCursors places the main cursor at positions[primary] and the extra
+// cursors at the other positions, merging those that met
+func (e *Editor) setCursors(positions []int, primary int) {
+	e.clearSelection()
+	e.setCursorIndex(positions[primary])
+	seen := map[int]bool{positions[primary]: true}
+	cursors := make([]extraCursor, 0, len(positions))
+	for i, p := range positions {
+		if i != primary && !seen[p] {
+			seen[p] = true
+			cursors = append(cursors, extraCursor{anchor: p, pos: p})
+		}
+	}
+	e.cursors = cursors
+	e.multiWord = false
+}
+
+// multiEdit replaces, at every cursor, the range edit returns for the
+// cursor's selection with its text
+func (e *Editor) multiEdit(edit func(val string, start, end int) (from, to int, text string)) {
+	e.snapshot()
+	cursors, primary := e.allCursors()
+	val := e.textarea.Value()
+	var b strings.Builder
+	last := 0
+	positions := make([]int, len(cursors))
+	for i, c := range cursors {
+		start, end := c.span()
+		from, to, text := edit(val, start, end)
+		from = max(from, last)
+		to = max(to, from)
+		b.WriteString(val[last:from])
+		b.WriteString(text)
+		positions[i] = b.Len()
+		last = to
+	}
+	b.WriteString(val[last:])
+	e.textarea.SetValue(b.String())
+	e.setCursors(positions, primary)
+	e.snapshot()
+}
+
+// moveCursors moves every cursor a character left or right; a selection
+// collapses to its start or end instead
+func (e *Editor) moveCursors(right bool) {
+	cursors, primary := e.allCursors()
+	val := e.textarea.Value()
+	positions := make([]int, len(cursors))
+	for i, c := range cursors {
+		start, end := c.span()
+		switch {
+		case start != end && right:
+			positions[i] = end
+		case start != end:
+			positions[i] = start
+		case right && end < len(val):
+			_, size := utf8.DecodeRuneInString(val[end:])
+			positions[i] = end + size
+		case !right && start > 0:
+			_, size := utf8.DecodeLastRuneInString(val[:start])
+			positions[i] = start - size
+		default:
+			positions[i] = start
+		}
+	}
+	e.setCursors(positions, primary)
+}
+
+// updateMulti handles a key while several cursors edit at once: typing,
+// deleting and moving sideways apply to all of them, Esc goes back to one
+// cursor and other keys do so before doing their usual work
+func (e Editor) updateMulti(msg tea.KeyMsg) (Editor, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "ctrl+[":
+		e.clearCursors()
+		e.clearSelection()
+		return e, nil, true
+	case "left":
+		e.moveCursors(false)
+		return e, nil, true
+	case "right":
+		e.moveCursors(true)
+		return e, nil, true
+	case "backspace":
+		e.multiEdit(func(val string, start, end int) (int, int, string) {
+			if start == end && start > 0 {
+				_, size := utf8.DecodeLastRuneInString(val[:start])
+				start -= size
+			}
+			return start, end, ""
+		})
+		return e, nil, true
+	case "delete":
+		e.multiEdit(func(val string, start, end int) (int, int, string) {
+			if start == end && end < len(val) {
+				_, size := utf8.DecodeRuneInString(val[end:])
+				end += size
+			}
+			return start, end, ""
+		})
+		return e, nil, true
+	}
+
+	var text string
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt {
+			break
+		}
+		text = string(msg.Runes)
+	case tea.KeySpace:
+		text = " "
+	case tea.KeyEnter:
+		text = "\n"
+	}
+	if text == "" {
+		e.clearCursors()
+		return e, nil, false
+	}
+	e.multiEdit(func(_ string, start, end int) (int, int, string) {
+		return start, end, text
+	})
+	return e, nil, true
+}
+
+// cursorMarks returns the columns of the extra cursors on the line from
+// lineStart to lineEnd and the column ranges they select there
+func (e Editor) cursorMarks(lineStart, lineEnd int) (cols []int, spans [][2]int) {
+	for _, c := range e.cursors {
+		if c.pos >= lineStart && c.pos <= lineEnd {
+			cols = append(cols, c.pos-lineStart)
+		}
+		start, end := c.span()
+		if start < end && end > lineStart && start < lineEnd {
+			spans = append(spans, [2]int{max(start, lineStart) - lineStart, min(end, lineEnd) - lineStart})
+		}
+	}
+	return cols, spans
+}
@@ -0,0 +1,231 @@
+package components
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tabStop is a placeholder of an expanded snippet, a byte range of the text
+type tabStop struct {
+	start, end int
+}
+
+// expandSnippet turns the tab stops of a snippet into plain text and their
+// places: ${1:table} leaves "table", $1 and ${1} leave nothing. The stops
+// are ordered by number and end with $0, the final cursor position, which
+// is the end of the text when the snippet has none. A number used twice
+// stops at its first place only; \$ writes a literal $.
+func expandSnippet(body string) (string, []tabStop) {
+	var text strings.Builder
+	stops := make(map[int]tabStop)
+	maxNum := 0
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c == '\\' && i+1 < len(body) && body[i+1] == '$' {
+			text.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' {
+			text.WriteByte(c)
+			continue
+		}
+
+		num, placeholder, n, ok := parseTabStop(body[i+1:])
+		if !ok {
+			text.WriteByte(c)
+			continue
+		}
+		start := text.Len()
+		text.WriteString(placeholder)
+		if _, seen := stops[num]; !seen {
+			stops[num] = tabStop{start: start, end: text.Len()}
+		}
+		maxNum = max(maxNum, num)
+		i += n
+	}
+
+	var ordered []tabStop
+	for num := 1; num <= maxNum; num++ {
+		if stop, ok := stops[num]; ok {
+			ordered = append(ordered, stop)
+		}
+	}
+	final, ok := stops[0]
+	if !ok {
+		final = tabStop{start: text.Len(), end: text.Len()}
+	}
+	return text.String(), append(ordered, final)
+}
+
+// parseTabStop reads the tab stop after a $: its number, placeholder and
+// length
+func parseTabStop(s string) (num int, placeholder string, n int, ok bool) {
+	braced := strings.HasPrefix(s, "{")
+	if braced {
+		n = 1
+	}
+	digits := n
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		num = num*10 + int(s[n]-'0')
+		n++
+	}
+	if n == digits {
+		return 0, "", 0, false
+	}
+	if !braced {
+		return num, "", n, true
+	}
+	if n < len(s) && s[n] == '}' {
+		return num, "", n + 1, true
+	}
+	if n >= len(s) || s[n] != ':' {
+		return 0, "", 0, false
+	}
+	end := strings.IndexByte(s[n:], '}')
+	if end < 0 {
+		return 0, "", 0, false
+	}
+	return num, s[n+1 : n+end], n + end + 1, true
+}
+
+// InsertSnippet inserts a snippet at the cursor and selects its first tab
+// stop; Tab and Shift+Tab move between the stops, typing replaces the
+// selected placeholder
+func (e *Editor) InsertSnippet(body string) {
+	e.insertSnippetAt(e.CursorOffset(), e.CursorOffset(), body)
+}
+
+// ReplaceWordWithSnippet replaces the word being typed with a snippet, as
+// InsertSnippet
+func (e *Editor) ReplaceWordWithSnippet(body string) {
+	value := e.textarea.Value()
+	pos := min(e.GetCursorPosition(), len(value))
+	start := pos
+	for start > 0 && isWordChar(value[start-1]) {
+		start--
+	}
+	e.insertSnippetAt(start, pos, body)
+}
+
+// insertSnippetAt replaces the text between start and end with a snippet
+func (e *Editor) insertSnippetAt(start, end int, body string) {
+	e.snapshot()
+	text, stops := expandSnippet(body)
+	value := e.textarea.Value()
+	e.textarea.SetValue(value[:start] + text + value[end:])
+	for i := range stops {
+		stops[i].start += start
+		stops[i].end += start
+	}
+	e.mode = ModeInsert
+	e.clearSelection()
+	e.snippetStops = stops
+	e.selectStop(0)
+	e.snapshot()
+}
+
+// InSnippet reports whether Tab moves between the stops of a snippet
+func (e Editor) InSnippet() bool {
+	return len(e.snippetStops) > 0
+}
+
+// endSnippet leaves the tab stops of the snippet
+func (e *Editor) endSnippet() {
+	if e.placeholderSelected() {
+		e.clearSelection()
+	}
+	e.snippetStops = nil
+	e.snippetStop = 0
+}
+
+// selectStop moves to the i-th tab stop and selects its placeholder. The
+// last stop is the final cursor position and ends the snippet.
+func (e *Editor) selectStop(i int) {
+	stop := e.snippetStops[i]
+	e.snippetStop = i
+	e.setCursorIndex(stop.end)
+	if i == len(e.snippetStops)-1 {
+		e.endSnippet()
+		return
+	}
+	e.hasSelection = stop.start < stop.end
+	e.selectionStart = stop.start
+	e.selectionEnd = stop.end
+}
+
+// placeholderSelected reports whether the selection is the placeholder of
+// the current tab stop
+func (e Editor) placeholderSelected() bool {
+	if !e.hasSelection || len(e.snippetStops) == 0 {
+		return false
+	}
+	stop := e.snippetStops[e.snippetStop]
+	return e.selectionStart == stop.start && e.selectionEnd == stop.end
+}
+
+// shiftStops moves the tab stops after the current one by delta bytes
+// once its text grew or shrank
+func (e *Editor) shiftStops(delta int) {
+	cur := &e.snippetStops[e.snippetStop]
+	oldEnd := cur.end
+	cur.end += delta
+	for i := range e.snippetStops {
+		if i != e.snippetStop && e.snippetStops[i].start >= oldEnd {
+			e.snippetStops[i].start += delta
+			e.snippetStops[i].end += delta
+		}
+	}
+}
+
+// updateInsert handles insert mode keys; while a snippet is expanded it
+// moves between its tab stops and keeps them in place as the text changes
+func (e Editor) updateInsert(msg tea.KeyMsg) (Editor, tea.Cmd) {
+	if len(e.snippetStops) == 0 {
+		return e.updateInsertKey(msg)
+	}
+
+	switch msg.String() {
+	case "tab":
+		e.selectStop(e.snippetStop + 1)
+		return e, nil
+	case "shift+tab":
+		e.selectStop(max(e.snippetStop-1, 0))
+		return e, nil
+	case "esc", "ctrl+[":
+		e.endSnippet()
+		return e.updateInsertKey(msg)
+	}
+
+	// Typing replaces the selected placeholder; deleting only removes it
+	if e.placeholderSelected() {
+		switch msg.Type {
+		case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace, tea.KeyDelete:
+			stop := e.snippetStops[e.snippetStop]
+			e.snapshot()
+			e.deleteSelection()
+			e.shiftStops(stop.start - stop.end)
+			if msg.Type == tea.KeyBackspace || msg.Type == tea.KeyDelete {
+				return e, nil
+			}
+		default:
+			e.clearSelection()
+		}
+	}
+
+	before := len(e.textarea.Value())
+	e, cmd := e.updateInsertKey(msg)
+	if len(e.snippetStops) == 0 {
+		return e, cmd
+	}
+	e.shiftStops(len(e.textarea.Value()) - before)
+
+	// Moving out of the placeholder ends the snippet
+	stop := e.snippetStops[e.snippetStop]
+	if cursor := e.getCursorIndex(); stop.end < stop.start || cursor < stop.start || cursor > stop.end {
+		e.endSnippet()
+	}
+	return e, cmd
+}
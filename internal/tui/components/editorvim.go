@@ -0,0 +1,511 @@
+package components
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// charClass groups characters the way vim words do: blanks, word
+// characters and other punctuation. Bytes of multi-byte runes count as
+// word characters so they aren't split.
+func charClass(c byte) int {
+	switch {
+	case c == ' ' || c == '\t' || c == '\n':
+		return 0
+	case isWordChar(c) || c >= 0x80:
+		return 1
+	}
+	return 2
+}
+
+// takeCount returns the count typed before a command, 1 without one, and
+// clears it
+func (e *Editor) takeCount() int {
+	n, err := strconv.Atoi(e.count)
+	e.count = ""
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// vimKey handles the normal mode keys that take counts or come in
+// sequences: counts, w/b/e, G/gg, the d/y/c operators with a motion, the
+// same key again or a text object, and za. It reports whether it used the
+// key; others are left to updateNormal.
+func (e *Editor) vimKey(key string) bool {
+	if key == "esc" || key == "ctrl+[" {
+		handled := e.pending != "" || e.count != ""
+		e.pending, e.count, e.opCount = "", "", 0
+		return handled
+	}
+
+	// Counts; a 0 that doesn't continue one goes to the line start
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && (key != "0" || e.count != "") {
+		e.count += key
+		return true
+	}
+
+	// Text objects after d, y or c and i or a
+	if len(e.pending) == 2 && (e.pending[1] == 'i' || e.pending[1] == 'a') {
+		op, kind := e.pending[:1], e.pending[1:]
+		e.pending, e.count, e.opCount = "", "", 0
+		if start, end, ok := e.textObject(kind == "a", key); ok {
+			e.applyOperator(op, start, end, false)
+		}
+		return true
+	}
+
+	switch e.pending {
+	case "z":
+		e.pending = ""
+		e.count = ""
+		if key == "a" {
+			e.toggleFold()
+		}
+		return true
+	case "g":
+		e.pending = ""
+		if key == "g" {
+			e.gotoLine(e.count, 0)
+		}
+		e.count = ""
+		return true
+	case "d", "y", "c":
+		op := e.pending
+		switch key {
+		case "i", "a":
+			e.pending = op + key
+			return true
+		case "g":
+			// dgg: wait for the second g
+			e.pending = op + "g"
+			return true
+		}
+		e.pending = ""
+		n := e.opCount * e.takeCount()
+		e.opCount = 0
+		if key == op {
+			e.operateLines(op, n)
+			return true
+		}
+		if op == "c" && key == "w" {
+			// cw changes to the end of the word, like ce
+			if idx := e.getCursorIndex(); idx < len(e.textarea.Value()) && charClass(e.textarea.Value()[idx]) != 0 {
+				key = "e"
+			}
+		}
+		if target, linewise, inclusive, ok := e.motion(key, n, true); ok {
+			start, end := min(e.getCursorIndex(), target), max(e.getCursorIndex(), target)
+			if inclusive {
+				end = min(end+1, len(e.textarea.Value()))
+			}
+			e.applyOperator(op, start, end, linewise)
+		}
+		return true
+	case "dg", "yg", "cg":
+		op := e.pending[:1]
+		e.pending = ""
+		line := 0
+		if e.count != "" || e.opCount > 1 {
+			line = e.opCount*e.takeCount() - 1
+		}
+		e.count, e.opCount = "", 0
+		if key == "g" {
+			start, end := e.getCursorIndex(), e.lineOffset(line)
+			e.applyOperator(op, min(start, end), max(start, end), true)
+		}
+		return true
+	}
+
+	switch key {
+	case "z", "g":
+		e.pending = key
+		return true
+	case "d", "y", "c":
+		e.pending = key
+		e.opCount = e.takeCount()
+		return true
+	case "G":
+		e.gotoLine(e.count, -1)
+		e.count = ""
+		return true
+	case "w", "b", "e", "h", "l", "j", "k", "left", "right", "up", "down":
+		if e.count == "" && key != "w" && key != "b" && key != "e" {
+			// Plain moves stay with the textarea
+			return false
+		}
+		target, _, _, _ := e.motion(key, e.takeCount(), false)
+		if key == "j" || key == "k" || key == "up" || key == "down" {
+			e.moveLines(target)
+		} else {
+			e.setCursorIndex(target)
+		}
+		e.updateViewport()
+		return true
+	case "x":
+		if e.count == "" {
+			return false
+		}
+		n := e.takeCount()
+		idx := e.getCursorIndex()
+		end := idx
+		val := e.textarea.Value()
+		for i := 0; i < n && end < len(val) && val[end] != '\n'; i++ {
+			end++
+		}
+		e.applyOperator("d", idx, end, false)
+		return true
+	case "p":
+		if e.yankLines && e.yanked != "" {
+			if text, err := clipboard.ReadAll(); err != nil || text == e.yanked {
+				e.putLines(e.yanked, e.takeCount())
+				return true
+			}
+		}
+		e.count = ""
+		return false
+	}
+	e.count = ""
+	return false
+}
+
+// motion returns where a motion key moves the cursor n times, whether it
+// moves by lines and whether the character at the target is part of an
+// operator's range. forOperator makes w stop at the end of the line, as
+// "dw" on the last word doesn't join lines.
+func (e Editor) motion(key string, n int, forOperator bool) (target int, linewise, inclusive, ok bool) {
+	val := e.textarea.Value()
+	idx := e.getCursorIndex()
+	lineStart := strings.LastIndexByte(val[:idx], '\n') + 1
+	lineEnd := len(val)
+	if i := strings.IndexByte(val[idx:], '\n'); i >= 0 {
+		lineEnd = idx + i
+	}
+
+	switch key {
+	case "w":
+		for i := 0; i < n; i++ {
+			idx = nextWordStart(val, idx)
+		}
+		if forOperator && idx > lineEnd && lineEnd > e.getCursorIndex() {
+			idx = lineEnd
+		}
+		return idx, false, false, true
+	case "e":
+		for i := 0; i < n; i++ {
+			idx = wordEnd(val, idx)
+		}
+		return idx, false, true, true
+	case "b":
+		for i := 0; i < n; i++ {
+			idx = prevWordStart(val, idx)
+		}
+		return idx, false, false, true
+	case "h", "left":
+		return max(idx-n, lineStart), false, false, true
+	case "l", "right":
+		return min(idx+n, max(lineEnd, lineStart)), false, false, true
+	case "0":
+		return lineStart, false, false, true
+	case "$":
+		return lineEnd, false, false, true
+	case "j", "down":
+		line, _ := e.getLineCol(idx)
+		return e.lineOffset(line + n), true, false, true
+	case "k", "up":
+		line, _ := e.getLineCol(idx)
+		return e.lineOffset(max(line-n, 0)), true, false, true
+	case "G":
+		return e.lineOffset(strings.Count(val, "\n")), true, false, true
+	}
+	return idx, false, false, false
+}
+
+// nextWordStart returns the start of the word after the one at i
+func nextWordStart(val string, i int) int {
+	if i >= len(val) {
+		return len(val)
+	}
+	if class := charClass(val[i]); class != 0 {
+		for i < len(val) && charClass(val[i]) == class {
+			i++
+		}
+	}
+	for i < len(val) && charClass(val[i]) == 0 {
+		i++
+	}
+	return i
+}
+
+// wordEnd returns the last character of the word ending after i
+func wordEnd(val string, i int) int {
+	i++
+	for i < len(val) && charClass(val[i]) == 0 {
+		i++
+	}
+	if i >= len(val) {
+		return max(len(val)-1, 0)
+	}
+	class := charClass(val[i])
+	for i+1 < len(val) && charClass(val[i+1]) == class {
+		i++
+	}
+	return i
+}
+
+// prevWordStart returns the start of the word before i
+func prevWordStart(val string, i int) int {
+	i--
+	for i > 0 && charClass(val[i]) == 0 {
+		i--
+	}
+	if i <= 0 {
+		return 0
+	}
+	class := charClass(val[i])
+	for i > 0 && charClass(val[i-1]) == class {
+		i--
+	}
+	return i
+}
+
+// lineOffset returns the offset of the start of line, the last line when
+// it is past the end
+func (e Editor) lineOffset(line int) int {
+	val := e.textarea.Value()
+	idx := 0
+	for i := 0; i < line; i++ {
+		j := strings.IndexByte(val[idx:], '\n')
+		if j < 0 {
+			break
+		}
+		idx += j + 1
+	}
+	return idx
+}
+
+// gotoLine moves to the start of the 1-based line in count, or of line
+// fallback (0-based, -1 for the last) without a count
+func (e *Editor) gotoLine(count string, fallback int) {
+	line := fallback
+	if n, err := strconv.Atoi(count); err == nil && n > 0 {
+		line = n - 1
+	} else if fallback < 0 {
+		line = strings.Count(e.textarea.Value(), "\n")
+	}
+	e.setCursorIndex(e.lineOffset(line))
+	e.updateViewport()
+}
+
+// moveLines moves the cursor to the line starting at target, keeping its
+// column where the line is long enough
+func (e *Editor) moveLines(target int) {
+	col := e.textarea.LineInfo().ColumnOffset
+	val := e.textarea.Value()
+	end := len(val)
+	if i := strings.IndexByte(val[target:], '\n'); i >= 0 {
+		end = target + i
+	}
+	e.setCursorIndex(target + min(col, end-target))
+}
+
+// operateLines applies op to n lines from the cursor line, as dd, yy and
+// cc do
+func (e *Editor) operateLines(op string, n int) {
+	line, _ := e.getLineCol(e.getCursorIndex())
+	start := e.lineOffset(line)
+	end := e.lineOffset(line + n - 1)
+	e.applyOperator(op, start, end, true)
+}
+
+// applyOperator deletes (d), copies (y) or changes (c) the text from start
+// to end. Linewise it covers the whole lines of the range, and d and y
+// remember them so p puts them on their own lines.
+func (e *Editor) applyOperator(op string, start, end int, linewise bool) {
+	val := e.textarea.Value()
+	if linewise {
+		start = strings.LastIndexByte(val[:start], '\n') + 1
+		if i := strings.IndexByte(val[end:], '\n'); i >= 0 {
+			end += i
+		} else {
+			end = len(val)
+		}
+	}
+	if start >= end && !linewise {
+		if op == "c" {
+			// Changing nothing still inserts there, e.g. ci( in ()
+			e.setCursorIndex(start)
+			e.mode = ModeInsert
+			e.updateViewport()
+		}
+		return
+	}
+
+	text := val[start:end]
+	if linewise {
+		text += "\n"
+	}
+	clipboard.WriteAll(text)
+	e.yanked, e.yankLines = text, linewise
+
+	switch op {
+	case "y":
+		if !linewise {
+			e.setCursorIndex(start)
+		}
+		return
+	case "d":
+		if linewise {
+			// Take a newline along, the one before on the last line
+			if end < len(val) {
+				end++
+			} else if start > 0 {
+				start--
+			}
+		}
+	case "c":
+		// Changed lines keep their indent
+		if linewise {
+			for start < end && (val[start] == ' ' || val[start] == '\t') {
+				start++
+			}
+		}
+	}
+
+	e.snapshot()
+	e.textarea.SetValue(val[:start] + val[end:])
+	cursor := start
+	if op == "d" && linewise && start > 0 && end == len(val) {
+		// Deleted the last lines: go to the start of the new last line
+		cursor = strings.LastIndexByte(val[:start], '\n') + 1
+	}
+	e.setCursorIndex(cursor)
+	e.snapshot()
+	if op == "c" {
+		e.mode = ModeInsert
+	}
+	e.updateViewport()
+}
+
+// putLines puts text, whole lines ending in a newline, n times below the
+// cursor line
+func (e *Editor) putLines(text string, n int) {
+	val := e.textarea.Value()
+	idx := e.getCursorIndex()
+	at := len(val)
+	if i := strings.IndexByte(val[idx:], '\n'); i >= 0 {
+		at = idx + i + 1
+	}
+	block := strings.Repeat(text, n)
+	if at == len(val) && !strings.HasSuffix(val, "\n") {
+		// Below the last line, which has no newline to end it
+		block = "\n" + strings.TrimSuffix(block, "\n")
+		at = len(val)
+	}
+
+	e.snapshot()
+	e.textarea.SetValue(val[:at] + block + val[at:])
+	if strings.HasPrefix(block, "\n") {
+		at++
+	}
+	e.setCursorIndex(at)
+	e.snapshot()
+	e.updateViewport()
+}
+
+// textObject returns the range of the text object of key around the
+// cursor: a word (w), brackets ((, ), b, [, ]) or quotes (', ", `). around
+// includes the brackets or quotes, or the blanks after the word.
+func (e Editor) textObject(around bool, key string) (start, end int, ok bool) {
+	val := e.textarea.Value()
+	idx := e.getCursorIndex()
+
+	switch key {
+	case "w":
+		if idx >= len(val) {
+			return 0, 0, false
+		}
+		class := charClass(val[idx])
+		start, end = idx, idx
+		for start > 0 && charClass(val[start-1]) == class {
+			start--
+		}
+		for end < len(val) && charClass(val[end]) == class {
+			end++
+		}
+		if around {
+			for end < len(val) && (val[end] == ' ' || val[end] == '\t') {
+				end++
+			}
+		}
+		return start, end, true
+	case "(", ")", "b", "[", "]":
+		open, close := "(", ")"
+		if key == "[" || key == "]" {
+			open, close = "[", "]"
+		}
+		tokens := e.highlight.tokensFor(val, e.sqlDialect)
+		at, match, found := enclosingBracket(tokens, idx, open, close)
+		if !found {
+			return 0, 0, false
+		}
+		if around {
+			return at, match + 1, true
+		}
+		return at + 1, match, true
+	case "'", "\"", "`":
+		tokens := e.highlight.tokensFor(val, e.sqlDialect)
+		i := sort.Search(len(tokens), func(i int) bool { return tokens[i].End > idx })
+		if i == len(tokens) || tokens[i].Start > idx {
+			return 0, 0, false
+		}
+		tok := tokens[i]
+		q := strings.IndexByte(tok.Text, key[0])
+		if q < 0 || (tok.Kind != sqlparse.TokenString && tok.Kind != sqlparse.TokenQuotedIdent) {
+			return 0, 0, false
+		}
+		if around {
+			return tok.Start, tok.End, true
+		}
+		end = tok.End
+		if !sqlparse.Unterminated(tok, e.sqlDialect) {
+			end--
+		}
+		return tok.Start + q + 1, max(end, tok.Start+q+1), true
+	}
+	return 0, 0, false
+}
+
+// enclosingBracket returns the offsets of the open and close brackets
+// around cursor, or of the pair the cursor is on. Brackets in strings and
+// comments don't count.
+func enclosingBracket(tokens []sqlparse.Token, cursor int, open, close string) (at, match int, ok bool) {
+	i := sort.Search(len(tokens), func(i int) bool { return tokens[i].Start > cursor }) - 1
+	if i >= 0 && tokens[i].Start == cursor && tokens[i].Kind == sqlparse.TokenPunct && (tokens[i].Text == open || tokens[i].Text == close) {
+		at, match, ok = matchingBracket(tokens, cursor)
+		return min(at, match), max(at, match), ok
+	}
+	depth := 0
+	for ; i >= 0; i-- {
+		if tokens[i].Kind != sqlparse.TokenPunct {
+			continue
+		}
+		switch tokens[i].Text {
+		case close:
+			depth++
+		case open:
+			if depth == 0 {
+				at, match, ok = matchingBracket(tokens, tokens[i].Start)
+				return at, match, ok
+			}
+			depth--
+		}
+	}
+	return 0, 0, false
+}
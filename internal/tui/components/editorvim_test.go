@@ -0,0 +1,46 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeKeys sends each rune of keys to the editor as a key press
+func typeKeys(e Editor, keys string) Editor {
+	for _, r := range keys {
+		e, _ = e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return e
+}
+
+func TestChangeInner(t *testing.T) {
+	tests := []struct {
+		value  string
+		cursor string // the cursor goes to the first occurrence
+		keys   string // normal-mode keys, then text typed in insert mode
+		want   string
+	}{
+		{"SELECT count(id) FROM t", "id", "ci(*", "SELECT count(*) FROM t"},
+		{"SELECT count() FROM t", ")", "ci(*", "SELECT count(*) FROM t"},
+		{"SELECT count() FROM t", "(", "cib*", "SELECT count(*) FROM t"},
+		{"SELECT 'abc' FROM t", "b", "ci'x", "SELECT 'x' FROM t"},
+		{"SELECT '' FROM t", "'", "ci'x", "SELECT 'x' FROM t"},
+		{"SELECT a FROM t WHERE x IN []", "]", "ci[1", "SELECT a FROM t WHERE x IN [1]"},
+	}
+	for _, tt := range tests {
+		e := NewEditor(EditorStyles{})
+		e.SetFocused(true)
+		e.SetValue(tt.value)
+		e.setCursorIndex(strings.Index(tt.value, tt.cursor))
+
+		e = typeKeys(e, tt.keys)
+		if got := e.GetValue(); got != tt.want {
+			t.Errorf("%q on %q = %q, want %q", tt.keys, tt.value, got, tt.want)
+		}
+		if e.InNormalMode() {
+			t.Errorf("%q on %q left the editor in normal mode", tt.keys, tt.value)
+		}
+	}
+}
@@ -50,6 +50,13 @@ var shortcuts = []ShortcutCategory{
 			{"↑/↓", "Navigate items"},
 			{"←/→", "Switch sidebar sections"},
 			{"Enter", "Select/Execute action"},
+			{"Space", "Expand/collapse partitions"},
+			{"i", "Table info"},
+			{"s", "Table structure"},
+			{"a", "Table actions (DDL, new row, maintenance)"},
+			{"i (Databases)", "Linked databases (ATTACH/FDW)"},
+			{"a / x (Databases)", "Attach/detach SQLite database"},
+			{"Enter / i (Views)", "Preview view / show definition"},
 		},
 	},
 	{
@@ -60,10 +67,17 @@ var shortcuts = []ShortcutCategory{
 			{"Ctrl+V", "Paste"},
 			{"Ctrl+X", "Cut selection"},
 			{"Ctrl+Z", "Undo"},
-			{"Ctrl+Y", "Redo"},
+			{"Ctrl+Y", "Redo (Ctrl+R in normal mode)"},
 			{"Ctrl+F", "Find"},
 			{"Ctrl+H", "Find & Replace"},
 			{"Ctrl+L", "Go to line"},
+			{"Ctrl+D", "Select the word / add its next occurrence"},
+			{"Alt+Click", "Add or remove a cursor"},
+			{"za (normal mode)", "Fold/unfold the subquery or CTE"},
+			{"w / b / e", "Next word / previous word / end of word (normal mode)"},
+			{"gg / G", "First / last line, or line N with a count"},
+			{"dd / yy / cc", "Delete / copy / change lines"},
+			{"ciw / di( / ci'", "Change or delete a word, inside brackets or quotes"},
 		},
 	},
 	{
@@ -71,6 +85,19 @@ var shortcuts = []ShortcutCategory{
 		Items: []ShortcutItem{
 			{"F5", "Run query"},
 			{"Ctrl+E", "Execute query"},
+			{"F9", "Run statement under cursor"},
+			{"F10", "Browse result snapshots"},
+			{"Ctrl+R", "Search query history (outside editor normal mode)"},
+			{"Ctrl+S", "Save query as a favorite"},
+			{"Alt+B", "Browse favorites"},
+			{"Alt+Shift+F", "Format the query or selection"},
+			{"F8", "Explain query plan"},
+			{"Alt+L", "Lint the query"},
+			{"Alt+S", "Browse snippets"},
+			{"Alt+N", "Toggle notebook mode"},
+			{"Alt+F", "Fold inline result"},
+			{"Alt+T", "Insert time filter"},
+			{"Alt+I", "Import a CSV file as a table"},
 			{"F3", "Toggle Keywords panel"},
 			{"Tab", "Accept suggestion"},
 		},
@@ -85,18 +112,46 @@ var shortcuts = []ShortcutCategory{
 	{
 		Name: "📊 Results",
 		Items: []ShortcutItem{
+			{"f / b", "Scroll down/up a screen of rows"},
 			{"c", "Copy selected row"},
+			{"y", "Copy selected cell value"},
+			{"Enter", "Show the full cell value"},
 			{"C", "Copy all data"},
+			{"J", "Copy all data as JSON"},
+			{"M", "Copy all data as a Markdown table"},
+			{"[ / ]", "Previous/next result set"},
+			{"{ / }", "Older/newer run from history"},
+			{"←/→", "Select column"},
+			{"+ / -", "Widen/narrow the selected column"},
+			{"p", "Pin columns up to the selected one"},
+			{"s", "Sort by the selected column (asc/desc/off)"},
+			{"/", "Filter rows (text, col=value, col~text)"},
+			{"*", "Find the selected cell's value in all rows"},
+			{"n / N", "Next/previous matching cell"},
+			{"e", "Edit the selected cell (UPDATE)"},
+			{"D", "Delete the selected row (DELETE)"},
+			{"F", "Value counts of the selected column"},
+			{"a", "Aggregates of the selected column (count/sum/avg/min/max)"},
 			{"v", "Toggle chart view"},
 			{"1/2/3", "Switch chart type"},
+			{"g / G", "First/last row; on a chart, group by the selected column / SUM, COUNT, AVG"},
+			{"5", "Record view: the selected row as column/value pairs"},
+			{"t", "Save results as temp table"},
+			{"i", "Insert column values as IN list"},
 		},
 	},
 	{
 		Name: "⚙️ General",
 		Items: []ShortcutItem{
 			{"F4", "Show/Hide this help"},
+			{"F6", "Server settings browser"},
+			{"F7", "Connection info"},
 			{"Ctrl+Q", "Quit application"},
 			{"Esc", "Close modal/Cancel"},
+			{"Esc/Ctrl+C", "Cancel running query"},
+			{"Ctrl+T", "Begin / commit / roll back transaction"},
+			{"F12", "Toggle read replica routing"},
+			{"Ctrl+O", "Export last query to CSV/JSON, or the session to Markdown"},
 		},
 	},
 }
@@ -0,0 +1,149 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportColumn is a column of a file being imported, as previewed
+type ImportColumn struct {
+	Name       string
+	Type       string
+	Overridden bool   // Type was chosen by the user
+	Samples    string // first values as parsed
+	Invalid    int    // values that don't parse as Type
+}
+
+// ImportPreview lists the columns of a file to import with their types and
+// parsed sample values, for checking them before the table is created
+type ImportPreview struct {
+	visible  bool
+	width    int
+	height   int
+	title    string
+	subtitle string
+	columns  []ImportColumn
+	selected int
+	offset   int
+	styles   ActionMenuStyles
+}
+
+// NewImportPreview creates a new import preview
+func NewImportPreview(styles ActionMenuStyles) ImportPreview {
+	return ImportPreview{styles: styles}
+}
+
+// Show opens the preview with the cursor on the first column
+func (p *ImportPreview) Show(title string) {
+	p.visible = true
+	p.title = title
+	p.selected = 0
+	p.offset = 0
+}
+
+// SetColumns replaces the columns and the line describing the file,
+// keeping the cursor
+func (p *ImportPreview) SetColumns(subtitle string, columns []ImportColumn) {
+	p.subtitle = subtitle
+	p.columns = columns
+	p.selected = min(p.selected, max(len(columns)-1, 0))
+}
+
+// Hide hides the preview
+func (p *ImportPreview) Hide() {
+	p.visible = false
+}
+
+// IsVisible returns if the preview is visible
+func (p ImportPreview) IsVisible() bool {
+	return p.visible
+}
+
+// SetSize sets the preview dimensions
+func (p *ImportPreview) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// Move moves the cursor by delta columns
+func (p *ImportPreview) Move(delta int) {
+	if len(p.columns) == 0 {
+		return
+	}
+	p.selected = min(max(p.selected+delta, 0), len(p.columns)-1)
+	rows := p.visibleRows()
+	if p.selected < p.offset {
+		p.offset = p.selected
+	}
+	if p.selected >= p.offset+rows {
+		p.offset = p.selected - rows + 1
+	}
+}
+
+// Selected returns the index of the column under the cursor
+func (p ImportPreview) Selected() int {
+	return p.selected
+}
+
+// visibleRows is how many columns fit, two lines each
+func (p ImportPreview) visibleRows() int {
+	return max((p.height-10)/2, 3)
+}
+
+// View renders the preview
+func (p ImportPreview) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	width := p.width
+	if width < 40 {
+		width = 40
+	}
+	rows := p.visibleRows()
+	nameWidth := 4
+	for _, col := range p.columns {
+		nameWidth = max(nameWidth, min(len([]rune(col.Name)), 24))
+	}
+
+	var b strings.Builder
+	b.WriteString(p.styles.Title.Render(p.title) + "\n")
+	b.WriteString(p.styles.Hint.Render(p.subtitle) + "\n\n")
+	end := min(p.offset+rows, len(p.columns))
+	for i := p.offset; i < end; i++ {
+		col := p.columns[i]
+		name := []rune(col.Name)
+		if len(name) > nameWidth {
+			name = append(name[:nameWidth-1], '…')
+		}
+		kind := col.Type
+		if col.Overridden {
+			kind += "*"
+		}
+		line := fmt.Sprintf("%-*s  %-10s", nameWidth, string(name), kind)
+		if col.Invalid > 0 {
+			line += fmt.Sprintf("  ✗ %d values don't parse", col.Invalid)
+		}
+		style := p.styles.Item
+		if i == p.selected {
+			style = p.styles.Selected
+		}
+		b.WriteString(style.Render(line) + "\n")
+
+		samples := []rune("    " + col.Samples)
+		if len(samples) > width-6 && width > 7 {
+			samples = append(samples[:width-7], '…')
+		}
+		b.WriteString(p.styles.Hint.Render(string(samples)) + "\n")
+	}
+	if len(p.columns) > rows {
+		b.WriteString(p.styles.Hint.Render(fmt.Sprintf("column %d of %d", p.selected+1, len(p.columns))) + "\n")
+	}
+
+	b.WriteString("\n" + p.styles.Hint.Render("↑↓: column • ←→: type (* = overridden) • d: date order • ,: decimal comma • Enter: continue • Esc: cancel"))
+
+	return p.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
@@ -0,0 +1,201 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// InfoRow is a single label/value line in the info panel
+type InfoRow struct {
+	Label string
+	Value string
+}
+
+// InfoSection groups rows (or free text) under a heading
+type InfoSection struct {
+	Title string
+	Rows  []InfoRow
+	Text  string
+}
+
+// InfoPanel component for read-only detail modals (table info, connection info...)
+type InfoPanel struct {
+	visible  bool
+	width    int
+	height   int
+	title    string
+	sections []InfoSection
+	offset   int
+	keys     string // extra key hints, shown before Esc
+	styles   InfoPanelStyles
+}
+
+// InfoPanelStyles holds styling for the info panel
+type InfoPanelStyles struct {
+	Modal   lipgloss.Style
+	Title   lipgloss.Style
+	Section lipgloss.Style
+	Label   lipgloss.Style
+	Value   lipgloss.Style
+	Hint    lipgloss.Style
+}
+
+// NewInfoPanel creates a new info panel
+func NewInfoPanel(styles InfoPanelStyles) InfoPanel {
+	return InfoPanel{
+		visible: false,
+		styles:  styles,
+	}
+}
+
+// Show shows the panel with the given content
+func (p *InfoPanel) Show(title string, sections []InfoSection) {
+	p.visible = true
+	p.title = title
+	p.sections = sections
+	p.offset = 0
+	p.keys = ""
+}
+
+// SetKeys sets extra key hints for the panel shown, e.g. "y: copy"
+func (p *InfoPanel) SetKeys(keys string) {
+	p.keys = keys
+}
+
+// Hide hides the panel
+func (p *InfoPanel) Hide() {
+	p.visible = false
+}
+
+// IsVisible returns if the panel is visible
+func (p InfoPanel) IsVisible() bool {
+	return p.visible
+}
+
+// SetSize sets the panel dimensions
+func (p *InfoPanel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// ScrollUp scrolls the content up by n lines
+func (p *InfoPanel) ScrollUp(n int) {
+	p.offset -= n
+	if p.offset < 0 {
+		p.offset = 0
+	}
+}
+
+// ScrollDown scrolls the content down by n lines
+func (p *InfoPanel) ScrollDown(n int) {
+	maxOffset := len(p.lines()) - p.visibleLines()
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	p.offset += n
+	if p.offset > maxOffset {
+		p.offset = maxOffset
+	}
+}
+
+// visibleLines returns how many content lines fit in the modal
+func (p InfoPanel) visibleLines() int {
+	// Title, hint and padding take about 8 lines
+	n := p.height - 8
+	if n < 5 {
+		n = 5
+	}
+	return n
+}
+
+// lines renders all sections into individual lines
+func (p InfoPanel) lines() []string {
+	labelWidth := 0
+	for _, s := range p.sections {
+		for _, r := range s.Rows {
+			if len(r.Label) > labelWidth {
+				labelWidth = len(r.Label)
+			}
+		}
+	}
+
+	var lines []string
+	for i, s := range p.sections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		if s.Title != "" {
+			lines = append(lines, p.styles.Section.Render(s.Title))
+		}
+		for _, r := range s.Rows {
+			label := p.styles.Label.Render(fmt.Sprintf("%-*s", labelWidth, r.Label))
+			lines = append(lines, "  "+label+"  "+p.styles.Value.Render(r.Value))
+		}
+		if s.Text != "" {
+			for _, l := range strings.Split(s.Text, "\n") {
+				for _, part := range wrapLine(l, p.textWidth()) {
+					lines = append(lines, "  "+p.styles.Value.Render(part))
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// textWidth returns how many columns free text gets before wrapping
+func (p InfoPanel) textWidth() int {
+	// Border, padding and the text indent
+	return max(p.width, 50) - 8
+}
+
+// wrapLine breaks a line into parts of at most width runes, so scrolling
+// counts the lines the modal shows
+func wrapLine(line string, width int) []string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return []string{line}
+	}
+	var parts []string
+	for len(runes) > width {
+		parts = append(parts, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(parts, string(runes))
+}
+
+// View renders the info panel
+func (p InfoPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	width := p.width
+	if width < 50 {
+		width = 50
+	}
+
+	content := p.styles.Title.Render(p.title) + "\n\n"
+
+	lines := p.lines()
+	end := p.offset + p.visibleLines()
+	if end > len(lines) {
+		end = len(lines)
+	}
+	content += strings.Join(lines[p.offset:end], "\n")
+
+	hint := "Esc: close"
+	if p.keys != "" {
+		hint = p.keys + " • " + hint
+	}
+	if len(lines) > p.visibleLines() {
+		hint = fmt.Sprintf("↑↓: scroll (%d/%d) • %s", end, len(lines), hint)
+	}
+	content += "\n\n" + p.styles.Hint.Render(hint)
+
+	return p.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InputPrompt asks for a single line of text, e.g. a name
+type InputPrompt struct {
+	input   textinput.Model
+	visible bool
+	width   int
+	title   string
+	message string
+	styles  ConfirmModalStyles
+}
+
+// NewInputPrompt creates a new input prompt
+func NewInputPrompt(styles ConfirmModalStyles) InputPrompt {
+	ti := textinput.New()
+	ti.CharLimit = 128
+	ti.Width = 40
+
+	return InputPrompt{
+		input:  ti,
+		styles: styles,
+	}
+}
+
+// Show opens the prompt with value prefilled
+func (p *InputPrompt) Show(title, message, value string) {
+	p.visible = true
+	p.title = title
+	p.message = message
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	p.input.Focus()
+}
+
+// Hide hides the prompt
+func (p *InputPrompt) Hide() {
+	p.visible = false
+	p.input.Blur()
+}
+
+// IsVisible returns if the prompt is visible
+func (p InputPrompt) IsVisible() bool {
+	return p.visible
+}
+
+// Value returns the entered text
+func (p InputPrompt) Value() string {
+	return p.input.Value()
+}
+
+// SetSize sets the prompt width
+func (p *InputPrompt) SetSize(width, height int) {
+	p.width = width
+	p.input.Width = width - 10
+}
+
+// Update handles input events
+func (p InputPrompt) Update(msg tea.Msg) (InputPrompt, tea.Cmd) {
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
+
+// View renders the prompt
+func (p InputPrompt) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	content := p.styles.Title.Render(p.title) + "\n\n"
+	content += p.input.View() + "\n\n"
+	if p.message != "" {
+		content += p.styles.Message.Render(p.message) + "\n"
+	}
+	content += p.styles.Hint.Render("Enter: confirm • Esc: cancel")
+
+	width := p.width
+	if width < 40 {
+		width = 40
+	}
+
+	return p.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
@@ -0,0 +1,38 @@
+package components
+
+import "github.com/charmbracelet/lipgloss"
+
+// PaneState is what a pane's border shows besides focus
+type PaneState int
+
+const (
+	PaneIdle PaneState = iota
+	PaneRunning
+	PaneError
+	PaneStale
+)
+
+// PaneBorders holds the border colors of the pane states; an unset color
+// keeps the focus color
+type PaneBorders struct {
+	Running lipgloss.Color
+	Error   lipgloss.Color
+	Stale   lipgloss.Color
+}
+
+// style returns the pane style with its border colored for state
+func (b PaneBorders) style(style lipgloss.Style, state PaneState) lipgloss.Style {
+	var color lipgloss.Color
+	switch state {
+	case PaneRunning:
+		color = b.Running
+	case PaneError:
+		color = b.Error
+	case PaneStale:
+		color = b.Stale
+	}
+	if color == "" {
+		return style
+	}
+	return style.BorderForeground(color)
+}
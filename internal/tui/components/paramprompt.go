@@ -0,0 +1,180 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ParamPrompt asks for the values of a query's placeholders before it runs
+type ParamPrompt struct {
+	names   []string
+	inputs  []textinput.Model
+	focus   int
+	visible bool
+	width   int
+	styles  ConfirmModalStyles
+
+	// Texts, replaced when the prompt is reused as a form
+	title string
+	note  string
+	hint  string
+}
+
+// NewParamPrompt creates a new parameter prompt
+func NewParamPrompt(styles ConfirmModalStyles) ParamPrompt {
+	return ParamPrompt{
+		styles: styles,
+		title:  "🧩 Query parameters",
+		note:   "Values are bound as text; NULL binds SQL NULL.",
+		hint:   "Tab: next • Enter: run • Esc: cancel",
+	}
+}
+
+// SetLabels reuses the prompt as another form: title heads it, note
+// explains the values and hint replaces the key help
+func (p *ParamPrompt) SetLabels(title, note, hint string) {
+	p.title = title
+	p.note = note
+	p.hint = hint
+}
+
+// SetPlaceholders sets the placeholder of each input, in Show order
+func (p *ParamPrompt) SetPlaceholders(placeholders []string) {
+	for i := range p.inputs {
+		if i < len(placeholders) && placeholders[i] != "" {
+			p.inputs[i].Placeholder = placeholders[i]
+		}
+	}
+}
+
+// Show opens the prompt with one input per parameter name, prefilled with
+// the values entered last time
+func (p *ParamPrompt) Show(names []string, previous map[string]string) {
+	p.visible = true
+	p.names = names
+	p.inputs = make([]textinput.Model, len(names))
+	for i, name := range names {
+		ti := textinput.New()
+		ti.Prompt = ""
+		ti.Placeholder = "value"
+		ti.Width = p.inputWidth()
+		ti.SetValue(previous[name])
+		ti.CursorEnd()
+		p.inputs[i] = ti
+	}
+	p.focus = 0
+	if len(p.inputs) > 0 {
+		p.inputs[0].Focus()
+	}
+}
+
+// Hide hides the prompt
+func (p *ParamPrompt) Hide() {
+	p.visible = false
+	p.names = nil
+	p.inputs = nil
+}
+
+// IsVisible returns if the prompt is visible
+func (p ParamPrompt) IsVisible() bool {
+	return p.visible
+}
+
+// Values returns the entered value of each parameter by name
+func (p ParamPrompt) Values() map[string]string {
+	values := make(map[string]string, len(p.names))
+	for i, name := range p.names {
+		values[name] = p.inputs[i].Value()
+	}
+	return values
+}
+
+// FocusNext moves to the next input, wrapping around
+func (p *ParamPrompt) FocusNext() {
+	p.move(1)
+}
+
+// FocusPrev moves to the previous input, wrapping around
+func (p *ParamPrompt) FocusPrev() {
+	p.move(-1)
+}
+
+func (p *ParamPrompt) move(delta int) {
+	if len(p.inputs) == 0 {
+		return
+	}
+	p.inputs[p.focus].Blur()
+	p.focus = (p.focus + delta + len(p.inputs)) % len(p.inputs)
+	p.inputs[p.focus].Focus()
+}
+
+// SetSize sets the prompt width
+func (p *ParamPrompt) SetSize(width, height int) {
+	p.width = width
+	for i := range p.inputs {
+		p.inputs[i].Width = p.inputWidth()
+	}
+}
+
+// inputWidth leaves room for the labels and the modal padding
+func (p ParamPrompt) inputWidth() int {
+	width := p.width - 10 - p.labelWidth()
+	if width < 20 {
+		width = 20
+	}
+	return width
+}
+
+func (p ParamPrompt) labelWidth() int {
+	width := 0
+	for _, name := range p.names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	return width
+}
+
+// Update handles input events for the focused input
+func (p ParamPrompt) Update(msg tea.Msg) (ParamPrompt, tea.Cmd) {
+	if len(p.inputs) == 0 {
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.inputs[p.focus], cmd = p.inputs[p.focus].Update(msg)
+	return p, cmd
+}
+
+// View renders the prompt
+func (p ParamPrompt) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	content := p.styles.Title.Render(p.title) + "\n\n"
+	labelWidth := p.labelWidth()
+	for i, name := range p.names {
+		label := name + strings.Repeat(" ", labelWidth-len(name))
+		if i == p.focus {
+			// Without the title margin, which would push the input to the next line
+			label = p.styles.Title.UnsetMarginBottom().Render(label)
+		} else {
+			label = p.styles.Message.Render(label)
+		}
+		content += label + "  " + p.inputs[i].View() + "\n"
+	}
+	content += "\n" + p.styles.Message.Render(p.note) + "\n"
+	content += p.styles.Hint.Render(p.hint)
+
+	width := p.width
+	if width < 40 {
+		width = 40
+	}
+
+	return p.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PasswordPrompt asks for a connection's password when it isn't stored in
+// the config
+type PasswordPrompt struct {
+	input   textinput.Model
+	visible bool
+	width   int
+	title   string
+	message string
+	styles  ConfirmModalStyles
+}
+
+// NewPasswordPrompt creates a new password prompt
+func NewPasswordPrompt(styles ConfirmModalStyles) PasswordPrompt {
+	ti := textinput.New()
+	ti.Placeholder = "password"
+	ti.EchoMode = textinput.EchoPassword
+	ti.Width = 40
+
+	return PasswordPrompt{
+		input:  ti,
+		styles: styles,
+	}
+}
+
+// Show opens the prompt for the named connection with an empty input
+func (p *PasswordPrompt) Show(connName string) {
+	p.visible = true
+	p.title = "🔑 Password for " + connName
+	p.message = "Kept in memory for this session only."
+	p.input.SetValue("")
+	p.input.Focus()
+}
+
+// Hide hides the prompt and forgets what was typed
+func (p *PasswordPrompt) Hide() {
+	p.visible = false
+	p.input.SetValue("")
+	p.input.Blur()
+}
+
+// IsVisible returns if the prompt is visible
+func (p PasswordPrompt) IsVisible() bool {
+	return p.visible
+}
+
+// Value returns the typed password
+func (p PasswordPrompt) Value() string {
+	return p.input.Value()
+}
+
+// SetSize sets the prompt width
+func (p *PasswordPrompt) SetSize(width, height int) {
+	p.width = width
+	p.input.Width = width - 10
+}
+
+// Update handles input events
+func (p PasswordPrompt) Update(msg tea.Msg) (PasswordPrompt, tea.Cmd) {
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
+
+// View renders the prompt
+func (p PasswordPrompt) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	content := p.styles.Title.Render(p.title) + "\n\n"
+	content += p.input.View() + "\n\n"
+	content += p.styles.Message.Render(p.message) + "\n"
+	content += p.styles.Hint.Render("Enter: connect • Esc: cancel")
+
+	width := p.width
+	if width < 40 {
+		width = 40
+	}
+
+	return p.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
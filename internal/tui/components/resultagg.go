@@ -0,0 +1,83 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ToggleAggregates shows or hides the footer with aggregates of the
+// selected column, reporting whether it is shown
+func (r *Results) ToggleAggregates() bool {
+	r.aggregates = !r.aggregates
+	r.table.SetHeight(r.tableHeight())
+	r.updateTable()
+	return r.aggregates
+}
+
+// renderAggregates renders the footer: count, sum, avg, min and max of the
+// selected column over the fetched rows the filter keeps, or the count of
+// values for columns that aren't numbers
+func (r Results) renderAggregates() string {
+	if r.selCol >= len(r.columns) {
+		return ""
+	}
+	col := r.columns[r.selCol]
+	count, nulls := 0, 0
+	var sum, lo, hi float64
+	numbers := 0
+	for _, row := range r.rows {
+		v := row[col]
+		if v.Null {
+			nulls++
+			continue
+		}
+		count++
+		if !r.colTypes[r.selCol].IsNumeric() {
+			continue
+		}
+		f, ok := numericValue(v)
+		if !ok {
+			if f, ok = parseNumber(CellText(v, r.colTypes[r.selCol])); !ok {
+				continue
+			}
+		}
+		if numbers == 0 || f < lo {
+			lo = f
+		}
+		if numbers == 0 || f > hi {
+			hi = f
+		}
+		sum += f
+		numbers++
+	}
+
+	parts := []string{fmt.Sprintf("count %d", count)}
+	if nulls > 0 {
+		parts[0] += fmt.Sprintf(" (%d NULL)", nulls)
+	}
+	if numbers > 0 {
+		parts = append(parts,
+			"sum "+formatAggregate(sum),
+			"avg "+formatAggregate(sum/float64(numbers)),
+			"min "+formatAggregate(lo),
+			"max "+formatAggregate(hi))
+	}
+	return r.styles.Info.Render(fmt.Sprintf("Σ %s: %s", col, strings.Join(parts, " · ")))
+}
+
+// parseNumber reads numeric text, e.g. of untyped results
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil
+}
+
+// formatAggregate formats an aggregate: whole numbers as they are, others
+// with up to four decimals
+func formatAggregate(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,126 @@
+package components
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// renderTable renders the table page with each cell styled by its value:
+// NULL muted, numbers and booleans in their own colors, numeric columns
+// right-aligned under right-aligned headers. The table keeps
+// the cursor; its rows are plain text, as its renderer cuts styled text.
+func (r Results) renderTable() string {
+	cols := r.table.Columns()
+	rows := r.table.Rows()
+
+	header := make([]string, 0, len(cols)+1)
+	gutter := r.gutterWidth() - 2
+	if gutter > 0 {
+		header = append(header, r.styles.Header.Render(fitCell("#", gutter, lipgloss.Right)))
+	}
+	for j, col := range cols {
+		header = append(header, r.styles.Header.Render(fitCell(col.Title, col.Width, r.columnAlign(j))))
+	}
+
+	height := max(r.tableHeight()-1, 1)
+	offset := r.rowOffset()
+	lines := make([]string, 0, height)
+	for i := offset; i < min(offset+height, len(rows)); i++ {
+		cells := make([]string, 0, len(cols)+1)
+		if gutter > 0 {
+			number := strconv.Itoa(r.rowBase + r.page*r.pageSize + i + 1)
+			text := fitCell(number, gutter, lipgloss.Right)
+			if i != r.table.Cursor() {
+				// Muted like NULL, which is what it is not
+				text = r.styles.Null.UnsetItalic().Render(text)
+			}
+			cells = append(cells, r.styles.Cell.Render(text))
+		}
+		for j, col := range cols {
+			text := fitCell(rows[i][j], col.Width, r.columnAlign(j))
+			if i != r.table.Cursor() {
+				text = r.cellStyle(r.page*r.pageSize+i, j).Render(text)
+			}
+			cells = append(cells, r.styles.Cell.Render(text))
+		}
+		line := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+		if i == r.table.Cursor() {
+			line = r.styles.SelectedRow.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	width := r.width - 4
+	body := lipgloss.NewStyle().Width(width).MaxWidth(width).Height(height).MaxHeight(height).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, header...) + "\n" + body
+}
+
+// SetRowNumbers shows or hides the row number gutter
+func (r *Results) SetRowNumbers(show bool) {
+	r.rowNumbers = show
+	r.updateTable()
+}
+
+// gutterWidth returns the width of the row number gutter, with its
+// padding, or 0 when it is hidden. It fits the last number of the page.
+func (r Results) gutterWidth() int {
+	if !r.rowNumbers {
+		return 0
+	}
+	last := r.rowBase + min((r.page+1)*r.pageSize, max(len(r.rows), 1))
+	return len(strconv.Itoa(last)) + 2
+}
+
+// fitCell pads or cuts text to width, aligned to align
+func fitCell(text string, width int, align lipgloss.Position) string {
+	return lipgloss.NewStyle().Width(width).MaxWidth(width).Align(align).Inline(true).Render(text)
+}
+
+// columnAlign returns the alignment of shown column j: numbers right, the
+// rest left
+func (r Results) columnAlign(j int) lipgloss.Position {
+	if j < len(r.shown) && r.shown[j] < len(r.colTypes) && r.colTypes[r.shown[j]].Kind == db.ColumnNumber {
+		return lipgloss.Right
+	}
+	return lipgloss.Left
+}
+
+// cellStyle returns the style of the cell at row and shown column j
+func (r Results) cellStyle(row, j int) lipgloss.Style {
+	if row >= len(r.rows) || j >= len(r.shown) {
+		return lipgloss.NewStyle()
+	}
+	i := r.shown[j]
+	return r.valueStyle(r.rows[row][r.columns[i]], r.colTypes[i])
+}
+
+// valueStyle returns the style of a value of a column of colType
+func (r Results) valueStyle(v db.Value, colType db.ColumnType) lipgloss.Style {
+	if v.Null {
+		return r.styles.Null
+	}
+	switch colType.Kind {
+	case db.ColumnNumber:
+		return r.styles.Number
+	case db.ColumnBool:
+		return r.styles.Bool
+	}
+	return lipgloss.NewStyle()
+}
+
+// rowOffset returns the first row of the page in view: the last one, or
+// moved just enough to show the cursor
+func (r Results) rowOffset() int {
+	height := max(r.tableHeight()-1, 1)
+	cursor := r.table.Cursor()
+	offset := min(r.offset, cursor)
+	if cursor >= offset+height {
+		offset = cursor - height + 1
+	}
+	return max(offset, 0)
+}
@@ -0,0 +1,145 @@
+package components
+
+import (
+	"fmt"
+)
+
+// ChartAgg is how grouped chart rows are aggregated
+type ChartAgg int
+
+const (
+	ChartSum ChartAgg = iota
+	ChartCount
+	ChartAvg
+)
+
+// chartLabelMax is the widest group label of a bar chart
+const chartLabelMax = 20
+
+// String returns the SQL name of the aggregate
+func (a ChartAgg) String() string {
+	switch a {
+	case ChartCount:
+		return "COUNT"
+	case ChartAvg:
+		return "AVG"
+	}
+	return "SUM"
+}
+
+// ToggleChartGroup groups the charted rows by the selected column, or
+// charts them row by row again when they already are. It returns a
+// description of what is charted.
+func (r *Results) ToggleChartGroup() string {
+	if len(r.columns) == 0 {
+		return ""
+	}
+	if r.chartGroup == r.selCol {
+		r.chartGroup = -1
+		return "Charting rows one by one"
+	}
+	r.chartGroup = r.selCol
+	return r.chartCaption()
+}
+
+// NextChartAgg switches grouped charts to the next aggregate: SUM, COUNT,
+// then AVG. It returns a description of what is charted.
+func (r *Results) NextChartAgg() string {
+	r.chartAgg = (r.chartAgg + 1) % 3
+	if r.chartGroup < 0 {
+		return fmt.Sprintf("Grouped charts use %s (g groups by the selected column)", r.chartAgg)
+	}
+	return r.chartCaption()
+}
+
+// chartCaption describes a grouped chart, e.g. SUM(amount) by country
+func (r Results) chartCaption() string {
+	_, _, caption := r.extractNumericData()
+	if caption == "" {
+		return fmt.Sprintf("No numeric column to %s by %s", r.chartAgg, r.columns[r.chartGroup])
+	}
+	return "Charting " + caption
+}
+
+// groupedData aggregates the valueCol values of the rows per value of the
+// group column, in the order groups first appear. COUNT counts rows and
+// needs no value column.
+func (r Results) groupedData(valueCol string) ([]float64, []string, string) {
+	group := r.columns[r.chartGroup]
+	if valueCol == "" && r.chartAgg != ChartCount {
+		return nil, nil, ""
+	}
+
+	var labels []string
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, row := range r.rows {
+		v := row[group]
+		label := "NULL"
+		if !v.Null {
+			label = CellText(v, r.colTypes[r.chartGroup])
+		}
+		if _, seen := counts[label]; !seen {
+			labels = append(labels, label)
+			counts[label] = 0
+		}
+		if r.chartAgg == ChartCount {
+			counts[label]++
+			continue
+		}
+		if f, ok := chartValue(row[valueCol]); ok {
+			sums[label] += f
+			counts[label]++
+		}
+	}
+
+	data := make([]float64, len(labels))
+	for i, label := range labels {
+		switch r.chartAgg {
+		case ChartCount:
+			data[i] = float64(counts[label])
+		case ChartAvg:
+			if counts[label] > 0 {
+				data[i] = sums[label] / float64(counts[label])
+			}
+		default:
+			data[i] = sums[label]
+		}
+	}
+
+	caption := fmt.Sprintf("%s(%s) by %s", r.chartAgg, valueCol, group)
+	if r.chartAgg == ChartCount {
+		caption = "COUNT(*) by " + group
+	}
+	return data, labels, caption
+}
+
+// IsChart reports whether the results are shown as a chart
+func (r Results) IsChart() bool {
+	switch r.viewMode {
+	case ViewChartBar, ViewChartLine, ViewChartPie:
+		return len(r.rows) > 0
+	}
+	return false
+}
+
+// ChartText renders the shown chart as text, with every bar and slice
+// rather than those that fit in the pane
+func (r Results) ChartText() string {
+	data, _, _ := r.extractNumericData()
+	full := r
+	full.height = max(r.height, len(data)+6)
+	switch r.viewMode {
+	case ViewChartLine:
+		return r.renderLineChart()
+	case ViewChartPie:
+		return full.renderPieChart()
+	}
+	return full.renderBarChart()
+}
+
+// ChartValues returns the values of the shown chart
+func (r Results) ChartValues() []float64 {
+	data, _, _ := r.extractNumericData()
+	return data
+}
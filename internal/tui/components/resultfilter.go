@@ -0,0 +1,205 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// filterTerm is one condition of the filter bar. Without a column it
+// matches the text anywhere in the row.
+type filterTerm struct {
+	column int // index in columns, -1 for any column
+	op     string
+	value  string
+}
+
+// StartFilter focuses the filter bar, keeping the current filter
+func (r *Results) StartFilter() tea.Cmd {
+	if len(r.columns) == 0 {
+		return nil
+	}
+	if r.filterIn.Prompt == "" {
+		r.filterIn = textinput.New()
+		r.filterIn.Prompt = "/ "
+		r.filterIn.Placeholder = "text, column=value, column!=value, column~text"
+		r.filterIn.CharLimit = 256
+	}
+	r.filterIn.Width = max(r.width-30, 20)
+	r.filterIn.SetValue(r.filter)
+	r.filterIn.CursorEnd()
+	r.filterOn = true
+	r.table.SetHeight(r.tableHeight())
+	return r.filterIn.Focus()
+}
+
+// IsFiltering reports whether the filter bar has focus
+func (r Results) IsFiltering() bool {
+	return r.filterOn
+}
+
+// UpdateFilter edits the filter, narrowing the rows as it changes
+func (r *Results) UpdateFilter(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	r.filterIn, cmd = r.filterIn.Update(msg)
+	if value := strings.TrimSpace(r.filterIn.Value()); value != r.filter {
+		r.filter = value
+		r.applyView()
+	}
+	return cmd
+}
+
+// EndFilter leaves the filter bar, keeping the filter applied
+func (r *Results) EndFilter() {
+	r.filterOn = false
+	r.filterIn.Blur()
+	r.table.SetHeight(r.tableHeight())
+}
+
+// ClearFilter shows every row again; false when no filter was set
+func (r *Results) ClearFilter() bool {
+	had := r.filter != "" || r.filterOn
+	r.resetFilter()
+	if had {
+		r.applyView()
+	}
+	return had
+}
+
+// Filter returns the applied filter, "" when none
+func (r Results) Filter() string {
+	return r.filter
+}
+
+// FilterCounts returns the rows matching the filter and the fetched rows
+func (r Results) FilterCounts() (matched, total int) {
+	return len(r.rows), len(r.base)
+}
+
+// resetFilter forgets the filter of the previous result set
+func (r *Results) resetFilter() {
+	r.filter = ""
+	r.filterOn = false
+	r.filterIn.Blur()
+	r.table.SetHeight(r.tableHeight())
+}
+
+// filterBarShown reports whether the filter bar takes a line
+func (r Results) filterBarShown() bool {
+	return r.filterOn || r.filter != ""
+}
+
+// renderFilterBar renders the filter input with the match count
+func (r Results) renderFilterBar() string {
+	matched, total := r.FilterCounts()
+	count := r.styles.Info.Render(fmt.Sprintf("  %d/%d rows", matched, total))
+	if r.filterOn {
+		return r.filterIn.View() + count
+	}
+	return r.styles.Info.Render("/ "+r.filter) + count
+}
+
+// applyView derives the shown rows from the fetched ones: filtered, then
+// sorted. Without either, rows is the result set itself.
+func (r *Results) applyView() {
+	rows := r.base
+	if r.filter != "" {
+		terms := parseFilter(r.filter, r.columns)
+		var kept []db.Row
+		for _, row := range rows {
+			if r.rowMatches(row, terms) {
+				kept = append(kept, row)
+			}
+		}
+		rows = kept
+	}
+	if r.sortDir != SortNone && r.sortCol >= 0 && r.sortCol < len(r.columns) {
+		rows = r.sortRows(rows)
+	}
+	r.rows = rows
+	r.rowCount = len(rows)
+
+	// Matches point at row positions, which just changed
+	r.matches = nil
+	r.finding = false
+	r.page = 0
+	r.table.SetCursor(0)
+	r.updateTable()
+}
+
+// parseFilter splits the filter into terms, all of which must match.
+// column=value compares whole values, column!=value excludes them and
+// column~text looks for text in the column; other words match anywhere.
+// Comparisons ignore case, and value null stands for NULL.
+func parseFilter(text string, columns []string) []filterTerm {
+	var terms []filterTerm
+	for _, word := range strings.Fields(text) {
+		term := filterTerm{column: -1, value: strings.ToLower(word)}
+		for _, op := range []string{"!=", "=", "~"} {
+			name, value, ok := strings.Cut(word, op)
+			if !ok || name == "" {
+				continue
+			}
+			for i, col := range columns {
+				if strings.EqualFold(col, name) {
+					term = filterTerm{column: i, op: op, value: strings.ToLower(value)}
+					break
+				}
+			}
+			break
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// rowMatches reports whether a row satisfies every term
+func (r Results) rowMatches(row db.Row, terms []filterTerm) bool {
+	for _, term := range terms {
+		if term.column < 0 {
+			if !r.rowContains(row, term.value) {
+				return false
+			}
+			continue
+		}
+
+		v := row[r.columns[term.column]]
+		text := strings.ToLower(CellText(v, r.colTypes[term.column]))
+		var ok bool
+		switch term.op {
+		case "=":
+			ok = equalsFilterValue(v, text, term.value)
+		case "!=":
+			ok = !equalsFilterValue(v, text, term.value)
+		case "~":
+			ok = !v.Null && strings.Contains(text, term.value)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// equalsFilterValue compares a cell to a filter value, null matching NULL
+func equalsFilterValue(v db.Value, text, value string) bool {
+	if value == "null" {
+		return v.Null
+	}
+	return !v.Null && text == value
+}
+
+// rowContains reports whether any non-NULL cell of the row contains text
+func (r Results) rowContains(row db.Row, text string) bool {
+	for i, col := range r.columns {
+		v := row[col]
+		if !v.Null && strings.Contains(strings.ToLower(CellText(v, r.colTypes[i])), text) {
+			return true
+		}
+	}
+	return false
+}
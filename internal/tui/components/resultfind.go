@@ -0,0 +1,125 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// matchMarker prefixes cells holding the value being found
+const matchMarker = "◆ "
+
+// cellPos is a cell of the shown result set
+type cellPos struct {
+	row, col int
+}
+
+// SelectedColumn returns the index of the selected column
+func (r Results) SelectedColumn() int {
+	return r.selCol
+}
+
+// MoveColumn moves the column selection by delta, staying in range
+func (r *Results) MoveColumn(delta int) {
+	if len(r.columns) == 0 {
+		return
+	}
+	r.selCol = min(max(r.selCol+delta, 0), len(r.columns)-1)
+	r.updateTable()
+}
+
+// SelectedCell returns the text of the selected cell and whether it is NULL
+func (r Results) SelectedCell() (text string, null bool, ok bool) {
+	row := r.SelectedRowIndex()
+	if row < 0 || r.selCol >= len(r.columns) {
+		return "", false, false
+	}
+	v := r.rows[row][r.columns[r.selCol]]
+	return CellText(v, r.colTypes[r.selCol]), v.Null, true
+}
+
+// FindOccurrences marks every fetched cell, in any column, that holds the
+// value of the selected cell and returns how many there are. The
+// selected cell is the current match.
+func (r *Results) FindOccurrences() (int, error) {
+	text, null, ok := r.SelectedCell()
+	if !ok {
+		return 0, fmt.Errorf("no cell selected")
+	}
+
+	r.matches = nil
+	r.matchIndex = 0
+	selected := cellPos{row: r.SelectedRowIndex(), col: r.selCol}
+	for i, row := range r.rows {
+		for j, col := range r.columns {
+			if r.cellMatches(row[col], r.colTypes[j], text, null) {
+				if (cellPos{i, j}) == selected {
+					r.matchIndex = len(r.matches)
+				}
+				r.matches = append(r.matches, cellPos{i, j})
+			}
+		}
+	}
+	r.findText, r.findNull, r.finding = text, null, true
+	r.updateTable()
+	return len(r.matches), nil
+}
+
+// cellMatches reports whether v shows as text, NULL matching only NULL
+func (r Results) cellMatches(v db.Value, colType db.ColumnType, text string, null bool) bool {
+	if v.Null || null {
+		return v.Null == null
+	}
+	return CellText(v, colType) == text
+}
+
+// NextMatch moves the selection to the next match, wrapping around, and
+// returns its 1-based position
+func (r *Results) NextMatch() int {
+	return r.gotoMatch(r.matchIndex + 1)
+}
+
+// PrevMatch moves the selection to the previous match, wrapping around
+func (r *Results) PrevMatch() int {
+	return r.gotoMatch(r.matchIndex - 1)
+}
+
+// gotoMatch selects match i, turning to its page
+func (r *Results) gotoMatch(i int) int {
+	if len(r.matches) == 0 {
+		return 0
+	}
+	r.matchIndex = (i + len(r.matches)) % len(r.matches)
+	m := r.matches[r.matchIndex]
+	r.page = m.row / r.pageSize
+	r.selCol = m.col
+	r.updateTable()
+	r.table.SetCursor(m.row % r.pageSize)
+	return r.matchIndex + 1
+}
+
+// MatchCount returns the number of matches of the value being found, 0
+// when not finding
+func (r Results) MatchCount() int {
+	return len(r.matches)
+}
+
+// FindText returns the value being found
+func (r Results) FindText() string {
+	if r.findNull {
+		return "NULL"
+	}
+	return r.findText
+}
+
+// ClearMatches stops finding, unmarking the matched cells. It reports
+// whether anything was marked.
+func (r *Results) ClearMatches() bool {
+	if !r.finding {
+		return false
+	}
+	r.finding = false
+	r.matches = nil
+	r.updateTable()
+	return true
+}
@@ -0,0 +1,147 @@
+package components
+
+import (
+	"strings"
+	"time"
+)
+
+// resultRun is a past query run kept as a tab in the results pane
+type resultRun struct {
+	query     string
+	at        time.Time
+	sets      []ResultSet
+	activeSet int
+}
+
+// SetHistoryLimit sets how many past runs are kept as tabs, 0 for none
+func (r *Results) SetHistoryLimit(n int) {
+	r.maxRuns = n
+	r.trimRuns()
+}
+
+// AddRun shows the result sets of query, run at at, and keeps them as the
+// newest history tab
+func (r *Results) AddRun(query string, at time.Time, sets []ResultSet) {
+	r.SetResultSets(sets)
+	r.keepRun(query, at, sets)
+}
+
+// keepRun adds the shown result sets as the newest history tab
+func (r *Results) keepRun(query string, at time.Time, sets []ResultSet) {
+	if r.maxRuns <= 0 {
+		return
+	}
+	r.runs = append(r.runs, resultRun{query: query, at: at, sets: sets})
+	r.trimRuns()
+	r.activeRun = len(r.runs) - 1
+	r.table.SetHeight(r.tableHeight())
+}
+
+// trimRuns drops the oldest runs beyond the limit
+func (r *Results) trimRuns() {
+	drop := len(r.runs) - r.maxRuns
+	if drop <= 0 {
+		return
+	}
+	r.runs = append([]resultRun(nil), r.runs[drop:]...)
+	if r.activeRun >= 0 {
+		r.activeRun = max(r.activeRun-drop, -1)
+	}
+}
+
+// RunCount returns the number of history tabs
+func (r Results) RunCount() int {
+	return len(r.runs)
+}
+
+// NextRun switches to the next newer history tab, wrapping around
+func (r *Results) NextRun() {
+	if len(r.runs) == 0 {
+		return
+	}
+	if r.activeRun < 0 {
+		r.showRun(len(r.runs) - 1)
+		return
+	}
+	r.showRun((r.activeRun + 1) % len(r.runs))
+}
+
+// PrevRun switches to the next older history tab, wrapping around
+func (r *Results) PrevRun() {
+	if len(r.runs) == 0 {
+		return
+	}
+	if r.activeRun < 0 {
+		r.showRun(len(r.runs) - 1)
+		return
+	}
+	r.showRun((r.activeRun - 1 + len(r.runs)) % len(r.runs))
+}
+
+// showRun shows history tab i with the result set tab it was left on
+func (r *Results) showRun(i int) {
+	run := r.runs[i]
+	r.sets = nil
+	if len(run.sets) > 1 {
+		r.sets = run.sets
+	}
+	r.activeSet = run.activeSet
+	r.activeRun = i
+	if len(run.sets) == 0 {
+		r.loadData(ResultSet{})
+		return
+	}
+	r.loadData(run.sets[run.activeSet])
+}
+
+// rememberActiveSet records the result set tab of the shown history tab
+func (r *Results) rememberActiveSet() {
+	if r.activeRun >= 0 {
+		r.runs[r.activeRun].activeSet = r.activeSet
+	}
+}
+
+// showRunTabs reports whether the history tab row is shown: once there is
+// another run to switch to
+func (r Results) showRunTabs() bool {
+	return len(r.runs) > 1 || (len(r.runs) == 1 && r.activeRun < 0)
+}
+
+// renderRunTabs renders the history tab row, each tab labelled with the
+// start of its query, the time it ran and how long it took
+func (r Results) renderRunTabs() string {
+	const hint = "  {/}: history"
+	// Each tab takes its label plus brackets, padding and the time
+	width := (r.width-4-len(hint))/len(r.runs) - len(" 15:04:05 · 1.27s ") - 4
+	width = max(width, 6)
+
+	tabs := make([]string, len(r.runs))
+	for i, run := range r.runs {
+		label := " " + truncateQuery(run.query, width) + " " + run.at.Format("15:04:05") + run.took() + " "
+		if i == r.activeRun {
+			tabs[i] = r.styles.Title.Render("[" + label + "]")
+		} else {
+			tabs[i] = r.styles.Info.Render(" " + label + " ")
+		}
+	}
+	return strings.Join(tabs, "") + r.styles.Info.Render(hint)
+}
+
+// took is the " · 1.27s" of a history tab, "" when the duration of the
+// run is unknown
+func (run resultRun) took() string {
+	if len(run.sets) == 0 || run.sets[0].Elapsed <= 0 {
+		return ""
+	}
+	return " · " + FormatElapsed(run.sets[0].Elapsed)
+}
+
+// truncateQuery collapses the whitespace of query and cuts it to width
+// runes
+func truncateQuery(query string, width int) string {
+	runes := []rune(strings.Join(strings.Fields(query), " "))
+	if len(runes) <= width {
+		return string(runes)
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,95 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TogglePin pins the columns up to and including the selected one, so they
+// stay on the left while scrolling sideways, and returns how many are
+// pinned. Pinning the same columns again unpins them.
+func (r *Results) TogglePin() int {
+	if len(r.columns) == 0 {
+		return 0
+	}
+	if r.pinned == r.selCol+1 {
+		r.pinned = 0
+	} else {
+		r.pinned = r.selCol + 1
+	}
+	r.colOffset = 0
+	r.updateTable()
+	return r.pinned
+}
+
+// Pinned returns how many leading columns are pinned
+func (r Results) Pinned() int {
+	return min(r.pinned, len(r.columns))
+}
+
+// layoutColumns returns the indexes of the columns that fit in the pane:
+// the pinned ones, then the others from colOffset, which moves so the
+// selected column is always shown
+func (r *Results) layoutColumns(widths []int) []int {
+	pinned := r.Pinned()
+	avail := r.width - 4 - r.gutterWidth()
+	used := 0
+	shown := make([]int, 0, len(widths))
+	for i := 0; i < pinned; i++ {
+		shown = append(shown, i)
+		used += widths[i] + 2
+	}
+	if pinned == len(widths) {
+		return shown
+	}
+
+	r.colOffset = min(max(r.colOffset, pinned), len(widths)-1)
+	if r.selCol >= pinned {
+		if r.selCol < r.colOffset {
+			r.colOffset = r.selCol
+		}
+		// Scroll right until the selected column fits
+		span := 0
+		for i := r.colOffset; i <= r.selCol; i++ {
+			span += widths[i] + 2
+		}
+		for r.colOffset < r.selCol && used+span > avail {
+			span -= widths[r.colOffset] + 2
+			r.colOffset++
+		}
+	}
+
+	// At least one scrolled column shows, however narrow the pane
+	for i := r.colOffset; i < len(widths); i++ {
+		if i > r.colOffset && used+widths[i]+2 > avail {
+			break
+		}
+		shown = append(shown, i)
+		used += widths[i] + 2
+	}
+	return shown
+}
+
+// columnsNote describes the shown columns when some are off screen, for
+// the pane title
+func (r Results) columnsNote() string {
+	if len(r.shown) == len(r.columns) && r.pinned == 0 {
+		return ""
+	}
+	var parts []string
+	if pinned := r.Pinned(); pinned > 0 {
+		parts = append(parts, fmt.Sprintf("%d pinned", pinned))
+	}
+	if len(r.shown) < len(r.columns) {
+		first, last := r.shown[0]+1, r.shown[len(r.shown)-1]+1
+		if r.Pinned() > 0 && r.Pinned() < len(r.shown) {
+			first = r.shown[r.Pinned()] + 1
+		}
+		if first == last {
+			parts = append(parts, fmt.Sprintf("column %d of %d", first, len(r.columns)))
+		} else {
+			parts = append(parts, fmt.Sprintf("columns %d-%d of %d", first, last, len(r.columns)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,67 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// recordNameMax is the widest column name shown in the record view
+const recordNameMax = 30
+
+// renderRecord renders the selected row as column/value pairs, one per
+// line, for rows too wide for the table. ↑/↓ move between rows as in the
+// table and ←/→ between fields.
+func (r Results) renderRecord() string {
+	row := r.SelectedRowIndex()
+	if row < 0 {
+		return r.styles.Info.Render("No row selected")
+	}
+
+	nameWidth := 0
+	for _, col := range r.columns {
+		nameWidth = max(nameWidth, lipgloss.Width(col))
+	}
+	nameWidth = min(nameWidth, recordNameMax)
+	valueWidth := max(r.width-4-nameWidth-4, 10)
+
+	var b strings.Builder
+	b.WriteString(r.styles.Header.Render(fmt.Sprintf("Row %d of %d", row+1, len(r.rows))))
+	b.WriteString(r.styles.Info.Render("  ↑↓: row • ←→: field"))
+
+	// Keep the selected field in view, centered once the list scrolls
+	fields := max(r.tableHeight()-1, 1)
+	first := min(max(r.selCol-fields/2, 0), max(len(r.columns)-fields, 0))
+	for i := first; i < min(first+fields, len(r.columns)); i++ {
+		v := r.rows[row][r.columns[i]]
+		text := "NULL"
+		if !v.Null {
+			text = CellText(v, r.colTypes[i])
+		}
+		// Multi-line values show their first line; Enter shows them whole
+		line, _, multiline := strings.Cut(text, "\n")
+		if multiline {
+			line += " ↵"
+		}
+		if runes := []rune(line); len(runes) > valueWidth {
+			line = string(runes[:valueWidth-3]) + "..."
+		}
+
+		name := r.columns[i]
+		if runes := []rune(name); len(runes) > nameWidth {
+			name = string(runes[:nameWidth-1]) + "…"
+		}
+		marker := " "
+		if i == r.selCol {
+			marker = "▸"
+		}
+		b.WriteString("\n")
+		if i == r.selCol {
+			b.WriteString(r.styles.SelectedRow.UnsetPadding().Render(marker + " " + fitCell(name, nameWidth, lipgloss.Left) + " " + line))
+			continue
+		}
+		b.WriteString(marker + " " + r.styles.Header.UnsetPadding().Render(fitCell(name, nameWidth, lipgloss.Left)) + " " + r.valueStyle(v, r.colTypes[i]).Render(line))
+	}
+	return b.String()
+}
@@ -1,14 +1,22 @@
 package components
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/guptarohit/asciigraph"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/export"
 )
 
 // ViewMode determines how results are displayed
@@ -19,23 +27,107 @@ const (
 	ViewChartBar
 	ViewChartLine
 	ViewChartPie
+	ViewRecord // the selected row as column/value pairs
 )
 
+// viewModes is how many view modes there are
+const viewModes = 5
+
+// ResultSet is one set of rows shown as a tab in the results pane
+type ResultSet struct {
+	Columns     []string
+	ColumnTypes []db.ColumnType // parallel to Columns; inferred when empty
+	Rows        []db.Row
+	Truncated   bool   // fetching stopped at the result limit
+	Title       string // tab label; numbered when empty
+	Query       string // the query that returned the rows, when known
+	// RowOffset is the position of the first row in the query's result
+	// when it is fetched a page at a time; MoreRows tells there are more
+	RowOffset int
+	MoreRows  bool
+	// Elapsed is how long the query took, zero when unknown
+	Elapsed time.Duration
+}
+
 // Results component for displaying query results
 type Results struct {
 	table     table.Model
 	columns   []string
-	rows      []map[string]interface{}
+	colTypes  []db.ColumnType
+	rows      []db.Row
 	width     int
 	height    int
 	focused   bool
+	state     PaneState
 	styles    ResultsStyles
 	rowCount  int
+	truncated bool
 	message   string
 	isError   bool
 	page      int
 	pageSize  int
 	viewMode  ViewMode
+	selCol    int    // selected column, marked in the header
+	// autoWidths are the measured column widths, widthDelta the manual
+	// resizes on top of them
+	autoWidths []int
+	widthDelta []int
+	// pinned leading columns stay on the left while the others scroll
+	// from colOffset; shown are the columns in the table
+	pinned    int
+	colOffset int
+	shown     []int
+	offset    int // first row of the page in view, see rowOffset
+	// rowNumbers shows each row's position in the result set
+	rowNumbers bool
+	fetching   bool // rows still arrive, see StartFetch
+	rowBase    int  // RowOffset of the shown result set
+	moreRows   bool // MoreRows of the shown result set
+	// elapsed is how long the query of the shown result set took
+	elapsed    time.Duration
+	aggregates bool // footer with aggregates of the selected column
+	// chartGroup is the column charted rows are grouped by, -1 for none,
+	// and chartAgg how their values are aggregated
+	chartGroup int
+	chartAgg   ChartAgg
+	query     string // query of the shown result set, "" when unknown
+
+	// base holds the rows of the result set in query order; rows is base
+	// narrowed by the filter bar and sorted (ToggleSort)
+	base     []db.Row
+	sortCol  int
+	sortDir  SortDirection
+	filter   string
+	filterOn bool // the filter bar has focus
+	filterIn textinput.Model
+
+	// Cells holding the value being found (FindOccurrences)
+	finding    bool
+	findText   string
+	findNull   bool
+	matches    []cellPos
+	matchIndex int
+
+	// Result sets from a multi-result query, shown as tabs
+	sets      []ResultSet
+	activeSet int
+
+	// Past runs kept as a tab row above the result set tabs, oldest first;
+	// activeRun is -1 while the pane shows something else
+	runs      []resultRun
+	activeRun int
+	maxRuns   int
+
+	// Log output (maintenance actions etc.)
+	log      []string
+	logTitle string
+	showLog  bool
+
+	// Running query indicator
+	running      bool
+	runningLabel string
+	runningSince time.Time
+	spinner      spinner.Model
 }
 
 // ResultsStyles holds styling for the results
@@ -46,8 +138,12 @@ type ResultsStyles struct {
 	Header      lipgloss.Style
 	Cell        lipgloss.Style
 	SelectedRow lipgloss.Style
+	Null        lipgloss.Style
+	Number      lipgloss.Style
+	Bool        lipgloss.Style
 	Error       lipgloss.Style
 	Info        lipgloss.Style
+	Borders     PaneBorders
 }
 
 // NewResults creates a new results component
@@ -68,12 +164,18 @@ func NewResults(styles ResultsStyles) Results {
 	s.Cell = styles.Cell
 	t.SetStyles(s)
 
+	sp := spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(styles.Info))
+
 	return Results{
 		table:    t,
 		focused:  false,
 		styles:   styles,
-		page:     0,
-		pageSize: 100,
+		page:      0,
+		pageSize:  100,
+		chartGroup: -1,
+		spinner:   sp,
+		activeRun: -1,
+		sortCol:   -1,
 	}
 }
 
@@ -82,7 +184,25 @@ func (r *Results) SetSize(width, height int) {
 	r.width = width
 	r.height = height
 	r.table.SetWidth(width - 4)
-	r.table.SetHeight(height - 4)
+	r.table.SetHeight(r.tableHeight())
+}
+
+// tableHeight returns the table height, leaving room for the tab bars
+func (r Results) tableHeight() int {
+	height := r.height - 4
+	if len(r.sets) > 1 {
+		height--
+	}
+	if r.showRunTabs() {
+		height--
+	}
+	if r.filterBarShown() {
+		height--
+	}
+	if r.aggregates {
+		height--
+	}
+	return height
 }
 
 // SetFocused sets the focus state
@@ -91,46 +211,157 @@ func (r *Results) SetFocused(focused bool) {
 	r.table.Focus()
 }
 
+// SetState sets the state the border shows
+func (r *Results) SetState(state PaneState) {
+	r.state = state
+}
+
+// HasError reports whether the pane shows an error
+func (r Results) HasError() bool {
+	return r.isError
+}
+
 // IsFocused returns if results is focused
 func (r Results) IsFocused() bool {
 	return r.focused
 }
 
 // SetData sets the query results data
-func (r *Results) SetData(columns []string, rows []map[string]interface{}) {
-	r.columns = columns
-	r.rows = rows
-	r.rowCount = len(rows)
+func (r *Results) SetData(columns []string, rows []db.Row) {
+	r.sets = nil
+	r.activeSet = 0
+	r.activeRun = -1
+	r.loadData(ResultSet{Columns: columns, Rows: rows})
+}
+
+// SetResultSets sets several result sets, showing the first as active tab
+func (r *Results) SetResultSets(sets []ResultSet) {
+	if len(sets) == 0 {
+		r.SetData(nil, nil)
+		return
+	}
+	// A single set is shown without tabs
+	r.sets = nil
+	if len(sets) > 1 {
+		r.sets = sets
+	}
+	r.activeSet = 0
+	r.activeRun = -1
+	r.loadData(sets[0])
+}
+
+// ResultSetCount returns the number of result set tabs (0 for a single result)
+func (r Results) ResultSetCount() int {
+	return len(r.sets)
+}
+
+// NextResultSet switches to the next result set tab
+func (r *Results) NextResultSet() {
+	if len(r.sets) > 1 {
+		r.activeSet = (r.activeSet + 1) % len(r.sets)
+		r.loadData(r.sets[r.activeSet])
+		r.rememberActiveSet()
+	}
+}
+
+// PrevResultSet switches to the previous result set tab
+func (r *Results) PrevResultSet() {
+	if len(r.sets) > 1 {
+		r.activeSet = (r.activeSet - 1 + len(r.sets)) % len(r.sets)
+		r.loadData(r.sets[r.activeSet])
+		r.rememberActiveSet()
+	}
+}
+
+// loadData shows a result set in the table
+func (r *Results) loadData(set ResultSet) {
+	r.columns = set.Columns
+	r.colTypes = set.ColumnTypes
+	if len(r.colTypes) != len(r.columns) {
+		r.colTypes = db.InferColumnTypes(set.Columns, set.Rows)
+	}
+	r.rows = set.Rows
+	r.base = set.Rows
+	r.rowCount = len(set.Rows)
+	r.truncated = set.Truncated
+	r.query = set.Query
+	r.rowBase = set.RowOffset
+	r.moreRows = set.MoreRows
+	r.elapsed = set.Elapsed
 	r.message = ""
 	r.isError = false
 	r.page = 0
+	r.showLog = false
+	r.fetching = false
+	r.chartGroup = -1
+	r.selCol = min(r.selCol, max(len(r.columns)-1, 0))
+	r.finding = false
+	r.matches = nil
+	r.resetSort()
+	r.resetFilter()
+	r.measureColumns()
+	r.colOffset = 0
 
 	// Convert to table format
+	r.table.SetHeight(r.tableHeight())
 	r.updateTable()
 }
 
 // SetError sets an error message
 func (r *Results) SetError(err error) {
+	r.sets = nil
+	r.activeRun = -1
 	r.message = err.Error()
 	r.isError = true
 	r.columns = nil
+	r.query = ""
+	r.colTypes = nil
 	r.rows = nil
+	r.base = nil
 	r.rowCount = 0
+	r.resetSort()
+	r.resetFilter()
+	r.showLog = false
+	r.fetching = false
 }
 
 // SetMessage sets an info message
 func (r *Results) SetMessage(msg string) {
+	r.activeRun = -1
 	r.message = msg
 	r.isError = false
+	r.showLog = false
+	r.fetching = false
+}
+
+// StartLog switches the pane to log output with a fresh log
+func (r *Results) StartLog(title string) {
+	r.log = nil
+	r.logTitle = title
+	r.showLog = true
+}
+
+// AppendLog appends a line to the log output
+func (r *Results) AppendLog(line string) {
+	r.log = append(r.log, line)
 }
 
 // Clear clears the results
 func (r *Results) Clear() {
+	r.sets = nil
+	r.activeRun = -1
 	r.columns = nil
+	r.query = ""
+	r.colTypes = nil
 	r.rows = nil
+	r.base = nil
 	r.rowCount = 0
+	r.resetSort()
+	r.resetFilter()
 	r.message = ""
 	r.isError = false
+	r.showLog = false
+	r.fetching = false
 }
 
 // updateTable updates the internal table with current data
@@ -139,74 +370,146 @@ func (r *Results) updateTable() {
 		return
 	}
 
-	// Calculate column widths
-	colWidth := (r.width - 4) / len(r.columns)
-	if colWidth < 10 {
-		colWidth = 10
-	}
-	if colWidth > 30 {
-		colWidth = 30
+	// Create table rows (paginated)
+	start := r.page * r.pageSize
+	end := start + r.pageSize
+	if end > len(r.rows) {
+		end = len(r.rows)
 	}
 
-	// Create table columns
-	cols := make([]table.Column, len(r.columns))
+	// Numeric columns widen to fit their values so IDs and amounts are
+	// never cut short
+	widths := make([]int, len(r.columns))
 	for i, col := range r.columns {
-		cols[i] = table.Column{
-			Title: strings.ToUpper(col),
-			Width: colWidth,
+		widths[i] = r.columnWidth(i)
+		if r.colTypes[i].IsNumeric() && !r.resized(i) {
+			for _, row := range r.rows[start:end] {
+				if w := len(CellText(row[col], r.colTypes[i])) + 2; w > widths[i] {
+					widths[i] = w
+				}
+			}
 		}
 	}
 
-	// Create table rows (paginated)
-	start := r.page * r.pageSize
-	end := start + r.pageSize
-	if end > len(r.rows) {
-		end = len(r.rows)
+	// Create table columns for those that fit: the pinned ones, then the
+	// scrolled ones from colOffset
+	r.shown = r.layoutColumns(widths)
+	cols := make([]table.Column, len(r.shown))
+	for k, i := range r.shown {
+		col := r.columns[i]
+		title := strings.ToUpper(col)
+		if i == r.selCol {
+			title = "▸" + title
+		}
+		if i == r.sortCol {
+			switch r.sortDir {
+			case SortAsc:
+				title += " ▲"
+			case SortDesc:
+				title += " ▼"
+			}
+		}
+		if i == r.Pinned()-1 {
+			title += " │"
+		}
+		cols[k] = table.Column{
+			Title: title,
+			Width: widths[i],
+		}
 	}
 
 	tableRows := make([]table.Row, 0)
 	for i := start; i < end; i++ {
 		row := r.rows[i]
-		tableRow := make(table.Row, len(r.columns))
-		for j, col := range r.columns {
-			val := row[col]
-			tableRow[j] = formatValue(val, colWidth-2)
+		tableRow := make(table.Row, len(r.shown))
+		for k, j := range r.shown {
+			col := r.columns[j]
+			if r.finding && r.cellMatches(row[col], r.colTypes[j], r.findText, r.findNull) {
+				tableRow[k] = matchMarker + formatValue(row[col], r.colTypes[j], widths[j]-4)
+				continue
+			}
+			tableRow[k] = formatValue(row[col], r.colTypes[j], widths[j]-2)
 		}
 		tableRows = append(tableRows, tableRow)
 	}
 
+	// Drop the old rows first: the table renders them against the new
+	// columns, which fails when switching to a result set with fewer
+	r.table.SetRows(nil)
 	r.table.SetColumns(cols)
 	r.table.SetRows(tableRows)
 }
 
-// formatValue formats a value for display
-func formatValue(val interface{}, maxWidth int) string {
-	var str string
-	switch v := val.(type) {
-	case nil:
-		str = "NULL"
-	case []byte:
-		str = string(v)
-	default:
-		str = fmt.Sprintf("%v", v)
+// formatValue formats a value for display, right-aligning numbers
+func formatValue(val db.Value, colType db.ColumnType, maxWidth int) string {
+	str := "NULL"
+	if !val.Null {
+		str = CellText(val, colType)
 	}
 
-	// Truncate if too long
-	if len(str) > maxWidth {
-		str = str[:maxWidth-3] + "..."
+	// Truncate if too long, by runes so characters stay whole; narrow
+	// columns have no room for the ellipsis
+	if runes := []rune(str); len(runes) > maxWidth {
+		if maxWidth > 3 {
+			str = string(runes[:maxWidth-3]) + "..."
+		} else {
+			str = string(runes[:max(maxWidth, 0)])
+		}
 	}
 
+	if colType.IsNumeric() {
+		str = fmt.Sprintf("%*s", maxWidth, str)
+	}
 	return str
 }
 
+// CellText formats a value by its column type, for display, copying and
+// export.
+// NULL is empty, as in TSV; the grid shows it as NULL.
+func CellText(val db.Value, colType db.ColumnType) string {
+	return val.Text(colType)
+}
+
+// StartRunning shows the spinner with label ("Running query") and the
+// elapsed time until StopRunning
+func (r *Results) StartRunning(label string) tea.Cmd {
+	r.running = true
+	r.runningLabel = label
+	r.runningSince = time.Now()
+	r.showLog = false
+	return r.spinner.Tick
+}
+
+// StopRunning hides the running query indicator
+func (r *Results) StopRunning() {
+	r.running = false
+}
+
+// IsRunning returns if a query is running
+func (r Results) IsRunning() bool {
+	return r.running
+}
+
 // Update handles input for the results
 func (r Results) Update(msg tea.Msg) (Results, tea.Cmd) {
+	// Spinner ticks arrive whether or not the pane is focused; letting
+	// them lapse once stopped ends the tick loop
+	if tick, ok := msg.(spinner.TickMsg); ok {
+		if !r.running {
+			return r, nil
+		}
+		var cmd tea.Cmd
+		r.spinner, cmd = r.spinner.Update(tick)
+		return r, cmd
+	}
+
 	if !r.focused {
 		return r, nil
 	}
 
 	var cmd tea.Cmd
 	r.table, cmd = r.table.Update(msg)
+	r.offset = r.rowOffset()
 	return r, cmd
 }
 
@@ -216,12 +519,13 @@ func (r Results) View() string {
 	if r.focused {
 		style = r.styles.Focused
 	}
+	style = r.styles.Borders.style(style, r.state)
 
 	var content strings.Builder
 
 	// Title with view mode
 	title := "RESULTS"
-	if r.rowCount > 0 {
+	if r.rowCount > 0 || r.filter != "" {
 		modeStr := ""
 		switch r.viewMode {
 		case ViewTable:
@@ -232,19 +536,65 @@ func (r Results) View() string {
 			modeStr = "Line Chart"
 		case ViewChartPie:
 			modeStr = "Pie Chart"
+		case ViewRecord:
+			modeStr = "Record"
+		}
+		rows := fmt.Sprintf("%d rows", r.rowCount)
+		if r.serverPaged() {
+			rows = fmt.Sprintf("rows %d-%d", r.rowBase+1, r.rowBase+len(r.base))
+			if r.moreRows {
+				rows += ", more on the server"
+			}
+		}
+		if r.filter != "" {
+			rows = fmt.Sprintf("%d of %d rows", r.rowCount, len(r.base))
+		}
+		if r.truncated {
+			rows += ", truncated"
+		}
+		if r.fetching {
+			rows += ", fetching"
+		} else {
+			rows += timingNote(r.elapsed)
+		}
+		title = fmt.Sprintf("RESULTS (%s) - %s", rows, modeStr)
+		if note := r.columnsNote(); note != "" && r.viewMode == ViewTable {
+			title += " - " + note
 		}
-		title = fmt.Sprintf("RESULTS (%d rows) - %s", r.rowCount, modeStr)
 	}
 	content.WriteString(r.styles.Title.Render(title))
 	content.WriteString("\n")
-
-	// Show message or content
-	if r.message != "" {
+	if r.showRunTabs() && !r.showLog {
+		content.WriteString(r.renderRunTabs())
+		content.WriteString("\n")
+	}
+	if len(r.sets) > 1 && !r.showLog {
+		content.WriteString(r.renderTabs())
+		content.WriteString("\n")
+	}
+	if r.filterBarShown() && !r.showLog && !r.running && r.message == "" {
+		content.WriteString(r.renderFilterBar())
+		content.WriteString("\n")
+	}
+
+	// Show running indicator, log, message or content
+	if r.running {
+		elapsed := time.Since(r.runningSince).Truncate(100 * time.Millisecond)
+		content.WriteString(r.spinner.View())
+		content.WriteString(r.styles.Info.Render(fmt.Sprintf(" %s... %s (Esc to cancel)", r.runningLabel, elapsed)))
+	} else if r.showLog {
+		content.Reset()
+		content.WriteString(r.styles.Title.Render("LOG - " + r.logTitle))
+		content.WriteString("\n")
+		content.WriteString(r.renderLog())
+	} else if r.message != "" {
 		if r.isError {
 			content.WriteString(r.styles.Error.Render("Error: " + r.message))
 		} else {
 			content.WriteString(r.styles.Info.Render(r.message))
 		}
+	} else if len(r.rows) == 0 && r.filter != "" {
+		content.WriteString(r.styles.Info.Render("No fetched rows match the filter (Esc clears it)"))
 	} else if len(r.rows) > 0 {
 		switch r.viewMode {
 		case ViewChartBar:
@@ -253,15 +603,27 @@ func (r Results) View() string {
 			content.WriteString(r.renderLineChart())
 		case ViewChartPie:
 			content.WriteString(r.renderPieChart())
+		case ViewRecord:
+			content.WriteString(r.renderRecord())
 		default:
-			content.WriteString(r.table.View())
+			content.WriteString(r.renderTable())
+			if r.aggregates {
+				content.WriteString("\n" + r.renderAggregates())
+			}
 			
 			// Pagination info
-			if r.rowCount > r.pageSize {
-				totalPages := (r.rowCount + r.pageSize - 1) / r.pageSize
+			if r.rowCount > r.pageSize || r.serverPaged() {
+				totalPages := max((r.rowCount+r.pageSize-1)/r.pageSize, 1)
 				pageInfo := fmt.Sprintf("\nPage %d/%d", r.page+1, totalPages)
+				if r.moreRows {
+					pageInfo += " — PgDn on the last page fetches more rows"
+				}
 				content.WriteString(r.styles.Info.Render(pageInfo))
 			}
+			if r.truncated {
+				note := fmt.Sprintf("\nTruncated at %d rows — export to file for the full set (Ctrl+O)", r.rowCount)
+				content.WriteString(r.styles.Error.Render(note))
+			}
 		}
 	} else {
 		content.WriteString(r.styles.Info.Render("No results. Run a query to see data here."))
@@ -273,11 +635,46 @@ func (r Results) View() string {
 		Render(content.String())
 }
 
+// renderTabs renders the result set tab bar
+func (r Results) renderTabs() string {
+	tabs := make([]string, len(r.sets))
+	for i, set := range r.sets {
+		label := fmt.Sprintf(" %d: %d rows ", i+1, len(set.Rows))
+		if set.Title != "" {
+			label = fmt.Sprintf(" %s (%d) ", set.Title, len(set.Rows))
+		}
+		if i == r.activeSet {
+			tabs[i] = r.styles.Title.Render("[" + label + "]")
+		} else {
+			tabs[i] = r.styles.Info.Render(" " + label + " ")
+		}
+	}
+	return strings.Join(tabs, "") + r.styles.Info.Render("  [/]: switch")
+}
+
+// renderLog renders the tail of the log that fits in the pane
+func (r Results) renderLog() string {
+	maxLines := r.height - 4
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	start := 0
+	if len(r.log) > maxLines {
+		start = len(r.log) - maxLines
+	}
+	return r.styles.Info.Render(strings.Join(r.log[start:], "\n"))
+}
+
 // SetViewMode sets the current view mode
 func (r *Results) SetViewMode(mode ViewMode) {
 	r.viewMode = mode
 }
 
+// NextViewMode switches to the view mode after the current one
+func (r *Results) NextViewMode() {
+	r.viewMode = (r.viewMode + 1) % viewModes
+}
+
 // GetViewMode returns the current view mode
 func (r Results) GetViewMode() ViewMode {
 	return r.viewMode
@@ -285,7 +682,7 @@ func (r Results) GetViewMode() ViewMode {
 
 // renderLineChart renders a line chart using asciigraph
 func (r Results) renderLineChart() string {
-	data, label := r.extractNumericData()
+	data, _, label := r.extractNumericData()
 	if len(data) == 0 {
 		return "No numeric data found for chart"
 	}
@@ -300,7 +697,7 @@ func (r Results) renderLineChart() string {
 
 // renderBarChart renders a simple bar chart
 func (r Results) renderBarChart() string {
-	data, label := r.extractNumericData()
+	data, labels, label := r.extractNumericData()
 	if len(data) == 0 {
 		return "No numeric data found for chart"
 	}
@@ -315,7 +712,13 @@ func (r Results) renderBarChart() string {
 		}
 	}
 	
-	maxBarWidth := r.width - 20
+	// Grouped bars are labelled with their group, others numbered
+	labelWidth := 0
+	for _, l := range labels {
+		labelWidth = min(max(labelWidth, lipgloss.Width(l)), chartLabelMax)
+	}
+
+	maxBarWidth := r.width - 20 - labelWidth
 	if maxBarWidth < 10 {
 		maxBarWidth = 10
 	}
@@ -330,7 +733,11 @@ func (r Results) renderBarChart() string {
 		val := data[i]
 		barLen := int((val / maxVal) * float64(maxBarWidth))
 		bar := strings.Repeat("█", barLen)
-		b.WriteString(fmt.Sprintf("%3d │ %s %.2f\n", i+1, bar, val))
+		name := fmt.Sprintf("%3d", i+1)
+		if labels != nil {
+			name = fitCell(labels[i], labelWidth, lipgloss.Left)
+		}
+		b.WriteString(fmt.Sprintf("%s │ %s %.2f\n", name, bar, val))
 	}
 	
 	return b.String()
@@ -338,7 +745,7 @@ func (r Results) renderBarChart() string {
 
 // renderPieChart renders a simple pie chart (hamburger style)
 func (r Results) renderPieChart() string {
-	data, label := r.extractNumericData()
+	data, labels, label := r.extractNumericData()
 	if len(data) == 0 {
 		return "No numeric data found for chart"
 	}
@@ -363,57 +770,61 @@ func (r Results) renderPieChart() string {
 		val := data[i]
 		percent := (val / total) * 100
 		char := chars[i%len(chars)]
+		if labels != nil {
+			b.WriteString(fmt.Sprintf("%s %.1f%% (%.2f) %s\n", char, percent, val, labels[i]))
+			continue
+		}
 		b.WriteString(fmt.Sprintf("%s %.1f%% (%.2f)\n", char, percent, val))
 	}
 	
 	return b.String()
 }
 
-// extractNumericData finds the first numeric column and returns data
-func (r Results) extractNumericData() ([]float64, string) {
+// extractNumericData returns the values to chart with their labels and
+// a caption: those of the first numeric column, or, when rows are grouped
+// (ToggleChartGroup), an aggregate of it per value of the group column
+func (r Results) extractNumericData() ([]float64, []string, string) {
 	if len(r.rows) == 0 || len(r.columns) == 0 {
-		return nil, ""
+		return nil, nil, ""
 	}
 
 	// Find first numeric column
 	targetCol := ""
-	for _, col := range r.columns {
-		// Check first row value
-		val := r.rows[0][col]
-		switch val.(type) {
-		case int, int64, float64, float32:
+	for i, col := range r.columns {
+		if r.colTypes[i].IsNumeric() && i != r.chartGroup {
 			targetCol = col
 			break
 		}
-		if targetCol != "" {
-			break
-		}
 	}
-
+	if r.chartGroup >= 0 && r.chartGroup < len(r.columns) {
+		return r.groupedData(targetCol)
+	}
 	if targetCol == "" {
-		return nil, ""
+		return nil, nil, ""
 	}
 
 	data := make([]float64, 0, len(r.rows))
 	for _, row := range r.rows {
-		val := row[targetCol]
-		var floatVal float64
-		switch v := val.(type) {
-		case int:
-			floatVal = float64(v)
-		case int64:
-			floatVal = float64(v)
-		case float32:
-			floatVal = float64(v)
-		case float64:
-			floatVal = v
-		default:
-			continue
+		if f, ok := chartValue(row[targetCol]); ok {
+			data = append(data, f)
 		}
-		data = append(data, floatVal)
 	}
+	return data, nil, targetCol
+}
 
-	return data, targetCol
+// chartValue returns a value as a number to chart
+func chartValue(v db.Value) (float64, bool) {
+	if v.Null {
+		return 0, false
+	}
+	if f, ok := numericValue(v); ok {
+		return f, true
+	}
+	if s, ok := v.Data.(string); ok {
+		// Numeric text that isn't a plain decimal, e.g. from untyped results
+		return parseNumber(s)
+	}
+	return 0, false
 }
 
 // NextPage moves to the next page
@@ -433,6 +844,88 @@ func (r *Results) PrevPage() {
 	}
 }
 
+// OnFirstPage reports whether the first page is shown
+func (r Results) OnFirstPage() bool {
+	return r.page == 0
+}
+
+// OnLastPage reports whether the last page is shown
+func (r Results) OnLastPage() bool {
+	return r.page >= (r.rowCount-1)/r.pageSize
+}
+
+// LastPage shows the last page
+func (r *Results) LastPage() {
+	r.page = max((r.rowCount-1)/r.pageSize, 0)
+	r.updateTable()
+}
+
+// serverPaged reports whether the shown rows are a page of a query run a
+// page at a time
+func (r Results) serverPaged() bool {
+	return r.rowBase > 0 || r.moreRows
+}
+
+// ColumnTypes returns the types of the shown columns
+func (r Results) ColumnTypes() []db.ColumnType {
+	return r.colTypes
+}
+
+// ActiveResult returns the shown result set
+func (r Results) ActiveResult() ResultSet {
+	return ResultSet{Columns: r.columns, ColumnTypes: r.colTypes, Rows: r.rows, Truncated: r.truncated, Query: r.query, RowOffset: r.rowBase, MoreRows: r.moreRows, Elapsed: r.elapsed}
+}
+
+// SelectedRowIndex returns the index of the selected row in the shown
+// result set, or -1 when there is none
+func (r Results) SelectedRowIndex() int {
+	rowIdx := r.page*r.pageSize + r.table.Cursor()
+	if rowIdx < 0 || rowIdx >= len(r.rows) {
+		return -1
+	}
+	return rowIdx
+}
+
+// UpdateCell replaces a value of a fetched row, e.g. after the row was
+// updated in the database. The row may belong to any tab.
+func (r *Results) UpdateCell(row db.Row, column string, v db.Value) {
+	row[column] = v
+	r.updateTable()
+}
+
+// RemoveRow drops a fetched row, e.g. after it was deleted in the
+// database, from the shown result set and from the tab it belongs to
+func (r *Results) RemoveRow(row db.Row) {
+	r.rows = removeRow(r.rows, row)
+	r.rowCount = len(r.rows)
+	r.base = removeRow(r.base, row)
+	for i := range r.sets {
+		r.sets[i].Rows = removeRow(r.sets[i].Rows, row)
+	}
+	for i := range r.runs {
+		for j := range r.runs[i].sets {
+			r.runs[i].sets[j].Rows = removeRow(r.runs[i].sets[j].Rows, row)
+		}
+	}
+	r.matches = nil
+	r.finding = false
+	if r.page > 0 && r.page*r.pageSize >= len(r.rows) {
+		r.page--
+	}
+	r.updateTable()
+}
+
+// removeRow returns rows without row, compared by identity
+func removeRow(rows []db.Row, row db.Row) []db.Row {
+	target := reflect.ValueOf(row).Pointer()
+	for i, candidate := range rows {
+		if reflect.ValueOf(candidate).Pointer() == target {
+			return append(rows[:i:i], rows[i+1:]...)
+		}
+	}
+	return rows
+}
+
 // GetRowCount returns the number of rows
 func (r Results) GetRowCount() int {
 	return r.rowCount
@@ -450,15 +943,24 @@ func (r Results) CopySelectedRow() error {
 	
 	row := r.rows[rowIdx]
 	var values []string
-	for _, col := range r.columns {
-		val := row[col]
-		values = append(values, fmt.Sprintf("%v", val))
+	for i, col := range r.columns {
+		values = append(values, CellText(row[col], r.colTypes[i]))
 	}
 	
 	text := strings.Join(values, "\t")
 	return clipboard.WriteAll(text)
 }
 
+// CopySelectedCell copies the raw value of the selected cell to clipboard,
+// line breaks and tabs included. NULL copies as empty text; null reports it.
+func (r Results) CopySelectedCell() (text string, null bool, err error) {
+	text, null, ok := r.SelectedCell()
+	if !ok {
+		return "", false, fmt.Errorf("no cell selected")
+	}
+	return text, null, clipboard.WriteAll(text)
+}
+
 // CopyAllData copies all data to clipboard as TSV
 func (r Results) CopyAllData() error {
 	if len(r.rows) == 0 {
@@ -473,9 +975,8 @@ func (r Results) CopyAllData() error {
 	// Rows
 	for _, row := range r.rows {
 		var values []string
-		for _, col := range r.columns {
-			val := row[col]
-			values = append(values, fmt.Sprintf("%v", val))
+		for i, col := range r.columns {
+			values = append(values, CellText(row[col], r.colTypes[i]))
 		}
 		lines = append(lines, strings.Join(values, "\t"))
 	}
@@ -483,3 +984,29 @@ func (r Results) CopyAllData() error {
 	text := strings.Join(lines, "\n")
 	return clipboard.WriteAll(text)
 }
+
+// CopyAllJSON copies all data to clipboard as a JSON array with one object
+// per row; NULL stays null and numbers stay numbers
+func (r Results) CopyAllJSON() error {
+	return r.copyAllAs(export.JSON)
+}
+
+// CopyAllMarkdown copies all data to clipboard as a GitHub-flavored
+// Markdown table, for pasting into PRs and issues
+func (r Results) CopyAllMarkdown() error {
+	return r.copyAllAs(export.Markdown)
+}
+
+// copyAllAs copies all data to clipboard in an export format
+func (r Results) copyAllAs(format export.Format) error {
+	if len(r.rows) == 0 {
+		return fmt.Errorf("no data to copy")
+	}
+
+	var buf bytes.Buffer
+	set := db.ResultSet{Columns: r.columns, ColumnTypes: r.colTypes, Rows: r.rows}
+	if _, err := export.WriteResultSet(&buf, set, export.Options{Format: format}); err != nil {
+		return err
+	}
+	return clipboard.WriteAll(buf.String())
+}
@@ -0,0 +1,37 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// Switching to a result set with fewer or more columns than the one shown
+// must not render the old rows against the new columns
+func TestSwitchResultSetColumns(t *testing.T) {
+	wide := ResultSet{
+		Columns: []string{"id", "name", "email"},
+		Rows: []db.Row{
+			{"id": db.Value{Data: int64(1)}, "name": db.Value{Data: "Ada"}, "email": db.Value{Data: "ada@example.com"}},
+			{"id": db.Value{Data: int64(2)}, "name": db.Value{Data: "Linus"}, "email": db.Value{Data: "linus@example.com"}},
+		},
+	}
+	narrow := ResultSet{
+		Columns: []string{"count"},
+		Rows:    []db.Row{{"count": db.Value{Data: int64(2)}}},
+	}
+
+	r := NewResults(ResultsStyles{})
+	r.SetSize(80, 20)
+	r.SetResultSets([]ResultSet{wide, narrow})
+	r.NextResultSet()
+	if got := r.ActiveResult().Columns; len(got) != 1 {
+		t.Fatalf("after NextResultSet columns = %v, want [count]", got)
+	}
+	r.View()
+	r.PrevResultSet()
+	if got := r.ActiveResult().Columns; len(got) != 3 {
+		t.Fatalf("after PrevResultSet columns = %v, want 3 columns", got)
+	}
+	r.View()
+}
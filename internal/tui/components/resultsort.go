@@ -0,0 +1,136 @@
+package components
+
+import (
+	"bytes"
+	"cmp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// SortDirection is the order of the sorted column
+type SortDirection int
+
+const (
+	SortNone SortDirection = iota
+	SortAsc
+	SortDesc
+)
+
+// ToggleSort sorts the fetched rows by the selected column, cycling
+// ascending, descending and the order of the query. NULLs sort last
+// either way. Returns the new direction.
+func (r *Results) ToggleSort() SortDirection {
+	if len(r.columns) == 0 || len(r.base) == 0 {
+		return SortNone
+	}
+
+	dir := SortAsc
+	if r.sortCol == r.selCol {
+		dir = (r.sortDir + 1) % 3
+	}
+	r.sortDir = dir
+	r.sortCol = r.selCol
+	if dir == SortNone {
+		r.sortCol = -1
+	}
+	r.applyView()
+	return dir
+}
+
+// sortRows orders a copy of rows by the sorted column
+func (r Results) sortRows(rows []db.Row) []db.Row {
+	col, colType, dir := r.columns[r.sortCol], r.colTypes[r.sortCol], r.sortDir
+	rows = append([]db.Row(nil), rows...)
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i][col], rows[j][col]
+		if a.Null || b.Null {
+			return !a.Null && b.Null
+		}
+		if dir == SortDesc {
+			return compareValues(b, a, colType) < 0
+		}
+		return compareValues(a, b, colType) < 0
+	})
+	return rows
+}
+
+// SortColumn returns the sorted column and its direction; SortNone when
+// the rows are in query order
+func (r Results) SortColumn() (string, SortDirection) {
+	if r.sortCol < 0 || r.sortCol >= len(r.columns) {
+		return "", SortNone
+	}
+	return r.columns[r.sortCol], r.sortDir
+}
+
+// resetSort forgets the sort of the previous result set
+func (r *Results) resetSort() {
+	r.sortCol = -1
+	r.sortDir = SortNone
+}
+
+// compareValues orders two non-NULL values of a column: numbers and times
+// by value, everything else by its text
+func compareValues(a, b db.Value, colType db.ColumnType) int {
+	switch x := a.Data.(type) {
+	case int64:
+		if y, ok := b.Data.(int64); ok {
+			return cmp.Compare(x, y)
+		}
+	case uint64:
+		if y, ok := b.Data.(uint64); ok {
+			return cmp.Compare(x, y)
+		}
+	case time.Time:
+		if y, ok := b.Data.(time.Time); ok {
+			return x.Compare(y)
+		}
+	case bool:
+		if y, ok := b.Data.(bool); ok {
+			return cmp.Compare(boolRank(x), boolRank(y))
+		}
+	case []byte:
+		if y, ok := b.Data.([]byte); ok && colType.Kind == db.ColumnBinary {
+			return bytes.Compare(x, y)
+		}
+	}
+
+	if x, ok := numericValue(a); ok {
+		if y, ok := numericValue(b); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	return strings.Compare(CellText(a, colType), CellText(b, colType))
+}
+
+// numericValue returns the value of a number of any Go type
+func numericValue(v db.Value) (float64, bool) {
+	switch d := v.Data.(type) {
+	case int64:
+		return float64(d), true
+	case int32:
+		return float64(d), true
+	case int:
+		return float64(d), true
+	case uint64:
+		return float64(d), true
+	case float64:
+		return d, true
+	case float32:
+		return float64(d), true
+	case db.Decimal:
+		return d.Float()
+	}
+	return 0, false
+}
+
+// boolRank orders false before true
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
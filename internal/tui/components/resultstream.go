@@ -0,0 +1,66 @@
+package components
+
+import (
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// StartFetch shows the first rows of a result set still being fetched;
+// AppendRows adds the rows that follow
+func (r *Results) StartFetch(set ResultSet) {
+	r.SetResultSets([]ResultSet{set})
+	r.viewMode = ViewTable
+	r.fetching = true
+}
+
+// IsFetching reports whether the shown result set is still being fetched.
+// Showing another result set ends it.
+func (r Results) IsFetching() bool {
+	return r.fetching
+}
+
+// AppendRows adds fetched rows to the result set being fetched, keeping
+// the page, selection, filter and sort
+func (r *Results) AppendRows(rows []db.Row) {
+	if !r.fetching || len(rows) == 0 {
+		return
+	}
+	r.base = append(r.base, rows...)
+
+	switch {
+	case r.filter == "" && r.sortDir == SortNone:
+		r.rows = r.base
+	default:
+		if r.filter != "" {
+			terms := parseFilter(r.filter, r.columns)
+			for _, row := range rows {
+				if r.rowMatches(row, terms) {
+					r.rows = append(r.rows, row)
+				}
+			}
+		} else {
+			r.rows = append(r.rows[:len(r.rows):len(r.rows)], rows...)
+		}
+		if r.sortDir != SortNone && r.sortCol >= 0 && r.sortCol < len(r.columns) {
+			// Matches point at row positions, which sorting moves
+			r.rows = r.sortRows(r.rows)
+			r.matches = nil
+			r.finding = false
+		}
+	}
+	r.rowCount = len(r.rows)
+	r.updateTable()
+}
+
+// EndFetch adds the last rows of the result set being fetched and keeps it
+// as the newest history tab, like AddRun
+func (r *Results) EndFetch(query string, at time.Time, elapsed time.Duration, rows []db.Row, truncated bool) {
+	r.AppendRows(rows)
+	r.fetching = false
+	r.truncated = truncated
+	r.query = query
+	r.elapsed = elapsed
+	set := ResultSet{Columns: r.columns, ColumnTypes: r.colTypes, Rows: r.base, Truncated: truncated, Query: query, Elapsed: elapsed}
+	r.keepRun(query, at, []ResultSet{set})
+}
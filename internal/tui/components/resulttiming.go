@@ -0,0 +1,28 @@
+package components
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatElapsed renders a query duration the way the status bar and the
+// results title show it: "80ms", "1.27s" or "2m5s"
+func FormatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// timingNote is the " in 1.27s" the results title and history tabs add
+// when the duration of the query is known
+func timingNote(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return " in " + FormatElapsed(d)
+}
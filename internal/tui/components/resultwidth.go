@@ -0,0 +1,64 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column width limits. Widths include a space on either side of the text.
+const (
+	autoWidthMax    = 40   // widest column sized from its values
+	autoWidthSample = 1000 // fetched rows measured per column
+	minColumnWidth  = 5
+	maxColumnWidth  = 200
+)
+
+// measureColumns sizes each column to fit its title and the widest of the
+// first fetched values, up to autoWidthMax, and drops manual resizes
+func (r *Results) measureColumns() {
+	r.autoWidths = make([]int, len(r.columns))
+	r.widthDelta = make([]int, len(r.columns))
+	sample := r.base[:min(len(r.base), autoWidthSample)]
+	for i, col := range r.columns {
+		// Room for the selection marker and the sort arrow
+		w := lipgloss.Width(col) + 3
+		for _, row := range sample {
+			v := row[col]
+			text := "NULL"
+			if !v.Null {
+				text = CellText(v, r.colTypes[i])
+			}
+			if line, _, _ := strings.Cut(text, "\n"); len(line) > w {
+				w = max(w, lipgloss.Width(line))
+			}
+		}
+		r.autoWidths[i] = min(w+2, autoWidthMax)
+	}
+}
+
+// columnWidth returns the width of column i: measured, then resized
+func (r Results) columnWidth(i int) int {
+	if i >= len(r.autoWidths) {
+		return minColumnWidth
+	}
+	return min(max(r.autoWidths[i]+r.widthDelta[i], minColumnWidth), maxColumnWidth)
+}
+
+// resized reports whether column i was resized by hand
+func (r Results) resized(i int) bool {
+	return i < len(r.widthDelta) && r.widthDelta[i] != 0
+}
+
+// ResizeColumn widens the selected column by delta, narrowing it when
+// negative, and returns its new width
+func (r *Results) ResizeColumn(delta int) int {
+	if r.selCol >= len(r.widthDelta) {
+		return 0
+	}
+	// Keep the adjustment within the limits so the opposite key undoes it
+	width := min(max(r.columnWidth(r.selCol)+delta, minColumnWidth), maxColumnWidth)
+	r.widthDelta[r.selCol] = width - r.autoWidths[r.selCol]
+	r.updateTable()
+	return width
+}
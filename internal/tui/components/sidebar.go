@@ -1,6 +1,8 @@
 package components
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -13,6 +15,7 @@ const (
 	SectionConnections SidebarSection = iota
 	SectionDatabases
 	SectionTables
+	SectionViews
 )
 
 // ConnectionItem represents a connection in the sidebar
@@ -20,6 +23,7 @@ type ConnectionItem struct {
 	Name   string
 	Driver string
 	Active bool
+	Shared bool // from the team's shared library, read-only
 }
 
 func (c ConnectionItem) Title() string {
@@ -28,7 +32,12 @@ func (c ConnectionItem) Title() string {
 	}
 	return "  " + c.Name
 }
-func (c ConnectionItem) Description() string { return c.Driver }
+func (c ConnectionItem) Description() string {
+	if c.Shared {
+		return c.Driver + " · shared"
+	}
+	return c.Driver
+}
 func (c ConnectionItem) FilterValue() string { return c.Name }
 
 // DatabaseItem represents a database in the sidebar
@@ -50,27 +59,70 @@ func (d DatabaseItem) FilterValue() string { return d.name }
 type TableItem struct {
 	name     string
 	selected bool
+
+	// Partitioning
+	parent     string // set when this item is a partition of parent
+	target     string // FROM-clause expression reading only this partition
+	partitions int    // number of partitions when this is a partitioned table
+	expanded   bool
 }
 
 func (t TableItem) Title() string {
+	marker := "  "
 	if t.selected {
-		return "● " + t.name
+		marker = "● "
 	}
-	return "  " + t.name
+	if t.parent != "" {
+		return marker + "└ " + t.name
+	}
+	if t.partitions > 0 {
+		arrow := "▸"
+		if t.expanded {
+			arrow = "▾"
+		}
+		return fmt.Sprintf("%s%s %s (%d)", marker, arrow, t.name, t.partitions)
+	}
+	return marker + t.name
 }
 func (t TableItem) Description() string { return "" }
 func (t TableItem) FilterValue() string { return t.name }
 
+// ViewItem represents a view in the sidebar
+type ViewItem struct {
+	name         string
+	materialized bool
+}
+
+func (v ViewItem) Title() string {
+	if v.materialized {
+		return "  " + v.name + " (mat.)"
+	}
+	return "  " + v.name
+}
+func (v ViewItem) Description() string { return "" }
+func (v ViewItem) FilterValue() string { return v.name }
+
+// TablePartition is a partition shown under its parent table
+type TablePartition struct {
+	Name   string
+	Target string
+}
+
 // Sidebar component for displaying connections, databases and tables
 type Sidebar struct {
 	connList      list.Model
 	dbList        list.Model
 	tableList     list.Model
+	viewList      list.Model
 	
 	currentDB     string
+	tables        []string
+	partitions    map[string][]TablePartition
+	expanded      map[string]bool
 	width         int
 	height        int
 	focused       bool
+	state         PaneState
 	section       SidebarSection
 	styles        SidebarStyles
 }
@@ -85,6 +137,7 @@ type SidebarStyles struct {
 	AddButton   lipgloss.Style
 	ActiveConn  lipgloss.Style
 	InactiveConn lipgloss.Style
+	Borders      PaneBorders
 }
 
 // NewSidebar creates a new sidebar component
@@ -117,13 +170,23 @@ func NewSidebar(styles SidebarStyles) Sidebar {
 	tableList.SetFilteringEnabled(true)
 	tableList.Styles.Title = styles.Title
 	
+	// Views List
+	viewList := list.New([]list.Item{}, delegate, 20, 5)
+	viewList.Title = "VIEWS"
+	viewList.SetShowStatusBar(false)
+	viewList.SetShowHelp(false)
+	viewList.SetFilteringEnabled(true)
+	viewList.Styles.Title = styles.Title
+	
 	return Sidebar{
 		connList:  connList,
 		dbList:    dbList,
 		tableList: tableList,
+		viewList:  viewList,
 		focused:   false,
 		section:   SectionConnections,
 		styles:    styles,
+		expanded:  make(map[string]bool),
 	}
 }
 
@@ -171,23 +234,104 @@ func (s Sidebar) GetSelectedConnection() int {
 
 // SetTables sets the list of tables
 func (s *Sidebar) SetTables(tables []string) {
-	items := make([]list.Item, len(tables))
-	for i, t := range tables {
-		items[i] = TableItem{name: t}
+	s.tables = tables
+	s.rebuildTableItems()
+}
+
+// SetPartitions sets the partitions of partitioned tables, keyed by parent name
+func (s *Sidebar) SetPartitions(partitions map[string][]TablePartition) {
+	s.partitions = partitions
+	s.rebuildTableItems()
+}
+
+// rebuildTableItems regenerates the table list, nesting partitions under their parent
+func (s *Sidebar) rebuildTableItems() {
+	isChild := make(map[string]bool)
+	for _, parts := range s.partitions {
+		for _, p := range parts {
+			isChild[p.Name] = true
+		}
+	}
+
+	selected := ""
+	for _, item := range s.tableList.Items() {
+		if t, ok := item.(TableItem); ok && t.selected {
+			selected = t.name
+		}
+	}
+
+	items := make([]list.Item, 0, len(s.tables))
+	for _, t := range s.tables {
+		if isChild[t] {
+			continue
+		}
+		parts := s.partitions[t]
+		items = append(items, TableItem{
+			name:       t,
+			selected:   t == selected,
+			partitions: len(parts),
+			expanded:   s.expanded[t],
+		})
+		if s.expanded[t] {
+			for _, p := range parts {
+				items = append(items, TableItem{
+					name:     p.Name,
+					selected: p.Name == selected,
+					parent:   t,
+					target:   p.Target,
+				})
+			}
+		}
 	}
 	s.tableList.SetItems(items)
 }
 
-// GetTables returns the list of table names
-func (s Sidebar) GetTables() []string {
-	items := s.tableList.Items()
-	tables := make([]string, len(items))
-	for i, item := range items {
-		if t, ok := item.(TableItem); ok {
-			tables[i] = t.name
+// ToggleExpanded expands or collapses the partitions of the selected table
+func (s *Sidebar) ToggleExpanded() bool {
+	item, ok := s.tableList.SelectedItem().(TableItem)
+	if !ok {
+		return false
+	}
+	name := item.name
+	if item.parent != "" {
+		name = item.parent
+	}
+	if len(s.partitions[name]) == 0 {
+		return false
+	}
+	s.expanded[name] = !s.expanded[name]
+	s.rebuildTableItems()
+
+	// Keep the cursor on the parent
+	for i, it := range s.tableList.Items() {
+		if t, ok := it.(TableItem); ok && t.name == name && t.parent == "" {
+			s.tableList.Select(i)
+			break
 		}
 	}
-	return tables
+	return true
+}
+
+// SetViews sets the list of views; materialized marks materialized views
+func (s *Sidebar) SetViews(views []string, materialized map[string]bool) {
+	items := make([]list.Item, len(views))
+	for i, v := range views {
+		items[i] = ViewItem{name: v, materialized: materialized[v]}
+	}
+	s.viewList.SetItems(items)
+}
+
+// SelectedView returns the currently selected view name
+func (s Sidebar) SelectedView() string {
+	if item := s.viewList.SelectedItem(); item != nil {
+		return item.(ViewItem).name
+	}
+	return ""
+}
+
+// GetTables returns the list of table names
+func (s Sidebar) GetTables() []string {
+	return s.tables
 }
 
 // SetDatabases sets the list of databases
@@ -230,6 +374,28 @@ func (s *Sidebar) SelectTable(tableName string) {
 	s.tableList.SetItems(items)
 }
 
+// SelectedPartition returns the parent table and FROM target when a partition is selected
+func (s Sidebar) SelectedPartition() (parent, target string, ok bool) {
+	if item, isTable := s.tableList.SelectedItem().(TableItem); isTable && item.parent != "" {
+		return item.parent, item.target, true
+	}
+	return "", "", false
+}
+
+// IsFiltering returns true while the user is typing a filter in the active list
+func (s Sidebar) IsFiltering() bool {
+	switch s.section {
+	case SectionConnections:
+		return s.connList.FilterState() == list.Filtering
+	case SectionDatabases:
+		return s.dbList.FilterState() == list.Filtering
+	case SectionViews:
+		return s.viewList.FilterState() == list.Filtering
+	default:
+		return s.tableList.FilterState() == list.Filtering
+	}
+}
+
 // SetSize sets the sidebar dimensions
 func (s *Sidebar) SetSize(width, height int) {
 	s.width = width
@@ -238,18 +404,22 @@ func (s *Sidebar) SetSize(width, height int) {
     // Distribute height
     minHeight := 5
     
-    connHeight := height / 4
+    connHeight := height / 5
     if connHeight < minHeight { connHeight = minHeight }
     
-    dbHeight := height / 4
+    dbHeight := height / 5
     if dbHeight < minHeight { dbHeight = minHeight }
     
-    tableHeight := height - connHeight - dbHeight
+    viewHeight := height / 5
+    if viewHeight < minHeight { viewHeight = minHeight }
+    
+    tableHeight := height - connHeight - dbHeight - viewHeight
     if tableHeight < minHeight { tableHeight = minHeight }
     
 	s.connList.SetSize(width-2, connHeight)
     s.dbList.SetSize(width-2, dbHeight)
     s.tableList.SetSize(width-2, tableHeight)
+	s.viewList.SetSize(width-2, viewHeight)
 }
 
 // SetFocused sets the focus state
@@ -257,6 +427,11 @@ func (s *Sidebar) SetFocused(focused bool) {
 	s.focused = focused
 }
 
+// SetState sets the state the border shows
+func (s *Sidebar) SetState(state PaneState) {
+	s.state = state
+}
+
 // IsFocused returns if sidebar is focused
 func (s Sidebar) IsFocused() bool {
 	return s.focused
@@ -272,12 +447,14 @@ func (s *Sidebar) SetSection(section SidebarSection) {
 	s.section = section
 }
 
-// ToggleSection switches between connections and tables
+// ToggleSection cycles connections, databases, tables and views
 func (s *Sidebar) ToggleSection() {
 	if s.section == SectionConnections {
 		s.section = SectionDatabases
 	} else if s.section == SectionDatabases {
         s.section = SectionTables
+	} else if s.section == SectionTables {
+		s.section = SectionViews
     } else {
 		s.section = SectionConnections
 	}
@@ -320,6 +497,9 @@ func (s Sidebar) Update(msg tea.Msg) (Sidebar, tea.Cmd) {
     case SectionTables:
         s.tableList, cmd = s.tableList.Update(msg)
         cmds = append(cmds, cmd)
+	case SectionViews:
+		s.viewList, cmd = s.viewList.Update(msg)
+		cmds = append(cmds, cmd)
     }
     
 	return s, tea.Batch(cmds...)
@@ -331,19 +511,27 @@ func (s Sidebar) View() string {
 	if s.focused {
 		style = s.styles.Focused
 	}
+	style = s.styles.Borders.style(style, s.state)
     
     // Update titles to show active section
     connTitle := "CONNECTIONS"
     dbTitle := "DATABASES"
     tableTitle := "TABLES"
+	viewTitle := "VIEWS"
     
     if s.section == SectionConnections { connTitle = "▼ " + connTitle } else { connTitle = "▶ " + connTitle }
     if s.section == SectionDatabases { dbTitle = "▼ " + dbTitle } else { dbTitle = "▶ " + dbTitle }
     if s.section == SectionTables { tableTitle = "▼ " + tableTitle } else { tableTitle = "▶ " + tableTitle }
+	if s.section == SectionViews {
+		viewTitle = "▼ " + viewTitle
+	} else {
+		viewTitle = "▶ " + viewTitle
+	}
     
     s.connList.Title = connTitle
     s.dbList.Title = dbTitle
     s.tableList.Title = tableTitle
+	s.viewList.Title = viewTitle
     
     return style.
         Width(s.width).
@@ -352,6 +540,7 @@ func (s Sidebar) View() string {
             s.connList.View(),
             s.dbList.View(),
             s.tableList.View(),
+			s.viewList.View(),
         ))
 }
 
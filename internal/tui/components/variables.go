@@ -0,0 +1,346 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// VariableItem is a single server setting shown in the browser
+type VariableItem struct {
+	Name        string
+	Value       string
+	Description string
+	Key         string   // optional identifier for the caller, not shown
+	Tags        []string // matched by #tag words of the filter
+}
+
+// VariablesBrowser component for searching server configuration variables
+type VariablesBrowser struct {
+	visible  bool
+	width    int
+	height   int
+	title    string
+	items    []VariableItem
+	filtered []int
+	selected int
+	offset   int
+	filter   textinput.Model
+	status   string
+	noun     string // what the items are, for the count line
+	hint     string
+	// fuzzy matches filter words as letters in order rather than as
+	// substrings; preview shows this many lines of the description
+	fuzzy   bool
+	preview int
+	styles  VariablesBrowserStyles
+}
+
+// VariablesBrowserStyles holds styling for the variables browser
+type VariablesBrowserStyles struct {
+	Modal    lipgloss.Style
+	Title    lipgloss.Style
+	Input    lipgloss.Style
+	Name     lipgloss.Style
+	Value    lipgloss.Style
+	Selected lipgloss.Style
+	Desc     lipgloss.Style
+	Hint     lipgloss.Style
+}
+
+// NewVariablesBrowser creates a new variables browser
+func NewVariablesBrowser(styles VariablesBrowserStyles) VariablesBrowser {
+	ti := textinput.New()
+	ti.Placeholder = "Filter settings..."
+	ti.CharLimit = 100
+	ti.Width = 40
+
+	return VariablesBrowser{
+		visible: false,
+		filter:  ti,
+		noun:    "settings",
+		hint:    "↑↓: navigate • Enter: copy name=value • Ctrl+N: copy name • Ctrl+Y: copy value • Esc: close",
+		styles:  styles,
+	}
+}
+
+// SetLabels reuses the browser for other lists: noun names the items in the
+// count line, placeholder the filter and hint replaces the key help
+func (v *VariablesBrowser) SetLabels(noun, placeholder, hint string) {
+	v.noun = noun
+	v.filter.Placeholder = placeholder
+	v.hint = hint
+}
+
+// SetFuzzy makes the filter match the letters of each word in order, so
+// "slcus" finds "SELECT * FROM customers"
+func (v *VariablesBrowser) SetFuzzy(on bool) {
+	v.fuzzy = on
+}
+
+// SetPreview shows up to lines lines of the selected item's description
+// instead of its first line
+func (v *VariablesBrowser) SetPreview(lines int) {
+	v.preview = lines
+}
+
+// Show shows the browser with the given settings
+func (v *VariablesBrowser) Show(title string, items []VariableItem) {
+	v.visible = true
+	v.title = title
+	v.items = items
+	v.status = ""
+	v.filter.SetValue("")
+	v.filter.Focus()
+	v.applyFilter()
+}
+
+// Hide hides the browser
+func (v *VariablesBrowser) Hide() {
+	v.visible = false
+	v.filter.Blur()
+}
+
+// IsVisible returns if the browser is visible
+func (v VariablesBrowser) IsVisible() bool {
+	return v.visible
+}
+
+// SetSize sets the browser dimensions
+func (v *VariablesBrowser) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.filter.Width = width - 10
+}
+
+// SetStatus sets the status line (e.g. after copying)
+func (v *VariablesBrowser) SetStatus(status string) {
+	v.status = status
+}
+
+// applyFilter rebuilds the filtered list from the filter text. Words
+// starting with # keep items with a tag starting with the word; the rest
+// of the text is looked for in the name, value and description, word by
+// word when the browser is fuzzy.
+func (v *VariablesBrowser) applyFilter() {
+	var tags, words []string
+	for _, word := range strings.Fields(strings.ToLower(v.filter.Value())) {
+		if len(word) > 1 && word[0] == '#' {
+			tags = append(tags, word[1:])
+		} else {
+			words = append(words, word)
+		}
+	}
+	query := strings.Join(words, " ")
+
+	v.filtered = v.filtered[:0]
+	for i, item := range v.items {
+		if !hasTags(item.Tags, tags) {
+			continue
+		}
+		if v.fuzzy {
+			if fuzzyMatch(strings.ToLower(item.Name+" "+item.Value+" "+item.Description), words) {
+				v.filtered = append(v.filtered, i)
+			}
+			continue
+		}
+		if query == "" ||
+			strings.Contains(strings.ToLower(item.Name), query) ||
+			strings.Contains(strings.ToLower(item.Value), query) ||
+			strings.Contains(strings.ToLower(item.Description), query) {
+			v.filtered = append(v.filtered, i)
+		}
+	}
+	v.selected = 0
+	v.offset = 0
+}
+
+// fuzzyMatch reports whether the letters of every word appear in text in
+// order, not necessarily next to each other
+func fuzzyMatch(text string, words []string) bool {
+	for _, word := range words {
+		rest := text
+		for _, r := range word {
+			i := strings.IndexRune(rest, r)
+			if i < 0 {
+				return false
+			}
+			rest = rest[i+len(string(r)):]
+		}
+	}
+	return true
+}
+
+// hasTags reports whether every prefix in want starts one of tags
+func hasTags(tags, want []string) bool {
+	for _, prefix := range want {
+		found := false
+		for _, tag := range tags {
+			if strings.HasPrefix(strings.ToLower(tag), prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// visibleRows returns how many rows fit in the list area
+func (v VariablesBrowser) visibleRows() int {
+	// title, filter, count, description, status, hint and padding
+	rows := v.height - 14 - max(v.preview-1, 0)
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// Move moves the selection by n rows
+func (v *VariablesBrowser) Move(n int) {
+	v.selected += n
+	if v.selected >= len(v.filtered) {
+		v.selected = len(v.filtered) - 1
+	}
+	if v.selected < 0 {
+		v.selected = 0
+	}
+
+	rows := v.visibleRows()
+	if v.selected < v.offset {
+		v.offset = v.selected
+	}
+	if v.selected >= v.offset+rows {
+		v.offset = v.selected - rows + 1
+	}
+}
+
+// Selected returns the selected setting
+func (v VariablesBrowser) Selected() (VariableItem, bool) {
+	if v.selected < 0 || v.selected >= len(v.filtered) {
+		return VariableItem{}, false
+	}
+	return v.items[v.filtered[v.selected]], true
+}
+
+// CopySelected copies the selected setting to the clipboard. part is "name",
+// "value" or "pair" (name = value); it returns the copied text.
+func (v VariablesBrowser) CopySelected(part string) (string, error) {
+	item, ok := v.Selected()
+	if !ok {
+		return "", fmt.Errorf("no setting selected")
+	}
+
+	var text string
+	switch part {
+	case "name":
+		text = item.Name
+	case "value":
+		text = item.Value
+	default:
+		text = item.Name + " = " + item.Value
+	}
+	return text, clipboard.WriteAll(text)
+}
+
+// Update handles filter input
+func (v VariablesBrowser) Update(msg tea.Msg) (VariablesBrowser, tea.Cmd) {
+	before := v.filter.Value()
+	var cmd tea.Cmd
+	v.filter, cmd = v.filter.Update(msg)
+	if v.filter.Value() != before {
+		v.applyFilter()
+	}
+	return v, cmd
+}
+
+// previewLines renders the first preview lines of text, padded so the
+// modal keeps its height while the selection moves
+func (v VariablesBrowser) previewLines(text string, width int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > v.preview {
+		lines = lines[:v.preview]
+		lines[v.preview-1] = "…"
+	}
+	for i, line := range lines {
+		lines[i] = v.styles.Desc.Render(truncate(strings.ReplaceAll(line, "\t", "    "), width))
+	}
+	for len(lines) < v.preview {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// View renders the browser
+func (v VariablesBrowser) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	width := v.width
+	if width < 50 {
+		width = 50
+	}
+	innerWidth := width - 6
+
+	content := v.styles.Title.Render(v.title) + "\n\n"
+	content += v.styles.Input.Render(v.filter.View()) + "\n"
+	content += v.styles.Hint.Render(fmt.Sprintf("%d of %d %s", len(v.filtered), len(v.items), v.noun)) + "\n\n"
+
+	// Size the name column to the longest visible name
+	nameWidth := 0
+	rows := v.visibleRows()
+	end := v.offset + rows
+	if end > len(v.filtered) {
+		end = len(v.filtered)
+	}
+	for _, idx := range v.filtered[v.offset:end] {
+		if w := lipgloss.Width(v.items[idx].Name); w > nameWidth {
+			nameWidth = w
+		}
+	}
+	if nameWidth > innerWidth/2 {
+		nameWidth = innerWidth / 2
+	}
+
+	for i := v.offset; i < end; i++ {
+		item := v.items[v.filtered[i]]
+		name := truncate(item.Name, nameWidth)
+		value := truncate(item.Value, innerWidth-nameWidth-3)
+		line := fmt.Sprintf("%-*s   %s", nameWidth, name, value)
+		if i == v.selected {
+			content += v.styles.Selected.Render(line) + "\n"
+		} else {
+			content += v.styles.Name.Render(fmt.Sprintf("%-*s", nameWidth, name)) + "   " + v.styles.Value.Render(value) + "\n"
+		}
+	}
+	for i := end - v.offset; i < rows; i++ {
+		content += "\n"
+	}
+
+	if item, ok := v.Selected(); ok && item.Description != "" {
+		if v.preview > 0 {
+			content += "\n" + v.previewLines(item.Description, innerWidth)
+		} else {
+			content += "\n" + v.styles.Desc.Render(truncate(item.Description, innerWidth))
+		}
+	} else if v.preview > 0 {
+		content += strings.Repeat("\n", v.preview)
+	}
+	content += "\n"
+	if v.status != "" {
+		content += v.styles.Hint.Render(v.status) + "\n"
+	}
+	content += v.styles.Hint.Render(v.hint)
+
+	return v.styles.Modal.
+		Width(width).
+		Padding(1, 2).
+		Render(content)
+}
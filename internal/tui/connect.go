@@ -0,0 +1,444 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/completion/sources"
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// connectPurpose says what to do with a finished connection attempt
+type connectPurpose int
+
+const (
+	// connectTest reports the result in the settings modal
+	connectTest connectPurpose = iota
+	// connectSave saves the settings form once the connection works
+	connectSave
+	// connectActivate switches the app to the connection
+	connectActivate
+)
+
+// connectResultMsg carries the outcome of a background connection attempt
+type connectResultMsg struct {
+	id        int
+	purpose   connectPurpose
+	connIdx   int
+	connector db.Connector // open on success, only kept for connectActivate
+	catalog   catalog      // loaded for connectActivate
+	// restoreFailed is set when switching to the last used database failed
+	restoreFailed bool
+	elapsed       time.Duration
+	err           error
+}
+
+// connTestAllMsg carries one connection's result from "Test all"
+type connTestAllMsg struct {
+	run     int
+	index   int
+	latency time.Duration
+	err     error
+}
+
+// connectDoneMsg reports a connection attempt that was neither cancelled
+// nor replaced by a newer one
+type connectDoneMsg connectResultMsg
+
+// testAllProgressMsg reports the "Test all" results so far
+type testAllProgressMsg struct {
+	results []components.ConnTestResult
+	done    bool
+}
+
+// connectionController holds the active connection and the background
+// attempts to open one. Its Update drops stale results and passes the
+// rest on to the model as connectDoneMsg and testAllProgressMsg.
+type connectionController struct {
+	connector    db.Connector
+	capabilities db.Capabilities
+	connected    bool
+
+	// Background connection attempt; id tells stale results apart
+	cancel context.CancelFunc
+	id     int
+
+	// Connection waiting for its password in the password prompt
+	passwordIdx int
+
+	// "Test all" in settings; testRun tells stale results apart
+	testCancel  context.CancelFunc
+	testRun     int
+	testPending int
+	testResults []components.ConnTestResult
+}
+
+// Update handles the results of connection attempts and "Test all"
+func (c connectionController) Update(msg tea.Msg) (connectionController, tea.Cmd) {
+	switch msg := msg.(type) {
+	case connectResultMsg:
+		if msg.id != c.id || c.cancel == nil {
+			// Cancelled or replaced by a newer attempt
+			if msg.connector != nil {
+				msg.connector.Close()
+			}
+			return c, nil
+		}
+		c.cancel()
+		c.cancel = nil
+		return c, func() tea.Msg { return connectDoneMsg(msg) }
+
+	case connTestAllMsg:
+		if msg.run != c.testRun || c.testCancel == nil || msg.index >= len(c.testResults) {
+			return c, nil
+		}
+		c.testResults[msg.index].Pending = false
+		c.testResults[msg.index].Latency = msg.latency
+		c.testResults[msg.index].Err = msg.err
+		c.testPending--
+		done := c.testPending == 0
+		if done {
+			c.testCancel()
+			c.testCancel = nil
+		}
+		progress := testAllProgressMsg{results: c.testResults, done: done}
+		return c, func() tea.Msg { return progress }
+	}
+	return c, nil
+}
+
+// connecting reports whether a connection attempt is running
+func (c connectionController) connecting() bool {
+	return c.cancel != nil
+}
+
+// start connects to cfg in the background, replacing any attempt still
+// running. Activating also loads the catalog, first going back to lastDB,
+// the database used last.
+func (c *connectionController) start(cfg *config.DatabaseConfig, purpose connectPurpose, connIdx int, lastDB string) tea.Cmd {
+	c.cancelAttempt()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.id++
+	id := c.id
+
+	return func() tea.Msg {
+		start := time.Now()
+		connector, err := openConnector(ctx, cfg)
+		msg := connectResultMsg{
+			id:      id,
+			purpose: purpose,
+			connIdx: connIdx,
+			elapsed: time.Since(start),
+			err:     err,
+		}
+		if err != nil {
+			return msg
+		}
+		if purpose != connectActivate {
+			connector.Close()
+			return msg
+		}
+		msg.connector = connector
+
+		// Back to the database used last, then what the sidebar shows
+		if lastDB != "" && connector.Capabilities().SupportsSwitchDatabase && connector.GetDatabaseName() != lastDB {
+			msg.restoreFailed = connector.SwitchDatabase(lastDB) != nil
+		}
+		msg.catalog = loadCatalog(connector)
+		return msg
+	}
+}
+
+// cancelAttempt aborts the running connection attempt, reporting whether
+// there was one
+func (c *connectionController) cancelAttempt() bool {
+	if c.cancel == nil {
+		return false
+	}
+	c.cancel()
+	c.cancel = nil
+	return true
+}
+
+// use makes an open connector the active connection, closing the previous
+// one
+func (c *connectionController) use(connector db.Connector) {
+	if c.connector != nil && c.connector != connector {
+		// Closing rolls back an open transaction
+		c.connector.Close()
+	}
+	c.connector = connector
+	c.connected = true
+	// Features are queried once; the TUI gates actions on them
+	c.capabilities = connector.Capabilities()
+}
+
+// close closes the active connection
+func (c *connectionController) close() {
+	if c.connector != nil {
+		c.connector.Close()
+		c.connector = nil
+	}
+	c.connected = false
+	c.capabilities = db.Capabilities{}
+}
+
+// testAll tests every connection of conns concurrently; each result
+// arrives as a connTestAllMsg
+func (c *connectionController) testAll(conns []config.DatabaseConfig) tea.Cmd {
+	c.cancelTestAll()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.testCancel = cancel
+	c.testRun++
+	c.testPending = len(conns)
+	run := c.testRun
+
+	c.testResults = make([]components.ConnTestResult, len(conns))
+	cmds := make([]tea.Cmd, len(conns))
+	for i, conn := range conns {
+		c.testResults[i] = components.ConnTestResult{Name: conn.Name, Driver: conn.Driver, Pending: true}
+		cfg := conn
+		index := i
+		if cfg.NeedsPassword() {
+			cmds[i] = func() tea.Msg {
+				return connTestAllMsg{run: run, index: index, err: errors.New("password not entered yet")}
+			}
+			continue
+		}
+		cmds[i] = func() tea.Msg {
+			start := time.Now()
+			connector, err := openConnector(ctx, &cfg)
+			if err == nil {
+				connector.Close()
+			}
+			return connTestAllMsg{run: run, index: index, latency: time.Since(start), err: err}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// cancelTestAll aborts a running "Test all", reporting whether one ran
+func (c *connectionController) cancelTestAll() bool {
+	if c.testCancel == nil {
+		return false
+	}
+	c.testCancel()
+	c.testCancel = nil
+	for i := range c.testResults {
+		if c.testResults[i].Pending {
+			c.testResults[i].Pending = false
+			c.testResults[i].Err = errors.New("cancelled")
+		}
+	}
+	return true
+}
+
+// openConnector connects to cfg, giving up after the connection's timeout
+// or when ctx is cancelled
+func openConnector(ctx context.Context, cfg *config.DatabaseConfig) (db.Connector, error) {
+	return db.Open(ctx, cfg)
+}
+
+// startConnect connects to cfg in the background. Esc aborts it through
+// cancelConnect. Switching connections waits for a running query, which
+// uses the current one.
+func (m *Model) startConnect(cfg *config.DatabaseConfig, purpose connectPurpose, connIdx int) tea.Cmd {
+	if purpose == connectActivate && m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return nil
+	}
+	return m.conn.start(cfg, purpose, connIdx, m.config.LastDatabase)
+}
+
+// activateConnection switches to the connection at idx in the background,
+// asking for its password first when the config doesn't store it
+func (m *Model) activateConnection(idx int) tea.Cmd {
+	conn := &m.config.Connections[idx]
+	if m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return nil
+	}
+	if conn.NeedsPassword() {
+		m.conn.passwordIdx = idx
+		m.password.Show(conn.Name)
+		m.state = StatePasswordPrompt
+		return nil
+	}
+	return m.startConnect(conn, connectActivate, idx)
+}
+
+// connectActive connects to the active connection in the background,
+// asking for its password first when needed
+func (m *Model) connectActive() tea.Cmd {
+	if m.config.GetActiveConnection() == nil {
+		m.statusMessage = "No database connection configured"
+		m.isError = true
+		return nil
+	}
+	if conn := m.config.GetActiveConnection(); !conn.NeedsPassword() {
+		m.statusMessage = "Connecting to " + conn.Name + "... (Esc to cancel)"
+		m.isError = false
+	}
+	return m.activateConnection(m.config.ActiveConnIndex)
+}
+
+// cancelConnect aborts the running connection attempt, reporting whether
+// there was one
+func (m *Model) cancelConnect() bool {
+	return m.conn.cancelAttempt()
+}
+
+// handleConnectDone applies a finished connection attempt
+func (m *Model) handleConnectDone(msg connectDoneMsg) tea.Cmd {
+	switch msg.purpose {
+	case connectTest:
+		if msg.err != nil {
+			m.settings.SetStatus("❌ Test failed: "+msg.err.Error(), true)
+		} else {
+			m.settings.SetStatus(fmt.Sprintf("✅ Connection test successful! (%s)", msg.elapsed.Round(time.Millisecond)), false)
+		}
+	case connectSave:
+		if msg.err != nil {
+			m.settings.SetStatus("❌ ERROR: "+msg.err.Error(), true)
+			return nil
+		}
+		return m.applySettings()
+	case connectActivate:
+		if msg.err != nil {
+			if msg.connIdx >= 0 && msg.connIdx < len(m.config.Connections) && m.config.Connections[msg.connIdx].PromptPassword {
+				// Likely a wrong password; ask again next time
+				m.config.Connections[msg.connIdx].Password = ""
+			}
+			if m.state == StateConnModal {
+				m.connModal.SetStatus("Connection failed: "+msg.err.Error(), true)
+			}
+			m.statusMessage = "Connection failed: " + msg.err.Error()
+			m.isError = true
+			return nil
+		}
+		if m.queryRunning {
+			// A query started on the old connection while connecting
+			msg.connector.Close()
+			m.statusMessage = "Connection not switched: a query is running"
+			m.isError = true
+			return nil
+		}
+		m.config.ActiveConnIndex = msg.connIdx
+		m.sidebar.SetActiveConnection(msg.connIdx)
+		if msg.restoreFailed {
+			m.config.LastDatabase = ""
+		}
+		m.useConnector(msg.connector, msg.catalog)
+		m.config.Save()
+		if m.state == StateConnModal {
+			m.connModal.Hide()
+			m.state = StateNormal
+		}
+	}
+	return nil
+}
+
+// useConnector makes an open connector the active connection, closing the
+// previous one, and shows its catalog loaded alongside
+func (m *Model) useConnector(connector db.Connector, cat catalog) {
+	connCfg := m.config.GetActiveConnection()
+	m.conn.use(connector)
+	m.replication = nil
+	m.replicationErr = nil
+
+	rows, bytes := m.config.ResultLimit()
+	db.SetResultLimit(connector, db.ResultLimit{Rows: rows, Bytes: bytes})
+
+	// Dialect keywords for highlighting and completion
+	m.keywordSource.SetDriver(connector.GetDriverName())
+	m.editor.SetDialectKeywords(sources.DialectKeywords(connector.GetDriverName()))
+	m.editor.SetDialect(sqlparse.DialectFor(connector.GetDriverName()))
+	m.switchBuffer(connCfg.Name)
+
+	if m.applyCatalog(cat) {
+		m.statusMessage = fmt.Sprintf("Connected to %s", connCfg.Name)
+		m.isError = false
+	} else {
+		m.statusMessage = "Connected, but failed to load tables: " + cat.tablesErr.Error()
+		m.isError = true
+	}
+}
+
+// Disconnect disconnects from the current database, unless a query is
+// running on it
+func (m *Model) Disconnect() {
+	if m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return
+	}
+	m.conn.close()
+	m.saveBuffer()
+	m.tables = nil
+	m.partitions = nil
+	m.replication = nil
+	m.replicationErr = nil
+	m.keywordSource.SetDriver("")
+	m.editor.SetDialectKeywords(nil)
+	m.editor.SetDialect(sqlparse.DialectStandard)
+	m.sidebar.SetPartitions(nil)
+	m.sidebar.SetViews(nil, nil)
+	m.views = nil
+	m.sidebar.SetTables(nil)
+	m.schema = nil
+	m.statusMessage = "Disconnected"
+	m.isError = false
+}
+
+// testAllConnections tests every configured connection concurrently,
+// reporting each result in the settings modal as it arrives
+func (m *Model) testAllConnections() tea.Cmd {
+	if len(m.config.Connections) == 0 {
+		m.settings.SetStatus("No connections configured", true)
+		return nil
+	}
+	cmd := m.conn.testAll(m.config.Connections)
+	m.settings.SetTestResults(m.conn.testResults)
+	m.settings.SetStatus(fmt.Sprintf("Testing %d connections... (Esc to cancel)", len(m.config.Connections)), false)
+	return cmd
+}
+
+// cancelTestAll aborts a running "Test all", reporting whether one ran
+func (m *Model) cancelTestAll() bool {
+	if !m.conn.cancelTestAll() {
+		return false
+	}
+	m.settings.SetTestResults(m.conn.testResults)
+	return true
+}
+
+// handleTestAllProgress shows the "Test all" results so far, summed up
+// once all arrived
+func (m *Model) handleTestAllProgress(msg testAllProgressMsg) {
+	m.settings.SetTestResults(msg.results)
+	if !msg.done {
+		return
+	}
+
+	failed := 0
+	for _, r := range msg.results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		m.settings.SetStatus(fmt.Sprintf("❌ %d of %d connections failed", failed, len(msg.results)), true)
+	} else {
+		m.settings.SetStatus(fmt.Sprintf("✅ All %d connections OK", len(msg.results)), false)
+	}
+}
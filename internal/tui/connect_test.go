@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// closeRecorder is a connector that only records being closed
+type closeRecorder struct {
+	db.Connector
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestConnectionControllerDropsStaleResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		msgID      int
+		connecting bool
+		wantDone   bool
+	}{
+		{"current attempt", 2, true, true},
+		{"replaced attempt", 1, true, false},
+		{"cancelled attempt", 2, false, false},
+	}
+	for _, tt := range tests {
+		c := connectionController{id: 2}
+		if tt.connecting {
+			_, c.cancel = context.WithCancel(context.Background())
+		}
+		connector := &closeRecorder{}
+
+		c, cmd := c.Update(connectResultMsg{id: tt.msgID, purpose: connectActivate, connector: connector})
+		// A stale result leaves the current attempt running
+		if want := tt.connecting && !tt.wantDone; c.connecting() != want {
+			t.Errorf("%s: connecting = %v, want %v", tt.name, c.connecting(), want)
+		}
+		if !tt.wantDone {
+			if cmd != nil {
+				t.Errorf("%s: passed on a stale result", tt.name)
+			}
+			if !connector.closed {
+				t.Errorf("%s: left the stale connector open", tt.name)
+			}
+			continue
+		}
+		if cmd == nil {
+			t.Fatalf("%s: dropped the result", tt.name)
+		}
+		if done, ok := cmd().(connectDoneMsg); !ok || done.connector != connector {
+			t.Errorf("%s: got %#v, want a connectDoneMsg with the connector", tt.name, cmd())
+		}
+		if connector.closed {
+			t.Errorf("%s: closed the connector it passed on", tt.name)
+		}
+	}
+}
+
+func TestConnectionControllerTestAll(t *testing.T) {
+	var c connectionController
+	c.testRun = 1
+	c.testPending = 2
+	_, c.testCancel = context.WithCancel(context.Background())
+	c.testResults = make([]components.ConnTestResult, 2)
+	for i := range c.testResults {
+		c.testResults[i].Pending = true
+	}
+
+	// A result of an earlier run is dropped
+	c, cmd := c.Update(connTestAllMsg{run: 0, index: 0})
+	if cmd != nil {
+		t.Fatal("passed on a result of an earlier run")
+	}
+
+	c, cmd = c.Update(connTestAllMsg{run: 1, index: 1, err: errors.New("refused")})
+	progress := cmd().(testAllProgressMsg)
+	if progress.done || progress.results[1].Pending || progress.results[1].Err == nil || !progress.results[0].Pending {
+		t.Fatalf("after one result got %+v, want only the second done and failed", progress)
+	}
+
+	c, cmd = c.Update(connTestAllMsg{run: 1, index: 0})
+	if progress = cmd().(testAllProgressMsg); !progress.done {
+		t.Errorf("after all results got %+v, want done", progress)
+	}
+	if c.cancelTestAll() {
+		t.Error("Test all still running once all results arrived")
+	}
+}
@@ -0,0 +1,345 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/csvimport"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// csvPreviewSamples is how many values the import preview parses per column
+const csvPreviewSamples = 3
+
+// csvImport is a CSV file being imported, between preview and insert
+type csvImport struct {
+	path    string
+	file    *csvimport.File
+	locale  csvimport.Locale
+	columns []csvimport.Column
+}
+
+// csvImportDoneMsg carries the outcome of an import
+type csvImportDoneMsg struct {
+	table     string
+	columns   []db.ColumnType
+	rows      int64
+	created   bool
+	elapsed   time.Duration
+	cancelled bool
+	err       error
+}
+
+// tableNameRe matches characters a generated table name drops
+var tableNameRe = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// PromptCSVImport asks for the CSV file to import into a new table
+func (m *Model) PromptCSVImport() {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+	if m.readOnly() {
+		m.statusMessage = "Connection is read-only"
+		m.isError = true
+		return
+	}
+	if !csvimport.Supported(m.conn.connector.GetDriverName()) {
+		m.statusMessage = "CSV import is not supported for this driver"
+		m.isError = true
+		return
+	}
+	m.askInput("📥 Import CSV", "Path of the CSV file; its first row names the columns", "", m.OpenCSVImport)
+}
+
+// OpenCSVImport reads a CSV file and previews its inferred columns
+func (m *Model) OpenCSVImport(path string) tea.Cmd {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		m.statusMessage = "Import failed: " + err.Error()
+		m.isError = true
+		return nil
+	}
+	defer f.Close()
+
+	cfg := m.config.CSVImport
+	file, err := csvimport.Read(f, csvDelimiter(cfg))
+	if err != nil {
+		m.statusMessage = "Import failed: " + err.Error()
+		m.isError = true
+		return nil
+	}
+
+	locale := csvimport.NewLocale(cfg.DateOrder, cfg.DecimalSeparator, cfg.ThousandsSeparator)
+	m.csvImport = &csvImport{path: path, file: file, locale: locale, columns: file.Columns(locale)}
+	m.importPreview.Show("📥 Import " + filepath.Base(path))
+	m.refreshImportPreview()
+	m.state = StateImportPreview
+	return nil
+}
+
+// refreshImportPreview shows the columns with their current types and
+// the first values parsed as them
+func (m *Model) refreshImportPreview() {
+	imp := m.csvImport
+	items := make([]components.ImportColumn, len(imp.columns))
+	for i, col := range imp.columns {
+		var samples []string
+		for _, row := range imp.file.Rows {
+			if len(samples) == csvPreviewSamples {
+				break
+			}
+			raw := strings.TrimSpace(row[i])
+			if raw == "" {
+				continue
+			}
+			parsed := "✗"
+			if v, err := csvimport.Parse(raw, col.Type, imp.locale); err == nil {
+				parsed = csvimport.Format(v, col.Type)
+			}
+			if parsed == raw {
+				samples = append(samples, raw)
+			} else {
+				samples = append(samples, raw+" → "+parsed)
+			}
+		}
+		if len(samples) == 0 {
+			samples = []string{"(empty)"}
+		}
+		items[i] = components.ImportColumn{
+			Name:       col.Name,
+			Type:       col.Type.String(),
+			Overridden: col.Override,
+			Samples:    strings.Join(samples, " · "),
+			Invalid:    imp.file.Invalid(i, col.Type, imp.locale),
+		}
+	}
+	subtitle := fmt.Sprintf("%d rows · %s", len(imp.file.Rows), imp.locale)
+	m.importPreview.SetColumns(subtitle, items)
+}
+
+// updateImportPreview handles the import preview
+func (m *Model) updateImportPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	imp := m.csvImport
+	switch msg.String() {
+	case "esc", "q":
+		m.importPreview.Hide()
+		m.csvImport = nil
+		m.state = StateNormal
+	case "up", "k":
+		m.importPreview.Move(-1)
+	case "down", "j":
+		m.importPreview.Move(1)
+	case "pgup":
+		m.importPreview.Move(-10)
+	case "pgdown":
+		m.importPreview.Move(10)
+	case "left", "h", "right", "l":
+		step := 1
+		if key := msg.String(); key == "left" || key == "h" {
+			step = -1
+		}
+		types := csvimport.Types()
+		col := &imp.columns[m.importPreview.Selected()]
+		col.Type = types[(int(col.Type)+step+len(types))%len(types)]
+		col.Override = col.Type != col.Inferred
+		m.refreshImportPreview()
+	case "d":
+		imp.locale = imp.locale.NextDateOrder()
+		imp.file.Reinfer(imp.columns, imp.locale)
+		m.refreshImportPreview()
+	case ",":
+		imp.locale = imp.locale.SwapDecimal()
+		imp.file.Reinfer(imp.columns, imp.locale)
+		m.refreshImportPreview()
+	case "enter":
+		for i, col := range imp.columns {
+			if n := imp.file.Invalid(i, col.Type, imp.locale); n > 0 {
+				m.statusMessage = fmt.Sprintf("%d values of %s are not a %s; change its type with ←→", n, col.Name, col.Type)
+				m.isError = true
+				return m, nil
+			}
+		}
+		m.importPreview.Hide()
+		m.state = StateNormal
+		m.askInput("📥 Import CSV", "Name of the new table", csvTableName(imp.path), m.confirmCSVImport)
+	}
+	return m, nil
+}
+
+// csvDelimiter returns the configured delimiter, 0 to detect it; \t in
+// the config stands for a tab
+func csvDelimiter(cfg config.CSVImportConfig) rune {
+	if d := []rune(strings.ReplaceAll(cfg.Delimiter, `\t`, "\t")); len(d) == 1 {
+		return d[0]
+	}
+	return 0
+}
+
+// csvTableName derives a table name from the file name
+func csvTableName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.Trim(tableNameRe.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "import_" + name
+	}
+	return name
+}
+
+// confirmCSVImport shows the CREATE TABLE for the file and imports it
+// once confirmed
+func (m *Model) confirmCSVImport(name string) tea.Cmd {
+	imp := m.csvImport
+	name = strings.TrimSpace(name)
+	if imp == nil || name == "" || m.conn.connector == nil {
+		m.csvImport = nil
+		return nil
+	}
+	for _, t := range m.tables {
+		if strings.EqualFold(t, name) {
+			m.csvImport = nil
+			m.statusMessage = "Table " + name + " already exists"
+			m.isError = true
+			return nil
+		}
+	}
+
+	ddl := csvimport.CreateTable(m.conn.connector.GetDriverName(), name, imp.columns)
+	message := fmt.Sprintf("%s\n\nthen insert %d rows", ddl, len(imp.file.Rows))
+	m.askConfirm("📥 Import CSV", message, func() tea.Cmd {
+		return m.runCSVImport(name, ddl)
+	})
+	return nil
+}
+
+// runCSVImport creates the table and inserts the rows in batches in the
+// background
+func (m *Model) runCSVImport(table, ddl string) tea.Cmd {
+	imp := m.csvImport
+	m.csvImport = nil
+	if imp == nil || m.conn.connector == nil || !m.conn.connected {
+		return nil
+	}
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil
+	}
+	querier, ok := db.GetParamQuerier(m.conn.connector)
+	if !ok {
+		m.statusMessage = "CSV import is not supported for this driver"
+		m.isError = true
+		return nil
+	}
+
+	driver := m.conn.connector.GetDriverName()
+	columns := make([]db.ColumnType, len(imp.columns))
+	for i, col := range imp.columns {
+		sqlType := csvimport.SQLType(driver, col.Type)
+		columns[i] = db.ColumnType{Name: col.Name, DatabaseType: sqlType, Kind: db.ColumnKindOf(sqlType)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	m.statusMessage = fmt.Sprintf("Importing %d rows into %s... (Esc to cancel)", len(imp.file.Rows), table)
+	m.isError = false
+
+	connector := m.conn.connector
+	run := func() tea.Msg {
+		start := time.Now()
+		msg := importCSV(ctx, connector, querier, imp, table, ddl)
+		msg.columns = columns
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+	return tea.Batch(run, m.results.StartRunning("Importing CSV"))
+}
+
+// importCSV creates the table and inserts the rows of imp, as many per
+// INSERT as the driver binds
+func importCSV(ctx context.Context, connector db.Connector, querier db.ParamQuerier, imp *csvImport, table, ddl string) csvImportDoneMsg {
+	msg := csvImportDoneMsg{table: table}
+	if _, err := connector.Execute(ctx, ddl); err != nil {
+		msg.err = err
+		return msg
+	}
+	msg.created = true
+
+	driver := connector.GetDriverName()
+	batch := csvimport.BatchSize(imp.columns)
+	rows := imp.file.Rows
+	for start := 0; start < len(rows); start += batch {
+		chunk := rows[start:min(start+batch, len(rows))]
+		var args []interface{}
+		for i, row := range chunk {
+			values, err := csvimport.Args(row, imp.columns, imp.locale)
+			if err != nil {
+				// Line numbers count the header
+				msg.err = fmt.Errorf("line %d: %w", start+i+2, err)
+				return msg
+			}
+			args = append(args, values...)
+		}
+		n, err := querier.ExecuteParams(ctx, csvimport.Insert(driver, table, imp.columns, len(chunk)), args)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.rows += n
+	}
+	return msg
+}
+
+// handleCSVImportDone lists the new table, also after a partial import
+func (m *Model) handleCSVImportDone(msg csvImportDoneMsg) {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+	if msg.created {
+		m.addTable(msg.table, msg.columns)
+	}
+
+	elapsed := msg.elapsed.Round(time.Millisecond)
+	switch {
+	case msg.cancelled && msg.created:
+		m.statusMessage = fmt.Sprintf("Import cancelled; %s holds the first %d rows", msg.table, msg.rows)
+		m.isError = true
+	case msg.cancelled:
+		m.statusMessage = "Import cancelled"
+		m.isError = true
+	case msg.err != nil && msg.created:
+		m.statusMessage = fmt.Sprintf("Import stopped after %d rows: %s", msg.rows, msg.err.Error())
+		m.isError = true
+	case msg.err != nil:
+		m.statusMessage = "Import failed: " + msg.err.Error()
+		m.isError = true
+	default:
+		m.statusMessage = fmt.Sprintf("Imported %d rows into %s (%s)", msg.rows, msg.table, elapsed)
+		m.isError = false
+	}
+}
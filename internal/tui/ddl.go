@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// tableDDL reads a table's DDL in the background and passes it to use,
+// reporting failures in the status bar
+func (m *Model) tableDDL(tableName string, use func(ddl string)) tea.Cmd {
+	var ddl string
+	read := func(connector db.Connector) error {
+		var err error
+		ddl, err = connector.GetTableDDL(tableName)
+		return err
+	}
+	return m.introspect("Reading DDL of "+tableName, read, func(err error) tea.Cmd {
+		if err != nil {
+			m.statusMessage = err.Error()
+			m.isError = true
+			return nil
+		}
+		use(ddl)
+		return nil
+	})
+}
+
+// CopyTableDDL copies the statements that create a table to the clipboard
+func (m *Model) CopyTableDDL(tableName string) tea.Cmd {
+	return m.tableDDL(tableName, func(ddl string) {
+		if err := clipboard.WriteAll(ddl); err != nil {
+			m.statusMessage = "Copy failed: " + err.Error()
+			m.isError = true
+			return
+		}
+		m.statusMessage = "DDL of " + tableName + " copied to clipboard"
+		m.isError = false
+	})
+}
+
+// InsertTableDDL inserts the statements that create a table at the editor
+// cursor
+func (m *Model) InsertTableDDL(tableName string) tea.Cmd {
+	return m.tableDDL(tableName, func(ddl string) {
+		m.editor.InsertText(ddl + "\n")
+		m.FocusEditor()
+		m.statusMessage = "Inserted DDL of " + tableName
+		m.isError = false
+	})
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// tableDropDoneMsg carries the outcome of truncating or dropping a table
+type tableDropDoneMsg struct {
+	table     string
+	drop      bool
+	elapsed   time.Duration
+	cancelled bool
+	err       error
+}
+
+// truncateTableSQL empties a table; SQLite has no TRUNCATE
+func truncateTableSQL(driver, quoted string) string {
+	if driver == "sqlite3" {
+		return "DELETE FROM " + quoted
+	}
+	return "TRUNCATE TABLE " + quoted
+}
+
+// confirmTableDrop asks the user to type the table name before truncating
+// or dropping it. Typing the name also covers protected tables.
+func (m *Model) confirmTableDrop(table string, drop bool) {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+	if m.readOnly() {
+		m.statusMessage = "Connection is read-only"
+		m.isError = true
+		return
+	}
+
+	sql := truncateTableSQL(m.conn.connector.GetDriverName(), m.quoteIdent(table))
+	title, effect := "🧹 Truncate "+table, "deletes every row of"
+	if drop {
+		sql = "DROP TABLE " + m.quoteIdent(table)
+		title, effect = "🗑 Drop "+table, "removes"
+	}
+	message := fmt.Sprintf("This %s the table and can't be undone:\n\n  %s\n\nType %s to run it", effect, sql, table)
+	m.askInput(title, message, "", func(text string) tea.Cmd {
+		if strings.TrimSpace(text) != table {
+			m.statusMessage = "Cancelled: the table name didn't match"
+			m.isError = true
+			return nil
+		}
+		return m.runTableDrop(table, sql, drop)
+	})
+}
+
+// runTableDrop runs the TRUNCATE or DROP of a table in the background
+func (m *Model) runTableDrop(table, sql string, drop bool) tea.Cmd {
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil
+	}
+	connector := m.conn.connector
+	timeout := m.config.QueryTimeoutFor(m.config.GetActiveConnection())
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	label := "Truncating " + table
+	if drop {
+		label = "Dropping " + table
+	}
+	m.statusMessage = label + "... (Esc to cancel)"
+	m.isError = false
+
+	run := func() tea.Msg {
+		msg := tableDropDoneMsg{table: table, drop: drop}
+		start := time.Now()
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			_, err := connector.Execute(ctx, sql)
+			return err
+		})
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+	return tea.Batch(run, m.results.StartRunning(label))
+}
+
+// handleTableDropDone reports a finished TRUNCATE or DROP and takes a
+// dropped table out of the sidebar and completion
+func (m *Model) handleTableDropDone(msg tableDropDoneMsg) {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+
+	switch {
+	case msg.cancelled:
+		m.statusMessage = "Cancelled"
+		m.isError = true
+		return
+	case msg.err != nil:
+		m.statusMessage = "Failed: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+
+	elapsed := msg.elapsed.Round(time.Millisecond)
+	if !msg.drop {
+		m.statusMessage = fmt.Sprintf("Truncated %s in %s", msg.table, elapsed)
+		m.isError = false
+		return
+	}
+
+	m.removeTable(msg.table)
+	m.statusMessage = fmt.Sprintf("Dropped %s in %s", msg.table, elapsed)
+	m.isError = false
+}
+
+// removeTable takes a table out of the sidebar and the schema
+func (m *Model) removeTable(name string) {
+	var tables []string
+	for _, t := range m.tables {
+		if t != name {
+			tables = append(tables, t)
+		}
+	}
+	m.tables = tables
+	m.sidebar.SetTables(m.tables)
+
+	if m.schema != nil {
+		delete(m.schema.Tables, name)
+		m.applySchema(m.schema)
+	}
+}
@@ -0,0 +1,243 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/export"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// exportOption is an entry of the export menu
+type exportOption struct {
+	label   string
+	format  export.Format
+	masked  bool
+	session bool   // the session log as a Markdown document
+	shown   bool   // the rows shown in the results grid, in the viewer
+	chart   string // "txt" or "png": the chart shown in results
+}
+
+// exportDoneMsg carries the outcome of an export run in the background
+type exportDoneMsg struct {
+	path      string
+	rows      int64
+	masked    int // number of masked columns
+	elapsed   time.Duration
+	cancelled bool
+	err       error
+}
+
+// exportQuery returns the last query when it can be exported: a single
+// statement that returns rows, on a driver that streams rows
+func (m *Model) exportQuery() (string, db.RowStreamer, bool) {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return "", nil, false
+	}
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return "", nil, false
+	}
+	if strings.TrimSpace(m.lastQuery) == "" {
+		m.statusMessage = "Run a query first, then export it"
+		m.isError = true
+		return "", nil, false
+	}
+	streamer, ok := db.GetRowStreamer(m.conn.connector)
+	if !ok {
+		m.statusMessage = "Export is not supported for this driver"
+		m.isError = true
+		return "", nil, false
+	}
+
+	// The query runs again, so only a single statement that reads rows is
+	// exported; repeating writes would repeat their effects
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	statements := sqlparse.Split(m.lastQuery, dialect)
+	if len(statements) != 1 || sqlparse.Classify(statements[0].Text, dialect) != sqlparse.KindQuery {
+		m.statusMessage = "Only a single query returning rows can be exported"
+		m.isError = true
+		return "", nil, false
+	}
+	return statements[0].Text, streamer, true
+}
+
+// maskRules returns the active connection's column masking rules
+func (m *Model) maskRules() []config.MaskRule {
+	if conn := m.config.GetActiveConnection(); conn != nil {
+		return conn.MaskColumns
+	}
+	return nil
+}
+
+// ShowExportMenu offers the export formats, with masked variants when the
+// connection has mask_columns rules, and the session log as Markdown
+func (m *Model) ShowExportMenu() {
+	if m.viewerPath != "" {
+		m.exportOptions = append(m.chartExportOptions(), viewerExportOptions()...)
+		labels := make([]string, len(m.exportOptions))
+		for i, opt := range m.exportOptions {
+			labels[i] = opt.label
+		}
+		m.exportMenu.Show("📤 Export the shown rows", labels)
+		m.state = StateExportMenu
+		return
+	}
+
+	charts := m.chartExportOptions()
+	_, _, queryOK := m.exportQuery()
+	if !queryOK && len(m.session) == 0 && len(charts) == 0 {
+		return
+	}
+
+	m.exportOptions = charts
+	if queryOK {
+		rules := m.maskRules()
+		if err := export.ValidateMask(rules); err != nil {
+			m.statusMessage = "Invalid mask_columns: " + err.Error()
+			m.isError = true
+			return
+		}
+		m.exportOptions = append(m.exportOptions,
+			exportOption{label: "📄 CSV", format: export.CSV},
+			exportOption{label: "🧾 JSON", format: export.JSON},
+		)
+		if len(rules) > 0 {
+			m.exportOptions = append(m.exportOptions,
+				exportOption{label: "🕶  CSV with masked columns", format: export.CSV, masked: true},
+				exportOption{label: "🕶  JSON with masked columns", format: export.JSON, masked: true},
+			)
+		}
+	}
+	if len(m.session) > 0 {
+		m.exportOptions = append(m.exportOptions, exportOption{
+			label:   fmt.Sprintf("📝 Session as Markdown (%d queries)", len(m.session)),
+			session: true,
+		})
+	}
+	labels := make([]string, len(m.exportOptions))
+	for i, opt := range m.exportOptions {
+		labels[i] = opt.label
+	}
+	title := "📤 Export all rows of the last query"
+	switch {
+	case len(charts) > 0:
+		title = "📤 Export"
+	case !queryOK:
+		title = "📤 Export the session"
+	}
+	m.exportMenu.Show(title, labels)
+	m.state = StateExportMenu
+}
+
+// runExportMenuAction starts the export chosen in the menu
+func (m *Model) runExportMenuAction() tea.Cmd {
+	index := m.exportMenu.Selected()
+	m.exportMenu.Hide()
+	m.state = StateNormal
+	if index < 0 || index >= len(m.exportOptions) {
+		return nil
+	}
+	opt := m.exportOptions[index]
+	if opt.session {
+		m.ExportSession()
+		return nil
+	}
+	if opt.shown {
+		m.ExportShown(opt.format)
+		return nil
+	}
+	if opt.chart != "" {
+		m.ExportChart(opt.chart)
+		return nil
+	}
+	var rules []config.MaskRule
+	if opt.masked {
+		rules = m.maskRules()
+	}
+	return m.ExportResults(opt.format, rules)
+}
+
+// ExportResults re-runs the last query and streams every row to a file in
+// the current directory, without the result limit of the grid. Columns
+// matching rules are masked.
+func (m *Model) ExportResults(format export.Format, rules []config.MaskRule) tea.Cmd {
+	sql, streamer, ok := m.exportQuery()
+	if !ok {
+		return nil
+	}
+
+	path := fmt.Sprintf("sqdesk-export-%s.%s", time.Now().Format("20060102-150405"), format)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	m.statusMessage = "Exporting to " + path + "... (Esc to cancel)"
+	m.isError = false
+	return tea.Batch(exportFile(ctx, streamer, sql, path, format, rules), m.results.StartRunning("Exporting"))
+}
+
+// exportFile streams the rows of sql into a file at path
+func exportFile(ctx context.Context, streamer db.RowStreamer, sql, path string, format export.Format, rules []config.MaskRule) tea.Cmd {
+	return func() tea.Msg {
+		msg := exportDoneMsg{path: path}
+		start := time.Now()
+		msg.rows, msg.masked, msg.err = writeExport(ctx, streamer, sql, path, format, rules)
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		if msg.err != nil {
+			// Don't leave a partial file that looks complete
+			os.Remove(path)
+		}
+		return msg
+	}
+}
+
+// writeExport writes the rows of sql to path and returns how many rows and
+// masked columns there were
+func writeExport(ctx context.Context, streamer db.RowStreamer, sql, path string, format export.Format, rules []config.MaskRule) (int64, int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	result, err := export.Write(ctx, streamer, sql, f, export.Options{Format: format, Mask: rules})
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	return result.Rows, result.MaskedColumns, err
+}
+
+// handleExportDone reports a finished export
+func (m *Model) handleExportDone(msg exportDoneMsg) {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+	elapsed := msg.elapsed.Round(time.Millisecond)
+
+	switch {
+	case msg.cancelled:
+		m.statusMessage = "Export cancelled"
+		m.isError = true
+	case msg.err != nil:
+		m.statusMessage = "Export failed: " + msg.err.Error()
+		m.isError = true
+	case msg.masked > 0:
+		m.statusMessage = fmt.Sprintf("Exported %d rows to %s in %s, %d columns masked", msg.rows, msg.path, elapsed, msg.masked)
+		m.isError = false
+	default:
+		m.statusMessage = fmt.Sprintf("Exported %d rows to %s in %s", msg.rows, msg.path, elapsed)
+		m.isError = false
+	}
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/favorites"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// favoritePreviewLines is how much of the selected query the favorites
+// browser shows
+const favoritePreviewLines = 6
+
+// favoriteKey identifies a favorite in the browser: its name and scope
+func favoriteKey(f favorites.Favorite) string {
+	return f.Name + "\x00" + f.Connection
+}
+
+// splitFavoriteKey is the reverse of favoriteKey
+func splitFavoriteKey(key string) (name, connection string) {
+	name, connection, _ = strings.Cut(key, "\x00")
+	return name, connection
+}
+
+// PromptSaveFavorite asks for the name and #tags to save the editor text
+// under. The favorite belongs to the active connection; Ctrl+G in the
+// browser offers it on every connection.
+func (m *Model) PromptSaveFavorite() {
+	if m.favorites == nil {
+		m.statusMessage = "Favorites are not available"
+		m.isError = true
+		return
+	}
+	query := strings.TrimSpace(m.editor.GetValue())
+	if query == "" {
+		m.statusMessage = "Nothing to save; the editor is empty"
+		m.isError = true
+		return
+	}
+
+	connection, driver := m.activeNames()
+	value := m.favoriteName
+	if tags := sqlparse.Tags(query, sqlparse.DialectFor(driver)); len(tags) > 0 {
+		value = strings.TrimSpace(value + " " + sqlparse.FormatTags(tags))
+	}
+	message := "Name and optional #tags, e.g. monthly revenue #billing"
+	if connection != "" {
+		message += " (saved for " + connection + ")"
+	}
+	m.askInput("⭐ Save query", message, value, func(text string) tea.Cmd {
+		var name, tags []string
+		for _, word := range strings.Fields(text) {
+			if strings.HasPrefix(word, "#") {
+				tags = append(tags, word)
+			} else {
+				name = append(name, word)
+			}
+		}
+		fav := favorites.Favorite{
+			Name:       strings.Join(name, " "),
+			Query:      query,
+			Tags:       sqlparse.ParseTags(strings.Join(tags, " ")),
+			Connection: connection,
+			Saved:      time.Now(),
+		}
+		if err := m.favorites.Save(fav); err != nil {
+			m.statusMessage = "Failed to save favorite: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		m.favoriteName = fav.Name
+		m.statusMessage = "Saved favorite " + fav.Name
+		m.isError = false
+		return nil
+	})
+}
+
+// ShowFavorites opens the browser of favorites saved for the active
+// connection or for all of them
+func (m *Model) ShowFavorites() {
+	if m.favorites == nil {
+		m.statusMessage = "Favorites are not available"
+		m.isError = true
+		return
+	}
+
+	connection, _ := m.activeNames()
+	favs, err := m.favorites.List(connection)
+	if err != nil {
+		m.statusMessage = "Failed to read favorites: " + err.Error()
+		m.isError = true
+		return
+	}
+	if len(favs) == 0 {
+		m.statusMessage = "No favorites yet; press Ctrl+S to save the editor text as one"
+		m.isError = false
+		return
+	}
+
+	items := make([]components.VariableItem, len(favs))
+	for i, f := range favs {
+		scope := "all connections"
+		if f.Connection != "" {
+			scope = f.Connection
+		}
+		value := scope + " · " + strings.Join(strings.Fields(f.Query), " ")
+		if len(f.Tags) > 0 {
+			value = sqlparse.FormatTags(f.Tags) + " · " + value
+		}
+		items[i] = components.VariableItem{
+			Name:        f.Name,
+			Value:       value,
+			Description: f.Query,
+			Key:         favoriteKey(f),
+			Tags:        f.Tags,
+		}
+	}
+
+	m.favoriteBrowser.Show("⭐ Favorites", items)
+	m.state = StateFavorites
+}
+
+// loadFavorite replaces the editor text with the selected favorite
+func (m *Model) loadFavorite() bool {
+	item, ok := m.favoriteBrowser.Selected()
+	if !ok {
+		return false
+	}
+	m.favoriteBrowser.Hide()
+	m.state = StateNormal
+	m.editor.SetValue(item.Description)
+	m.favoriteName = item.Name
+	m.FocusEditor()
+	return true
+}
+
+// toggleFavoriteScope offers the selected favorite on every connection or
+// only on the active one, and reopens the browser
+func (m *Model) toggleFavoriteScope() {
+	item, ok := m.favoriteBrowser.Selected()
+	if !ok {
+		return
+	}
+	name, from := splitFavoriteKey(item.Key)
+	to, _ := m.activeNames()
+	if from != "" {
+		to = ""
+	} else if to == "" {
+		m.favoriteBrowser.SetStatus("Connect first to keep the favorite to one connection")
+		return
+	}
+	if err := m.favorites.Rescope(name, from, to); err != nil {
+		m.favoriteBrowser.SetStatus("Failed to change the favorite: " + err.Error())
+		return
+	}
+	m.ShowFavorites()
+	if to == "" {
+		m.favoriteBrowser.SetStatus(name + " is offered on all connections")
+	} else {
+		m.favoriteBrowser.SetStatus(name + " is offered on " + to + " only")
+	}
+}
+
+// confirmDeleteFavorite removes the selected favorite once confirmed
+func (m *Model) confirmDeleteFavorite() {
+	item, ok := m.favoriteBrowser.Selected()
+	if !ok {
+		return
+	}
+	m.favoriteBrowser.Hide()
+	m.askConfirm("🗑  Delete favorite", "Delete the favorite "+item.Name+"?", func() tea.Cmd {
+		name, connection := splitFavoriteKey(item.Key)
+		if err := m.favorites.Delete(name, connection); err != nil {
+			m.statusMessage = "Failed to delete favorite: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		if name == m.favoriteName {
+			m.favoriteName = ""
+		}
+		m.ShowFavorites()
+		m.favoriteBrowser.SetStatus("Deleted favorite " + name)
+		m.statusMessage = "Deleted favorite " + name
+		m.isError = false
+		return nil
+	})
+}
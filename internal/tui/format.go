@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// formatTimeout bounds an external formatter
+const formatTimeout = 10 * time.Second
+
+// formattedMsg carries the output of the external formatter for text
+type formattedMsg struct {
+	text      string
+	formatted string
+	selection bool
+	err       error
+}
+
+// FormatSQL pretty-prints the selection, or the whole editor text, with
+// the built-in formatter or the format.command of the config
+func (m *Model) FormatSQL() tea.Cmd {
+	selection := m.editor.HasSelection()
+	text := m.editor.GetValue()
+	if selection {
+		text = m.editor.GetSelectedText()
+	}
+	if strings.TrimSpace(text) == "" {
+		m.statusMessage = "Nothing to format"
+		m.isError = false
+		return nil
+	}
+
+	if command := m.config.Format.Command; command != "" {
+		m.statusMessage = "Formatting..."
+		m.isError = false
+		return func() tea.Msg {
+			formatted, err := runFormatter(command, text)
+			return formattedMsg{text: text, formatted: formatted, selection: selection, err: err}
+		}
+	}
+
+	formatted := sqlparse.Format(text, m.lintDialect(), m.formatOptions())
+	m.applyFormat(text, formatted, selection)
+	return nil
+}
+
+// formatOptions returns the built-in formatter options of the config
+func (m *Model) formatOptions() sqlparse.FormatOptions {
+	cfg := m.config.Format
+	opts := sqlparse.FormatOptions{CommaFirst: cfg.CommaFirst}
+	switch strings.ToLower(cfg.KeywordCase) {
+	case "lower":
+		opts.KeywordCase = sqlparse.KeywordsLower
+	case "preserve":
+		opts.KeywordCase = sqlparse.KeywordsPreserve
+	}
+	if cfg.Indent > 0 {
+		opts.Indent = strings.Repeat(" ", cfg.Indent)
+	}
+	return opts
+}
+
+// runFormatter pipes text through the external formatter command
+func runFormatter(command, text string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), formatTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = strings.NewReader(text)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, firstLine(msg))
+		}
+		return "", err
+	}
+	formatted := strings.TrimRight(stdout.String(), "\n")
+	if strings.TrimSpace(formatted) == "" {
+		return "", fmt.Errorf("%s wrote nothing", command)
+	}
+	return formatted, nil
+}
+
+// handleFormatted applies the output of the external formatter, unless
+// the text it formatted was edited meanwhile
+func (m *Model) handleFormatted(msg formattedMsg) {
+	if msg.err != nil {
+		m.statusMessage = "Format failed: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+	current := m.editor.GetValue()
+	if msg.selection {
+		current = m.editor.GetSelectedText()
+	}
+	if m.editor.HasSelection() != msg.selection || current != msg.text {
+		m.statusMessage = "The query changed while formatting; press Alt+Shift+F again"
+		m.isError = true
+		return
+	}
+	m.applyFormat(msg.text, msg.formatted, msg.selection)
+}
+
+// applyFormat replaces text, the selection or the editor text, with its
+// formatted version
+func (m *Model) applyFormat(text, formatted string, selection bool) {
+	m.isError = false
+	if formatted == text {
+		m.statusMessage = "Already formatted"
+		return
+	}
+	m.editor.ReplaceSelection(formatted)
+	if selection {
+		m.statusMessage = "Formatted the selection"
+	} else {
+		m.statusMessage = "Formatted the query"
+	}
+}
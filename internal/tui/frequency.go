@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// frequencyTop is how many of the most common values are listed
+const frequencyTop = 20
+
+// ShowColumnFrequency counts the values of the selected results column over
+// the fetched rows and lists the most common ones, like
+// SELECT col, COUNT(*) ... GROUP BY col ORDER BY 2 DESC
+func (m *Model) ShowColumnFrequency() {
+	set := m.results.ActiveResult()
+	col := m.results.SelectedColumn()
+	if len(set.Rows) == 0 || col >= len(set.Columns) {
+		m.statusMessage = "No result values to count"
+		m.isError = true
+		return
+	}
+
+	type bucket struct {
+		value string
+		count int
+	}
+	index := make(map[string]int)
+	var buckets []bucket
+	nulls := 0
+	for _, row := range set.Rows {
+		v := row[set.Columns[col]]
+		if v.Null {
+			nulls++
+			continue
+		}
+		text := components.CellText(v, set.ColumnTypes[col])
+		if i, ok := index[text]; ok {
+			buckets[i].count++
+			continue
+		}
+		index[text] = len(buckets)
+		buckets = append(buckets, bucket{value: text, count: 1})
+	}
+	// Most common first; ties keep the order values were first seen in
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].count > buckets[j].count
+	})
+
+	total := len(set.Rows)
+	percent := func(n int) string {
+		return fmt.Sprintf("%d (%.1f%%)", n, float64(n)*100/float64(total))
+	}
+	rowsLabel := fmt.Sprintf("%d", total)
+	if set.Truncated {
+		rowsLabel += " fetched (result was truncated)"
+	}
+	sections := []components.InfoSection{{
+		Title: "Summary",
+		Rows: []components.InfoRow{
+			{Label: "Rows", Value: rowsLabel},
+			{Label: "Distinct values", Value: fmt.Sprintf("%d", len(buckets))},
+			{Label: "NULL", Value: percent(nulls)},
+		},
+	}}
+
+	top := buckets
+	title := "Most common values"
+	if len(top) > frequencyTop {
+		top = top[:frequencyTop]
+		title = fmt.Sprintf("Top %d of %d values", frequencyTop, len(buckets))
+	}
+	rows := make([]components.InfoRow, len(top))
+	for i, b := range top {
+		label := b.value
+		if label == "" {
+			label = "(empty)"
+		}
+		rows[i] = components.InfoRow{Label: label, Value: percent(b.count)}
+	}
+	if len(rows) > 0 {
+		sections = append(sections, components.InfoSection{Title: title, Rows: rows})
+	}
+
+	m.infoPanel.Show("📊 "+set.Columns[col], sections)
+	m.state = StateInfo
+}
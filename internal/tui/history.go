@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/completion/sources"
+	"github.com/febritecno/sqdesk-cli/internal/queryhistory"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// historyPreviewLines is how much of the selected query the history
+// browser shows
+const historyPreviewLines = 6
+
+// historySavedMsg reports a query appended to the history file
+type historySavedMsg struct {
+	err error
+}
+
+// historyController keeps past queries: in completion, and in the history
+// file when query_history is on. Its Update reports a history file that
+// can't be written; saving is otherwise silent.
+type historyController struct {
+	// store is nil when query_history is off
+	store  *queryhistory.Store
+	source *sources.HistorySource
+}
+
+// Update handles the outcome of appending to the history file
+func (c historyController) Update(msg tea.Msg) (historyController, tea.Cmd) {
+	if msg, ok := msg.(historySavedMsg); ok && msg.err != nil {
+		status := statusMsg{text: "Saving query history failed: " + msg.err.Error(), isError: true}
+		return c, func() tea.Msg { return status }
+	}
+	return c, nil
+}
+
+// load opens the history file keeping limit queries, if any, and feeds
+// its queries to completion, oldest first so the newest rank first
+func (c *historyController) load(limit int) {
+	if limit == 0 {
+		return
+	}
+	store, err := queryhistory.NewStore(limit)
+	if err != nil {
+		return
+	}
+	c.store = store
+
+	entries, err := store.List()
+	if err != nil {
+		return
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		c.source.AddQuery(entries[i].Query)
+	}
+}
+
+// record offers a finished query to completion and appends it to the
+// history file in the background
+func (c *historyController) record(entry queryhistory.Entry) tea.Cmd {
+	c.source.AddQuery(entry.Query)
+	if c.store == nil || strings.TrimSpace(entry.Query) == "" {
+		return nil
+	}
+
+	store := c.store
+	return func() tea.Msg {
+		return historySavedMsg{err: store.Append(entry)}
+	}
+}
+
+// saveHistory records a finished query in the history
+func (m *Model) saveHistory(msg queryDoneMsg) tea.Cmd {
+	entry := queryhistory.Entry{
+		Time:       time.Now().Add(-msg.elapsed),
+		Query:      msg.sql,
+		DurationMs: msg.elapsed.Milliseconds(),
+		Affected:   msg.affected,
+	}
+	entry.Connection, entry.Driver = m.activeNames()
+	for _, set := range msg.sets {
+		entry.Rows += len(set.Rows)
+	}
+	switch {
+	case msg.cancelled:
+		entry.Error = "cancelled"
+	case msg.err != nil:
+		entry.Error = firstLine(msg.err.Error())
+	}
+	return m.history.record(entry)
+}
+
+// ShowHistory opens the browser of past queries, newest first. A query run
+// several times is listed once, at its last run.
+func (m *Model) ShowHistory() {
+	if m.history.store == nil {
+		m.statusMessage = "Query history is off; set query_history in the config to keep it"
+		m.isError = false
+		return
+	}
+
+	entries, err := m.history.store.List()
+	if err != nil {
+		m.statusMessage = "Failed to read query history: " + err.Error()
+		m.isError = true
+		return
+	}
+
+	seen := make(map[string]bool)
+	var items []components.VariableItem
+	for _, e := range entries {
+		query := strings.TrimSpace(e.Query)
+		if seen[query] {
+			continue
+		}
+		seen[query] = true
+
+		name := e.Time.Format("2006-01-02 15:04")
+		if e.Connection != "" {
+			name += "  " + e.Connection
+		}
+		items = append(items, components.VariableItem{
+			Name:        name,
+			Value:       historySummary(e) + " · " + strings.Join(strings.Fields(query), " "),
+			Description: query,
+		})
+	}
+	if len(items) == 0 {
+		m.statusMessage = "No queries in the history yet"
+		m.isError = false
+		return
+	}
+
+	m.historyBrowser.Show("📜 Query History", items)
+	m.state = StateHistory
+}
+
+// historySummary is the outcome of a history entry: "342 rows in 1.27s",
+// "3 rows affected in 80ms" or the error
+func historySummary(e queryhistory.Entry) string {
+	elapsed := components.FormatElapsed(time.Duration(e.DurationMs) * time.Millisecond)
+	switch {
+	case e.Error != "":
+		return "✗ " + e.Error
+	case e.Affected > 0 && e.Rows == 0:
+		return fmt.Sprintf("%d rows affected in %s", e.Affected, elapsed)
+	}
+	return fmt.Sprintf("%d rows in %s", e.Rows, elapsed)
+}
+
+// loadHistoryQuery replaces the editor text with the selected query of
+// the history browser
+func (m *Model) loadHistoryQuery() bool {
+	item, ok := m.historyBrowser.Selected()
+	if !ok {
+		return false
+	}
+	m.historyBrowser.Hide()
+	m.state = StateNormal
+	m.editor.SetValue(item.Description)
+	m.FocusEditor()
+	return true
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// introspectDoneMsg carries the outcome of a background read of metadata:
+// table columns, DDL, triggers and the like
+type introspectDoneMsg struct {
+	show      func(err error) tea.Cmd
+	cancelled bool
+	err       error
+}
+
+// introspect reads metadata with read in the background and passes its
+// error to show once it finished, so a slow server doesn't freeze the UI.
+// The read counts as a running query: Esc stops waiting for it and nothing
+// else uses the connection meanwhile.
+func (m *Model) introspect(label string, read func(connector db.Connector) error, show func(err error) tea.Cmd) tea.Cmd {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return nil
+	}
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil
+	}
+	connector := m.conn.connector
+	timeout := m.config.QueryTimeoutFor(m.config.GetActiveConnection())
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	m.statusMessage = label + "... (Esc to cancel)"
+	m.isError = false
+
+	return func() tea.Msg {
+		msg := introspectDoneMsg{show: show}
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			// The metadata methods take no context: stop waiting when
+			// cancelled and leave the read to finish on its own
+			done := make(chan error, 1)
+			go func() { done <- read(connector) }()
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+}
+
+// handleIntrospectDone shows a finished metadata read
+func (m *Model) handleIntrospectDone(msg introspectDoneMsg) tea.Cmd {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	if msg.cancelled {
+		m.statusMessage = "Cancelled"
+		m.isError = true
+		return nil
+	}
+	m.statusMessage = ""
+	m.isError = false
+	return msg.show(msg.err)
+}
+
+// databasesLoadedMsg carries the databases read for the sidebar
+type databasesLoadedMsg struct {
+	databases []string
+	currentDB string
+	err       error
+}
+
+// loadDatabases reads the databases of the server in the background
+func loadDatabases(connector db.Connector) tea.Cmd {
+	return func() tea.Msg {
+		msg := databasesLoadedMsg{}
+		if msg.databases, msg.err = connector.GetDatabases(); msg.err == nil {
+			msg.currentDB = connector.GetDatabaseName()
+		}
+		return msg
+	}
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// fdwHint shows how to reach another Postgres database when no foreign
+// server is configured yet
+const fdwHint = `No foreign servers configured. To query another database:
+
+CREATE EXTENSION postgres_fdw;
+CREATE SERVER other FOREIGN DATA WRAPPER postgres_fdw
+  OPTIONS (host 'localhost', dbname 'other');
+CREATE USER MAPPING FOR CURRENT_USER SERVER other
+  OPTIONS (user 'me', password '...');
+IMPORT FOREIGN SCHEMA public FROM SERVER other INTO public;`
+
+// ShowLinkedDatabases opens the info panel listing the databases reachable
+// from this session: SQLite attachments or Postgres foreign servers
+func (m *Model) ShowLinkedDatabases() {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+
+	var sections []components.InfoSection
+	if attacher, ok := db.GetDatabaseAttacher(m.conn.connector); ok {
+		attached, err := attacher.AttachedDatabases(context.Background())
+		if err != nil {
+			m.statusMessage = err.Error()
+			m.isError = true
+			return
+		}
+		for _, a := range attached {
+			rows := []components.InfoRow{{Label: "File", Value: a.File}}
+			for _, table := range a.Tables {
+				rows = append(rows, components.InfoRow{Label: "Table", Value: a.Name + "." + table})
+			}
+			sections = append(sections, components.InfoSection{Title: a.Name, Rows: rows})
+		}
+		if len(sections) == 0 {
+			sections = append(sections, components.InfoSection{Text: "No databases attached. Press a in Databases to attach a file, then query its tables as name.table."})
+		}
+	} else if lister, ok := db.GetForeignServerLister(m.conn.connector); ok {
+		servers, err := lister.GetForeignServers()
+		if err != nil {
+			m.statusMessage = err.Error()
+			m.isError = true
+			return
+		}
+		for _, s := range servers {
+			rows := []components.InfoRow{{Label: "Wrapper", Value: s.Wrapper}}
+			if s.Options != "" {
+				rows = append(rows, components.InfoRow{Label: "Options", Value: s.Options})
+			}
+			for _, t := range s.Tables {
+				rows = append(rows, components.InfoRow{Label: t.Schema + "." + t.Name, Value: t.Options})
+			}
+			sections = append(sections, components.InfoSection{Title: s.Name, Rows: rows})
+		}
+		if len(sections) == 0 {
+			sections = append(sections, components.InfoSection{Text: fdwHint})
+		}
+	} else {
+		m.statusMessage = "Linked databases are not supported for " + m.conn.connector.GetDriverName()
+		m.isError = true
+		return
+	}
+
+	m.infoPanel.Show("🔗 Linked Databases", sections)
+	m.state = StateInfo
+}
+
+// PromptAttach asks for a database file to attach to the session
+func (m *Model) PromptAttach() {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+	if _, ok := db.GetDatabaseAttacher(m.conn.connector); !ok {
+		m.statusMessage = "Attaching databases is not supported for " + m.conn.connector.GetDriverName()
+		m.isError = true
+		return
+	}
+	m.askInput("📎 Attach Database", "File path, optionally followed by AS name", "", m.attachDatabase)
+}
+
+// attachDatabase attaches the file of "path [AS name]"
+func (m *Model) attachDatabase(value string) tea.Cmd {
+	attacher, ok := db.GetDatabaseAttacher(m.conn.connector)
+	if !ok {
+		return nil
+	}
+	if m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return nil
+	}
+	file, name := parseAttach(value)
+	if file == "" {
+		return nil
+	}
+
+	if err := attacher.AttachDatabase(context.Background(), file, name); err != nil {
+		m.statusMessage = err.Error()
+		m.isError = true
+		return nil
+	}
+	m.statusMessage = fmt.Sprintf("Attached %s as %s; query its tables as %s.table", file, name, name)
+	m.isError = false
+	return m.LoadDatabases()
+}
+
+// parseAttach splits "path [AS name]", naming the database after the file
+// when no name is given
+func parseAttach(value string) (file, name string) {
+	value = strings.TrimSpace(value)
+	if i := strings.LastIndex(strings.ToUpper(value), " AS "); i >= 0 {
+		return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+len(" AS "):])
+	}
+	if value == "" {
+		return "", ""
+	}
+
+	base := strings.TrimSuffix(filepath.Base(value), filepath.Ext(value))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, base)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "db_" + name
+	}
+	return value, name
+}
+
+// confirmDetach asks to detach the database selected in the sidebar
+func (m *Model) confirmDetach() {
+	attacher, ok := db.GetDatabaseAttacher(m.conn.connector)
+	if !ok {
+		return
+	}
+	name := m.sidebar.GetSelectedDatabase()
+	attached, err := attacher.AttachedDatabases(context.Background())
+	if err != nil {
+		m.statusMessage = err.Error()
+		m.isError = true
+		return
+	}
+	found := false
+	for _, a := range attached {
+		found = found || a.Name == name
+	}
+	if !found {
+		m.statusMessage = "Only attached databases can be detached"
+		m.isError = true
+		return
+	}
+
+	m.askConfirm("Detach Database", fmt.Sprintf("Detach %s from this session?", name), func() tea.Cmd {
+		if m.queryRunning {
+			m.statusMessage = "Wait for the running query to finish"
+			m.isError = true
+			return nil
+		}
+		if err := attacher.DetachDatabase(context.Background(), name); err != nil {
+			m.statusMessage = err.Error()
+			m.isError = true
+			return nil
+		}
+		m.statusMessage = "Detached " + name
+		m.isError = false
+		return m.LoadDatabases()
+	})
+}
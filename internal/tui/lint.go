@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/lint"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// lintOptions returns the linter options for the active connection and
+// schema. Invalid rule settings are reported and the defaults used.
+func (m *Model) lintOptions() lint.Options {
+	severities, err := lint.Severities(m.config.Lint.Rules)
+	if err != nil {
+		m.statusMessage = "Invalid lint config: " + err.Error()
+		m.isError = true
+	}
+	opts := lint.Options{Severities: severities}
+	if conn := m.config.GetActiveConnection(); conn != nil {
+		opts.Production = conn.Production
+	}
+	if m.schema != nil {
+		opts.Columns = columnKinds(m.schema)
+		opts.Tables = schemaTables(m.schema)
+	}
+	return opts
+}
+
+// schemaTables maps the lower-case names of the schema's tables to their
+// lower-case column names. Qualified names are also listed by their last
+// part, the way queries usually name them.
+func schemaTables(schema *db.Schema) map[string]map[string]bool {
+	tables := make(map[string]map[string]bool, len(schema.Tables))
+	for name, table := range schema.Tables {
+		columns := make(map[string]bool, len(table.Columns))
+		for _, col := range table.Columns {
+			columns[strings.ToLower(col.Name)] = true
+		}
+		name = strings.ToLower(name)
+		tables[name] = columns
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			tables[name[i+1:]] = columns
+		}
+	}
+	return tables
+}
+
+// columnKinds maps lower-case column names to their kind, leaving out
+// names whose kind differs between tables
+func columnKinds(schema *db.Schema) map[string]db.ColumnKind {
+	kinds := make(map[string]db.ColumnKind)
+	ambiguous := make(map[string]bool)
+	for _, table := range schema.Tables {
+		for _, col := range table.Columns {
+			name := strings.ToLower(col.Name)
+			kind := db.ColumnKindOf(col.Type)
+			if prev, ok := kinds[name]; ok && prev != kind {
+				ambiguous[name] = true
+			}
+			kinds[name] = kind
+		}
+	}
+	for name := range ambiguous {
+		delete(kinds, name)
+	}
+	return kinds
+}
+
+// lintDialect returns the SQL dialect of the active connection
+func (m *Model) lintDialect() sqlparse.Dialect {
+	if m.conn.connector == nil {
+		return sqlparse.DialectStandard
+	}
+	return sqlparse.DialectFor(m.conn.connector.GetDriverName())
+}
+
+// markLint flags the editor lines of findings in text, the editor's value,
+// keeping the most severe finding of each line
+func (m *Model) markLint(findings []lint.Finding, text string) {
+	markers := make(map[int]components.LineMarker)
+	worst := make(map[int]lint.Severity)
+	for _, f := range findings {
+		line := strings.Count(text[:f.Offset], "\n")
+		if f.Severity <= worst[line] {
+			continue
+		}
+		worst[line] = f.Severity
+		markers[line] = components.LineMarker{Icon: f.Severity.Icon(), Style: m.lintStyle(f.Severity), Message: f.Message}
+	}
+	m.editor.SetMarkers(markers)
+}
+
+// lintStyle returns the marker style for a severity
+func (m *Model) lintStyle(sev lint.Severity) lipgloss.Style {
+	switch sev {
+	case lint.Error:
+		return m.styles.ErrorText
+	case lint.Warning:
+		return m.styles.WarningText
+	}
+	return m.styles.InfoText
+}
+
+// LintQuery lints the editor on demand, marking findings in the gutter and
+// listing them in the results pane
+func (m *Model) LintQuery() {
+	text := m.editor.GetValue()
+	if strings.TrimSpace(text) == "" {
+		m.statusMessage = "No query to lint"
+		m.isError = true
+		return
+	}
+
+	m.isError = false
+	findings := lint.Check(text, m.lintDialect(), m.lintOptions())
+	m.markLint(findings, text)
+	if len(findings) == 0 {
+		if !m.isError {
+			m.statusMessage = "Lint: no issues found"
+		}
+		return
+	}
+
+	counts := make(map[lint.Severity]int)
+	m.results.StartLog("Lint")
+	for _, f := range findings {
+		counts[f.Severity]++
+		line := strings.Count(text[:f.Offset], "\n") + 1
+		m.results.AppendLog(fmt.Sprintf("line %-4d %s %-7s %s (%s)", line, f.Severity.Icon(), f.Severity, f.Message, f.Rule))
+	}
+
+	var parts []string
+	for _, sev := range []lint.Severity{lint.Error, lint.Warning, lint.Info} {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	m.statusMessage = "Lint: " + strings.Join(parts, ", ")
+	m.isError = counts[lint.Error] > 0
+}
+
+// guardLint checks sql before it runs. The validation rules always run
+// and mark the editor lines the server would likely reject; with linting
+// before execution on, all rules run and errors need confirmation.
+func (m *Model) guardLint(sql string, run func() tea.Cmd) tea.Cmd {
+	opts := m.lintOptions()
+	text := m.editor.GetValue()
+	if !m.config.Lint.OnExecute {
+		opts.Severities = lint.Only(opts.Severities, lint.ValidationRules...)
+		m.markLint(lint.Check(text, m.lintDialect(), opts), text)
+		return run()
+	}
+
+	m.markLint(lint.Check(text, m.lintDialect(), opts), text)
+
+	var errors []string
+	for _, f := range lint.Check(sql, m.lintDialect(), opts) {
+		if f.Severity == lint.Error {
+			errors = append(errors, "• "+f.Message)
+		}
+	}
+	if len(errors) == 0 {
+		return run()
+	}
+	m.askConfirm("⚠ Lint errors", strings.Join(errors, "\n")+"\n\nRun anyway?", run)
+	return nil
+}
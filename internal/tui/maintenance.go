@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// maintenanceDoneMsg is sent when a maintenance action finishes
+type maintenanceDoneMsg struct {
+	table  string
+	action string
+	lines  []string
+	err    error
+}
+
+// maintenanceTickMsg triggers a progress poll while maintenance runs
+type maintenanceTickMsg struct{}
+
+// maintenanceProgressMsg carries a progress snapshot from the server
+type maintenanceProgressMsg struct {
+	progress string
+}
+
+// confirmMaintenance asks for confirmation before running a maintenance action
+func (m *Model) confirmMaintenance(action db.MaintenanceAction, table string) {
+	if m.maintenanceRunning {
+		m.statusMessage = "A maintenance action is already running"
+		m.isError = true
+		return
+	}
+	if m.readOnly() {
+		m.statusMessage = "Maintenance is disabled on read-only connections"
+		m.isError = true
+		return
+	}
+
+	sql := action.SQL(m.quoteIdent(table))
+	message := fmt.Sprintf("%s\n\n  %s", action.Description, sql)
+	m.askConfirm("🔧 Run "+action.Name+" on "+table+"?", message, func() tea.Cmd {
+		return m.guardProtected(m.protectedTablesIn(sql), func() tea.Cmd {
+			return m.startMaintenance(action, table)
+		})
+	})
+}
+
+// startMaintenance runs a maintenance action in the background, streaming
+// progress to the results log and adding the server's output when it ends.
+// It waits for a running query and refuses inside a transaction, where
+// VACUUM can't run and a lock would be held until COMMIT.
+func (m *Model) startMaintenance(action db.MaintenanceAction, table string) tea.Cmd {
+	mt, ok := m.conn.connector.(db.Maintainer)
+	if !ok {
+		m.statusMessage = "Maintenance is not supported by this driver"
+		m.isError = true
+		return nil
+	}
+	if m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return nil
+	}
+	if m.txOpen() {
+		m.statusMessage = "Commit or roll back the open transaction first"
+		m.isError = true
+		return nil
+	}
+
+	m.maintenanceRunning = true
+	m.maintenanceStart = time.Now()
+	m.maintenanceTable = table
+	m.maintenanceProgress = ""
+
+	m.results.StartLog(action.Name + " " + table)
+	m.results.AppendLog(fmt.Sprintf("[%s] %s", m.maintenanceStart.Format("15:04:05"), action.SQL(m.quoteIdent(table))))
+	m.statusMessage = "Running " + action.Name + " on " + table + "..."
+	m.isError = false
+
+	run := func() tea.Msg {
+		lines, err := mt.RunMaintenance(action, table)
+		return maintenanceDoneMsg{table: table, action: action.Name, lines: lines, err: err}
+	}
+	return tea.Batch(run, maintenanceTick())
+}
+
+// maintenanceTick schedules the next progress poll
+func maintenanceTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return maintenanceTickMsg{}
+	})
+}
+
+// pollMaintenanceProgress queries progress from a separate session
+func (m *Model) pollMaintenanceProgress() tea.Cmd {
+	reporter, ok := m.conn.connector.(db.MaintenanceProgressReporter)
+	if !ok {
+		return nil
+	}
+	table := m.maintenanceTable
+	return func() tea.Msg {
+		progress, err := reporter.MaintenanceProgress(table)
+		if err != nil {
+			return nil
+		}
+		return maintenanceProgressMsg{progress: progress}
+	}
+}
+
+// handleMaintenanceMsg handles messages from a running maintenance action
+func (m *Model) handleMaintenanceMsg(msg tea.Msg) tea.Cmd {
+	elapsed := func() string {
+		return fmt.Sprintf("%5.1fs", time.Since(m.maintenanceStart).Seconds())
+	}
+
+	switch msg := msg.(type) {
+	case maintenanceTickMsg:
+		if !m.maintenanceRunning {
+			return nil
+		}
+		return tea.Batch(m.pollMaintenanceProgress(), maintenanceTick())
+
+	case maintenanceProgressMsg:
+		if m.maintenanceRunning && msg.progress != m.maintenanceProgress {
+			m.maintenanceProgress = msg.progress
+			m.results.AppendLog(fmt.Sprintf("[%s] %s", elapsed(), msg.progress))
+		}
+
+	case maintenanceDoneMsg:
+		m.maintenanceRunning = false
+		for _, line := range msg.lines {
+			m.results.AppendLog(fmt.Sprintf("[%s] %s", elapsed(), line))
+		}
+		if msg.err != nil {
+			m.results.AppendLog(fmt.Sprintf("[%s] ERROR: %v", elapsed(), msg.err))
+			m.statusMessage = msg.action + " failed on " + msg.table
+			m.isError = true
+		} else {
+			m.results.AppendLog(fmt.Sprintf("[%s] %s finished", elapsed(), msg.action))
+			m.statusMessage = fmt.Sprintf("%s on %s finished in %s", msg.action, msg.table, time.Since(m.maintenanceStart).Round(time.Millisecond))
+			m.isError = false
+		}
+	}
+	return nil
+}
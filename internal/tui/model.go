@@ -1,17 +1,28 @@
 package tui
 
 import (
+	"context"
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/febritecno/sqdesk-cli/internal/ai"
+	"github.com/febritecno/sqdesk-cli/internal/buffers"
 	"github.com/febritecno/sqdesk-cli/internal/completion"
 	"github.com/febritecno/sqdesk-cli/internal/completion/sources"
 	"github.com/febritecno/sqdesk-cli/internal/config"
 	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/favorites"
+	"github.com/febritecno/sqdesk-cli/internal/hooks"
+	"github.com/febritecno/sqdesk-cli/internal/metrics"
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+	"github.com/febritecno/sqdesk-cli/internal/snippets"
+	"github.com/febritecno/sqdesk-cli/internal/snapshot"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
 	"github.com/febritecno/sqdesk-cli/internal/tui/components"
 	"github.com/febritecno/sqdesk-cli/internal/tui/setup"
 )
@@ -34,6 +45,25 @@ const (
 	StateAIPrompt
 	StateSettings
 	StateConnModal
+	StateInfo
+	StateTableMenu
+	StateConfirm
+	StateVariables
+	StatePasswordPrompt
+	StateTxMenu
+	StateParamPrompt
+	StateSnapshots
+	StateInput
+	StateColumnPicker
+	StateExportMenu
+	StateRowForm
+	StateSnippets
+	StateTimeMenu
+	StateDatePicker
+	StateImportPreview
+	StateCellDetail
+	StateHistory
+	StateFavorites
 )
 
 // Model is the main application model
@@ -42,13 +72,38 @@ type Model struct {
 	config *config.Config
 	styles *Styles
 
-	// Database
-	connector db.Connector
-	schema    *db.Schema
-	tables    []string
-
-	// AI
-	aiProvider ai.Provider
+	// Database: the active connection and the attempts to open one
+	conn       connectionController
+	schema     *db.Schema
+	tables     []string
+	partitions map[string]*db.PartitionInfo
+	views      []db.View
+
+	// Replication
+	replication    []db.ReplicaStatus
+	replicationErr error
+
+	// AI requests
+	ai aiController
+
+	// Query metrics export
+	metrics metrics.Recorder
+	// Query completion hooks
+	hooks *hooks.Runner
+	// unfocused is set while the terminal reports it lost focus
+	unfocused bool
+	// Saved result snapshots; nil when the directory can't be resolved
+	snapshots *snapshot.Store
+	// Past queries of all sessions, in the history file and completion
+	history historyController
+	// Saved queries; favoriteName is the one last loaded or saved, offered
+	// as the name when saving again
+	favorites    *favorites.Store
+	favoriteName string
+	// Editor text and undo history of each connection; nil when
+	// undo_history is off. bufferConn is the connection the editor holds.
+	buffers    *buffers.Store
+	bufferConn string
 
 	// UI Components
 	sidebar    components.Sidebar
@@ -57,29 +112,120 @@ type Model struct {
 	aiPrompt   components.AIPrompt
 	settings   components.Settings
 	connModal  components.ConnectionModal
+	infoPanel  components.InfoPanel
+	tableMenu  components.ActionMenu
+	txMenu     components.ActionMenu
+	exportMenu components.ActionMenu
+	// exportOptions are the entries of exportMenu
+	exportOptions []exportOption
+	confirm    components.ConfirmModal
+	variables  components.VariablesBrowser
+	// snapshotBrowser lists saved result snapshots
+	snapshotBrowser components.VariablesBrowser
+	// columnPicker picks the result column to insert as an IN list
+	columnPicker components.VariablesBrowser
+	// snippetBrowser lists the snippets of the shared library
+	snippetBrowser components.VariablesBrowser
+	// historyBrowser lists the queries of history
+	historyBrowser components.VariablesBrowser
+	// favoriteBrowser lists the saved queries of favorites
+	favoriteBrowser components.VariablesBrowser
+	// timeMenu lists the time filter presets
+	timeMenu components.ActionMenu
+	// datePicker picks the days of a time filter range for pendingDate
+	datePicker  components.DatePicker
+	pendingDate func(time.Time) tea.Cmd
+	// timeColumn is the column of the last time filter
+	timeColumn string
+	// importPreview shows the columns of csvImport before it runs
+	importPreview components.ImportPreview
+	csvImport     *csvImport
+	// cellDetail is the results cell shown in infoPanel
+	cellDetail *cellDetail
+	password   components.PasswordPrompt
+	params     components.ParamPrompt
+	// rowForm asks for the values of a new row of newRowTable
+	rowForm       components.ParamPrompt
+	newRowTable   string
+	newRowColumns []db.Column
+	// input asks for a line of text, e.g. a table name
+	input      components.InputPrompt
 	wizard     *setup.Wizard
 	completion components.CompletionPopup
 	help       components.Help
 
 	// Completion Engine
 	completionEngine *completion.Engine
+	keywordSource    *sources.KeywordSource
 	schemaSource     *sources.SchemaSource
-	historySource    *sources.HistorySource
+	snippetSource    *sources.SnippetSource
+	// userSnippetSource offers userSnippets, the user's own snippets kept
+	// in snippetsDir
+	userSnippetSource *sources.SnippetSource
+	userSnippets      []shared.Snippet
+	snippetsDir       string
 
 	// State
 	state       AppState
 	focusedPane Pane
 	width       int
 	height      int
+	// viewerPath is the file shown by "sqdesk view", "" in the workspace
+	viewerPath string
 	
 	// Status
 	statusMessage string
 	isError       bool
-	isConnected   bool
 	
 	// Query
 	lastQuery     string
+	// lastTiming is "342 rows in 1.27s" of the last query, kept in the
+	// status bar
+	lastTiming    string
 	queryRunning  bool
+	queryCancel   context.CancelFunc
+	// runEditorText is the editor text when the running query started;
+	// resultsEditorText that of the query whose rows are shown
+	runEditorText     string
+	resultsEditorText string
+	// streamRows is how many rows of the running streamed query are shown
+	streamRows int
+
+	// Table actions
+	menuTable      string
+	pendingConfirm func() tea.Cmd
+	pendingInput   func(string) tea.Cmd
+
+	// Maintenance
+	maintenanceRunning  bool
+	maintenanceStart    time.Time
+	maintenanceTable    string
+	maintenanceProgress string
+
+	// Notebook mode: results of each statement shown under it, by
+	// notebookKey; notebookQueue holds the rest of a run-all
+	notebook      bool
+	notebookCells map[string]*notebookCell
+	notebookQueue []string
+	notebookTotal int
+
+	// Queries run in this session, for the Markdown session export
+	session []sessionEntry
+
+	// Team library of snippets and connection templates, if configured
+	sharedLib   *shared.Library
+	syncRunning bool
+
+	// Query waiting for its values in the parameter prompt, and the values
+	// entered last, prefilled next time
+	paramQuery  *paramQuery
+	paramValues map[string]string
+}
+
+// statusMsg sets the status bar; the controllers report through it
+type statusMsg struct {
+	text    string
+	isError bool
 }
 
 // NewModel creates a new application model
@@ -89,6 +235,7 @@ func NewModel(cfg *config.Config) *Model {
 	styles := NewStyles(colors)
 
 	// Create component styles
+	borders := components.PaneBorders{Running: colors.Warning, Error: colors.Error, Stale: colors.Stale}
 	sidebarStyles := components.SidebarStyles{
 		Normal:       styles.Panel,
 		Focused:      styles.PanelFocused,
@@ -98,6 +245,7 @@ func NewModel(cfg *config.Config) *Model {
 		AddButton:    styles.AddButton,
 		ActiveConn:   styles.ActiveConn,
 		InactiveConn: styles.InactiveConn,
+		Borders:      borders,
 	}
 
 	editorStyles := components.EditorStyles{
@@ -107,11 +255,17 @@ func NewModel(cfg *config.Config) *Model {
 		LineNum:   styles.InfoText,
 		Keyword:   styles.Keyword,
 		String:    styles.String,
+		Number:    styles.Number,
+		Comment:   styles.Comment,
 		GhostText: styles.GhostText,
+		Match:     lipgloss.NewStyle().Background(colors.Selection).Foreground(colors.TextBright),
+		CurMatch:  lipgloss.NewStyle().Background(colors.Warning).Foreground(colors.Background).Bold(true),
+		Bracket:   lipgloss.NewStyle().Background(colors.Selection).Foreground(colors.Accent).Bold(true),
 		Suggestion: styles.InfoText.Copy().Foreground(lipgloss.Color("208")).Bold(true),
 		Type:       styles.Keyword.Copy().Foreground(lipgloss.Color("33")), // Blue
 		Function:   styles.Keyword.Copy().Foreground(lipgloss.Color("220")), // Yellow
 		Operator:   styles.Keyword.Copy().Foreground(lipgloss.Color("201")), // Pink
+		Borders:    borders,
 	}
 
 	resultsStyles := components.ResultsStyles{
@@ -121,8 +275,12 @@ func NewModel(cfg *config.Config) *Model {
 		Header:      styles.ResultsHeader,
 		Cell:        styles.ResultsCell,
 		SelectedRow: styles.SidebarSelected,
+		Null:        styles.ResultsNull,
+		Number:      styles.ResultsNumber,
+		Bool:        styles.ResultsBool,
 		Error:       styles.ErrorText,
 		Info:        styles.InfoText,
+		Borders:     borders,
 	}
 
 	aiPromptStyles := components.AIPromptStyles{
@@ -178,6 +336,45 @@ func NewModel(cfg *config.Config) *Model {
 		Error:    styles.ErrorText,
 	}
 
+	// Info panel styles
+	infoPanelStyles := components.InfoPanelStyles{
+		Modal:   styles.Modal,
+		Title:   styles.ModalTitle,
+		Section: styles.PanelTitle,
+		Label:   styles.InputLabel.Copy().MarginBottom(0),
+		Value:   styles.ModalContent,
+		Hint:    styles.HelpDesc,
+	}
+
+	// Table action menu styles
+	tableMenuStyles := components.ActionMenuStyles{
+		Modal:    styles.Modal,
+		Title:    styles.ModalTitle,
+		Item:     styles.Button,
+		Selected: styles.ButtonActive,
+		Hint:     styles.HelpDesc,
+	}
+
+	// Confirm modal styles
+	confirmStyles := components.ConfirmModalStyles{
+		Modal:   styles.Modal,
+		Title:   styles.ModalTitle,
+		Message: styles.ModalContent,
+		Hint:    styles.HelpDesc,
+	}
+
+	// Server variables browser styles
+	variablesStyles := components.VariablesBrowserStyles{
+		Modal:    styles.Modal,
+		Title:    styles.ModalTitle,
+		Input:    styles.Input,
+		Name:     styles.InfoText,
+		Value:    styles.ModalContent,
+		Selected: styles.ButtonActive,
+		Desc:     styles.HelpDesc,
+		Hint:     styles.HelpDesc,
+	}
+
 	// Always start in normal state (removed setup wizard)
 	state := StateNormal
 	if cfg.FirstRun {
@@ -233,10 +430,15 @@ func NewModel(cfg *config.Config) *Model {
 	compEngine := completion.NewEngine()
 	schemaSource := sources.NewSchemaSource()
 	historySource := sources.NewHistorySource()
+	keywordSource := sources.NewKeywordSource()
 	
-	compEngine.RegisterSource(sources.NewKeywordSource())
+	compEngine.RegisterSource(keywordSource)
 	compEngine.RegisterSource(schemaSource)
 	compEngine.RegisterSource(historySource)
+	snippetSource := sources.NewSnippetSource()
+	compEngine.RegisterSource(snippetSource)
+	userSnippetSource := sources.NewUserSnippetSource(snippets.Body)
+	compEngine.RegisterSource(userSnippetSource)
 
 	m := &Model{
 		config:           cfg,
@@ -249,25 +451,88 @@ func NewModel(cfg *config.Config) *Model {
 		aiPrompt:         components.NewAIPrompt(aiPromptStyles),
 		settings:         components.NewSettings(settingsStyles),
 		connModal:        components.NewConnectionModal(connModalStyles),
+		infoPanel:        components.NewInfoPanel(infoPanelStyles),
+		tableMenu:        components.NewActionMenu(tableMenuStyles),
+		txMenu:           components.NewActionMenu(tableMenuStyles),
+		exportMenu:       components.NewActionMenu(tableMenuStyles),
+		timeMenu:         components.NewActionMenu(tableMenuStyles),
+		importPreview:    components.NewImportPreview(tableMenuStyles),
+		datePicker:       components.NewDatePicker(confirmStyles),
+		confirm:          components.NewConfirmModal(confirmStyles),
+		variables:        components.NewVariablesBrowser(variablesStyles),
+		password:         components.NewPasswordPrompt(confirmStyles),
+		params:           components.NewParamPrompt(confirmStyles),
+		rowForm:          components.NewParamPrompt(confirmStyles),
+		input:            components.NewInputPrompt(confirmStyles),
 		wizard:           setup.NewWizard(wizardStyles),
 		completion:       components.NewCompletionPopup(completionStyles),
 		help:             components.NewHelp(helpStyles),
 		completionEngine: compEngine,
+		keywordSource:    keywordSource,
 		schemaSource:     schemaSource,
-		historySource:    historySource,
+		snippetSource:    snippetSource,
+		userSnippetSource: userSnippetSource,
 	}
 
 	// Initialize AI provider if configured
 	if cfg.AI.Provider != "none" && cfg.AI.Provider != "" {
 		provider, _ := ai.NewProvider(cfg.AI.Provider, cfg.AI.APIKey, cfg.AI.Model)
-		m.aiProvider = provider
+		m.ai.provider = provider
 	} else {
-		m.aiProvider = ai.NewNoopProvider()
+		m.ai.provider = ai.NewNoopProvider()
+	}
+
+	// Metrics are optional; a bad config only disables them
+	recorder, err := metrics.NewRecorder(cfg.Metrics)
+	if err != nil {
+		recorder = metrics.NewNoopRecorder()
+		m.statusMessage = "Metrics disabled: " + err.Error()
+		m.isError = true
+	}
+	m.metrics = recorder
+	m.hooks = hooks.NewRunner(cfg.Hooks)
+	if store, err := snapshot.NewStore(cfg.Snapshots); err == nil {
+		m.snapshots = store
+	}
+	m.snapshotBrowser = components.NewVariablesBrowser(variablesStyles)
+	m.snapshotBrowser.SetLabels("snapshots", "Filter snapshots, #tag for a tag...", "↑↓: navigate • Enter: open results • Ctrl+T: edit tags • Esc: close")
+	m.columnPicker = components.NewVariablesBrowser(variablesStyles)
+	m.columnPicker.SetLabels("columns", "Filter columns...", "↑↓: navigate • Enter: insert IN list • Esc: close")
+	m.snippetBrowser = components.NewVariablesBrowser(variablesStyles)
+	m.snippetBrowser.SetLabels("snippets", "Filter snippets...", "↑↓: navigate • Enter: insert • Ctrl+N: new from editor • Ctrl+E: edit • Ctrl+X: delete • Ctrl+R: sync shared • Esc: close")
+	m.snippetBrowser.SetPreview(snippetPreviewLines)
+	m.historyBrowser = components.NewVariablesBrowser(variablesStyles)
+	m.historyBrowser.SetLabels("queries", "Search queries...", "↑↓: navigate • Enter: load into editor • Alt+Enter: run • Esc: close")
+	m.historyBrowser.SetFuzzy(true)
+	m.historyBrowser.SetPreview(historyPreviewLines)
+	m.history.source = historySource
+	m.history.load(cfg.QueryHistorySize())
+	if limit := cfg.UndoHistorySize(); limit > 0 {
+		if store, err := buffers.NewStore(limit); err == nil {
+			m.buffers = store
+		}
+	}
+	if store, err := favorites.NewStore(); err == nil {
+		m.favorites = store
+	}
+	m.favoriteBrowser = components.NewVariablesBrowser(variablesStyles)
+	m.favoriteBrowser.SetLabels("favorites", "Filter favorites, #tag for a tag...", "↑↓: navigate • Enter: load into editor • Alt+Enter: run • Ctrl+G: this/all connections • Ctrl+X: delete • Esc: close")
+	m.favoriteBrowser.SetPreview(favoritePreviewLines)
+	m.results.SetHistoryLimit(cfg.ResultHistorySize())
+	m.results.SetRowNumbers(cfg.RowNumbers)
+
+	// The shared library adds read-only connections before they're listed
+	m.loadSharedLibrary()
+	if err := m.loadUserSnippets(); err != nil {
+		m.statusMessage = "Snippets not loaded: " + err.Error()
+		m.isError = true
 	}
 
 	// Load connections into sidebar
 	m.loadConnections()
 
+	m.editor.SetAutoClose(cfg.AutoClose)
+
 	// Set focus
 	m.sidebar.SetFocused(true)
 	m.editor.SetFocused(false)
@@ -284,331 +549,670 @@ func (m *Model) loadConnections() {
 			Name:   conn.Name,
 			Driver: conn.Driver,
 			Active: i == m.config.ActiveConnIndex,
+			Shared: conn.Shared,
 		}
 	}
 	m.sidebar.SetConnections(conns)
 }
 
-// Connect connects to the active database
-func (m *Model) Connect() error {
-	connCfg := m.config.GetActiveConnection()
-	if connCfg == nil {
-		return fmt.Errorf("no database connection configured")
+// LoadDatabases reloads the list of available databases in the background;
+// it arrives as a databasesLoadedMsg
+func (m *Model) LoadDatabases() tea.Cmd {
+	if m.conn.connector == nil {
+		return nil
 	}
+	return loadDatabases(m.conn.connector)
+}
 
-	// Close existing connection if any
-	if m.connector != nil {
-		m.connector.Close()
-		m.connector = nil
-		m.isConnected = false
+// ShowViewInfo opens the info panel for a view with its columns and
+// defining SQL, read in the background
+func (m *Model) ShowViewInfo(viewName string) tea.Cmd {
+	if _, ok := m.conn.connector.(db.ViewLister); !ok {
+		return nil
 	}
 
-	connector, err := db.NewConnector(connCfg)
-	if err != nil {
-		return fmt.Errorf("failed to create connector: %w", err)
+	kind := "View"
+	for _, v := range m.views {
+		if v.Name == viewName && v.Materialized {
+			kind = "Materialized view"
+		}
 	}
 
-	if err := connector.Connect(); err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+	var columns []db.Column
+	var definition string
+	read := func(connector db.Connector) error {
+		columns, _ = connector.GetColumns(viewName)
+		var err error
+		definition, err = connector.(db.ViewLister).GetViewDefinition(viewName)
+		if err != nil {
+			definition = err.Error()
+		}
+		return nil
 	}
+	return m.introspect("Reading view "+viewName, read, func(err error) tea.Cmd {
+		if err != nil {
+			m.statusMessage = err.Error()
+			m.isError = true
+			return nil
+		}
+		sections := []components.InfoSection{{
+			Title: "View",
+			Rows:  []components.InfoRow{{Label: "Kind", Value: kind}},
+		}}
+		if len(columns) > 0 {
+			rows := make([]components.InfoRow, len(columns))
+			for i, col := range columns {
+				rows[i] = components.InfoRow{Label: col.Name, Value: col.Type}
+			}
+			sections = append(sections, components.InfoSection{Title: "Columns", Rows: rows})
+		}
+		sections = append(sections, components.InfoSection{Title: "Definition", Text: definition})
 
-	// Verify connection is working
-	if !connector.IsConnected() {
-		return fmt.Errorf("connection test failed")
+		m.infoPanel.Show("👁  "+viewName, sections)
+		m.state = StateInfo
+		return nil
+	})
+}
+
+// applySchema keeps a freshly loaded schema and feeds its tables, columns,
+// indexes and foreign keys to the editor suggestions and completion
+func (m *Model) applySchema(schema *db.Schema) {
+	m.schema = schema
+	schemaMap := make(map[string][]string)
+	indexes := make(map[string][]sources.IndexInfo)
+	foreignKeys := make(map[string][]sources.ForeignKeyInfo)
+	for tableName, table := range schema.Tables {
+		cols := make([]string, len(table.Columns))
+		for i, col := range table.Columns {
+			cols[i] = col.Name
+		}
+		schemaMap[tableName] = cols
+		for _, idx := range table.Indexes {
+			indexes[tableName] = append(indexes[tableName], sources.IndexInfo{Name: idx.Name, Columns: idx.Columns, Unique: idx.Unique})
+		}
+		for _, fk := range table.ForeignKeys {
+			foreignKeys[tableName] = append(foreignKeys[tableName], sources.ForeignKeyInfo{Columns: fk.Columns, RefTable: fk.RefTable, RefColumns: fk.RefColumns})
+		}
 	}
+	m.editor.SetSchema(schemaMap)
+	m.schemaSource.SetIndexes(indexes)
+	m.schemaSource.SetForeignKeys(foreignKeys)
+}
 
-	m.connector = connector
-	m.isConnected = true
+// ShowTableInfo opens the info panel for a table or partition. Its triggers
+// are read in the background first; the rest comes from the loaded schema.
+func (m *Model) ShowTableInfo(tableName string) tea.Cmd {
+	var sections []components.InfoSection
 
-	// Load tables
-	tables, err := connector.GetTables()
-	if err != nil {
-		m.statusMessage = "Connected, but failed to load tables: " + err.Error()
-		m.isError = true
-	} else {
-		m.tables = tables
-		m.sidebar.SetTables(tables)
-		m.statusMessage = fmt.Sprintf("Connected to %s", connCfg.Name)
-		m.isError = false
+	// Partition of another table
+	for parent, info := range m.partitions {
+		for _, p := range info.Partitions {
+			if p.Name != tableName {
+				continue
+			}
+			sections = append(sections, components.InfoSection{
+				Title: "Partition",
+				Rows: []components.InfoRow{
+					{Label: "Parent", Value: parent},
+					{Label: "Strategy", Value: info.Strategy},
+					{Label: "Key", Value: info.Key},
+					{Label: "Bounds", Value: p.Bounds},
+				},
+			})
+		}
 	}
 
-	// Load schema for auto-completion
-	schema, err := connector.GetSchema()
-	if err == nil {
-		m.schema = schema
-		// Convert schema to map for editor
-		schemaMap := make(map[string][]string)
-		for tableName, table := range schema.Tables {
-			cols := make([]string, len(table.Columns))
+	// Partitioned table
+	if info, ok := m.partitions[tableName]; ok {
+		sections = append(sections, components.InfoSection{
+			Title: "Partitioning",
+			Rows: []components.InfoRow{
+				{Label: "Strategy", Value: info.Strategy},
+				{Label: "Key", Value: info.Key},
+				{Label: "Partitions", Value: fmt.Sprintf("%d", len(info.Partitions))},
+			},
+		})
+		if len(info.Partitions) > 0 {
+			rows := make([]components.InfoRow, len(info.Partitions))
+			for i, p := range info.Partitions {
+				rows[i] = components.InfoRow{Label: p.Name, Value: p.Bounds}
+			}
+			sections = append(sections, components.InfoSection{Title: "Partitions", Rows: rows})
+		}
+	}
+
+	// Columns
+	if m.schema != nil {
+		if table, ok := m.schema.Tables[tableName]; ok {
+			rows := make([]components.InfoRow, len(table.Columns))
 			for i, col := range table.Columns {
-				cols[i] = col.Name
+				detail := col.Type
+				if col.IsPK {
+					detail += " PRIMARY KEY"
+				}
+				if !col.Nullable {
+					detail += " NOT NULL"
+				}
+				rows[i] = components.InfoRow{Label: col.Name, Value: detail}
+			}
+			sections = append(sections, components.InfoSection{Title: "Columns", Rows: rows})
+
+			if len(table.Indexes) > 0 {
+				rows := make([]components.InfoRow, len(table.Indexes))
+				for i, idx := range table.Indexes {
+					detail := "(" + strings.Join(idx.Columns, ", ") + ")"
+					switch {
+					case idx.Primary:
+						detail = "PRIMARY KEY " + detail
+					case idx.Unique:
+						detail = "UNIQUE " + detail
+					}
+					if idx.Method != "" {
+						detail += " " + idx.Method
+					}
+					rows[i] = components.InfoRow{Label: idx.Name, Value: detail}
+				}
+				sections = append(sections, components.InfoSection{Title: "Indexes", Rows: rows})
 			}
-			schemaMap[tableName] = cols
+
+			if len(table.ForeignKeys) > 0 {
+				rows := make([]components.InfoRow, len(table.ForeignKeys))
+				for i, fk := range table.ForeignKeys {
+					rows[i] = components.InfoRow{Label: fk.Name, Value: foreignKeyText(fk)}
+				}
+				sections = append(sections, components.InfoSection{Title: "Foreign Keys", Rows: rows})
+			}
+		}
+
+		// Tables whose foreign keys point here
+		if refs := m.schema.ReferencedBy(tableName); len(refs) > 0 {
+			names := make([]string, 0, len(refs))
+			for name := range refs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			var rows []components.InfoRow
+			for _, name := range names {
+				for _, fk := range refs[name] {
+					rows = append(rows, components.InfoRow{Label: name, Value: foreignKeyText(fk)})
+				}
+			}
+			sections = append(sections, components.InfoSection{Title: "Referenced By", Rows: rows})
 		}
-		m.editor.SetSchema(schemaMap)
 	}
 
-	// Load available databases
-	m.LoadDatabases()
-	
-	// Restore last state (database and table)
-	m.RestoreLastState()
+	show := func(triggers []db.Trigger, err error) tea.Cmd {
+		// The trigger summary comes first, then each body
+		if err != nil {
+			sections = append(sections, components.InfoSection{Title: "Triggers", Text: err.Error()})
+		} else if len(triggers) > 0 {
+			rows := make([]components.InfoRow, len(triggers))
+			for i, t := range triggers {
+				rows[i] = components.InfoRow{Label: t.Name, Value: triggerText(t)}
+			}
+			sections = append(sections, components.InfoSection{Title: "Triggers", Rows: rows})
+			for _, t := range triggers {
+				text := strings.TrimSpace(t.Definition + "\n\n" + t.Body)
+				sections = append(sections, components.InfoSection{Title: "Trigger " + t.Name, Text: text})
+			}
+		}
 
-	return nil
+		if len(sections) == 0 {
+			sections = append(sections, components.InfoSection{Text: "No details available"})
+		}
+
+		m.infoPanel.Show("ℹ️  "+tableName, sections)
+		m.state = StateInfo
+		return nil
+	}
+
+	// Triggers are read on demand, when the connection is free
+	if _, ok := m.conn.connector.(db.TriggerLister); !ok || !m.conn.connected {
+		return show(nil, nil)
+	}
+	if m.queryRunning {
+		return show(nil, fmt.Errorf("not read while a query is running"))
+	}
+	var triggers []db.Trigger
+	read := func(connector db.Connector) error {
+		var err error
+		triggers, err = db.GetTriggers(connector, tableName)
+		return err
+	}
+	return m.introspect("Reading triggers of "+tableName, read, func(err error) tea.Cmd {
+		if err != nil {
+			// A read that timed out may still be filling triggers
+			return show(nil, err)
+		}
+		return show(triggers, nil)
+	})
 }
 
-// LoadDatabases loads the list of available databases
-func (m *Model) LoadDatabases() {
-	if m.connector == nil {
-		return
+// triggerText describes when a trigger fires, e.g.
+// BEFORE INSERT OR UPDATE FOR EACH ROW
+func triggerText(t db.Trigger) string {
+	text := t.Timing + " " + strings.Join(t.Events, " OR ") + " FOR EACH " + t.ForEach
+	if !t.Enabled {
+		text += " (disabled)"
 	}
-	
-	databases, err := m.connector.GetDatabases()
-	if err != nil {
-		return
+	return text
+}
+
+// foreignKeyText describes a foreign key, e.g.
+// (user_id) → users (id) ON DELETE CASCADE
+func foreignKeyText(fk db.ForeignKey) string {
+	text := "(" + strings.Join(fk.Columns, ", ") + ") → " + fk.RefTable + " (" + strings.Join(fk.RefColumns, ", ") + ")"
+	// NO ACTION is the default and not worth the space
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+		text += " ON DELETE " + fk.OnDelete
 	}
-	
-	currentDB := m.connector.GetDatabaseName()
-	m.sidebar.SetDatabases(databases, currentDB)
+	if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+		text += " ON UPDATE " + fk.OnUpdate
+	}
+	return text
 }
 
-// SwitchDatabase switches to a different database
-func (m *Model) SwitchDatabase(dbName string) error {
-	if m.connector == nil {
-		return fmt.Errorf("not connected")
+// tableMenuFixedItems are the table actions shown before maintenance actions
+var tableMenuFixedItems = []string{"▶  Preview", "ℹ️  Info", "📋 Copy DDL", "📝 Insert DDL into editor", "➕ New row", "🧹 Truncate", "🗑  Drop"}
+
+// ShowTableMenu opens the action menu for a table
+func (m *Model) ShowTableMenu(tableName string) {
+	items := append([]string{}, tableMenuFixedItems...)
+	for _, action := range db.GetMaintenanceActions(m.conn.connector) {
+		items = append(items, "🔧 "+action.Name)
 	}
-	
-	if err := m.connector.SwitchDatabase(dbName); err != nil {
-		return err
+
+	m.menuTable = tableName
+	m.tableMenu.Show("⚡ "+tableName, items)
+	m.state = StateTableMenu
+}
+
+// runTableMenuAction runs the selected table menu action
+func (m *Model) runTableMenuAction() tea.Cmd {
+	index := m.tableMenu.Selected()
+	table := m.menuTable
+	m.tableMenu.Hide()
+	m.state = StateNormal
+
+	switch index {
+	case 0:
+		return m.PreviewTable(table)
+	case 1:
+		return m.ShowTableInfo(table)
+	case 2:
+		return m.CopyTableDDL(table)
+	case 3:
+		return m.InsertTableDDL(table)
+	case 4:
+		return m.ShowNewRowForm(table)
+	case 5:
+		m.confirmTableDrop(table, false)
+		return nil
+	case 6:
+		m.confirmTableDrop(table, true)
+		return nil
 	}
-	
-	// Reload tables for new database
-	tables, err := m.connector.GetTables()
-	if err == nil {
-		m.tables = tables
-		m.sidebar.SetTables(tables)
+
+	actions := db.GetMaintenanceActions(m.conn.connector)
+	index -= len(tableMenuFixedItems)
+	if index >= 0 && index < len(actions) {
+		m.confirmMaintenance(actions[index], table)
 	}
-	
-	// Reload schema
-	schema, err := m.connector.GetSchema()
-	if err == nil {
-		m.schema = schema
-		schemaMap := make(map[string][]string)
-		for tableName, table := range schema.Tables {
-			cols := make([]string, len(table.Columns))
-			for i, col := range table.Columns {
-				cols[i] = col.Name
-			}
-			schemaMap[tableName] = cols
+	return nil
+}
+
+// askConfirm shows the confirm modal and runs onConfirm if accepted
+func (m *Model) askConfirm(title, message string, onConfirm func() tea.Cmd) {
+	m.pendingConfirm = onConfirm
+	m.confirm.Show(title, message)
+	m.state = StateConfirm
+}
+
+// askInput shows the input prompt prefilled with value and passes the
+// entered text to onSubmit
+func (m *Model) askInput(title, message, value string, onSubmit func(string) tea.Cmd) {
+	m.pendingInput = onSubmit
+	m.input.Show(title, message, value)
+	m.state = StateInput
+}
+
+// ShowServerSettings opens the server variables browser
+func (m *Model) ShowServerSettings() tea.Cmd {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return nil
+	}
+	if _, ok := m.conn.connector.(db.SettingsLister); !ok {
+		m.statusMessage = "Server settings are not available for this driver"
+		m.isError = true
+		return nil
+	}
+
+	var settings []db.ServerSetting
+	read := func(connector db.Connector) error {
+		var err error
+		settings, err = db.GetServerSettings(connector)
+		return err
+	}
+	return m.introspect("Loading server settings", read, func(err error) tea.Cmd {
+		if err != nil {
+			m.statusMessage = "Failed to load settings: " + err.Error()
+			m.isError = true
+			return nil
+		}
+
+		items := make([]components.VariableItem, len(settings))
+		for i, s := range settings {
+			items[i] = components.VariableItem{Name: s.Name, Value: s.Value, Description: s.Description}
+		}
+
+		m.variables.Show("⚙️  Server Settings", items)
+		m.state = StateVariables
+		return nil
+	})
+}
+
+// sshChain renders SSH hops as "user@bastion → user@jump"
+func sshChain(hops []config.SSHHop) string {
+	parts := make([]string, len(hops))
+	for i, hop := range hops {
+		parts[i] = hop.Address()
+		if hop.User != "" {
+			parts[i] = hop.User + "@" + parts[i]
 		}
-		m.editor.SetSchema(schemaMap)
 	}
-	
-	// Update sidebar
-	m.LoadDatabases()
-	
-	// Save last database to config
-	m.config.LastDatabase = dbName
-	m.config.Save()
-	
-	m.statusMessage = "Switched to database: " + dbName
-	m.isError = false
-	return nil
+	return strings.Join(parts, " → ")
 }
 
-// TestConnection tests a database connection without storing it
-func (m *Model) TestConnection(cfg *config.DatabaseConfig) error {
-	connector, err := db.NewConnector(cfg)
-	if err != nil {
-		return fmt.Errorf("invalid config: %w", err)
+// ShowConnectionInfo opens the info panel for the active connection
+func (m *Model) ShowConnectionInfo() {
+	connCfg := m.config.GetActiveConnection()
+	if m.conn.connector == nil || !m.conn.connected || connCfg == nil {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
 	}
 
-	if err := connector.Connect(); err != nil {
-		return fmt.Errorf("connection failed: %w", err)
+	caps := m.conn.capabilities
+	orNA := func(s string) string {
+		if s == "" {
+			return "n/a"
+		}
+		return s
 	}
-	
-	// Test the connection
-	if !connector.IsConnected() {
-		connector.Close()
-		return fmt.Errorf("connection test failed")
+	supported := func(ok bool) string {
+		if ok {
+			return "✓ yes"
+		}
+		return "✗ no"
+	}
+	_, maintenance := m.conn.connector.(db.Maintainer)
+	_, settings := m.conn.connector.(db.SettingsLister)
+	_, replication := m.conn.connector.(db.ReplicationReporter)
+
+	host := connCfg.Host
+	if connCfg.UsesSocket() {
+		host = "unix:" + connCfg.Socket
+	} else if connCfg.Port != 0 {
+		host = fmt.Sprintf("%s:%d", connCfg.Host, connCfg.Port)
 	}
 
-	connector.Close()
-	return nil
-}
+	replicaPort := connCfg.ReplicaPort
+	if replicaPort == 0 {
+		replicaPort = connCfg.Port
+	}
+	routing := "n/a"
+	if router, ok := db.GetReplicaRouter(m.conn.connector); ok {
+		routing = "reads on replica, writes on primary"
+		if router.PrimaryOnly() {
+			routing = "all on primary (F12)"
+		}
+	}
 
-// Disconnect disconnects from the current database
-func (m *Model) Disconnect() {
-	if m.connector != nil {
-		m.connector.Close()
-		m.connector = nil
+	queryTimeout := "none"
+	if t := m.config.QueryTimeoutFor(connCfg); t > 0 {
+		queryTimeout = t.String()
 	}
-	m.isConnected = false
-	m.tables = nil
-	m.sidebar.SetTables(nil)
-	m.schema = nil
-	m.statusMessage = "Disconnected"
-	m.isError = false
+
+	sections := []components.InfoSection{
+		{
+			Title: "Server",
+			Rows: []components.InfoRow{
+				{Label: "Driver", Value: m.conn.connector.GetDriverName()},
+				{Label: "Version", Value: orNA(caps.ServerVersion)},
+				{Label: "Host", Value: orNA(host)},
+				{Label: "SSH tunnel", Value: orNA(sshChain(connCfg.SSHHops))},
+				{Label: "Read replica", Value: orNA(replicaHost(connCfg.ReplicaHost, replicaPort))},
+				{Label: "Routing", Value: routing},
+				{Label: "Database", Value: orNA(m.conn.connector.GetDatabaseName())},
+				{Label: "Read-only", Value: supported(connCfg.ReadOnly)},
+				{Label: "Query timeout", Value: queryTimeout},
+				{Label: "User", Value: orNA(caps.CurrentUser)},
+				{Label: "Encoding", Value: orNA(caps.Encoding)},
+				{Label: "Timezone", Value: orNA(caps.Timezone)},
+			},
+		},
+		{
+			Title: "Features",
+			Rows: []components.InfoRow{
+				{Label: "Transactions", Value: supported(caps.SupportsTransactions)},
+				{Label: "Cancel queries", Value: supported(caps.SupportsCancel)},
+				{Label: "Multiple databases", Value: supported(caps.SupportsSwitchDatabase)},
+				{Label: "EXPLAIN", Value: supported(caps.SupportsExplain)},
+				{Label: "Table maintenance", Value: supported(maintenance)},
+				{Label: "Server settings", Value: supported(settings)},
+				{Label: "Replication status", Value: supported(replication)},
+			},
+		},
+	}
+
+	if len(m.replication) > 0 {
+		section := components.InfoSection{Title: "Replication"}
+		for _, r := range m.replication {
+			section.Rows = append(section.Rows, components.InfoRow{
+				Label: orNA(r.Name),
+				Value: fmt.Sprintf("%s, %s, lag %s", r.Role, r.State, r.LagString()),
+			})
+		}
+		sections = append(sections, section)
+	}
+
+	m.infoPanel.Show("🔌 "+connCfg.Name, sections)
+	m.state = StateInfo
 }
 
-// ExecuteQuery executes the current SQL query
-func (m *Model) ExecuteQuery() {
+// ExecuteQuery starts the current SQL query in the background; results
+// arrive as a queryDoneMsg
+func (m *Model) ExecuteQuery() tea.Cmd {
 	// Use selected text if available, otherwise full content
 	sql := m.editor.GetSelectedText()
 	isSelection := false
 	if sql != m.editor.GetValue() {
 		isSelection = true
 	}
+	return m.executeSQL(sql, isSelection, "Running query")
+}
 
+// ExecuteStatementAtCursor runs only the statement under the editor cursor,
+// or the selection when there is one
+func (m *Model) ExecuteStatementAtCursor() tea.Cmd {
+	if m.editor.GetSelectedText() != m.editor.GetValue() || m.conn.connector == nil {
+		return m.ExecuteQuery()
+	}
+
+	value := m.editor.GetValue()
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	stmt, ok := sqlparse.StatementAt(value, m.editor.CursorOffset(), dialect)
+	if !ok {
+		m.results.SetMessage("No query to execute")
+		return nil
+	}
+	line := strings.Count(value[:stmt.Start], "\n") + 1
+	return m.executeSQL(stmt.Text, true, fmt.Sprintf("Running statement at line %d", line))
+}
+
+// executeSQL runs sql in the background after the connection, running-query
+// and read-only checks. label is shown while it runs.
+func (m *Model) executeSQL(sql string, isSelection bool, label string) tea.Cmd {
 	if strings.TrimSpace(sql) == "" {
 		m.results.SetMessage("No query to execute")
-		return
+		return nil
 	}
 
-	if m.connector == nil || !m.isConnected {
+	if m.conn.connector == nil || !m.conn.connected {
 		m.results.SetError(fmt.Errorf("not connected to database"))
-		return
+		return nil
 	}
 
-	m.queryRunning = true
-	m.lastQuery = sql
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil
+	}
 
-	// Determine query type
-	// Strip comments and leading whitespace to find the actual command
-	cleanSQL := sql
-	// Strip single line comments
-	reLine := regexp.MustCompile(`(?m)^--.*$`)
-	cleanSQL = reLine.ReplaceAllString(cleanSQL, "")
-	// Strip multi-line comments
-	reMulti := regexp.MustCompile(`(?s)/\\*.*?\\*/`)
-	cleanSQL = reMulti.ReplaceAllString(cleanSQL, "")
-	
-	trimmedSQL := strings.TrimSpace(strings.ToLower(cleanSQL))
-	isSelect := strings.HasPrefix(trimmedSQL, "select") ||
-				strings.HasPrefix(trimmedSQL, "show") ||
-				strings.HasPrefix(trimmedSQL, "describe") ||
-				strings.HasPrefix(trimmedSQL, "explain") ||
-				strings.HasPrefix(trimmedSQL, "with") ||
-				strings.HasPrefix(trimmedSQL, "pragma") // SQLite pragma often returns data
-
-	if isSelect {
-		rows, columns, err := m.connector.Query(sql)
-		if err != nil {
-			m.results.SetError(err)
-			if isSelection {
-				m.statusMessage = "Selected query failed"
-			} else {
-				m.statusMessage = "Query failed"
-			}
-			m.isError = true
-		} else {
-			m.results.SetData(columns, rows)
-			if isSelection {
-				lines := len(strings.Split(sql, "\n"))
-				m.statusMessage = fmt.Sprintf("Selected query (%d lines) returned %d rows", lines, len(rows))
-			} else {
-				m.statusMessage = fmt.Sprintf("Query returned %d rows", len(rows))
-			}
-			m.isError = false
-			// Default to table view for new results
-			m.results.SetViewMode(components.ViewTable)
-		}
-	} else {
-		// Execute non-select query
-		affected, err := m.connector.Execute(sql)
-		if err != nil {
-			m.results.SetError(err)
-			if isSelection {
-				m.statusMessage = "Selected execution failed"
-			} else {
-				m.statusMessage = "Execution failed"
-			}
-			m.isError = true
-		} else {
-			m.results.SetMessage(fmt.Sprintf("Query executed successfully. %d rows affected.", affected))
-			if isSelection {
-				lines := len(strings.Split(sql, "\n"))
-				m.statusMessage = fmt.Sprintf("Selected query (%d lines) affected %d rows", lines, affected)
-			} else {
-				m.statusMessage = fmt.Sprintf("Affected %d rows", affected)
+	// {{result.column}} references take values from the shown result
+	sql, err := m.expandResultRefs(sql)
+	if err != nil {
+		m.statusMessage = err.Error()
+		m.isError = true
+		return nil
+	}
+
+	// Route the batch to Query when any statement returns rows; result sets
+	// of the other statements are skipped by QueryMulti
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	statements := sqlparse.Split(sql, dialect)
+	if len(statements) == 0 {
+		m.results.SetMessage("No query to execute")
+		return nil
+	}
+	if m.readOnly() {
+		for _, stmt := range statements {
+			if !sqlparse.IsReadOnly(stmt.Text, dialect) {
+				m.results.SetError(fmt.Errorf("connection is read-only, refusing: %s", firstLine(stmt.Text)))
+				m.statusMessage = "Blocked by read-only mode"
+				m.isError = true
+				return nil
 			}
-			m.isError = false
 		}
 	}
-
-	m.queryRunning = false
+	return m.guardLint(sql, func() tea.Cmd {
+		return m.guardProtected(m.protectedTablesIn(sql), func() tea.Cmd {
+			return m.runStatements(sql, statements, isSelection, label)
+		})
+	})
 }
 
-// PreviewTable previews the selected table
-func (m *Model) PreviewTable(tableName string) {
-	if tableName == "" {
-		return
+// runStatements runs the checked statements of sql, asking for their
+// parameters first
+func (m *Model) runStatements(sql string, statements []sqlparse.Statement, isSelection bool, label string) tea.Cmd {
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	isSelect := false
+	for _, stmt := range statements {
+		if sqlparse.Classify(stmt.Text, dialect) == sqlparse.KindQuery {
+			isSelect = true
+			break
+		}
 	}
 
-	sql := fmt.Sprintf("SELECT * FROM %s LIMIT 100", tableName)
-	m.editor.SetValue(sql)
-	m.ExecuteQuery()
-}
-
-// GenerateSQL uses AI to generate SQL from natural language
-func (m *Model) GenerateSQL(prompt string) {
-	if m.aiProvider == nil || !m.aiProvider.IsConfigured() {
-		m.statusMessage = "AI not configured"
-		m.isError = true
-		return
+	if m.notebook && len(statements) > 1 {
+		return m.runNotebook(statements)
 	}
 
-	sql, err := m.aiProvider.NL2SQL(prompt, m.schema)
-	if err != nil {
-		m.statusMessage = "AI error: " + err.Error()
-		m.isError = true
-		return
+	q := paramQuery{sql: sql, isSelect: isSelect, isSelection: isSelection, label: label}
+	if q.params = sqlparse.Params(sql, dialect, sqlparse.ParamStyleFor(m.conn.connector.GetDriverName())); len(q.params) > 0 {
+		return m.promptParams(q, len(statements))
 	}
+	m.lastQuery = sql
+	return m.startQuery(sql, nil, isSelect, isSelection, label)
+}
 
-	m.editor.SetValue(sql)
-	m.statusMessage = "SQL generated by AI"
+// startQuery runs sql with args bound in the background
+func (m *Model) startQuery(sql string, args []interface{}, isSelect, isSelection bool, label string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	m.runEditorText = m.editor.GetValue()
+	m.statusMessage = label + "... (Esc to cancel)"
 	m.isError = false
+	timeout := m.config.QueryTimeoutFor(m.config.GetActiveConnection())
+	if query, ok := m.pagedQuery(sql, args, isSelect); ok {
+		return tea.Batch(runFirstPage(ctx, m.conn.connector, sql, query, m.config.PageRows, timeout, isSelection), m.results.StartRunning(label))
+	}
+	if streamer, ok := m.streamer(sql, args, isSelect); ok {
+		rows, bytes := m.config.ResultLimit()
+		limit := db.ResultLimit{Rows: rows, Bytes: bytes}
+		return tea.Batch(streamQuery(ctx, streamer, sql, limit, timeout, isSelection), m.results.StartRunning(label))
+	}
+	return tea.Batch(runQuery(ctx, m.conn.connector, sql, args, timeout, isSelect, isSelection), m.results.StartRunning(label))
 }
 
-// RefactorSQL uses AI to refactor SQL
-func (m *Model) RefactorSQL(instruction string) {
-	if m.aiProvider == nil || !m.aiProvider.IsConfigured() {
-		m.statusMessage = "AI not configured"
+// ExplainQuery runs EXPLAIN for the query in the editor (or the selection)
+// in the background, through the checks of executeSQL. EXPLAIN ANALYZE runs
+// the statement, so it is refused for statements that write.
+func (m *Model) ExplainQuery() tea.Cmd {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.results.SetError(fmt.Errorf("not connected to database"))
+		return nil
+	}
+	if !m.conn.capabilities.SupportsExplain {
+		m.statusMessage = "EXPLAIN is not supported by " + m.conn.connector.GetDriverName()
 		m.isError = true
-		return
+		return nil
 	}
 
-	// Use selected text if available, otherwise full content
-	currentSQL := m.editor.GetSelectedText()
-	isSelection := false
-	if currentSQL != m.editor.GetValue() {
-		isSelection = true
-	}
-	
-	if currentSQL == "" {
-		m.statusMessage = "No SQL to refactor"
+	sql := strings.TrimSpace(m.editor.GetSelectedText())
+	if sql == "" {
+		m.statusMessage = "No query to explain"
 		m.isError = true
-		return
+		return nil
 	}
 
-	sql, err := m.aiProvider.RefactorSQL(currentSQL, instruction, m.schema)
-	if err != nil {
-		m.statusMessage = "AI error: " + err.Error()
+	prefix := "EXPLAIN "
+	if m.conn.connector.GetDriverName() == "sqlite3" {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	if len(sqlparse.Split(sql, dialect)) != 1 {
+		m.statusMessage = "Select a single statement to explain"
 		m.isError = true
-		return
+		return nil
+	}
+	sql = prefix + sql
+	if !sqlparse.IsReadOnly(sql, dialect) {
+		m.statusMessage = "EXPLAIN ANALYZE would run a statement that writes; refusing"
+		m.isError = true
+		return nil
 	}
+	return m.executeSQL(sql, true, "Explaining query")
+}
 
-	m.editor.SetValue(sql)
-	if isSelection {
-		m.statusMessage = "Selected SQL refactored by AI"
-	} else {
-		m.statusMessage = "SQL refactored by AI"
+// quoteIdent quotes an identifier for the connected driver
+func (m *Model) quoteIdent(name string) string {
+	driver := ""
+	if m.conn.connector != nil {
+		driver = m.conn.connector.GetDriverName()
 	}
-	m.isError = false
+	return db.QuoteIdentifier(driver, name)
+}
+
+// PreviewTable previews the selected table
+func (m *Model) PreviewTable(tableName string) tea.Cmd {
+	if tableName == "" {
+		return nil
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s LIMIT 100", m.quoteIdent(tableName))
+	m.editor.SetValue(sql)
+	return m.ExecuteQuery()
 }
 
 // FocusNext moves focus to the next pane
 func (m *Model) FocusNext() {
+	if m.viewerPath != "" {
+		return
+	}
 	m.sidebar.SetFocused(false)
 	m.editor.SetFocused(false)
 	m.results.SetFocused(false)
@@ -627,6 +1231,9 @@ func (m *Model) FocusNext() {
 
 // FocusPrev moves focus to the previous pane
 func (m *Model) FocusPrev() {
+	if m.viewerPath != "" {
+		return
+	}
 	m.sidebar.SetFocused(false)
 	m.editor.SetFocused(false)
 	m.results.SetFocused(false)
@@ -656,7 +1263,7 @@ func (m *Model) UpdateTheme(themeName string) {
 
 // GetConnectionInfo returns the current connection info string
 func (m *Model) GetConnectionInfo() string {
-	if !m.isConnected || m.connector == nil {
+	if !m.conn.connected || m.conn.connector == nil {
 		return "Not Connected"
 	}
 	connCfg := m.config.GetActiveConnection()
@@ -666,35 +1273,31 @@ func (m *Model) GetConnectionInfo() string {
 	return "Connected"
 }
 
-// GetAIInfo returns the AI provider info string
-func (m *Model) GetAIInfo() string {
-	if m.aiProvider == nil || !m.aiProvider.IsConfigured() {
-		return "AI: Disabled"
+// firstLine returns the first line of a statement for messages
+func firstLine(sql string) string {
+	if i := strings.IndexByte(sql, '\n'); i >= 0 {
+		return strings.TrimSpace(sql[:i]) + " ..."
 	}
-	return fmt.Sprintf("%s: %s", m.aiProvider.GetProviderName(), m.aiProvider.GetModelName())
+	return sql
 }
 
-// RestoreLastState restores the last database and table selection
-func (m *Model) RestoreLastState() {
-	if !m.isConnected || m.connector == nil {
-		return
+// readOnly reports whether the active connection is in read-only mode
+func (m *Model) readOnly() bool {
+	connCfg := m.config.GetActiveConnection()
+	return connCfg != nil && connCfg.ReadOnly
+}
+
+// completionTables returns the names completed as tables: tables and views
+func (m *Model) completionTables() []string {
+	tables := m.sidebar.GetTables()
+	if len(m.views) == 0 {
+		return tables
 	}
-	
-	// Restore last database if different from current
-	if m.config.LastDatabase != "" {
-		currentDB := m.connector.GetDatabaseName()
-		if currentDB != m.config.LastDatabase {
-			// Try to switch to last database
-			if err := m.SwitchDatabase(m.config.LastDatabase); err != nil {
-				// If failed, clear last database from config
-				m.config.LastDatabase = ""
-				m.config.Save()
-			}
-		}
+	names := append([]string{}, tables...)
+	for _, v := range m.views {
+		names = append(names, v.Name)
 	}
-	
-	// Note: Last table restoration would need sidebar support
-	// which we'll implement when sidebar has SelectTable method
+	return names
 }
 
 // triggerCompletion triggers the completion popup
@@ -703,8 +1306,8 @@ func (m *Model) triggerCompletion() {
 	query := m.editor.GetValue()
 	cursor := m.editor.GetCursorPosition()
 	
-	// Get available tables for context
-	tables := m.sidebar.GetTables()
+	// Get available tables and views for context
+	tables := m.completionTables()
 	database := m.sidebar.GetCurrentDatabase()
 	
 	// Update schema source with current tables
@@ -730,8 +1333,8 @@ func (m *Model) refreshCompletions() {
 	query := m.editor.GetValue()
 	cursor := m.editor.GetCursorPosition()
 	
-	// Get available tables for context
-	tables := m.sidebar.GetTables()
+	// Get available tables and views for context
+	tables := m.completionTables()
 	database := m.sidebar.GetCurrentDatabase()
 	
 	// Update schema source with current tables
@@ -748,8 +1351,10 @@ func (m *Model) refreshCompletions() {
 
 // Close cleans up resources
 func (m *Model) Close() error {
-	if m.connector != nil {
-		return m.connector.Close()
+	m.saveBuffer()
+	m.metrics.Close()
+	if m.conn.connector != nil {
+		return m.conn.connector.Close()
 	}
 	return nil
 }
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// ShowNewRowForm opens a form with one input per column of a table for
+// inserting a row, once the columns were read in the background
+func (m *Model) ShowNewRowForm(tableName string) tea.Cmd {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return nil
+	}
+	if m.readOnly() {
+		m.statusMessage = "Connection is read-only"
+		m.isError = true
+		return nil
+	}
+	var columns []db.Column
+	read := func(connector db.Connector) error {
+		var err error
+		columns, err = connector.GetColumns(tableName)
+		return err
+	}
+	return m.introspect("Reading columns of "+tableName, read, func(err error) tea.Cmd {
+		if err != nil {
+			m.statusMessage = "Failed to read columns: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		return m.showNewRowForm(tableName, columns)
+	})
+}
+
+// showNewRowForm opens the new-row form for the columns of a table
+func (m *Model) showNewRowForm(tableName string, columns []db.Column) tea.Cmd {
+	if len(columns) == 0 {
+		m.statusMessage = "Table " + tableName + " has no columns"
+		m.isError = true
+		return nil
+	}
+
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+		placeholders[i] = columnHint(col)
+	}
+	m.newRowTable = tableName
+	m.newRowColumns = columns
+	m.rowForm.Show(names, nil)
+	m.rowForm.SetPlaceholders(placeholders)
+	m.rowForm.SetLabels("➕ New row in "+tableName,
+		"Empty uses the column default; NULL inserts NULL.",
+		"Tab: next • Enter: insert • Esc: cancel")
+	m.state = StateRowForm
+	return textinput.Blink
+}
+
+// columnHint describes a column in its empty input: type, default and
+// whether it takes NULL
+func columnHint(col db.Column) string {
+	parts := []string{strings.ToLower(col.Type)}
+	if col.IsPK {
+		parts = append(parts, "primary key")
+	}
+	if col.Default != "" {
+		parts = append(parts, "default "+col.Default)
+	}
+	if !col.Nullable {
+		parts = append(parts, "not null")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// submitNewRow builds the INSERT for the entered values, by column name,
+// and runs it once confirmed
+func (m *Model) submitNewRow(values map[string]string) tea.Cmd {
+	table, columns := m.newRowTable, m.newRowColumns
+	m.newRowTable, m.newRowColumns = "", nil
+	if m.conn.connector == nil || table == "" {
+		return nil
+	}
+
+	sql := insertRowSQL(m.conn.connector, m.quoteIdent(table), columns, values)
+	m.askConfirm("➕ Insert Row", sql, func() tea.Cmd {
+		return m.executeSQL(sql, false, "Inserting row")
+	})
+	return nil
+}
+
+// insertRowSQL builds an INSERT of the non-empty values. Empty inputs are
+// left out so the column default applies; NULL inserts NULL.
+func insertRowSQL(c db.Connector, table string, columns []db.Column, values map[string]string) string {
+	driver := c.GetDriverName()
+	var names, literals []string
+	for _, col := range columns {
+		text := values[col.Name]
+		if text == "" {
+			continue
+		}
+		names = append(names, db.QuoteIdentifier(driver, col.Name))
+		if strings.EqualFold(text, "null") {
+			literals = append(literals, "NULL")
+			continue
+		}
+		colType := db.ColumnType{Name: col.Name, DatabaseType: col.Type, Kind: db.ColumnKindOf(col.Type)}
+		literals = append(literals, sqlLiteral(c, db.Value{Data: text}, colType))
+	}
+
+	if len(names) == 0 {
+		// Every column takes its default
+		if driver == "mysql" || driver == "mariadb" {
+			return fmt.Sprintf("INSERT INTO %s () VALUES ()", table)
+		}
+		return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", table)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(names, ", "), strings.Join(literals, ", "))
+}
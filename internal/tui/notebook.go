@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// notebookCellWidth caps the width of a value in an inline result
+const notebookCellWidth = 24
+
+// notebookCell is the last outcome of a statement run in notebook mode,
+// shown under the statement in the editor
+type notebookCell struct {
+	isSelect  bool
+	columns   []string
+	types     []db.ColumnType
+	rows      []db.Row
+	truncated bool
+	affected  int64
+	elapsed   time.Duration
+	at        time.Time
+	cancelled bool
+	err       error
+	collapsed bool
+}
+
+// notebookKey identifies a statement independent of its layout
+func notebookKey(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// ToggleNotebook switches notebook mode, where every statement shows its
+// own results under it in the editor
+func (m *Model) ToggleNotebook() {
+	m.notebook = !m.notebook
+	if m.notebook {
+		if m.notebookCells == nil {
+			m.notebookCells = make(map[string]*notebookCell)
+		}
+		m.statusMessage = "Notebook mode on: results show under each statement (Alt+F folds)"
+	} else {
+		m.notebookQueue = nil
+		m.statusMessage = "Notebook mode off"
+	}
+	m.isError = false
+	m.refreshNotebook()
+}
+
+// ToggleNotebookCell folds or unfolds the inline result of the statement
+// under the cursor
+func (m *Model) ToggleNotebookCell() {
+	if !m.notebook {
+		m.statusMessage = "Notebook mode is off (Alt+N)"
+		m.isError = true
+		return
+	}
+	stmt, ok := sqlparse.StatementAt(m.editor.GetValue(), m.editor.CursorOffset(), m.lintDialect())
+	if !ok {
+		return
+	}
+	cell, ok := m.notebookCells[notebookKey(stmt.Text)]
+	if !ok {
+		m.statusMessage = "This statement has no results yet (F9 runs it)"
+		m.isError = false
+		return
+	}
+	cell.collapsed = !cell.collapsed
+	m.refreshNotebook()
+}
+
+// runNotebook runs a script one statement at a time, so each statement
+// gets its own inline result. It stops at the first failure.
+func (m *Model) runNotebook(statements []sqlparse.Statement) tea.Cmd {
+	dialect := m.lintDialect()
+	style := sqlparse.ParamStyleFor(m.conn.connector.GetDriverName())
+	queue := make([]string, len(statements))
+	for i, stmt := range statements {
+		if len(sqlparse.Params(stmt.Text, dialect, style)) > 0 {
+			m.statusMessage = "Run statements with parameters one at a time (F9)"
+			m.isError = true
+			return nil
+		}
+		queue[i] = stmt.Text
+	}
+	m.notebookQueue = queue
+	m.notebookTotal = len(queue)
+	return m.nextNotebookStatement()
+}
+
+// nextNotebookStatement starts the next statement of a notebook run
+func (m *Model) nextNotebookStatement() tea.Cmd {
+	if len(m.notebookQueue) == 0 {
+		return nil
+	}
+	sql := m.notebookQueue[0]
+	m.notebookQueue = m.notebookQueue[1:]
+	isSelect := sqlparse.Classify(sql, m.lintDialect()) == sqlparse.KindQuery
+	label := fmt.Sprintf("Running statement %d/%d", m.notebookTotal-len(m.notebookQueue), m.notebookTotal)
+	m.lastQuery = sql
+	return m.startQuery(sql, nil, isSelect, false, label)
+}
+
+// handleNotebookQueryDone keeps the outcome of a query for its inline
+// result and continues a notebook run
+func (m *Model) handleNotebookQueryDone(msg queryDoneMsg) tea.Cmd {
+	if !m.notebook {
+		return nil
+	}
+	// Key by the statement as Split sees it, without comments and the
+	// semicolon; a batch belongs to its last statement
+	statements := sqlparse.Split(msg.sql, m.lintDialect())
+	if len(statements) == 0 {
+		return nil
+	}
+	key := notebookKey(statements[len(statements)-1].Text)
+	cell := &notebookCell{
+		isSelect:  msg.isSelect,
+		affected:  msg.affected,
+		elapsed:   msg.elapsed,
+		at:        time.Now(),
+		cancelled: msg.cancelled,
+		err:       msg.err,
+	}
+	if old, ok := m.notebookCells[key]; ok {
+		cell.collapsed = old.collapsed
+	}
+	// A batch shows its last result set
+	if n := len(msg.sets); n > 0 {
+		set := msg.sets[n-1]
+		cell.columns, cell.types, cell.rows, cell.truncated = set.Columns, set.ColumnTypes, set.Rows, set.Truncated
+	}
+	m.notebookCells[key] = cell
+	m.refreshNotebook()
+
+	if msg.cancelled || msg.err != nil {
+		m.notebookQueue = nil
+		return nil
+	}
+	return m.nextNotebookStatement()
+}
+
+// refreshNotebook anchors the inline results to the statements of the
+// current text; statements that changed since they ran show none
+func (m *Model) refreshNotebook() {
+	if !m.notebook || len(m.notebookCells) == 0 {
+		m.editor.SetInlineBlocks(nil)
+		return
+	}
+	value := m.editor.GetValue()
+	blocks := make(map[int][]string)
+	for _, stmt := range sqlparse.Split(value, m.lintDialect()) {
+		cell, ok := m.notebookCells[notebookKey(stmt.Text)]
+		if !ok {
+			continue
+		}
+		line := strings.Count(value[:stmt.Start+len(stmt.Text)], "\n")
+		blocks[line] = cell.render(m.config.NotebookPreviewRows())
+	}
+	m.editor.SetInlineBlocks(blocks)
+}
+
+// render lays out the cell as text lines: a summary, then up to limit
+// rows as a table unless folded
+func (c *notebookCell) render(limit int) []string {
+	fold := "▾"
+	if c.collapsed {
+		fold = "▸"
+	}
+	elapsed := c.elapsed.Round(time.Millisecond)
+	at := c.at.Format("15:04:05")
+
+	switch {
+	case c.cancelled:
+		return []string{fmt.Sprintf("%s ✗ cancelled after %s · %s", fold, elapsed, at)}
+	case c.err != nil:
+		return []string{fmt.Sprintf("%s ✗ %s · %s", fold, firstLine(c.err.Error()), at)}
+	case !c.isSelect:
+		return []string{fmt.Sprintf("%s ✓ %d rows affected in %s · %s", fold, c.affected, elapsed, at)}
+	}
+
+	summary := fmt.Sprintf("%s ✓ %d rows in %s · %s", fold, len(c.rows), elapsed, at)
+	if c.truncated {
+		summary = fmt.Sprintf("%s ✓ %d rows (truncated) in %s · %s", fold, len(c.rows), elapsed, at)
+	}
+	if c.collapsed || len(c.columns) == 0 {
+		return []string{summary}
+	}
+
+	rows := c.rows
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(c.columns))
+	for i, col := range c.columns {
+		widths[i] = len([]rune(col))
+	}
+	for r, row := range rows {
+		cells[r] = make([]string, len(c.columns))
+		for i, col := range c.columns {
+			var colType db.ColumnType
+			if i < len(c.types) {
+				colType = c.types[i]
+			}
+			text := clipCell(row[col].Text(colType), notebookCellWidth)
+			cells[r][i] = text
+			if w := len([]rune(text)); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] > notebookCellWidth {
+			widths[i] = notebookCellWidth
+		}
+	}
+
+	lines := []string{summary, "  " + notebookRow(c.columns, widths)}
+	rule := make([]string, len(widths))
+	for i, w := range widths {
+		rule[i] = strings.Repeat("─", w)
+	}
+	lines = append(lines, "  "+strings.Join(rule, "─┼─"))
+	for _, row := range cells {
+		lines = append(lines, "  "+notebookRow(row, widths))
+	}
+	if more := len(c.rows) - len(rows); more > 0 {
+		lines = append(lines, fmt.Sprintf("  … %d more rows in the Results panel", more))
+	}
+	return lines
+}
+
+// notebookRow pads values to the column widths
+func notebookRow(values []string, widths []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		v = clipCell(v, widths[i])
+		parts[i] = v + strings.Repeat(" ", widths[i]-len([]rune(v)))
+	}
+	return strings.Join(parts, " │ ")
+}
+
+// clipCell puts a value on one line and cuts it to width runes
+func clipCell(value string, width int) string {
+	runes := []rune(strings.Join(strings.Fields(value), " "))
+	if len(runes) <= width {
+		return string(runes)
+	}
+	if width < 1 {
+		return ""
+	}
+	return string(runes[:width-1]) + "…"
+}
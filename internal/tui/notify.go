@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notifyQueryDone alerts about a query that ran past the notify threshold
+// and finished while the terminal was unfocused. Terminals that don't
+// report focus are treated as focused.
+func (m *Model) notifyQueryDone(msg queryDoneMsg) tea.Cmd {
+	cfg := m.config.Notify
+	if cfg.After <= 0 || !m.unfocused || msg.cancelled {
+		return nil
+	}
+	if msg.elapsed < time.Duration(cfg.After)*time.Second {
+		return nil
+	}
+
+	connection, _ := m.activeNames()
+	elapsed := msg.elapsed.Round(time.Second)
+	body := fmt.Sprintf("Query on %s finished in %s", connection, elapsed)
+	if msg.err != nil {
+		body = fmt.Sprintf("Query on %s failed after %s", connection, elapsed)
+	}
+	method := cfg.Method
+	return func() tea.Msg {
+		notify(method, "SQDesk", body)
+		return nil
+	}
+}
+
+// terminal is the output of the program. The renderer writes each frame
+// with one Write, so alerts written through it land between frames rather
+// than inside one.
+var terminal = &lockedFile{File: os.Stdout}
+
+// lockedFile is a file whose writes don't interleave. It stays a terminal
+// file, so the program can still read its size and set raw mode.
+type lockedFile struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (f *lockedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.File.Write(p)
+}
+
+func (f *lockedFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// notify sends an alert by method, falling back to the terminal bell
+func notify(method, title, body string) {
+	switch method {
+	case "osc777":
+		// Supported by e.g. foot, WezTerm, Ghostty and VTE terminals
+		fmt.Fprintf(terminal, "\x1b]777;notify;%s;%s\x1b\\", oscText(title), oscText(body))
+		return
+	case "desktop":
+		if desktopNotify(title, body) == nil {
+			return
+		}
+	}
+	fmt.Fprint(terminal, "\a")
+}
+
+// desktopNotify shows a desktop notification through the OS
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		cmd = exec.Command("notify-send", title, body)
+	}
+	return cmd.Run()
+}
+
+// oscText strips characters that would end or break an OSC sequence
+func oscText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ';' || r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// pageDoneMsg carries a page of a query run a page at a time
+type pageDoneMsg struct {
+	query     string
+	offset    int
+	set       db.ResultSet
+	more      bool
+	toLast    bool // show the last page of the rows, when paging back
+	elapsed   time.Duration
+	cancelled bool
+	err       error
+}
+
+// pagedQuery returns the statement of sql to run a page at a time, when
+// page_rows is set and sql is a single query without a LIMIT of its own
+func (m *Model) pagedQuery(sql string, args []interface{}, isSelect bool) (string, bool) {
+	if m.config.PageRows <= 0 || !isSelect || len(args) > 0 || m.notebook {
+		return "", false
+	}
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	statements := sqlparse.Split(sql, dialect)
+	if len(statements) != 1 || !sqlparse.Pageable(statements[0].Text, dialect) {
+		return "", false
+	}
+	if _, ok := db.PageQuery(m.conn.connector.GetDriverName(), statements[0].Text, 1, 0); !ok {
+		return "", false
+	}
+	return statements[0].Text, true
+}
+
+// runFirstPage runs the first page of query like runQuery. One row more
+// than a page tells whether there are more.
+func runFirstPage(ctx context.Context, connector db.Connector, sql, query string, pageRows int, timeout time.Duration, isSelection bool) tea.Cmd {
+	paged, _ := db.PageQuery(connector.GetDriverName(), query, pageRows+1, 0)
+	run := runQuery(ctx, connector, paged, nil, timeout, true, isSelection)
+	return func() tea.Msg {
+		msg := run().(queryDoneMsg)
+		msg.sql = sql
+		if len(msg.sets) == 1 && len(msg.sets[0].Rows) > pageRows {
+			msg.sets[0].Rows = msg.sets[0].Rows[:pageRows]
+			msg.more = true
+		}
+		return msg
+	}
+}
+
+// runPage fetches the page of query starting at offset
+func runPage(ctx context.Context, connector db.Connector, query string, offset, pageRows int, timeout time.Duration, toLast bool) tea.Cmd {
+	paged, _ := db.PageQuery(connector.GetDriverName(), query, pageRows+1, offset)
+	return func() tea.Msg {
+		msg := pageDoneMsg{query: query, offset: offset, toLast: toLast}
+		start := time.Now()
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			sets, err := db.QueryMulti(ctx, connector, paged)
+			if err == nil && len(sets) > 0 {
+				msg.set = sets[0]
+			}
+			return err
+		})
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		if len(msg.set.Rows) > pageRows {
+			msg.set.Rows = msg.set.Rows[:pageRows]
+			msg.more = true
+		}
+		return msg
+	}
+}
+
+// FetchPage fetches the next page of rows of the shown query, or the
+// previous one when back is set. It reports false when there is none, so
+// the results pager moves within the fetched rows instead.
+func (m *Model) FetchPage(back bool) (tea.Cmd, bool) {
+	set := m.results.ActiveResult()
+	if m.config.PageRows <= 0 || set.Query == "" || m.conn.connector == nil || !m.conn.connected {
+		return nil, false
+	}
+	offset := set.RowOffset + m.config.PageRows
+	if back {
+		if set.RowOffset == 0 || !m.results.OnFirstPage() {
+			return nil, false
+		}
+		offset = max(set.RowOffset-m.config.PageRows, 0)
+	} else if !set.MoreRows || !m.results.OnLastPage() {
+		return nil, false
+	}
+	query, ok := m.pagedQuery(set.Query, nil, true)
+	if !ok {
+		return nil, false
+	}
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return nil, true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	label := fmt.Sprintf("Fetching rows %d-%d", offset+1, offset+m.config.PageRows)
+	m.statusMessage = label + "... (Esc to cancel)"
+	m.isError = false
+	timeout := m.config.QueryTimeoutFor(m.config.GetActiveConnection())
+	return tea.Batch(runPage(ctx, m.conn.connector, query, offset, m.config.PageRows, timeout, back), m.results.StartRunning(label)), true
+}
+
+// handlePageDone shows a fetched page in place of the shown one
+func (m *Model) handlePageDone(msg pageDoneMsg) {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+
+	if msg.cancelled || msg.err != nil {
+		// The rows shown so far stay
+		m.statusMessage = "Fetching rows cancelled"
+		if msg.err != nil && !msg.cancelled {
+			m.statusMessage = "Fetching rows failed: " + firstLine(msg.err.Error())
+		}
+		m.isError = true
+		return
+	}
+
+	set := msg.set
+	m.results.SetResultSets([]components.ResultSet{{
+		Columns:     set.Columns,
+		ColumnTypes: set.ColumnTypes,
+		Rows:        set.Rows,
+		Truncated:   set.Truncated,
+		Query:       msg.query,
+		RowOffset:   msg.offset,
+		MoreRows:    msg.more,
+		Elapsed:     msg.elapsed,
+	}})
+	if msg.toLast {
+		m.results.LastPage()
+	}
+	elapsed := components.FormatElapsed(msg.elapsed)
+	m.lastTiming = fmt.Sprintf("%d rows in %s", len(set.Rows), elapsed)
+	m.statusMessage = fmt.Sprintf("Rows %d-%d in %s", msg.offset+1, msg.offset+len(set.Rows), elapsed)
+	m.isError = false
+}
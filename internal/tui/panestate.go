@@ -0,0 +1,40 @@
+package tui
+
+import "github.com/febritecno/sqdesk-cli/internal/tui/components"
+
+// syncPaneStates sets the state each pane's border shows: the sidebar
+// while connecting, the editor while the AI writes to it, and the results
+// while a query runs, on an error and once the query was edited since
+func (m *Model) syncPaneStates() {
+	sidebar := components.PaneIdle
+	if m.conn.connecting() {
+		sidebar = components.PaneRunning
+	}
+	m.sidebar.SetState(sidebar)
+
+	editor := components.PaneIdle
+	if m.ai.running {
+		editor = components.PaneRunning
+	}
+	m.editor.SetState(editor)
+
+	results := components.PaneIdle
+	switch {
+	case m.queryRunning:
+		results = components.PaneRunning
+	case m.results.HasError():
+		results = components.PaneError
+	case m.resultsStale():
+		results = components.PaneStale
+	}
+	m.results.SetState(results)
+}
+
+// resultsStale reports whether the editor changed since the query whose
+// rows are shown was run
+func (m *Model) resultsStale() bool {
+	if m.resultsEditorText == "" || len(m.results.ActiveResult().Columns) == 0 {
+		return false
+	}
+	return m.editor.GetValue() != m.resultsEditorText
+}
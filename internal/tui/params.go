@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// paramQuery is a query with placeholders waiting for their values
+type paramQuery struct {
+	sql         string
+	params      []sqlparse.Param
+	isSelect    bool
+	isSelection bool
+	label       string
+}
+
+// promptParams opens the parameter prompt for a query with placeholders.
+// Values are bound by the driver, so the connector must support it and the
+// query must be a single statement.
+func (m *Model) promptParams(q paramQuery, statements int) tea.Cmd {
+	driver := m.conn.connector.GetDriverName()
+	if _, ok := db.GetParamQuerier(m.conn.connector); !ok {
+		m.results.SetError(fmt.Errorf("query parameters are not supported by %s", driver))
+		m.statusMessage = "Query parameters not supported"
+		m.isError = true
+		return nil
+	}
+	if statements > 1 {
+		m.results.SetError(fmt.Errorf("query parameters need a single statement, got %d", statements))
+		m.statusMessage = "Run one statement at a time to bind parameters"
+		m.isError = true
+		return nil
+	}
+
+	m.paramQuery = &q
+	m.params.Show(sqlparse.ParamNames(q.params), m.paramValues)
+	m.state = StateParamPrompt
+	return textinput.Blink
+}
+
+// runParamQuery binds the entered values and runs the waiting query
+func (m *Model) runParamQuery() tea.Cmd {
+	q := m.paramQuery
+	values := m.params.Values()
+	m.paramQuery = nil
+	if q == nil || m.conn.connector == nil {
+		return nil
+	}
+
+	if m.paramValues == nil {
+		m.paramValues = make(map[string]string)
+	}
+	for name, v := range values {
+		m.paramValues[name] = v
+	}
+
+	sql, names := sqlparse.Bind(q.sql, q.params, sqlparse.ParamStyleFor(m.conn.connector.GetDriverName()))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		if v := values[name]; !strings.EqualFold(v, "null") {
+			args[i] = v
+		}
+	}
+
+	m.lastQuery = q.sql
+	return m.startQuery(sql, args, q.isSelect, q.isSelection, q.label)
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// protectedTablesIn returns the connection's protected tables that sql
+// mentions
+func (m *Model) protectedTablesIn(sql string) []string {
+	conn := m.config.GetActiveConnection()
+	if conn == nil || m.conn.connector == nil {
+		return nil
+	}
+	return sqlparse.MentionedTables(sql, sqlparse.DialectFor(m.conn.connector.GetDriverName()), conn.ProtectedTables)
+}
+
+// guardProtected runs run right away when tables is empty, and otherwise
+// only after the user types the name of the first protected table
+func (m *Model) guardProtected(tables []string, run func() tea.Cmd) tea.Cmd {
+	if len(tables) == 0 {
+		return run()
+	}
+
+	message := fmt.Sprintf("This touches the protected table %s.", tables[0])
+	if len(tables) > 1 {
+		message = fmt.Sprintf("This touches the protected tables %s.", strings.Join(tables, ", "))
+	}
+	message += fmt.Sprintf(" Type %s to run it", tables[0])
+	m.askInput("🛡 Protected Table", message, "", func(text string) tea.Cmd {
+		if strings.TrimSpace(text) != tables[0] {
+			m.statusMessage = "Cancelled: the table name didn't match"
+			m.isError = true
+			return nil
+		}
+		return run()
+	})
+	return nil
+}
@@ -0,0 +1,243 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/hooks"
+	"github.com/febritecno/sqdesk-cli/internal/metrics"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// queryDoneMsg carries the outcome of a query run in the background
+type queryDoneMsg struct {
+	sql         string
+	isSelect    bool
+	isSelection bool
+	sets        []db.ResultSet // isSelect
+	affected    int64          // !isSelect
+	elapsed     time.Duration
+	cancelled   bool
+	stopped     bool // a streamed query stopped early, its rows kept
+	more        bool // a query run a page at a time has more rows
+	err         error
+}
+
+// runQuery runs sql off the event loop until it finishes, runs past
+// timeout (0 = no limit) or ctx is cancelled. Batches with a statement that
+// returns rows go through QueryMulti, others through Execute. With args the
+// statement goes through the connector's ParamQuerier.
+func runQuery(ctx context.Context, connector db.Connector, sql string, args []interface{}, timeout time.Duration, isSelect, isSelection bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := queryDoneMsg{sql: sql, isSelect: isSelect, isSelection: isSelection}
+		start := time.Now()
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			var err error
+			if p, ok := db.GetParamQuerier(connector); ok && len(args) > 0 {
+				if isSelect {
+					msg.sets, err = p.QueryParams(ctx, sql, args)
+				} else {
+					msg.affected, err = p.ExecuteParams(ctx, sql, args)
+				}
+				return err
+			}
+			if isSelect {
+				msg.sets, err = db.QueryMulti(ctx, connector, sql)
+			} else {
+				msg.affected, err = connector.Execute(ctx, sql)
+			}
+			return err
+		})
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+}
+
+// cancelQuery cancels the running query, reporting whether there was one.
+// The query stays running until its queryDoneMsg arrives.
+func (m *Model) cancelQuery() bool {
+	if m.queryCancel == nil {
+		return false
+	}
+	m.queryCancel()
+	m.queryCancel = nil
+	m.statusMessage = "Cancelling query..."
+	if m.streamRows > 0 {
+		m.statusMessage = fmt.Sprintf("Stopping at %d rows...", m.streamRows)
+	}
+	m.isError = false
+	return true
+}
+
+// hookDoneMsg reports the outcome of the query completion hooks
+type hookDoneMsg struct {
+	err error
+}
+
+// queryStatus classifies how a query finished
+func queryStatus(msg queryDoneMsg) string {
+	var timeout *db.QueryTimeoutError
+	switch {
+	case msg.cancelled:
+		return metrics.StatusCancelled
+	case errors.As(msg.err, &timeout):
+		return metrics.StatusTimeout
+	case msg.err != nil:
+		return metrics.StatusError
+	}
+	return metrics.StatusOK
+}
+
+// activeNames returns the active connection and driver names
+func (m *Model) activeNames() (connection, driver string) {
+	if conn := m.config.GetActiveConnection(); conn != nil {
+		connection = conn.Name
+	}
+	if m.conn.connector != nil {
+		driver = m.conn.connector.GetDriverName()
+	}
+	return connection, driver
+}
+
+// recordQueryMetrics reports a finished query to the metrics exporter
+func (m *Model) recordQueryMetrics(msg queryDoneMsg) {
+	q := metrics.Query{Duration: msg.elapsed, Status: queryStatus(msg)}
+	q.Connection, q.Driver = m.activeNames()
+	m.metrics.RecordQuery(q)
+}
+
+// runQueryHooks runs the configured hooks for a finished query in the
+// background. Queries the user cancelled don't run them.
+func (m *Model) runQueryHooks(msg queryDoneMsg) tea.Cmd {
+	if msg.cancelled || !m.hooks.Wants(msg.elapsed) {
+		return nil
+	}
+
+	rows := msg.affected
+	for _, set := range msg.sets {
+		rows += int64(len(set.Rows))
+	}
+	connection, driver := m.activeNames()
+	ev := hooks.NewEvent(connection, driver, msg.sql, queryStatus(msg), msg.elapsed, rows, msg.err)
+	runner := m.hooks
+	return func() tea.Msg {
+		return hookDoneMsg{err: runner.Run(ev)}
+	}
+}
+
+// handleHookDone reports failed hooks; successful ones stay silent
+func (m *Model) handleHookDone(msg hookDoneMsg) {
+	if msg.err != nil {
+		m.statusMessage = "Query hook failed: " + firstLine(msg.err.Error())
+		m.isError = true
+	}
+}
+
+// handleQueryDone shows the results of a finished query
+func (m *Model) handleQueryDone(msg queryDoneMsg) {
+	m.queryRunning = false
+	streamed := m.streamRows
+	m.streamRows = 0
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+	elapsed := components.FormatElapsed(msg.elapsed)
+	m.recordQueryMetrics(msg)
+
+	if msg.cancelled {
+		m.lastTiming = "cancelled after " + elapsed
+		// Drivers report cancellation in their own words
+		m.results.SetError(fmt.Errorf("query cancelled after %s", elapsed))
+		m.statusMessage = "Query cancelled"
+		m.isError = true
+		return
+	}
+
+	if msg.err != nil {
+		m.lastTiming = "failed after " + elapsed
+		m.results.SetError(msg.err)
+		var timeout *db.QueryTimeoutError
+		switch {
+		case errors.As(msg.err, &timeout):
+			m.statusMessage = "Query timed out"
+		case msg.isSelect && msg.isSelection:
+			m.statusMessage = "Selected query failed"
+		case msg.isSelect:
+			m.statusMessage = "Query failed"
+		case msg.isSelection:
+			m.statusMessage = "Selected execution failed"
+		default:
+			m.statusMessage = "Execution failed"
+		}
+		m.isError = true
+		return
+	}
+	m.isError = false
+
+	m.resultsEditorText = ""
+	if !msg.isSelect {
+		m.lastTiming = fmt.Sprintf("Affected %d rows in %s", msg.affected, elapsed)
+		m.results.SetMessage(fmt.Sprintf("Query executed successfully. %d rows affected in %s.", msg.affected, elapsed))
+		if msg.isSelection {
+			lines := len(strings.Split(msg.sql, "\n"))
+			m.statusMessage = fmt.Sprintf("Selected query (%d lines) affected %d rows in %s", lines, msg.affected, elapsed)
+		} else {
+			m.statusMessage = fmt.Sprintf("Affected %d rows in %s", msg.affected, elapsed)
+		}
+		return
+	}
+
+	if streamed > 0 && m.results.IsFetching() {
+		// The rows shown so far stay put; the last ones join them
+		set := msg.sets[0]
+		m.results.EndFetch(msg.sql, time.Now(), msg.elapsed, set.Rows[streamed:], set.Truncated)
+	} else {
+		tabs := make([]components.ResultSet, len(msg.sets))
+		for i, set := range msg.sets {
+			tabs[i] = components.ResultSet{Columns: set.Columns, ColumnTypes: set.ColumnTypes, Rows: set.Rows, Truncated: set.Truncated, Elapsed: msg.elapsed}
+		}
+		if len(tabs) == 1 {
+			tabs[0].Query = msg.sql
+			tabs[0].MoreRows = msg.more
+		}
+		m.results.AddRun(msg.sql, time.Now(), tabs)
+		// Default to table view for new results
+		m.results.SetViewMode(components.ViewTable)
+	}
+	m.resultsEditorText = m.runEditorText
+	m.lastTiming = fmt.Sprintf("%d rows in %s", m.results.GetRowCount(), elapsed)
+
+	if len(msg.sets) > 1 {
+		m.statusMessage = fmt.Sprintf("Query returned %d result sets in %s", len(msg.sets), elapsed)
+		return
+	}
+	rows := m.results.GetRowCount()
+	if msg.more {
+		m.statusMessage = fmt.Sprintf("Query returned its first %d rows in %s — PgDn past the last page fetches more", rows, elapsed)
+		return
+	}
+	if msg.stopped {
+		m.statusMessage = fmt.Sprintf("Stopped at %d rows in %s — export to file for the full set (Ctrl+O)", len(msg.sets[0].Rows), elapsed)
+		return
+	}
+	if len(msg.sets) == 1 && msg.sets[0].Truncated {
+		m.statusMessage = fmt.Sprintf("Truncated at %d rows — export to file for the full set (Ctrl+O)", rows)
+		m.isError = true
+		return
+	}
+	if msg.isSelection {
+		lines := len(strings.Split(msg.sql, "\n"))
+		m.statusMessage = fmt.Sprintf("Selected query (%d lines) returned %d rows in %s", lines, rows, elapsed)
+	} else {
+		m.statusMessage = fmt.Sprintf("Query returned %d rows in %s", rows, elapsed)
+	}
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// TogglePrimaryOnly switches between routing reads to the read replica and
+// sending every query to the primary
+func (m *Model) TogglePrimaryOnly() {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+	router, ok := db.GetReplicaRouter(m.conn.connector)
+	if !ok {
+		m.statusMessage = "No read replica configured (replica_host)"
+		m.isError = true
+		return
+	}
+	if m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return
+	}
+
+	router.SetPrimaryOnly(!router.PrimaryOnly())
+	if router.PrimaryOnly() {
+		m.statusMessage = "All queries go to the primary"
+	} else {
+		m.statusMessage = "Read-only queries go to the replica, writes to the primary"
+	}
+	m.isError = false
+}
+
+// renderEndpoint renders the header widget showing where the last query
+// ran, or "" without a read replica
+func (m *Model) renderEndpoint() string {
+	if m.conn.connector == nil {
+		return ""
+	}
+	router, ok := db.GetReplicaRouter(m.conn.connector)
+	if !ok {
+		return ""
+	}
+	if router.PrimaryOnly() {
+		return m.styles.WarningText.Render("⇢ primary only")
+	}
+	last := router.LastEndpoint()
+	if last == "" {
+		return m.styles.StatusItem.Render("⇢ reads on replica")
+	}
+	return m.styles.StatusItem.Render(fmt.Sprintf("⇢ last on %s", last))
+}
+
+// replicaHost describes the read replica for the connection info panel
+func replicaHost(host string, port int) string {
+	if host == "" {
+		return ""
+	}
+	if port == 0 {
+		return host
+	}
+	return fmt.Sprintf("%s:%d", host, port)
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+const (
+	// replicationRefresh is how often replication status is polled
+	replicationRefresh = 5 * time.Second
+	// replicationLagWarn is the lag above which the widget turns amber
+	replicationLagWarn = 10 * time.Second
+)
+
+// replicationTickMsg triggers a replication status poll
+type replicationTickMsg struct{}
+
+// replicationStatusMsg carries polled replication status
+type replicationStatusMsg struct {
+	statuses []db.ReplicaStatus
+	err      error
+}
+
+// replicationTick schedules the next replication poll
+func replicationTick() tea.Cmd {
+	return tea.Tick(replicationRefresh, func(time.Time) tea.Msg {
+		return replicationTickMsg{}
+	})
+}
+
+// pollReplication fetches replication status in the background
+func (m *Model) pollReplication() tea.Cmd {
+	if m.conn.connector == nil || !m.conn.connected {
+		return nil
+	}
+	if _, ok := m.conn.connector.(db.ReplicationReporter); !ok {
+		return nil
+	}
+
+	connector := m.conn.connector
+	return func() tea.Msg {
+		statuses, err := db.GetReplicationStatus(connector)
+		return replicationStatusMsg{statuses: statuses, err: err}
+	}
+}
+
+// handleReplicationMsg handles replication polling messages
+func (m *Model) handleReplicationMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case replicationTickMsg:
+		return tea.Batch(m.pollReplication(), replicationTick())
+	case replicationStatusMsg:
+		if !m.conn.connected {
+			return nil
+		}
+		m.replication = msg.statuses
+		m.replicationErr = msg.err
+	}
+	return nil
+}
+
+// renderReplication renders the header replication widget, or "" when
+// the server has nothing to report
+func (m *Model) renderReplication() string {
+	if !m.conn.connected {
+		return ""
+	}
+	if m.replicationErr != nil {
+		return m.styles.WarningText.Render("⇄ lag n/a")
+	}
+	if len(m.replication) == 0 {
+		return ""
+	}
+
+	// Summarise by the worst link
+	worst := m.replication[0]
+	for _, r := range m.replication[1:] {
+		if !r.LagKnown || (worst.LagKnown && r.Lag > worst.Lag) {
+			worst = r
+		}
+	}
+
+	var text string
+	if worst.Role == "replica" {
+		text = fmt.Sprintf("⇄ replica lag %s", worst.LagString())
+	} else {
+		text = fmt.Sprintf("⇄ %d replica(s) max lag %s", len(m.replication), worst.LagString())
+	}
+
+	if !worst.LagKnown || worst.Lag > replicationLagWarn {
+		return m.styles.WarningText.Render(text)
+	}
+	return m.styles.SuccessText.Render(text)
+}
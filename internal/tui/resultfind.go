@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FindCellOccurrences marks every fetched cell holding the value of the
+// selected cell, to spot duplicates and related rows
+func (m *Model) FindCellOccurrences() {
+	count, err := m.results.FindOccurrences()
+	if err != nil {
+		m.statusMessage = err.Error()
+		m.isError = true
+		return
+	}
+	m.statusMessage = fmt.Sprintf("%s: %d matching cells in fetched rows (n/N: next/previous, Esc: clear)", m.results.FindText(), count)
+	m.isError = false
+}
+
+// stepMatch moves to the next (or previous) cell holding the found value
+func (m *Model) stepMatch(forward bool) {
+	if m.results.MatchCount() == 0 {
+		m.statusMessage = "Press f on a cell to find its value"
+		m.isError = false
+		return
+	}
+	var pos int
+	if forward {
+		pos = m.results.NextMatch()
+	} else {
+		pos = m.results.PrevMatch()
+	}
+	m.statusMessage = fmt.Sprintf("%s: match %d of %d", m.results.FindText(), pos, m.results.MatchCount())
+	m.isError = false
+}
+
+// handleResultsFilterKeys edits the results filter bar: Enter keeps the
+// filter and returns to the rows, Esc clears it
+func (m *Model) handleResultsFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.results.EndFilter()
+		if filter := m.results.Filter(); filter != "" {
+			matched, total := m.results.FilterCounts()
+			m.statusMessage = fmt.Sprintf("Filter %s: %d of %d fetched rows (/ edits, Esc clears)", filter, matched, total)
+			m.isError = false
+		}
+		return m, nil
+	case "esc":
+		m.results.ClearFilter()
+		m.statusMessage = "Filter cleared"
+		m.isError = false
+		return m, nil
+	}
+	return m, m.results.UpdateFilter(msg)
+}
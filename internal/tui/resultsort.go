@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// SortResults sorts the fetched rows by the selected column without
+// running the query again
+func (m *Model) SortResults() {
+	if m.results.GetRowCount() == 0 {
+		m.statusMessage = "No rows to sort"
+		m.isError = true
+		return
+	}
+
+	dir := m.results.ToggleSort()
+	column, _ := m.results.SortColumn()
+	switch dir {
+	case components.SortAsc:
+		m.statusMessage = fmt.Sprintf("Sorted by %s ascending (fetched rows only)", column)
+	case components.SortDesc:
+		m.statusMessage = fmt.Sprintf("Sorted by %s descending (fetched rows only)", column)
+	default:
+		m.statusMessage = "Rows back in query order"
+	}
+	if m.results.ActiveResult().Truncated {
+		m.statusMessage += "; the result was truncated, add ORDER BY to sort every row"
+	}
+	m.isError = false
+}
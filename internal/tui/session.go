@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/export"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// Limits of the session log; older queries and further rows are dropped
+const (
+	sessionMaxEntries = 200
+	sessionMaxRows    = 50
+)
+
+// sessionEntry is a query run in this session, kept for the Markdown
+// session export
+type sessionEntry struct {
+	connection string
+	sql        string
+	tags       []string // #tags of the query's comments
+	at         time.Time
+	elapsed    time.Duration
+	isSelect   bool
+	sets       []db.ResultSet // rows cut to sessionMaxRows
+	rowCounts  []int          // rows of each set before cutting
+	affected   int64
+	cancelled  bool
+	err        error
+}
+
+// recordSession adds a finished query to the session log
+func (m *Model) recordSession(msg queryDoneMsg) {
+	entry := sessionEntry{
+		sql:       msg.sql,
+		at:        time.Now().Add(-msg.elapsed),
+		elapsed:   msg.elapsed,
+		isSelect:  msg.isSelect,
+		affected:  msg.affected,
+		cancelled: msg.cancelled,
+		err:       msg.err,
+	}
+	var driver string
+	entry.connection, driver = m.activeNames()
+	entry.tags = sqlparse.Tags(msg.sql, sqlparse.DialectFor(driver))
+	for _, set := range msg.sets {
+		entry.rowCounts = append(entry.rowCounts, len(set.Rows))
+		if len(set.Rows) > sessionMaxRows {
+			set.Rows = set.Rows[:sessionMaxRows]
+		}
+		entry.sets = append(entry.sets, set)
+	}
+
+	m.session = append(m.session, entry)
+	if len(m.session) > sessionMaxEntries {
+		m.session = m.session[len(m.session)-sessionMaxEntries:]
+	}
+}
+
+// ExportSession writes the queries of this session with their timings and
+// results to a Markdown file in the current directory
+func (m *Model) ExportSession() {
+	if len(m.session) == 0 {
+		m.statusMessage = "No queries in this session yet"
+		m.isError = true
+		return
+	}
+
+	path := fmt.Sprintf("sqdesk-session-%s.md", time.Now().Format("20060102-150405"))
+	f, err := os.Create(path)
+	if err != nil {
+		m.statusMessage = "Export failed: " + err.Error()
+		m.isError = true
+		return
+	}
+	err = writeSessionMarkdown(f, m.session, time.Now())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		m.statusMessage = "Export failed: " + err.Error()
+		m.isError = true
+		return
+	}
+	m.statusMessage = fmt.Sprintf("Exported %d queries to %s", len(m.session), path)
+	m.isError = false
+}
+
+// writeSessionMarkdown writes entries as a Markdown document: each
+// statement as a SQL block followed by its timing and result
+func writeSessionMarkdown(w io.Writer, entries []sessionEntry, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# SQDesk session\n\n_Exported %s, %d queries_\n", now.Format("2006-01-02 15:04"), len(entries))
+
+	for i, entry := range entries {
+		heading := entry.at.Format("15:04:05")
+		if entry.connection != "" {
+			heading = entry.connection + " · " + heading
+		}
+		fmt.Fprintf(bw, "\n## %d. %s\n\n", i+1, heading)
+		if len(entry.tags) > 0 {
+			fmt.Fprintf(bw, "Tags: %s\n\n", sqlparse.FormatTags(entry.tags))
+		}
+		fmt.Fprintf(bw, "```sql\n%s\n```\n\n", strings.TrimSpace(entry.sql))
+
+		elapsed := entry.elapsed.Round(time.Millisecond)
+		switch {
+		case entry.cancelled:
+			fmt.Fprintf(bw, "_Cancelled after %s_\n", elapsed)
+			continue
+		case entry.err != nil:
+			fmt.Fprintf(bw, "**Error** after %s:\n\n```\n%s\n```\n", elapsed, strings.TrimSpace(entry.err.Error()))
+			continue
+		case !entry.isSelect:
+			fmt.Fprintf(bw, "_%d rows affected in %s_\n", entry.affected, elapsed)
+			continue
+		}
+
+		for j, set := range entry.sets {
+			total := entry.rowCounts[j]
+			summary := fmt.Sprintf("%d rows in %s", total, elapsed)
+			if set.Truncated {
+				summary = fmt.Sprintf("%d rows (truncated) in %s", total, elapsed)
+			}
+			if len(set.Rows) < total {
+				summary += fmt.Sprintf(", showing the first %d", len(set.Rows))
+			}
+			if len(entry.sets) > 1 {
+				summary = fmt.Sprintf("Result %d: %s", j+1, summary)
+			}
+			fmt.Fprintf(bw, "_%s_\n\n", summary)
+			if len(set.Columns) == 0 {
+				continue
+			}
+			if _, err := export.WriteResultSet(bw, set, export.Options{Format: export.Markdown}); err != nil {
+				return err
+			}
+			if j < len(entry.sets)-1 {
+				bw.WriteString("\n")
+			}
+		}
+	}
+	return bw.Flush()
+}
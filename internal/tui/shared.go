@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+)
+
+// sharedSyncTimeout bounds a git clone or pull of the shared library
+const sharedSyncTimeout = 2 * time.Minute
+
+// sharedSyncDoneMsg carries the library re-read after a sync
+type sharedSyncDoneMsg struct {
+	lib *shared.Library
+	err error
+}
+
+// loadSharedLibrary merges the shared library into the config at startup.
+// A broken library only leaves it out.
+func (m *Model) loadSharedLibrary() {
+	lib, err := shared.Apply(m.config)
+	if err != nil {
+		m.statusMessage = "Shared library not loaded: " + err.Error()
+		m.isError = true
+		return
+	}
+	m.setSharedLibrary(lib)
+}
+
+// setSharedLibrary offers the snippets of lib in completion and the
+// snippet browser
+func (m *Model) setSharedLibrary(lib *shared.Library) {
+	m.sharedLib = lib
+	if lib != nil {
+		m.snippetSource.SetSnippets(lib.Snippets)
+	}
+	m.completionEngine.ClearCache()
+}
+
+// sharedReadOnly reports that a shared connection can't be changed here
+func (m *Model) sharedReadOnly(name string) {
+	m.statusMessage = name + " is a shared connection; change it in the shared repository"
+	m.isError = true
+}
+
+// ShowSnippets opens the browser of the user's and the shared snippets
+func (m *Model) ShowSnippets() {
+	items := m.snippetItems()
+	m.snippetBrowser.SetStatus("")
+	if len(items) == 0 {
+		m.snippetBrowser.SetStatus("No snippets yet; Ctrl+N saves the editor text as one")
+	}
+	m.snippetBrowser.Show("✂️  Snippets", items)
+	m.state = StateSnippets
+}
+
+// updateSnippets handles the snippet browser
+func (m *Model) updateSnippets(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.snippetBrowser.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "up":
+		m.snippetBrowser.Move(-1)
+		return m, nil
+	case "down":
+		m.snippetBrowser.Move(1)
+		return m, nil
+	case "pgup", "ctrl+u":
+		m.snippetBrowser.Move(-10)
+		return m, nil
+	case "pgdown", "ctrl+d":
+		m.snippetBrowser.Move(10)
+		return m, nil
+	case "ctrl+r":
+		m.snippetBrowser.Hide()
+		m.state = StateNormal
+		return m, m.SyncShared()
+	case "ctrl+n":
+		m.promptNewSnippet()
+		return m, nil
+	case "ctrl+e":
+		return m, m.editSnippet()
+	case "ctrl+x":
+		m.confirmDeleteSnippet()
+		return m, nil
+	case "enter":
+		item, ok := m.snippetBrowser.Selected()
+		if !ok {
+			return m, nil
+		}
+		m.snippetBrowser.Hide()
+		m.state = StateNormal
+		m.insertSnippet(item.Key)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.snippetBrowser, cmd = m.snippetBrowser.Update(msg)
+	return m, cmd
+}
+
+// SyncShared pulls the shared library in the background and re-reads it
+func (m *Model) SyncShared() tea.Cmd {
+	if !m.config.Shared.Enabled() {
+		m.statusMessage = "No shared library; set shared.repo or shared.dir in the config"
+		m.isError = true
+		return nil
+	}
+	if m.syncRunning {
+		m.statusMessage = "The shared library is already syncing"
+		m.isError = true
+		return nil
+	}
+	m.syncRunning = true
+	m.statusMessage = "Syncing the shared library..."
+	m.isError = false
+
+	cfg := m.config.Shared
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedSyncTimeout)
+		defer cancel()
+		if _, err := shared.Sync(ctx, cfg); err != nil {
+			return sharedSyncDoneMsg{err: err}
+		}
+		dir, err := cfg.SharedDir()
+		if err != nil {
+			return sharedSyncDoneMsg{err: err}
+		}
+		lib, err := shared.Load(dir)
+		return sharedSyncDoneMsg{lib: lib, err: err}
+	}
+}
+
+// handleSharedSyncDone merges the synced library over the config
+func (m *Model) handleSharedSyncDone(msg sharedSyncDoneMsg) {
+	m.syncRunning = false
+	if msg.err != nil {
+		m.statusMessage = "Sync failed: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+
+	m.config.MergeShared(msg.lib.Connections)
+	m.loadConnections()
+	m.setSharedLibrary(msg.lib)
+	m.statusMessage = fmt.Sprintf("Shared library synced: %d snippets, %d connections", len(msg.lib.Snippets), len(msg.lib.Connections))
+	m.isError = false
+}
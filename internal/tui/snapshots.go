@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/snapshot"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// snapshotSavedMsg reports a result snapshot written in the background
+type snapshotSavedMsg struct {
+	err error
+}
+
+// snapshotLoadedMsg carries a snapshot read in the background
+type snapshotLoadedMsg struct {
+	meta snapshot.Meta
+	sets []db.ResultSet
+	err  error
+}
+
+// saveSnapshot writes the results of a successful query to the snapshot
+// store when snapshots are enabled
+func (m *Model) saveSnapshot(msg queryDoneMsg) tea.Cmd {
+	if !m.config.Snapshots.Enabled || m.snapshots == nil {
+		return nil
+	}
+	if !msg.isSelect || msg.err != nil || msg.cancelled {
+		return nil
+	}
+
+	meta := snapshot.Meta{
+		Time:       time.Now(),
+		Query:      msg.sql,
+		DurationMs: msg.elapsed.Milliseconds(),
+	}
+	meta.Connection, meta.Driver = m.activeNames()
+	meta.Tags = sqlparse.Tags(msg.sql, sqlparse.DialectFor(meta.Driver))
+	if m.conn.connector != nil {
+		meta.Database = m.conn.connector.GetDatabaseName()
+	}
+	store, sets := m.snapshots, msg.sets
+	return func() tea.Msg {
+		_, err := store.Save(meta, sets)
+		return snapshotSavedMsg{err: err}
+	}
+}
+
+// handleSnapshotSaved reports failed snapshots; saving is otherwise silent
+func (m *Model) handleSnapshotSaved(msg snapshotSavedMsg) {
+	if msg.err != nil {
+		m.statusMessage = "Snapshot failed: " + msg.err.Error()
+		m.isError = true
+	}
+}
+
+// ShowSnapshots opens the browser of saved result snapshots
+func (m *Model) ShowSnapshots() {
+	if m.snapshots == nil {
+		m.statusMessage = "Snapshots are not available"
+		m.isError = true
+		return
+	}
+
+	metas, err := m.snapshots.List()
+	if err != nil {
+		m.statusMessage = "Failed to list snapshots: " + err.Error()
+		m.isError = true
+		return
+	}
+	if len(metas) == 0 {
+		if m.config.Snapshots.Enabled {
+			m.statusMessage = "No snapshots yet in " + m.snapshots.Dir()
+		} else {
+			m.statusMessage = "No snapshots; enable snapshots.enabled in the config to save results"
+		}
+		m.isError = false
+		return
+	}
+
+	items := make([]components.VariableItem, len(metas))
+	for i, meta := range metas {
+		name := meta.Time.Format("2006-01-02 15:04:05")
+		if meta.Connection != "" {
+			name += "  " + meta.Connection
+		}
+		value := fmt.Sprintf("%d rows", meta.Rows)
+		if meta.Truncated {
+			value += " (truncated)"
+		}
+		if len(meta.Tags) > 0 {
+			value = sqlparse.FormatTags(meta.Tags) + " · " + value
+		}
+		items[i] = components.VariableItem{
+			Name:        name,
+			Value:       value + " · " + firstLine(meta.Query),
+			Description: strings.Join(strings.Fields(meta.Query), " "),
+			Key:         meta.ID,
+			Tags:        meta.Tags,
+		}
+	}
+
+	m.snapshotBrowser.Show("🕓 Result Snapshots", items)
+	m.state = StateSnapshots
+}
+
+// promptSnapshotTags asks for the tags of the selected snapshot and
+// reopens the browser once they are saved
+func (m *Model) promptSnapshotTags() {
+	item, ok := m.snapshotBrowser.Selected()
+	if !ok {
+		return
+	}
+	m.snapshotBrowser.Hide()
+	m.state = StateNormal
+	m.askInput("🏷  Snapshot tags", "Tags separated by spaces, e.g. #billing #incident-1234", sqlparse.FormatTags(item.Tags), func(text string) tea.Cmd {
+		meta, err := m.snapshots.SetTags(item.Key, sqlparse.ParseTags(text))
+		m.ShowSnapshots()
+		if err != nil {
+			m.statusMessage = "Failed to tag snapshot: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		status := "Tags removed"
+		if len(meta.Tags) > 0 {
+			status = "Tagged " + sqlparse.FormatTags(meta.Tags)
+		}
+		m.snapshotBrowser.SetStatus(status)
+		return nil
+	})
+}
+
+// loadSnapshot reads the selected snapshot off the event loop
+func (m *Model) loadSnapshot(id string) tea.Cmd {
+	store := m.snapshots
+	return func() tea.Msg {
+		meta, sets, err := store.Load(id)
+		return snapshotLoadedMsg{meta: meta, sets: sets, err: err}
+	}
+}
+
+// handleSnapshotLoaded shows a snapshot's results in the results pane
+func (m *Model) handleSnapshotLoaded(msg snapshotLoadedMsg) {
+	if msg.err != nil {
+		m.statusMessage = "Failed to open snapshot: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+
+	tabs := make([]components.ResultSet, len(msg.sets))
+	for i, set := range msg.sets {
+		tabs[i] = components.ResultSet{Columns: set.Columns, ColumnTypes: set.ColumnTypes, Rows: set.Rows, Truncated: set.Truncated}
+	}
+	m.results.SetResultSets(tabs)
+	m.results.SetViewMode(components.ViewTable)
+
+	source := msg.meta.Connection
+	if source == "" {
+		source = msg.meta.Driver
+	}
+	m.statusMessage = fmt.Sprintf("Snapshot of %s on %s: %s", msg.meta.Time.Format("2006-01-02 15:04:05"), source, firstLine(msg.meta.Query))
+	if len(msg.meta.Tags) > 0 {
+		m.statusMessage += " " + sqlparse.FormatTags(msg.meta.Tags)
+	}
+	m.isError = false
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/shared"
+	"github.com/febritecno/sqdesk-cli/internal/snippets"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// snippetPreviewLines is how much of the selected snippet the browser shows
+const snippetPreviewLines = 6
+
+// Origins of the snippets in the browser, the prefix of their item keys
+const (
+	snippetUser   = "user:"
+	snippetShared = "shared:"
+)
+
+// snippetEditedMsg reports the external editor closed on a snippet file
+type snippetEditedMsg struct {
+	name string
+	err  error
+}
+
+// loadUserSnippets reads the user's snippets and offers them in completion.
+// A broken directory only leaves them out.
+func (m *Model) loadUserSnippets() error {
+	if m.snippetsDir == "" {
+		dir, err := snippets.Dir()
+		if err != nil {
+			return err
+		}
+		m.snippetsDir = dir
+	}
+	list, err := snippets.Load(m.snippetsDir)
+	if err != nil {
+		return err
+	}
+	m.userSnippets = list
+	m.userSnippetSource.SetSnippets(list)
+	m.completionEngine.ClearCache()
+	return nil
+}
+
+// findSnippet returns the snippet of a browser item key
+func (m *Model) findSnippet(key string) (shared.Snippet, bool) {
+	list := m.userSnippets
+	name, ok := strings.CutPrefix(key, snippetUser)
+	if !ok {
+		name = strings.TrimPrefix(key, snippetShared)
+		list = nil
+		if m.sharedLib != nil {
+			list = m.sharedLib.Snippets
+		}
+	}
+	for _, s := range list {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return shared.Snippet{}, false
+}
+
+// insertSnippet inserts the snippet of a browser item key. The user's
+// snippets expand their tab stops; shared ones go in as they are, since
+// their $1 may be a query parameter.
+func (m *Model) insertSnippet(key string) {
+	s, ok := m.findSnippet(key)
+	if !ok {
+		return
+	}
+	if strings.HasPrefix(key, snippetUser) {
+		m.editor.InsertSnippet(snippets.Body(s))
+	} else {
+		m.editor.InsertText(s.SQL)
+	}
+	m.FocusEditor()
+	m.statusMessage = "Inserted snippet " + s.Name
+	m.isError = false
+}
+
+// promptNewSnippet asks for the name to save the selection, or the editor
+// text, as a snippet under
+func (m *Model) promptNewSnippet() {
+	sql := strings.TrimSpace(m.editor.GetSelectedText())
+	if sql == "" {
+		m.snippetBrowser.SetStatus("Write the snippet in the editor first")
+		return
+	}
+	m.snippetBrowser.Hide()
+	m.askInput("✂️  New snippet", "Name, e.g. selcount; mark tab stops in the text with $1 or ${1:placeholder}", "", func(name string) tea.Cmd {
+		if err := snippets.Save(m.snippetsDir, name, "", sql); err != nil {
+			m.statusMessage = "Failed to save snippet: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		m.reopenSnippets("Saved snippet " + strings.TrimSpace(name))
+		return nil
+	})
+}
+
+// editSnippet opens the file of the selected snippet in the external
+// editor; the shared ones belong to the shared repository
+func (m *Model) editSnippet() tea.Cmd {
+	item, ok := m.snippetBrowser.Selected()
+	if !ok {
+		return nil
+	}
+	name, ok := strings.CutPrefix(item.Key, snippetUser)
+	if !ok {
+		m.snippetBrowser.SetStatus(item.Name + " is a shared snippet; change it in the shared repository")
+		return nil
+	}
+	path, err := snippets.Path(m.snippetsDir, name)
+	if err != nil {
+		m.snippetBrowser.SetStatus(err.Error())
+		return nil
+	}
+	editor := externalEditor(m.config.Editor)
+	if editor == nil {
+		m.snippetBrowser.SetStatus("No editor found; set editor in the config or $EDITOR")
+		return nil
+	}
+
+	m.snippetBrowser.Hide()
+	m.state = StateNormal
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return snippetEditedMsg{name: name, err: err}
+	})
+}
+
+// externalEditor returns the command line of the editor to open files in:
+// the configured one, else $VISUAL or $EDITOR, else vi; nil if none is
+// installed
+func externalEditor(configured string) []string {
+	for _, candidate := range []string{configured, os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi"} {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, err := exec.LookPath(fields[0]); err == nil {
+			return fields
+		}
+	}
+	return nil
+}
+
+// handleSnippetEdited re-reads the snippets after the external editor
+// closed and shows the browser again
+func (m *Model) handleSnippetEdited(msg snippetEditedMsg) {
+	if msg.err != nil {
+		m.statusMessage = "Editor failed: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+	m.reopenSnippets("Saved snippet " + msg.name)
+}
+
+// confirmDeleteSnippet removes the selected snippet once confirmed
+func (m *Model) confirmDeleteSnippet() {
+	item, ok := m.snippetBrowser.Selected()
+	if !ok {
+		return
+	}
+	name, ok := strings.CutPrefix(item.Key, snippetUser)
+	if !ok {
+		m.snippetBrowser.SetStatus(item.Name + " is a shared snippet; remove it in the shared repository")
+		return
+	}
+	m.snippetBrowser.Hide()
+	m.askConfirm("🗑  Delete snippet", "Delete the snippet "+name+"?", func() tea.Cmd {
+		if err := snippets.Delete(m.snippetsDir, name); err != nil {
+			m.statusMessage = "Failed to delete snippet: " + err.Error()
+			m.isError = true
+			return nil
+		}
+		m.reopenSnippets("Deleted snippet " + name)
+		return nil
+	})
+}
+
+// reopenSnippets re-reads the user's snippets and shows the browser with
+// status
+func (m *Model) reopenSnippets(status string) {
+	if err := m.loadUserSnippets(); err != nil {
+		m.statusMessage = "Failed to read snippets: " + err.Error()
+		m.isError = true
+		return
+	}
+	m.ShowSnippets()
+	m.snippetBrowser.SetStatus(status)
+	m.statusMessage = status
+	m.isError = false
+}
+
+// snippetItems lists the user's snippets and then the shared ones for the
+// browser
+func (m *Model) snippetItems() []components.VariableItem {
+	var items []components.VariableItem
+	for _, s := range m.userSnippets {
+		items = append(items, components.VariableItem{
+			Name:        s.Name,
+			Value:       s.Description,
+			Description: snippets.Body(s),
+			Key:         snippetUser + s.Name,
+		})
+	}
+	if m.sharedLib != nil {
+		for _, s := range m.sharedLib.Snippets {
+			value := "shared"
+			if s.Description != "" {
+				value += " · " + s.Description
+			}
+			items = append(items, components.VariableItem{
+				Name:        s.Name,
+				Value:       value,
+				Description: s.SQL,
+				Key:         snippetShared + s.Name,
+			})
+		}
+	}
+	return items
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// streamInterval is how often rows of a streamed query are shown
+const streamInterval = 200 * time.Millisecond
+
+// queryBatchMsg carries the rows of a streamed query read since the last
+// batch; more batches and then a queryDoneMsg arrive from stream
+type queryBatchMsg struct {
+	columns []db.ColumnType
+	rows    []db.Row
+	stream  <-chan tea.Msg
+}
+
+// streamer returns the connector's row streaming when sql, a single
+// read-only query without parameters, should show its rows as they arrive.
+// Writes with RETURNING aren't streamed: stopping one aborts the write,
+// which must not show as a result.
+func (m *Model) streamer(sql string, args []interface{}, isSelect bool) (db.RowStreamer, bool) {
+	if !m.config.StreamResults || !isSelect || len(args) > 0 || m.notebook {
+		return nil, false
+	}
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	if len(sqlparse.Split(sql, dialect)) != 1 || !sqlparse.IsReadOnly(sql, dialect) {
+		return nil, false
+	}
+	return db.GetRowStreamer(m.conn.connector)
+}
+
+// streamQuery runs sql, a read-only query from streamer, like runQuery,
+// sending its rows in batches as they arrive. Cancelling ctx after rows
+// arrived stops the fetch, keeping them as a truncated result.
+func streamQuery(ctx context.Context, streamer db.RowStreamer, sql string, limit db.ResultLimit, timeout time.Duration, isSelection bool) tea.Cmd {
+	stream := make(chan tea.Msg)
+	go func() {
+		defer close(stream)
+		msg := queryDoneMsg{sql: sql, isSelect: true, isSelection: isSelection}
+		start := time.Now()
+		var set db.ResultSet
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			var err error
+			set, err = db.StreamBatches(ctx, streamer, sql, limit, streamInterval, func(columns []db.ColumnType, rows []db.Row) error {
+				select {
+				case stream <- queryBatchMsg{columns: columns, rows: rows, stream: stream}:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			return err
+		})
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		if msg.cancelled && len(set.Rows) > 0 {
+			msg.err, msg.cancelled, msg.stopped = nil, false, true
+			set.Truncated = true
+		}
+		if msg.err == nil && !msg.cancelled {
+			msg.sets = []db.ResultSet{set}
+		}
+		stream <- msg
+	}()
+	return waitQueryStream(stream)
+}
+
+// waitQueryStream waits for the next message of a streamed query
+func waitQueryStream(stream <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-stream
+	}
+}
+
+// handleQueryBatch shows rows of a streamed query as they arrive
+func (m *Model) handleQueryBatch(msg queryBatchMsg) tea.Cmd {
+	if m.streamRows == 0 {
+		m.results.StopRunning()
+		names := make([]string, len(msg.columns))
+		for i, col := range msg.columns {
+			names[i] = col.Name
+		}
+		m.results.StartFetch(components.ResultSet{Columns: names, ColumnTypes: msg.columns, Rows: msg.rows})
+	} else {
+		m.results.AppendRows(msg.rows)
+	}
+	m.streamRows += len(msg.rows)
+	if m.queryCancel != nil {
+		m.statusMessage = fmt.Sprintf("Fetched %d rows... (Esc to stop)", m.streamRows)
+		m.isError = false
+	}
+	return waitQueryStream(msg.stream)
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// ShowTableStructure shows the columns, indexes and foreign keys of a table
+// as result tabs, read fresh from the database in the background
+func (m *Model) ShowTableStructure(tableName string) tea.Cmd {
+	var columns []db.Column
+	var indexes []db.Index
+	var foreignKeys []db.ForeignKey
+	read := func(connector db.Connector) error {
+		var err error
+		if columns, err = connector.GetColumns(tableName); err != nil {
+			return err
+		}
+		// Indexes and foreign keys are best effort, as in GetSchema
+		indexes, _ = connector.GetIndexes(tableName)
+		foreignKeys, _ = db.GetForeignKeys(connector, tableName)
+		return nil
+	}
+	return m.introspect("Reading structure of "+tableName, read, func(err error) tea.Cmd {
+		if err != nil {
+			m.statusMessage = err.Error()
+			m.isError = true
+			return nil
+		}
+		m.showTableStructure(tableName, columns, indexes, foreignKeys)
+		return nil
+	})
+}
+
+// showTableStructure fills the result tabs with a table's structure
+func (m *Model) showTableStructure(tableName string, columns []db.Column, indexes []db.Index, foreignKeys []db.ForeignKey) {
+	// A column's key: PK, FK and UNIQUE for single-column unique indexes
+	references := make(map[string]string)
+	for _, fk := range foreignKeys {
+		for i, col := range fk.Columns {
+			references[col] = fk.RefTable + "." + fk.RefColumns[i]
+		}
+	}
+	unique := make(map[string]bool)
+	for _, idx := range indexes {
+		if idx.Unique && !idx.Primary && len(idx.Columns) == 1 {
+			unique[idx.Columns[0]] = true
+		}
+	}
+
+	colSet := structureSet("Columns", "column", "type", "nullable", "default", "key", "references")
+	for _, col := range columns {
+		var keys []string
+		if col.IsPK {
+			keys = append(keys, "PK")
+		}
+		if references[col.Name] != "" {
+			keys = append(keys, "FK")
+		}
+		if unique[col.Name] {
+			keys = append(keys, "UNIQUE")
+		}
+		colSet.Rows = append(colSet.Rows, structureRow(colSet.Columns,
+			col.Name, col.Type, yesNo(col.Nullable), col.Default, strings.Join(keys, ", "), references[col.Name]))
+	}
+	sets := []components.ResultSet{colSet}
+
+	if len(indexes) > 0 {
+		idxSet := structureSet("Indexes", "index", "columns", "unique", "primary", "method")
+		for _, idx := range indexes {
+			idxSet.Rows = append(idxSet.Rows, structureRow(idxSet.Columns,
+				idx.Name, strings.Join(idx.Columns, ", "), yesNo(idx.Unique), yesNo(idx.Primary), idx.Method))
+		}
+		sets = append(sets, idxSet)
+	}
+
+	if len(foreignKeys) > 0 {
+		fkSet := structureSet("Foreign Keys", "constraint", "columns", "references", "on delete", "on update")
+		for _, fk := range foreignKeys {
+			fkSet.Rows = append(fkSet.Rows, structureRow(fkSet.Columns,
+				fk.Name, strings.Join(fk.Columns, ", "), fk.RefTable+" ("+strings.Join(fk.RefColumns, ", ")+")", fk.OnDelete, fk.OnUpdate))
+		}
+		sets = append(sets, fkSet)
+	}
+
+	m.results.SetResultSets(sets)
+	m.results.SetViewMode(components.ViewTable)
+	m.FocusResults()
+	m.statusMessage = "Structure of " + tableName + " ([/]: columns, indexes, foreign keys)"
+	m.isError = false
+}
+
+// structureSet starts a result tab of text columns
+func structureSet(title string, columns ...string) components.ResultSet {
+	types := make([]db.ColumnType, len(columns))
+	for i, col := range columns {
+		types[i] = db.ColumnType{Name: col, Kind: db.ColumnText}
+	}
+	return components.ResultSet{Title: title, Columns: columns, ColumnTypes: types}
+}
+
+// structureRow builds a row of text values, empty values shown as blank
+func structureRow(columns []string, values ...string) db.Row {
+	row := make(db.Row, len(columns))
+	for i, col := range columns {
+		row[col] = db.Value{Data: values[i]}
+	}
+	return row
+}
+
+// yesNo renders a flag for the structure view
+func yesNo(b bool) string {
+	if b {
+		return "YES"
+	}
+	return "NO"
+}
@@ -30,6 +30,9 @@ type Styles struct {
 	// Results table styles
 	ResultsHeader lipgloss.Style
 	ResultsCell   lipgloss.Style
+	ResultsNull   lipgloss.Style
+	ResultsNumber lipgloss.Style
+	ResultsBool   lipgloss.Style
 	ResultsRow    lipgloss.Style
 	
 	// Status bar styles
@@ -87,6 +90,7 @@ type ThemeColors struct {
 	Info             lipgloss.Color
 	Border           lipgloss.Color
 	BorderFocus      lipgloss.Color
+	Stale            lipgloss.Color // border of results older than the query
 	Selection        lipgloss.Color
 	GhostText        lipgloss.Color
 }
@@ -177,6 +181,16 @@ func NewStyles(colors ThemeColors) *Styles {
 		Foreground(colors.Text).
 		Padding(0, 1)
 	
+	s.ResultsNull = lipgloss.NewStyle().
+		Foreground(colors.TextMuted).
+		Italic(true)
+	
+	s.ResultsNumber = lipgloss.NewStyle().
+		Foreground(colors.Info)
+	
+	s.ResultsBool = lipgloss.NewStyle().
+		Foreground(colors.Accent)
+	
 	s.ResultsRow = lipgloss.NewStyle().
 		Background(colors.Background)
 	
@@ -304,6 +318,7 @@ func GetDraculaColors() ThemeColors {
 		Info:             lipgloss.Color("#8be9fd"),
 		Border:           lipgloss.Color("#44475a"),
 		BorderFocus:      lipgloss.Color("#bd93f9"),
+		Stale:            lipgloss.Color("#9580ff"),
 		Selection:        lipgloss.Color("#44475a"),
 		GhostText:        lipgloss.Color("#6272a4"),
 	}
@@ -327,6 +342,7 @@ func GetNordColors() ThemeColors {
 		Info:             lipgloss.Color("#88c0d0"),
 		Border:           lipgloss.Color("#3b4252"),
 		BorderFocus:      lipgloss.Color("#88c0d0"),
+		Stale:            lipgloss.Color("#b48ead"),
 		Selection:        lipgloss.Color("#434c5e"),
 		GhostText:        lipgloss.Color("#4c566a"),
 	}
@@ -350,6 +366,7 @@ func GetDefaultColors() ThemeColors {
 		Info:             lipgloss.Color("#3794ff"),
 		Border:           lipgloss.Color("#404040"),
 		BorderFocus:      lipgloss.Color("#007acc"),
+		Stale:            lipgloss.Color("#c586c0"),
 		Selection:        lipgloss.Color("#264f78"),
 		GhostText:        lipgloss.Color("#5a5a5a"),
 	}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/sqlparse"
+)
+
+// tempTableDoneMsg carries the outcome of materializing a query into a
+// temporary table
+type tempTableDoneMsg struct {
+	name      string
+	rows      int64
+	columns   []db.ColumnType
+	elapsed   time.Duration
+	cancelled bool
+	err       error
+}
+
+// tempTableQuery returns the last query when it can be materialized: one
+// statement that returns rows, on a driver with temporary tables
+func (m *Model) tempTableQuery() (string, bool) {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return "", false
+	}
+	if m.queryRunning {
+		m.statusMessage = "A query is already running"
+		m.isError = true
+		return "", false
+	}
+	if strings.TrimSpace(m.lastQuery) == "" {
+		m.statusMessage = "Run a query first, then save it as a table"
+		m.isError = true
+		return "", false
+	}
+	if _, ok := db.GetTempTableCreator(m.conn.connector); !ok {
+		m.statusMessage = "Temporary tables are not supported for this driver"
+		m.isError = true
+		return "", false
+	}
+
+	dialect := sqlparse.DialectFor(m.conn.connector.GetDriverName())
+	statements := sqlparse.Split(m.lastQuery, dialect)
+	if len(statements) != 1 || sqlparse.Classify(statements[0].Text, dialect) != sqlparse.KindQuery {
+		m.statusMessage = "Only a single query returning rows can be saved as a table"
+		m.isError = true
+		return "", false
+	}
+	return statements[0].Text, true
+}
+
+// PromptTempTable asks for the name of the temporary table to create from
+// the last query
+func (m *Model) PromptTempTable() {
+	if _, ok := m.tempTableQuery(); !ok {
+		return
+	}
+
+	creator, _ := db.GetTempTableCreator(m.conn.connector)
+	name := fmt.Sprintf("tmp_result_%d", len(creator.TempTables())+1)
+	m.askInput("📥 Save Results as Temporary Table", "The table lives until you disconnect or switch databases", name, m.CreateTempTable)
+}
+
+// CreateTempTable materializes the last query into table name. The query
+// runs again, so the table holds all its rows, not only the fetched ones.
+func (m *Model) CreateTempTable(name string) tea.Cmd {
+	query, ok := m.tempTableQuery()
+	if !ok {
+		return nil
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		m.statusMessage = "Table name is empty"
+		m.isError = true
+		return nil
+	}
+
+	creator, _ := db.GetTempTableCreator(m.conn.connector)
+	columns := m.results.ColumnTypes()
+	timeout := m.config.QueryTimeoutFor(m.config.GetActiveConnection())
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queryRunning = true
+	m.queryCancel = cancel
+	m.statusMessage = "Creating temporary table " + name + "... (Esc to cancel)"
+	m.isError = false
+
+	run := func() tea.Msg {
+		msg := tempTableDoneMsg{name: name, columns: columns}
+		start := time.Now()
+		msg.err = db.WithQueryTimeout(ctx, timeout, func(ctx context.Context) error {
+			var err error
+			msg.rows, err = creator.CreateTempTable(ctx, name, query)
+			return err
+		})
+		msg.elapsed = time.Since(start)
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+	return tea.Batch(run, m.results.StartRunning("Creating table"))
+}
+
+// handleTempTableDone registers a new temporary table with the sidebar and
+// completion
+func (m *Model) handleTempTableDone(msg tempTableDoneMsg) {
+	m.queryRunning = false
+	if m.queryCancel != nil {
+		m.queryCancel()
+		m.queryCancel = nil
+	}
+	m.results.StopRunning()
+
+	switch {
+	case msg.cancelled:
+		m.statusMessage = "Temporary table cancelled"
+		m.isError = true
+		return
+	case msg.err != nil:
+		m.statusMessage = "Failed to create temporary table: " + msg.err.Error()
+		m.isError = true
+		return
+	}
+
+	m.addTable(msg.name, msg.columns)
+	m.statusMessage = fmt.Sprintf("Saved %d rows as temporary table %s (%s)", msg.rows, msg.name, msg.elapsed.Round(time.Millisecond))
+	m.isError = false
+}
+
+// addTable lists a table the schema doesn't know about yet, with the columns
+// of the results it was created from
+func (m *Model) addTable(name string, columns []db.ColumnType) {
+	found := false
+	for _, t := range m.tables {
+		if t == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		m.tables = append(m.tables, name)
+		m.sidebar.SetTables(m.tables)
+	}
+
+	if m.schema == nil {
+		m.schema = &db.Schema{Tables: map[string]db.Table{}}
+	}
+	table := db.Table{Name: name}
+	for _, col := range columns {
+		table.Columns = append(table.Columns, db.Column{Name: col.Name, Type: col.DatabaseType, Nullable: true})
+	}
+	m.schema.Tables[name] = table
+	m.applySchema(m.schema)
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/timefilter"
+)
+
+// defaultTimeColumn is offered when neither the config nor an earlier
+// filter names a column
+const defaultTimeColumn = "created_at"
+
+// ShowTimeFilter opens the menu of time predicates to insert at the cursor
+func (m *Model) ShowTimeFilter() {
+	presets := timefilter.Presets()
+	labels := make([]string, len(presets))
+	for i, preset := range presets {
+		labels[i] = preset.String()
+	}
+	m.timeMenu.Show("🕒 Insert time filter", labels)
+	m.state = StateTimeMenu
+}
+
+// updateTimeMenu handles the time filter menu
+func (m *Model) updateTimeMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.timeMenu.Hide()
+		m.state = StateNormal
+	case "up", "k":
+		m.timeMenu.MoveUp()
+	case "down", "j":
+		m.timeMenu.MoveDown()
+	case "enter":
+		presets := timefilter.Presets()
+		index := m.timeMenu.Selected()
+		m.timeMenu.Hide()
+		m.state = StateNormal
+		if index < 0 || index >= len(presets) {
+			return m, nil
+		}
+		m.askTimeColumn(timefilter.Range{
+			Preset:    presets[index],
+			WeekStart: timefilter.ParseWeekStart(m.config.TimeFilter.WeekStart),
+		})
+	}
+	return m, nil
+}
+
+// askTimeColumn asks which column to filter, then the days of a Between
+// range, and inserts the predicate
+func (m *Model) askTimeColumn(r timefilter.Range) {
+	column := m.timeColumn
+	if column == "" {
+		column = m.config.TimeFilter.Column
+	}
+	if column == "" {
+		column = defaultTimeColumn
+	}
+
+	m.askInput("🕒 "+r.Preset.String(), "Column or expression to filter:", column, func(value string) tea.Cmd {
+		column := strings.TrimSpace(value)
+		if column == "" {
+			return nil
+		}
+		m.timeColumn = column
+		if r.Preset != timefilter.Between {
+			m.insertTimeFilter(column, r)
+			return nil
+		}
+
+		m.askDate("🕒 From", "First day of the range", time.Now().AddDate(0, 0, -7), func(from time.Time) tea.Cmd {
+			r.From = from
+			m.askDate("🕒 To", "Last day of the range, included (from "+from.Format("2006-01-02")+")", from, func(to time.Time) tea.Cmd {
+				r.To = to
+				m.insertTimeFilter(column, r)
+				return nil
+			})
+			return nil
+		})
+		return nil
+	})
+}
+
+// askDate shows the date picker on date and passes the picked day to
+// onPick
+func (m *Model) askDate(title, message string, date time.Time, onPick func(time.Time) tea.Cmd) {
+	m.pendingDate = onPick
+	m.datePicker.SetWeekStart(timefilter.ParseWeekStart(m.config.TimeFilter.WeekStart))
+	m.datePicker.Show(title, message, date)
+	m.state = StateDatePicker
+}
+
+// updateDatePicker handles the date picker
+func (m *Model) updateDatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pendingDate = nil
+		m.datePicker.Hide()
+		m.state = StateNormal
+	case "left", "h":
+		m.datePicker.MoveDays(-1)
+	case "right", "l":
+		m.datePicker.MoveDays(1)
+	case "up", "k":
+		m.datePicker.MoveDays(-7)
+	case "down", "j":
+		m.datePicker.MoveDays(7)
+	case "pgup":
+		m.datePicker.MoveMonths(-1)
+	case "pgdown":
+		m.datePicker.MoveMonths(1)
+	case "t":
+		m.datePicker.Today()
+	case "enter":
+		onPick := m.pendingDate
+		m.pendingDate = nil
+		date := m.datePicker.Selected()
+		m.datePicker.Hide()
+		m.state = StateNormal
+		if onPick == nil {
+			return m, nil
+		}
+		return m, onPick(date)
+	}
+	return m, nil
+}
+
+// insertTimeFilter writes the predicate for the active connection's
+// dialect at the cursor
+func (m *Model) insertTimeFilter(column string, r timefilter.Range) {
+	driver := ""
+	if m.conn.connector != nil {
+		driver = m.conn.connector.GetDriverName()
+	}
+	m.editor.InsertText(timefilter.Predicate(driver, column, r, time.Now()))
+	m.FocusEditor()
+	m.statusMessage = "Inserted time filter: " + strings.ToLower(r.Preset.String())
+	m.isError = false
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// txMenuItems are the actions offered while a transaction is open
+var txMenuItems = []string{"✔  COMMIT", "↺  ROLLBACK"}
+
+// txOpen reports whether the connector holds an open transaction
+func (m *Model) txOpen() bool {
+	if m.conn.connector == nil {
+		return false
+	}
+	tx, ok := db.GetTransactor(m.conn.connector)
+	return ok && tx.InTransaction()
+}
+
+// ToggleTransaction starts a transaction, or offers COMMIT/ROLLBACK for the
+// one already open
+func (m *Model) ToggleTransaction() {
+	if m.conn.connector == nil || !m.conn.connected {
+		m.statusMessage = "Not connected to database"
+		m.isError = true
+		return
+	}
+	tx, ok := db.GetTransactor(m.conn.connector)
+	if !ok || !m.conn.capabilities.SupportsTransactions {
+		m.statusMessage = "Transactions are not supported for this driver"
+		m.isError = true
+		return
+	}
+	if m.queryRunning {
+		m.statusMessage = "Wait for the running query to finish"
+		m.isError = true
+		return
+	}
+
+	if tx.InTransaction() {
+		m.txMenu.Show("🔒 Open transaction", txMenuItems)
+		m.state = StateTxMenu
+		return
+	}
+
+	if err := tx.Begin(); err != nil {
+		m.statusMessage = "Failed to start transaction: " + err.Error()
+		m.isError = true
+		return
+	}
+	m.statusMessage = "Transaction started; statements run in it until COMMIT/ROLLBACK (Ctrl+T)"
+	m.isError = false
+}
+
+// runTxMenuAction commits or rolls back the open transaction
+func (m *Model) runTxMenuAction() {
+	index := m.txMenu.Selected()
+	m.txMenu.Hide()
+	m.state = StateNormal
+
+	tx, ok := db.GetTransactor(m.conn.connector)
+	if !ok || !tx.InTransaction() {
+		return
+	}
+
+	var err error
+	action := "Committed"
+	if index == 0 {
+		err = tx.Commit()
+	} else {
+		action = "Rolled back"
+		err = tx.Rollback()
+	}
+	if err != nil {
+		m.statusMessage = err.Error()
+		m.isError = true
+		return
+	}
+	m.statusMessage = action + " transaction"
+	m.isError = false
+}
@@ -2,7 +2,9 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/febritecno/sqdesk-cli/internal/ai"
 	"github.com/febritecno/sqdesk-cli/internal/config"
@@ -11,7 +13,14 @@ import (
 
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
-	return nil
+	if m.viewerPath != "" {
+		return nil
+	}
+	if m.config.FirstRun {
+		return replicationTick()
+	}
+	// Connect in the background so the UI comes up straight away
+	return tea.Batch(replicationTick(), m.connectActive())
 }
 
 // Update handles all input and state changes
@@ -28,7 +37,131 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.MouseMsg:
 		return m.handleMouse(msg)
 
+	case maintenanceTickMsg, maintenanceProgressMsg, maintenanceDoneMsg:
+		return m, m.handleMaintenanceMsg(msg)
+
+	case replicationTickMsg, replicationStatusMsg:
+		return m, m.handleReplicationMsg(msg)
+
+	case connectResultMsg, connTestAllMsg:
+		var cmd tea.Cmd
+		m.conn, cmd = m.conn.Update(msg)
+		return m, cmd
+
+	case connectDoneMsg:
+		return m, m.handleConnectDone(msg)
+
+	case testAllProgressMsg:
+		m.handleTestAllProgress(msg)
+		return m, nil
+
+	case databaseSwitchedMsg:
+		m.handleDatabaseSwitched(msg)
+		return m, nil
+
+	case introspectDoneMsg:
+		return m, m.handleIntrospectDone(msg)
+
+	case databasesLoadedMsg:
+		if msg.err == nil {
+			m.sidebar.SetDatabases(msg.databases, msg.currentDB)
+		}
+		return m, nil
+
+	case aiResultMsg:
+		var cmd tea.Cmd
+		m.ai, cmd = m.ai.Update(msg)
+		return m, cmd
+
+	case aiDoneMsg:
+		m.handleAIDone(msg)
+		return m, nil
+
+	case sharedSyncDoneMsg:
+		m.handleSharedSyncDone(msg)
+		return m, nil
+
+	case pageDoneMsg:
+		m.handlePageDone(msg)
+		return m, nil
+
+	case queryBatchMsg:
+		return m, m.handleQueryBatch(msg)
+
+	case queryDoneMsg:
+		m.handleQueryDone(msg)
+		m.recordSession(msg)
+		return m, tea.Batch(m.runQueryHooks(msg), m.notifyQueryDone(msg), m.saveSnapshot(msg), m.saveHistory(msg), m.handleNotebookQueryDone(msg))
+
+	case historySavedMsg:
+		var cmd tea.Cmd
+		m.history, cmd = m.history.Update(msg)
+		return m, cmd
+
+	case statusMsg:
+		m.statusMessage = msg.text
+		m.isError = msg.isError
+		return m, nil
+
+	case snippetEditedMsg:
+		m.handleSnippetEdited(msg)
+		return m, nil
+
+	case formattedMsg:
+		m.handleFormatted(msg)
+		return m, nil
+
+	case snapshotSavedMsg:
+		m.handleSnapshotSaved(msg)
+		return m, nil
+
+	case snapshotLoadedMsg:
+		m.handleSnapshotLoaded(msg)
+		return m, nil
+
+	case tea.BlurMsg:
+		m.unfocused = true
+		return m, nil
+
+	case tea.FocusMsg:
+		m.unfocused = false
+		return m, nil
+
+	case hookDoneMsg:
+		m.handleHookDone(msg)
+		return m, nil
+
+	case exportDoneMsg:
+		m.handleExportDone(msg)
+		return m, nil
+
+	case tempTableDoneMsg:
+		m.handleTempTableDone(msg)
+		return m, nil
+
+	case csvImportDoneMsg:
+		m.handleCSVImportDone(msg)
+		return m, nil
+
+	case rowChangeDoneMsg:
+		m.handleRowChangeDone(msg)
+		return m, nil
+
+	case tableDropDoneMsg:
+		m.handleTableDropDone(msg)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.results, cmd = m.results.Update(msg)
+		return m, cmd
+
 	case tea.KeyMsg:
+		// Ctrl+C stops a running query rather than quitting
+		if msg.String() == "ctrl+c" && m.cancelQuery() {
+			return m, nil
+		}
+
 		// Handle global keys first
 		cmd := m.handleGlobalKeys(msg)
 		if cmd != nil {
@@ -45,6 +178,44 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSettings(msg)
 		case StateConnModal:
 			return m.updateConnModal(msg)
+		case StateInfo:
+			return m.updateInfo(msg)
+		case StateCellDetail:
+			return m.updateCellDetail(msg)
+		case StateTableMenu:
+			return m.updateTableMenu(msg)
+		case StateConfirm:
+			return m.updateConfirm(msg)
+		case StateVariables:
+			return m.updateVariables(msg)
+		case StatePasswordPrompt:
+			return m.updatePasswordPrompt(msg)
+		case StateParamPrompt:
+			return m.updateParamPrompt(msg)
+		case StateSnapshots:
+			return m.updateSnapshots(msg)
+		case StateHistory:
+			return m.updateHistory(msg)
+		case StateFavorites:
+			return m.updateFavorites(msg)
+		case StateSnippets:
+			return m.updateSnippets(msg)
+		case StateTimeMenu:
+			return m.updateTimeMenu(msg)
+		case StateDatePicker:
+			return m.updateDatePicker(msg)
+		case StateImportPreview:
+			return m.updateImportPreview(msg)
+		case StateInput:
+			return m.updateInput(msg)
+		case StateColumnPicker:
+			return m.updateColumnPicker(msg)
+		case StateTxMenu:
+			return m.updateTxMenu(msg)
+		case StateExportMenu:
+			return m.updateExportMenu(msg)
+		case StateRowForm:
+			return m.updateRowForm(msg)
 		case StateNormal:
 			return m.updateNormal(msg)
 		}
@@ -55,8 +226,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleMouse handles mouse events
 func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	// Skip if in modal state
-	if m.state != StateNormal {
+	// Skip if in modal state; the viewer has a single pane
+	if m.state != StateNormal || m.viewerPath != "" {
 		return m, nil
 	}
 
@@ -86,12 +257,17 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Check if click is in editor
+		// Check if click is in editor; Alt+Click adds a cursor
 		if x >= sidebarWidth && y >= headerHeight && y < headerHeight+editorHeight {
 			m.focusedPane = PaneEditor
 			m.sidebar.SetFocused(false)
 			m.editor.SetFocused(true)
 			m.results.SetFocused(false)
+			if msg.Alt {
+				var cmd tea.Cmd
+				m.editor, cmd = m.editor.Update(msg)
+				return m, cmd
+			}
 			return m, nil
 		}
 
@@ -143,6 +319,14 @@ func (m *Model) FocusEditor() {
 	m.results.SetFocused(false)
 }
 
+// FocusResults focuses the results pane
+func (m *Model) FocusResults() {
+	m.focusedPane = PaneResults
+	m.sidebar.SetFocused(false)
+	m.editor.SetFocused(false)
+	m.results.SetFocused(true)
+}
+
 // handleGlobalKeys handles keys that work in any state
 func (m *Model) handleGlobalKeys(msg tea.KeyMsg) tea.Cmd {
 	switch msg.String() {
@@ -162,15 +346,12 @@ func (m *Model) updateSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.config = m.wizard.GetConfig()
 			m.config.Save()
 			m.state = StateNormal
-			
-			// Try to connect
-			if err := m.Connect(); err != nil {
-				m.statusMessage = "Connection failed: " + err.Error()
-				m.isError = true
-			}
-			
+
 			// Reinitialize with new theme
 			m.UpdateTheme(m.config.Theme)
+
+			// Connect in the background
+			cmd = tea.Batch(cmd, m.connectActive())
 		}
 		return m, cmd
 	default:
@@ -189,17 +370,18 @@ func (m *Model) updateAIPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "enter":
 		prompt := m.aiPrompt.GetValue()
+		var cmd tea.Cmd
 		if prompt != "" {
 			if m.aiPrompt.GetMode() == components.AIPromptModeNL2SQL {
-				m.GenerateSQL(prompt)
+				cmd = m.GenerateSQL(prompt)
 			} else {
-				m.RefactorSQL(prompt)
+				cmd = m.RefactorSQL(prompt)
 			}
 		}
 		m.aiPrompt.Hide()
 		m.aiPrompt.ClearContext()
 		m.state = StateNormal
-		return m, nil
+		return m, cmd
 	default:
 		var cmd tea.Cmd
 		m.aiPrompt, cmd = m.aiPrompt.Update(msg)
@@ -211,6 +393,10 @@ func (m *Model) updateAIPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m *Model) updateConnModal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
+		if m.cancelConnect() {
+			m.connModal.SetStatus("Cancelled", false)
+			return m, nil
+		}
 		m.connModal.Hide()
 		m.state = StateNormal
 		return m, nil
@@ -220,199 +406,626 @@ func (m *Model) updateConnModal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "down", "j":
 		m.connModal.MoveDown()
 		return m, nil
-	case "enter":
-		action := m.connModal.GetSelectedAction()
-		connIdx := m.connModal.GetConnectionIndex()
-		m.connModal.Hide()
-		m.state = StateNormal
-		
-		switch action {
-		case components.ActionConnect:
-			// Connect to selected connection
-			if connIdx >= 0 && connIdx < len(m.config.Connections) {
-				// Test connection first
-				conn := m.config.Connections[connIdx]
-				m.connModal.SetStatus("Connecting...", false)
-				
-				// We need to run this async or just block briefly since it's a TUI
-				// For now, we'll block briefly as we don't have async msg handling set up for this yet
-				if err := m.TestConnection(&conn); err != nil {
-					m.connModal.SetStatus("Connection failed: "+err.Error(), true)
-					return m, nil
-				}
-				
-				// If test passes, proceed to connect
-				m.config.ActiveConnIndex = connIdx
-				m.sidebar.SetActiveConnection(connIdx)
-				if m.connector != nil {
-					m.connector.Close()
-				}
-				if err := m.Connect(); err != nil {
-					m.connModal.SetStatus("Connect error: "+err.Error(), true)
-					return m, nil
-				} else {
-					m.statusMessage = "Connected to " + m.config.Connections[connIdx].Name
-					m.isError = false
-					m.connModal.Hide()
-					m.state = StateNormal
-				}
-			}
-		case components.ActionEdit:
-			// Load connection for editing
-			if connIdx >= 0 && connIdx < len(m.config.Connections) {
-				conn := m.config.Connections[connIdx]
-				m.settings.LoadConnection(conn.Name, conn.Driver, conn.Host, conn.Port, conn.User, conn.Password, conn.Database, connIdx)
-				m.settings.SetTheme(m.config.Theme)
-				m.settings.SetAIProvider(m.config.AI.Provider)
-				m.settings.SetAPIKey(m.config.AI.APIKey)
-				m.settings.SetModel(m.config.AI.Model)
-				m.settings.Show()
-				m.state = StateSettings
-			}
-		case components.ActionDelete:
-			// Delete connection
-			if connIdx >= 0 && connIdx < len(m.config.Connections) {
-				connName := m.config.Connections[connIdx].Name
-				m.config.RemoveConnection(connIdx)
-				m.loadConnections()
-				m.config.Save()
-				m.statusMessage = "Deleted connection: " + connName
-				m.isError = false
-			}
-		case components.ActionCancel:
-			// Do nothing
+	case "enter":
+		action := m.connModal.GetSelectedAction()
+		connIdx := m.connModal.GetConnectionIndex()
+		if action != components.ActionConnect {
+			m.connModal.Hide()
+			m.state = StateNormal
+		}
+		
+		switch action {
+		case components.ActionConnect:
+			// Connect in the background; the modal stays open until it
+			// succeeds so failures and Esc to cancel show there
+			if connIdx >= 0 && connIdx < len(m.config.Connections) {
+				if m.config.Connections[connIdx].NeedsPassword() {
+					m.connModal.Hide()
+					return m, m.activateConnection(connIdx)
+				}
+				m.connModal.SetStatus("Connecting... (Esc to cancel)", false)
+				return m, m.startConnect(&m.config.Connections[connIdx], connectActivate, connIdx)
+			}
+		case components.ActionEdit:
+			// Load connection for editing
+			if connIdx >= 0 && connIdx < len(m.config.Connections) {
+				conn := m.config.Connections[connIdx]
+				if conn.Shared {
+					m.sharedReadOnly(conn.Name)
+					return m, nil
+				}
+				host, user := conn.Host, conn.User
+				if conn.Driver == "bigquery" {
+					user = conn.CredentialsFile
+				}
+				if conn.UsesSocket() {
+					host = conn.Socket
+				}
+				m.settings.LoadConnection(conn.Name, conn.Driver, host, conn.Port, user, conn.Password, conn.Database, connIdx)
+				m.settings.SetTLSConfig(conn.SSLMode, conn.SSLRootCert, conn.SSLCert, conn.SSLKey)
+				m.settings.SetPromptPassword(conn.PromptPassword)
+				m.settings.SetTheme(m.config.Theme)
+				m.settings.SetAIProvider(m.config.AI.Provider)
+				m.settings.SetAPIKey(m.config.AI.APIKey)
+				m.settings.SetModel(m.config.AI.Model)
+				m.settings.Show()
+				m.state = StateSettings
+			}
+		case components.ActionDelete:
+			// Delete connection
+			if connIdx >= 0 && connIdx < len(m.config.Connections) {
+				connName := m.config.Connections[connIdx].Name
+				if m.config.Connections[connIdx].Shared {
+					m.sharedReadOnly(connName)
+					return m, nil
+				}
+				m.config.RemoveConnection(connIdx)
+				m.loadConnections()
+				m.config.Save()
+				m.statusMessage = "Deleted connection: " + connName
+				m.isError = false
+			}
+		case components.ActionCancel:
+			// Do nothing
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// updateInfo handles info panel state
+func (m *Model) updateInfo(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "enter":
+		m.infoPanel.Hide()
+		m.state = StateNormal
+	case "up", "k":
+		m.infoPanel.ScrollUp(1)
+	case "down", "j":
+		m.infoPanel.ScrollDown(1)
+	case "pgup", "ctrl+u":
+		m.infoPanel.ScrollUp(10)
+	case "pgdown", "ctrl+d":
+		m.infoPanel.ScrollDown(10)
+	}
+	return m, nil
+}
+
+// updateTableMenu handles table action menu state
+func (m *Model) updateTableMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.tableMenu.Hide()
+		m.state = StateNormal
+	case "up", "k":
+		m.tableMenu.MoveUp()
+	case "down", "j":
+		m.tableMenu.MoveDown()
+	case "enter":
+		return m, m.runTableMenuAction()
+	}
+	return m, nil
+}
+
+// updateTxMenu handles the COMMIT/ROLLBACK menu of an open transaction
+func (m *Model) updateTxMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.txMenu.Hide()
+		m.state = StateNormal
+	case "up", "k":
+		m.txMenu.MoveUp()
+	case "down", "j":
+		m.txMenu.MoveDown()
+	case "enter":
+		m.runTxMenuAction()
+	}
+	return m, nil
+}
+
+// updateExportMenu handles the export format menu
+func (m *Model) updateExportMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.exportMenu.Hide()
+		m.state = StateNormal
+	case "up", "k":
+		m.exportMenu.MoveUp()
+	case "down", "j":
+		m.exportMenu.MoveDown()
+	case "enter":
+		return m, m.runExportMenuAction()
+	}
+	return m, nil
+}
+
+// updateConfirm handles confirm modal state
+func (m *Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		onConfirm := m.pendingConfirm
+		m.pendingConfirm = nil
+		m.confirm.Hide()
+		m.state = StateNormal
+		if onConfirm != nil {
+			return m, onConfirm()
+		}
+	case "n", "N", "esc", "q":
+		m.pendingConfirm = nil
+		m.confirm.Hide()
+		m.state = StateNormal
+		m.statusMessage = "Cancelled"
+		m.isError = false
+	}
+	return m, nil
+}
+
+// updatePasswordPrompt handles the password prompt shown before connecting
+func (m *Model) updatePasswordPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.password.Hide()
+		m.state = StateNormal
+		m.statusMessage = "Connection cancelled"
+		m.isError = false
+		return m, nil
+	case "enter":
+		idx := m.conn.passwordIdx
+		password := m.password.Value()
+		m.password.Hide()
+		m.state = StateNormal
+		if idx < 0 || idx >= len(m.config.Connections) || password == "" {
+			return m, nil
+		}
+		// Held in memory only; Config.Save leaves it out of the file
+		conn := &m.config.Connections[idx]
+		conn.Password = password
+		m.statusMessage = "Connecting to " + conn.Name + "... (Esc to cancel)"
+		m.isError = false
+		return m, m.startConnect(conn, connectActivate, idx)
+	default:
+		var cmd tea.Cmd
+		m.password, cmd = m.password.Update(msg)
+		return m, cmd
+	}
+}
+
+// updateColumnPicker handles the picker of the column to insert as an IN
+// list
+func (m *Model) updateColumnPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.columnPicker.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "up":
+		m.columnPicker.Move(-1)
+		return m, nil
+	case "down":
+		m.columnPicker.Move(1)
+		return m, nil
+	case "pgup", "ctrl+u":
+		m.columnPicker.Move(-10)
+		return m, nil
+	case "pgdown", "ctrl+d":
+		m.columnPicker.Move(10)
+		return m, nil
+	case "enter":
+		item, ok := m.columnPicker.Selected()
+		if !ok {
+			return m, nil
+		}
+		m.columnPicker.Hide()
+		m.state = StateNormal
+		m.insertInList(item.Key)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.columnPicker, cmd = m.columnPicker.Update(msg)
+	return m, cmd
+}
+
+// updateInput handles the input prompt
+func (m *Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pendingInput = nil
+		m.input.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "enter":
+		onSubmit := m.pendingInput
+		m.pendingInput = nil
+		value := m.input.Value()
+		m.input.Hide()
+		m.state = StateNormal
+		if onSubmit == nil {
+			return m, nil
+		}
+		return m, onSubmit(value)
+	default:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+}
+
+// updateParamPrompt handles the parameter prompt shown before running a
+// query with placeholders
+func (m *Model) updateParamPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.params.Hide()
+		m.paramQuery = nil
+		m.state = StateNormal
+		m.statusMessage = "Query cancelled"
+		m.isError = false
+		return m, nil
+	case "enter":
+		cmd := m.runParamQuery()
+		m.params.Hide()
+		m.state = StateNormal
+		return m, cmd
+	case "tab", "down":
+		m.params.FocusNext()
+		return m, nil
+	case "shift+tab", "up":
+		m.params.FocusPrev()
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.params, cmd = m.params.Update(msg)
+		return m, cmd
+	}
+}
+
+// updateRowForm handles the form of a new table row
+func (m *Model) updateRowForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.rowForm.Hide()
+		m.newRowTable, m.newRowColumns = "", nil
+		m.state = StateNormal
+		return m, nil
+	case "enter":
+		values := m.rowForm.Values()
+		m.rowForm.Hide()
+		m.state = StateNormal
+		return m, m.submitNewRow(values)
+	case "tab", "down":
+		m.rowForm.FocusNext()
+		return m, nil
+	case "shift+tab", "up":
+		m.rowForm.FocusPrev()
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.rowForm, cmd = m.rowForm.Update(msg)
+		return m, cmd
+	}
+}
+
+// updateVariables handles server variables browser state
+func (m *Model) updateVariables(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.variables.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "up":
+		m.variables.Move(-1)
+		return m, nil
+	case "down":
+		m.variables.Move(1)
+		return m, nil
+	case "pgup", "ctrl+u":
+		m.variables.Move(-10)
+		return m, nil
+	case "pgdown", "ctrl+d":
+		m.variables.Move(10)
+		return m, nil
+	case "enter", "ctrl+n", "ctrl+y":
+		part := "pair"
+		if msg.String() == "ctrl+n" {
+			part = "name"
+		} else if msg.String() == "ctrl+y" {
+			part = "value"
+		}
+		if text, err := m.variables.CopySelected(part); err != nil {
+			m.variables.SetStatus("Copy failed: " + err.Error())
+		} else {
+			m.variables.SetStatus("Copied: " + text)
+		}
+		return m, nil
+	}
+
+	// Everything else goes to the filter input
+	var cmd tea.Cmd
+	m.variables, cmd = m.variables.Update(msg)
+	return m, cmd
+}
+
+// updateSnapshots handles the result snapshot browser
+func (m *Model) updateSnapshots(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.snapshotBrowser.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "up":
+		m.snapshotBrowser.Move(-1)
+		return m, nil
+	case "down":
+		m.snapshotBrowser.Move(1)
+		return m, nil
+	case "pgup", "ctrl+u":
+		m.snapshotBrowser.Move(-10)
+		return m, nil
+	case "pgdown", "ctrl+d":
+		m.snapshotBrowser.Move(10)
+		return m, nil
+	case "enter":
+		item, ok := m.snapshotBrowser.Selected()
+		if !ok {
+			return m, nil
+		}
+		m.snapshotBrowser.Hide()
+		m.state = StateNormal
+		m.statusMessage = "Opening snapshot..."
+		m.isError = false
+		return m, m.loadSnapshot(item.Key)
+	case "ctrl+t":
+		m.promptSnapshotTags()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.snapshotBrowser, cmd = m.snapshotBrowser.Update(msg)
+	return m, cmd
+}
+
+// updateHistory handles the query history browser
+func (m *Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+r":
+		m.historyBrowser.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "up":
+		m.historyBrowser.Move(-1)
+		return m, nil
+	case "down":
+		m.historyBrowser.Move(1)
+		return m, nil
+	case "pgup", "ctrl+u":
+		m.historyBrowser.Move(-10)
+		return m, nil
+	case "pgdown", "ctrl+d":
+		m.historyBrowser.Move(10)
+		return m, nil
+	case "enter":
+		if m.loadHistoryQuery() {
+			m.statusMessage = "Loaded query from history"
+			m.isError = false
+		}
+		return m, nil
+	case "alt+enter", "shift+enter", "ctrl+e":
+		// Terminals rarely tell Shift+Enter from Enter; Alt+Enter does
+		if !m.loadHistoryQuery() {
+			return m, nil
+		}
+		return m, m.ExecuteQuery()
+	}
+
+	var cmd tea.Cmd
+	m.historyBrowser, cmd = m.historyBrowser.Update(msg)
+	return m, cmd
+}
+
+// updateFavorites handles the favorites browser
+func (m *Model) updateFavorites(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.favoriteBrowser.Hide()
+		m.state = StateNormal
+		return m, nil
+	case "up":
+		m.favoriteBrowser.Move(-1)
+		return m, nil
+	case "down":
+		m.favoriteBrowser.Move(1)
+		return m, nil
+	case "pgup", "ctrl+u":
+		m.favoriteBrowser.Move(-10)
+		return m, nil
+	case "pgdown", "ctrl+d":
+		m.favoriteBrowser.Move(10)
+		return m, nil
+	case "enter":
+		if m.loadFavorite() {
+			m.statusMessage = "Loaded favorite " + m.favoriteName
+			m.isError = false
+		}
+		return m, nil
+	case "alt+enter", "shift+enter", "ctrl+e":
+		if !m.loadFavorite() {
+			return m, nil
 		}
+		return m, m.ExecuteQuery()
+	case "ctrl+g":
+		m.toggleFavoriteScope()
+		return m, nil
+	case "ctrl+x":
+		m.confirmDeleteFavorite()
 		return m, nil
 	}
-	return m, nil
+
+	var cmd tea.Cmd
+	m.favoriteBrowser, cmd = m.favoriteBrowser.Update(msg)
+	return m, cmd
 }
 
 // updateSettings handles settings modal state
 func (m *Model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
+		if m.cancelConnect() {
+			m.settings.SetStatus("Connection attempt cancelled", false)
+			return m, nil
+		}
+		if m.cancelTestAll() {
+			m.settings.SetStatus("Test all cancelled", false)
+			return m, nil
+		}
 		m.settings.Hide()
 		m.state = StateNormal
 		return m, nil
+	case "ctrl+t":
+		if m.settings.ActiveTab() == components.SettingsTabConnections {
+			return m, m.testAllConnections()
+		}
+		return m, nil
+	case "ctrl+p":
+		if m.settings.ActiveTab() == components.SettingsTabConnections {
+			m.settings.TogglePromptPassword()
+		}
+		return m, nil
 	case "ctrl+s", "f5":
 		// Test connection without saving
-		name, driver, host, port, user, pass, database := m.settings.GetConnectionConfig()
+		name, _, host, _, _, _, _ := m.settings.GetConnectionConfig()
 		if name != "" && host != "" {
-			testCfg := &config.DatabaseConfig{
-				Name:     name,
-				Driver:   driver,
-				Host:     host,
-				Port:     parsePort(port, driver),
-				User:     user,
-				Password: pass,
-				Database: database,
-			}
-			m.settings.SetStatus("Testing connection...", false)
-			if err := m.TestConnection(testCfg); err != nil {
-				m.settings.SetStatus("❌ Test failed: "+err.Error(), true)
-			} else {
-				m.settings.SetStatus("✅ Connection test successful!", false)
+			testCfg := m.connectionFromForm()
+			if testCfg.NeedsPassword() {
+				m.settings.SetStatus("❌ Type the password to test (it won't be saved)", true)
+				return m, nil
 			}
-		} else {
-			m.settings.SetStatus("❌ Please fill in name and host", true)
+			m.settings.SetStatus("Testing connection... (Esc to cancel)", false)
+			return m, m.startConnect(&testCfg, connectTest, -1)
 		}
+		m.settings.SetStatus("❌ Please fill in name and host", true)
 		return m, nil
 	case "enter":
-		// Validate connection if on Connections tab
-		name, driver, host, port, user, pass, database := m.settings.GetConnectionConfig()
+		// Validate connection if on Connections tab, saving once it works
+		name, _, host, _, _, _, _ := m.settings.GetConnectionConfig()
 		if name != "" && host != "" {
-			// Test connection before saving
-			testConn := config.DatabaseConfig{
-				Name:     name,
-				Driver:   driver,
-				Host:     host,
-				Port:     parsePort(port, driver),
-				User:     user,
-				Password: pass,
-				Database: database,
-			}
-			m.settings.SetStatus("Validating connection...", false)
-			if err := m.TestConnection(&testConn); err != nil {
-				m.settings.SetStatus("❌ ERROR: "+err.Error(), true)
-				return m, nil
+			testConn := m.connectionFromForm()
+			if testConn.NeedsPassword() {
+				// Nothing to validate with; the password is asked for
+				// when connecting
+				return m, m.applySettings()
 			}
-			m.settings.SetStatus("✅ CONNECTED!", false)
+			m.settings.SetStatus("Validating connection... (Esc to cancel)", false)
+			return m, m.startConnect(&testConn, connectSave, -1)
 		}
-		
-		// Apply settings
-		theme := m.settings.GetSelectedTheme()
-		if theme != m.config.Theme {
-			m.UpdateTheme(theme)
-			m.config.Theme = theme
-		}
-		
-		m.config.AI.Provider = m.settings.GetSelectedProvider()
-		m.config.AI.APIKey = m.settings.GetAPIKey()
-		m.config.AI.Model = m.settings.GetModel()
-		
-		// Reinitialize AI provider
-		if m.config.AI.Provider != "none" {
-			provider, _ := NewAIProvider(m.config.AI.Provider, m.config.AI.APIKey, m.config.AI.Model)
-			m.aiProvider = provider
-		}
-		
-		// Handle connection from Connections tab
-		if name != "" && host != "" {
-			newConn := config.DatabaseConfig{
-				Name:     name,
-				Driver:   driver,
-				Host:     host,
-				Port:     parsePort(port, driver),
-				User:     user,
-				Password: pass,
-				Database: database,
-			}
-			
-			if m.settings.IsEditingConnection() {
-				// Update existing connection
-				editIdx := m.settings.GetEditingConnIndex()
-				if editIdx >= 0 && editIdx < len(m.config.Connections) {
-					m.config.Connections[editIdx] = newConn
-					m.statusMessage = "Connection updated: " + name
-					m.isError = false
-					
-					// If updating active connection, reconnect
-					if m.config.ActiveConnIndex == editIdx {
-						if err := m.Connect(); err != nil {
-							m.statusMessage = "Updated but failed to connect: " + err.Error()
-							m.isError = true
-						}
-					}
-				}
-			} else {
-				// Add new connection
-				m.config.Connections = append(m.config.Connections, newConn)
-				m.config.ActiveConnIndex = len(m.config.Connections) - 1
+		return m, m.applySettings()
+	default:
+		var cmd tea.Cmd
+		m.settings, cmd = m.settings.Update(msg)
+		return m, cmd
+	}
+}
+
+// applySettings saves the settings form, adding or updating the form's
+// connection and reconnecting to it in the background when needed
+func (m *Model) applySettings() tea.Cmd {
+	var cmd tea.Cmd
+	name, _, host, _, _, _, _ := m.settings.GetConnectionConfig()
+
+	// Apply settings
+	theme := m.settings.GetSelectedTheme()
+	if theme != m.config.Theme {
+		m.UpdateTheme(theme)
+		m.config.Theme = theme
+	}
+	
+	m.config.AI.Provider = m.settings.GetSelectedProvider()
+	m.config.AI.APIKey = m.settings.GetAPIKey()
+	m.config.AI.Model = m.settings.GetModel()
+	
+	// Reinitialize AI provider
+	if m.config.AI.Provider != "none" {
+		provider, _ := NewAIProvider(m.config.AI.Provider, m.config.AI.APIKey, m.config.AI.Model)
+		m.ai.provider = provider
+	}
+	
+	// Handle connection from Connections tab
+	if name != "" && host != "" {
+		newConn := m.connectionFromForm()
+
+		if m.settings.IsEditingConnection() {
+			// Update existing connection
+			editIdx := m.settings.GetEditingConnIndex()
+			if editIdx >= 0 && editIdx < len(m.config.Connections) {
+				m.config.Connections[editIdx] = newConn
+				m.statusMessage = "Connection updated: " + name
+				m.isError = false
 				
-				// Connect to the new database
-				if err := m.Connect(); err != nil {
-					m.statusMessage = "Added but failed to connect: " + err.Error()
-					m.isError = true
-				} else {
-					m.statusMessage = "Connection added: " + name
-					m.isError = false
+				// If updating active connection, reconnect
+				if m.config.ActiveConnIndex == editIdx {
+					cmd = m.activateConnection(editIdx)
 				}
 			}
-			m.loadConnections()
-		}
-		
-		m.config.Save()
-		m.settings.Hide()
-		m.state = StateNormal
-		if m.statusMessage == "" {
-			m.statusMessage = "Settings saved"
+		} else {
+			// Add new connection and connect to it
+			newIdx := m.config.AddConnection(newConn)
+			m.statusMessage = "Connection added: " + name + ", connecting..."
 			m.isError = false
+			cmd = m.activateConnection(newIdx)
+		}
+		m.loadConnections()
+	}
+	
+	m.config.Save()
+	m.settings.Hide()
+	m.state = StateNormal
+	if m.statusMessage == "" {
+		m.statusMessage = "Settings saved"
+		m.isError = false
+	}
+	return cmd
+}
+
+// connectionFromForm builds a connection from the settings form. When editing,
+// fields the form doesn't show (SSH hops, ...) are kept from the existing
+// connection.
+func (m *Model) connectionFromForm() config.DatabaseConfig {
+	name, driver, host, port, user, pass, database := m.settings.GetConnectionConfig()
+
+	var cfg config.DatabaseConfig
+	if m.settings.IsEditingConnection() {
+		if idx := m.settings.GetEditingConnIndex(); idx >= 0 && idx < len(m.config.Connections) {
+			cfg = m.config.Connections[idx]
+		}
+	}
+
+	cfg.Name = name
+	cfg.Driver = driver
+	cfg.Host = host
+	cfg.Port = parsePort(port, driver)
+	cfg.User = user
+	cfg.Password = pass
+	cfg.PromptPassword = m.settings.GetPromptPassword()
+	cfg.Database = database
+	cfg.SSLMode, cfg.SSLRootCert, cfg.SSLCert, cfg.SSLKey = m.settings.GetTLSConfig()
+	applyDriverFields(&cfg)
+	return cfg
+}
+
+// applyDriverFields maps settings form fields onto driver-specific config
+func applyDriverFields(cfg *config.DatabaseConfig) {
+	switch cfg.Driver {
+	case "bigquery":
+		// The form's User field holds the service account key path
+		cfg.CredentialsFile = cfg.User
+		cfg.User = ""
+	case "postgres", "cockroachdb", "mysql", "mariadb":
+		// A path in the Host field is a unix socket
+		cfg.Socket = ""
+		if strings.HasPrefix(cfg.Host, "/") || strings.HasPrefix(cfg.Host, "~/") {
+			cfg.Socket = cfg.Host
+			cfg.Host = ""
 		}
-		return m, nil
-	default:
-		var cmd tea.Cmd
-		m.settings, cmd = m.settings.Update(msg)
-		return m, cmd
 	}
 }
 
@@ -422,8 +1035,12 @@ func parsePort(portStr string, driver string) int {
 		switch driver {
 		case "postgres":
 			return 5432
-		case "mysql":
+		case "cockroachdb":
+			return 26257
+		case "mysql", "mariadb":
 			return 3306
+		case "cassandra":
+			return 9042
 		default:
 			return 0
 		}
@@ -441,10 +1058,36 @@ func parsePort(portStr string, driver string) int {
 func (m *Model) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
+	// Esc aborts a background connection attempt before anything else
+	if key == "esc" && m.cancelConnect() {
+		m.statusMessage = "Connection attempt cancelled"
+		m.isError = false
+		return m, nil
+	}
+	if key == "esc" && m.cancelQuery() {
+		return m, nil
+	}
+	if key == "esc" && m.cancelAI() {
+		return m, nil
+	}
+	if m.viewerPath != "" {
+		return m.updateViewer(msg)
+	}
+
 	// Global shortcuts (always work regardless of focused pane)
 	switch key {
 	case "f5", "ctrl+e":
-		m.ExecuteQuery()
+		return m, m.ExecuteQuery()
+
+	case "f9":
+		return m, m.ExecuteStatementAtCursor()
+
+	case "ctrl+t":
+		m.ToggleTransaction()
+		return m, nil
+
+	case "ctrl+o":
+		m.ShowExportMenu()
 		return m, nil
 
 	case "ctrl+g":
@@ -495,6 +1138,67 @@ func (m *Model) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Toggle Help modal
 		m.help.Toggle()
 		return m, nil
+
+	case "f6":
+		return m, m.ShowServerSettings()
+
+	case "f7":
+		m.ShowConnectionInfo()
+		return m, nil
+
+	case "f8":
+		return m, m.ExplainQuery()
+
+	case "alt+l":
+		m.LintQuery()
+		return m, nil
+
+	case "alt+s":
+		m.ShowSnippets()
+		return m, nil
+
+	case "alt+n":
+		m.ToggleNotebook()
+		return m, nil
+
+	case "alt+f":
+		m.ToggleNotebookCell()
+		return m, nil
+
+	case "alt+t":
+		m.ShowTimeFilter()
+		return m, nil
+
+	case "alt+i":
+		m.PromptCSVImport()
+		return m, nil
+
+	case "f10":
+		m.ShowSnapshots()
+		return m, nil
+
+	case "ctrl+r":
+		// In the editor's normal mode Ctrl+R is redo, as in vim
+		if m.focusedPane == PaneEditor && m.editor.InNormalMode() {
+			break
+		}
+		m.ShowHistory()
+		return m, nil
+
+	case "ctrl+s":
+		m.PromptSaveFavorite()
+		return m, nil
+
+	case "alt+b":
+		m.ShowFavorites()
+		return m, nil
+
+	case "alt+F", "ctrl+shift+f":
+		return m, m.FormatSQL()
+
+	case "f12":
+		m.TogglePrimaryOnly()
+		return m, nil
 	}
 	
 	// Handle Help modal navigation when visible
@@ -522,9 +1226,15 @@ func (m *Model) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.completion.MoveDown()
 			return m, nil
 		case "tab":
+			// Tab moves between the stops of an expanded snippet first
+			if m.editor.InSnippet() {
+				break
+			}
 			// Accept completion - replace current word with suggestion
 			item := m.completion.GetSelected()
-			if item != nil {
+			if item != nil && item.Source == m.userSnippetSource.Name() {
+				m.editor.ReplaceWordWithSnippet(item.InsertText)
+			} else if item != nil {
 				m.editor.ReplaceCurrentWord(item.InsertText)
 			}
 			// Don't hide - keep panel open
@@ -556,25 +1266,81 @@ func (m *Model) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleSidebarKeys handles keys when sidebar is focused
 func (m *Model) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Let the list filter input receive every key while typing
+	if m.sidebar.IsFiltering() {
+		var cmd tea.Cmd
+		m.sidebar, cmd = m.sidebar.Update(msg)
+		return m, cmd
+	}
+
 	switch msg.String() {
+	case " ":
+		if m.sidebar.GetSection() == components.SectionTables {
+			m.sidebar.ToggleExpanded()
+			return m, nil
+		}
+	case "i":
+		if m.sidebar.GetSection() == components.SectionTables {
+			if tableName := m.sidebar.SelectedTable(); tableName != "" {
+				return m, m.ShowTableInfo(tableName)
+			}
+			return m, nil
+		}
+		if m.sidebar.GetSection() == components.SectionDatabases {
+			m.ShowLinkedDatabases()
+			return m, nil
+		}
+		if m.sidebar.GetSection() == components.SectionViews {
+			if viewName := m.sidebar.SelectedView(); viewName != "" {
+				return m, m.ShowViewInfo(viewName)
+			}
+			return m, nil
+		}
+	case "s":
+		if m.sidebar.GetSection() == components.SectionTables {
+			if tableName := m.sidebar.SelectedTable(); tableName != "" {
+				return m, m.ShowTableStructure(tableName)
+			}
+			return m, nil
+		}
+	case "a":
+		if m.sidebar.GetSection() == components.SectionTables {
+			if tableName := m.sidebar.SelectedTable(); tableName != "" {
+				m.ShowTableMenu(tableName)
+			}
+			return m, nil
+		}
+		if m.sidebar.GetSection() == components.SectionDatabases {
+			m.PromptAttach()
+			return m, nil
+		}
+	case "x":
+		if m.sidebar.GetSection() == components.SectionDatabases {
+			m.confirmDetach()
+			return m, nil
+		}
 	case "left":
-		// Cycle sections: Connections -> Databases -> Tables -> Connections
+		// Cycle sections: Connections -> Databases -> Tables -> Views -> Connections
 		section := m.sidebar.GetSection()
 		if section == components.SectionConnections {
-			m.sidebar.SetSection(components.SectionTables)
+			m.sidebar.SetSection(components.SectionViews)
 		} else if section == components.SectionDatabases {
 			m.sidebar.SetSection(components.SectionConnections)
+		} else if section == components.SectionViews {
+			m.sidebar.SetSection(components.SectionTables)
 		} else {
 			m.sidebar.SetSection(components.SectionDatabases)
 		}
 		return m, nil
 	case "right":
-		// Cycle sections: Connections -> Databases -> Tables -> Connections
+		// Cycle sections: Connections -> Databases -> Tables -> Views -> Connections
 		section := m.sidebar.GetSection()
 		if section == components.SectionConnections {
 			m.sidebar.SetSection(components.SectionDatabases)
 		} else if section == components.SectionDatabases {
 			m.sidebar.SetSection(components.SectionTables)
+		} else if section == components.SectionTables {
+			m.sidebar.SetSection(components.SectionViews)
 		} else {
 			m.sidebar.SetSection(components.SectionConnections)
 		}
@@ -607,12 +1373,14 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		
 		// Handle Databases section
 		if section == components.SectionDatabases {
+			if !m.conn.capabilities.SupportsSwitchDatabase {
+				m.statusMessage = "This driver does not support switching databases"
+				m.isError = true
+				return m, nil
+			}
 			dbName := m.sidebar.GetSelectedDatabase()
 			if dbName != "" && dbName != m.sidebar.GetCurrentDatabase() {
-				if err := m.SwitchDatabase(dbName); err != nil {
-					m.statusMessage = "Failed to switch: " + err.Error()
-					m.isError = true
-				}
+				return m, m.SwitchDatabase(dbName)
 			}
 			return m, nil
 		}
@@ -626,8 +1394,12 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Save to config
 				m.config.LastTable = tableName
 				m.config.Save()
-				// Insert SELECT * query
-				query := fmt.Sprintf("SELECT * FROM %s LIMIT 100;", tableName)
+				// Insert SELECT * query, reading a single partition when one is selected
+				target := m.quoteIdent(tableName)
+				if _, partTarget, ok := m.sidebar.SelectedPartition(); ok {
+					target = partTarget
+				}
+				query := fmt.Sprintf("SELECT * FROM %s LIMIT 100;", target)
 				m.editor.SetValue(query)
 				m.FocusEditor()
 				m.statusMessage = "Selected table: " + tableName
@@ -635,6 +1407,14 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+
+		// Handle Views section: preview the view's rows
+		if section == components.SectionViews {
+			if viewName := m.sidebar.SelectedView(); viewName != "" {
+				return m, m.PreviewTable(viewName)
+			}
+			return m, nil
+		}
 	}
 	
 	// Pass other keys to sidebar
@@ -650,13 +1430,121 @@ func SectionConnections() components.SidebarSection {
 
 // handleResultsKeys handles keys when results pane is focused
 func (m *Model) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.results.IsFiltering() {
+		return m.handleResultsFilterKeys(msg)
+	}
+
 	switch msg.String() {
 	case "pgdown", "ctrl+d":
+		// Past the last fetched page, a query run a page at a time
+		// fetches the next one
+		if cmd, ok := m.FetchPage(false); ok {
+			return m, cmd
+		}
 		m.results.NextPage()
 		return m, nil
 	case "pgup", "ctrl+u":
+		if cmd, ok := m.FetchPage(true); ok {
+			return m, cmd
+		}
 		m.results.PrevPage()
 		return m, nil
+	case "]":
+		m.results.NextResultSet()
+		return m, nil
+	case "[":
+		m.results.PrevResultSet()
+		return m, nil
+	case "left":
+		m.results.MoveColumn(-1)
+		return m, nil
+	case "right":
+		m.results.MoveColumn(1)
+		return m, nil
+	case "+", "=", "-":
+		// Widen or narrow the selected column
+		delta := 4
+		if msg.String() == "-" {
+			delta = -4
+		}
+		if width := m.results.ResizeColumn(delta); width > 0 {
+			col := m.results.ActiveResult().Columns[m.results.SelectedColumn()]
+			m.statusMessage = fmt.Sprintf("%s is %d columns wide", col, width)
+			m.isError = false
+		}
+		return m, nil
+	case "g", "G":
+		// On a chart, group charted rows by the selected column or switch
+		// the aggregate; in the table they go to the first or last row
+		if !m.results.IsChart() {
+			break
+		}
+		if msg.String() == "g" {
+			m.statusMessage = m.results.ToggleChartGroup()
+		} else {
+			m.statusMessage = m.results.NextChartAgg()
+		}
+		m.isError = false
+		return m, nil
+	case "a":
+		// Aggregates of the selected column under the table
+		if m.results.ToggleAggregates() {
+			m.statusMessage = "Showing aggregates of the selected column (←/→ to change it)"
+		} else {
+			m.statusMessage = "Aggregates hidden"
+		}
+		m.isError = false
+		return m, nil
+	case "p":
+		// Pin the columns up to the selected one while scrolling sideways
+		if n := m.results.TogglePin(); n > 0 {
+			m.statusMessage = fmt.Sprintf("Pinned %d column(s), ←/→ scroll the others", n)
+		} else {
+			m.statusMessage = "Columns unpinned"
+		}
+		m.isError = false
+		return m, nil
+	case "s":
+		// Sort the fetched rows by the selected column
+		m.SortResults()
+		return m, nil
+	case "*":
+		// Mark the cells holding the selected cell's value
+		m.FindCellOccurrences()
+		return m, nil
+	case "e":
+		// Edit the selected cell, writing it back with an UPDATE
+		return m, m.EditCell()
+	case "D":
+		// Delete the selected row from its table
+		return m, m.DeleteRow()
+	case "F":
+		// Count the selected column's values over the fetched rows
+		m.ShowColumnFrequency()
+		return m, nil
+	case "n":
+		m.stepMatch(true)
+		return m, nil
+	case "N":
+		m.stepMatch(false)
+		return m, nil
+	case "/":
+		// Narrow the fetched rows without running the query again
+		return m, m.results.StartFilter()
+	case "esc":
+		if m.results.ClearMatches() {
+			m.statusMessage = ""
+		} else if m.results.ClearFilter() {
+			m.statusMessage = "Filter cleared"
+			m.isError = false
+		}
+		return m, nil
+	case "}":
+		m.results.NextRun()
+		return m, nil
+	case "{":
+		m.results.PrevRun()
+		return m, nil
 	case "c":
 		// Copy selected row
 		if err := m.results.CopySelectedRow(); err != nil {
@@ -667,6 +1555,28 @@ func (m *Model) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.isError = false
 		}
 		return m, nil
+	case "enter":
+		// Show the selected cell's full value
+		m.ShowCellDetail()
+		return m, nil
+	case "y":
+		// Copy the selected cell's value
+		text, null, err := m.results.CopySelectedCell()
+		switch {
+		case err != nil:
+			m.statusMessage = "Copy failed: " + err.Error()
+			m.isError = true
+		case null:
+			m.statusMessage = "Cell is NULL; copied an empty value"
+			m.isError = false
+		case strings.Contains(text, "\n"):
+			m.statusMessage = fmt.Sprintf("Cell copied to clipboard (%d lines)", strings.Count(text, "\n")+1)
+			m.isError = false
+		default:
+			m.statusMessage = fmt.Sprintf("Cell copied to clipboard (%d chars)", len([]rune(text)))
+			m.isError = false
+		}
+		return m, nil
 	case "C", "ctrl+shift+c":
 		// Copy all data
 		if err := m.results.CopyAllData(); err != nil {
@@ -677,11 +1587,29 @@ func (m *Model) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.isError = false
 		}
 		return m, nil
+	case "J":
+		// Copy all data as JSON
+		if err := m.results.CopyAllJSON(); err != nil {
+			m.statusMessage = "Copy failed: " + err.Error()
+			m.isError = true
+		} else {
+			m.statusMessage = fmt.Sprintf("All data (%d rows) copied to clipboard as JSON", m.results.GetRowCount())
+			m.isError = false
+		}
+		return m, nil
+	case "M":
+		// Copy all data as a Markdown table
+		if err := m.results.CopyAllMarkdown(); err != nil {
+			m.statusMessage = "Copy failed: " + err.Error()
+			m.isError = true
+		} else {
+			m.statusMessage = fmt.Sprintf("All data (%d rows) copied to clipboard as a Markdown table", m.results.GetRowCount())
+			m.isError = false
+		}
+		return m, nil
 	case "v":
 		// Cycle view modes
-		current := m.results.GetViewMode()
-		next := (current + 1) % 4 // 4 modes
-		m.results.SetViewMode(next)
+		m.results.NextViewMode()
 		return m, nil
 	case "1":
 		m.results.SetViewMode(components.ViewTable)
@@ -695,6 +1623,18 @@ func (m *Model) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "4":
 		m.results.SetViewMode(components.ViewChartPie)
 		return m, nil
+	case "5":
+		// Show the selected row as column/value pairs
+		m.results.SetViewMode(components.ViewRecord)
+		return m, nil
+	case "t":
+		// Save the results as a temporary table
+		m.PromptTempTable()
+		return m, nil
+	case "i":
+		// Insert a column's values into the editor as an IN list
+		m.ShowInListPicker()
+		return m, nil
 	}
 
 	var cmd tea.Cmd
@@ -728,6 +1668,24 @@ func (m *Model) updateLayout() {
 	}
 	m.aiPrompt.SetSize(modalWidth, 10)
 	m.settings.SetSize(modalWidth, m.height*70/100)
+	m.infoPanel.SetSize(modalWidth, m.height*70/100)
+	m.tableMenu.SetSize(modalWidth, m.height*70/100)
+	m.txMenu.SetSize(modalWidth, m.height*70/100)
+	m.exportMenu.SetSize(modalWidth, m.height*70/100)
+	m.timeMenu.SetSize(modalWidth, m.height*70/100)
+	m.datePicker.SetSize(modalWidth, 0)
+	m.importPreview.SetSize(modalWidth, m.height*80/100)
+	m.confirm.SetSize(modalWidth, m.height*70/100)
+	m.variables.SetSize(modalWidth, m.height*80/100)
+	m.snapshotBrowser.SetSize(modalWidth, m.height*80/100)
+	m.columnPicker.SetSize(modalWidth, m.height*80/100)
+	m.snippetBrowser.SetSize(modalWidth, m.height*80/100)
+	m.historyBrowser.SetSize(modalWidth, m.height*80/100)
+	m.favoriteBrowser.SetSize(modalWidth, m.height*80/100)
+	m.password.SetSize(modalWidth, 0)
+	m.input.SetSize(modalWidth, 0)
+	m.params.SetSize(modalWidth, 0)
+	m.rowForm.SetSize(modalWidth, 0)
 	m.wizard.SetSize(m.width, m.height)
 }
 
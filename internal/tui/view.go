@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -23,7 +25,8 @@ func (m *Model) View() string {
 	content.WriteString(m.renderHeader())
 	content.WriteString("\n")
 
-	// Main content
+	// Main content; inline notebook results follow the edited text
+	m.refreshNotebook()
 	content.WriteString(m.renderMainContent())
 
 	// Footer
@@ -66,6 +69,204 @@ func (m *Model) View() string {
 		)
 	}
 	
+	if (m.state == StateInfo || m.state == StateCellDetail) && m.infoPanel.IsVisible() {
+		modalContent := m.infoPanel.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateTxMenu && m.txMenu.IsVisible() {
+		modalContent := m.txMenu.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateExportMenu && m.exportMenu.IsVisible() {
+		modalContent := m.exportMenu.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateTableMenu && m.tableMenu.IsVisible() {
+		modalContent := m.tableMenu.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateConfirm && m.confirm.IsVisible() {
+		modalContent := m.confirm.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StatePasswordPrompt && m.password.IsVisible() {
+		modalContent := m.password.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateInput && m.input.IsVisible() {
+		modalContent := m.input.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateParamPrompt && m.params.IsVisible() {
+		modalContent := m.params.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateRowForm && m.rowForm.IsVisible() {
+		modalContent := m.rowForm.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateColumnPicker && m.columnPicker.IsVisible() {
+		modalContent := m.columnPicker.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateImportPreview && m.importPreview.IsVisible() {
+		modalContent := m.importPreview.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateTimeMenu && m.timeMenu.IsVisible() {
+		modalContent := m.timeMenu.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateDatePicker && m.datePicker.IsVisible() {
+		modalContent := m.datePicker.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateSnippets && m.snippetBrowser.IsVisible() {
+		modalContent := m.snippetBrowser.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateFavorites && m.favoriteBrowser.IsVisible() {
+		modalContent := m.favoriteBrowser.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateHistory && m.historyBrowser.IsVisible() {
+		modalContent := m.historyBrowser.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateSnapshots && m.snapshotBrowser.IsVisible() {
+		modalContent := m.snapshotBrowser.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
+	if m.state == StateVariables && m.variables.IsVisible() {
+		modalContent := m.variables.View()
+		baseView = lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalContent,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#333333")),
+		)
+	}
+
 	// Render Help modal if visible
 	if m.help.IsVisible() {
 		modalContent := m.help.View()
@@ -88,8 +289,25 @@ func (m *Model) renderHeader() string {
 
 	// Center: Connection status
 	var connStatus string
-	if m.isConnected {
+	if m.viewerPath != "" {
+		connStatus = m.styles.StatusItem.Render("📄 " + filepath.Base(m.viewerPath))
+	} else if m.conn.connected {
 		connStatus = m.styles.SuccessText.Render("● Connected")
+		if label := m.serverLabel(); label != "" {
+			connStatus += m.styles.StatusItem.Render(" " + label)
+		}
+		if m.readOnly() {
+			connStatus += " " + m.styles.ErrorText.Bold(true).Render("[RO]")
+		}
+		if m.txOpen() {
+			connStatus += " " + m.styles.ErrorText.Bold(true).Render("[TX OPEN]")
+		}
+		if replication := m.renderReplication(); replication != "" {
+			connStatus += "  " + replication
+		}
+		if endpoint := m.renderEndpoint(); endpoint != "" {
+			connStatus += "  " + endpoint
+		}
 	} else {
 		connStatus = m.styles.ErrorText.Render("○ Disconnected")
 	}
@@ -116,8 +334,41 @@ func (m *Model) renderHeader() string {
 	return m.styles.Header.Width(m.width).Render(header)
 }
 
+// driverProducts maps driver names to product names shown in the header
+var driverProducts = map[string]string{
+	"postgres":    "PostgreSQL",
+	"cockroachdb": "CockroachDB",
+	"mysql":       "MySQL",
+	"mariadb":     "MariaDB",
+	"sqlite3":     "SQLite",
+	"bigquery":    "BigQuery",
+	"cassandra":   "Cassandra",
+}
+
+// versionRe extracts a dotted version number from a server version string
+var versionRe = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// serverLabel returns e.g. "MariaDB 10.11.6" for the connected server
+func (m *Model) serverLabel() string {
+	if m.conn.connector == nil {
+		return ""
+	}
+
+	driver := m.conn.connector.GetDriverName()
+	product, ok := driverProducts[driver]
+	if !ok {
+		product = driver
+	}
+	if version := versionRe.FindString(m.conn.capabilities.ServerVersion); version != "" {
+		return product + " " + version
+	}
+	return product
+}
+
 // renderMainContent renders the main workspace
 func (m *Model) renderMainContent() string {
+	m.syncPaneStates()
+
 	// Calculate dimensions
 	sidebarWidth := 20
 	if m.width < 80 {
@@ -133,6 +384,11 @@ func (m *Model) renderMainContent() string {
 	mainWidth := m.width - sidebarWidth - rightPanelWidth - 1
 	contentHeight := m.height - 4 // header + footer + margins
 
+	// The viewer shows only the results of its file
+	if m.viewerPath != "" {
+		return m.renderResults(m.width, contentHeight)
+	}
+
 	editorHeight := contentHeight * 45 / 100
 	resultsHeight := contentHeight - editorHeight - 1
 
@@ -211,6 +467,19 @@ func (m *Model) renderFooter() string {
 		{"F4", "Help"},
 		{"Ctrl+Q", "Quit"},
 	}
+	if m.viewerPath != "" {
+		helpItems = []struct {
+			key  string
+			desc string
+		}{
+			{"/", "Filter"},
+			{"s", "Sort"},
+			{"v", "Chart"},
+			{"Ctrl+O", "Export"},
+			{"F4", "Help"},
+			{"Ctrl+Q", "Quit"},
+		}
+	}
 
 	var help strings.Builder
 	for i, item := range helpItems {
@@ -232,6 +501,16 @@ func (m *Model) renderFooter() string {
 		}
 	}
 
+	// The timing of the last query stays until the next one, unless the
+	// status message already tells it
+	if m.lastTiming != "" && !strings.Contains(strings.ToLower(m.statusMessage), strings.ToLower(m.lastTiming)) {
+		timing := m.styles.HelpDesc.Render("⏱ " + m.lastTiming)
+		if status != "" {
+			timing += "  "
+		}
+		status = timing + status
+	}
+
 	footer := help.String()
 	if status != "" {
 		spacing := m.width - lipgloss.Width(help.String()) - lipgloss.Width(status) - 2
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/export"
+	"github.com/febritecno/sqdesk-cli/internal/tui/components"
+)
+
+// startViewer turns the model into a viewer of a file's rows: the results
+// grid fills the window and nothing connects to a database
+func (m *Model) startViewer(path string, set db.ResultSet) {
+	m.viewerPath = path
+	m.state = StateNormal
+	m.focusedPane = PaneResults
+	m.sidebar.SetFocused(false)
+	m.editor.SetFocused(false)
+	m.results.SetFocused(true)
+	m.results.SetResultSets([]components.ResultSet{{
+		Columns:     set.Columns,
+		ColumnTypes: set.ColumnTypes,
+		Rows:        set.Rows,
+		Truncated:   set.Truncated,
+		Title:       filepath.Base(path),
+	}})
+	m.statusMessage = fmt.Sprintf("%d rows from %s", len(set.Rows), filepath.Base(path))
+	if set.Truncated {
+		m.statusMessage += fmt.Sprintf(" (first %d, see max_result_rows)", len(set.Rows))
+	}
+	m.isError = false
+}
+
+// updateViewer handles keys in the viewer: the results keys, except those
+// that write to a database or the editor, plus export and help
+func (m *Model) updateViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.results.IsFiltering() {
+		return m.handleResultsFilterKeys(msg)
+	}
+	switch msg.String() {
+	case "ctrl+o":
+		m.ShowExportMenu()
+		return m, nil
+	case "f4":
+		m.help.Toggle()
+		return m, nil
+	case "e", "D", "t", "i":
+		m.statusMessage = "The viewer is read-only"
+		m.isError = true
+		return m, nil
+	}
+	return m.handleResultsKeys(msg)
+}
+
+// viewerExportOptions are the export menu entries of the viewer, which
+// writes the rows as shown, filtered and sorted
+func viewerExportOptions() []exportOption {
+	return []exportOption{
+		{label: "📄 CSV", format: export.CSV, shown: true},
+		{label: "🧾 JSON", format: export.JSON, shown: true},
+		{label: "📝 Markdown", format: export.Markdown, shown: true},
+	}
+}
+
+// ExportShown writes the rows shown in the results grid to a file in the
+// current directory
+func (m *Model) ExportShown(format export.Format) {
+	set := m.results.ActiveResult()
+	if len(set.Columns) == 0 {
+		m.statusMessage = "No rows to export"
+		m.isError = true
+		return
+	}
+
+	path := fmt.Sprintf("sqdesk-export-%s.%s", time.Now().Format("20060102-150405"), format)
+	f, err := os.Create(path)
+	if err != nil {
+		m.statusMessage = "Export failed: " + err.Error()
+		m.isError = true
+		return
+	}
+	rs := db.ResultSet{Columns: set.Columns, ColumnTypes: set.ColumnTypes, Rows: set.Rows}
+	result, err := export.WriteResultSet(f, rs, export.Options{Format: format})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		m.statusMessage = "Export failed: " + err.Error()
+		m.isError = true
+		return
+	}
+	m.statusMessage = fmt.Sprintf("Exported %d rows to %s", result.Rows, path)
+	m.isError = false
+}
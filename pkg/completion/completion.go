@@ -0,0 +1,183 @@
+// Package completion suggests SQL keywords, tables, columns, indexes, joins
+// and past queries for a cursor position, as the SQDesk editor does.
+package completion
+
+import (
+	"github.com/febritecno/sqdesk-cli/internal/completion"
+	"github.com/febritecno/sqdesk-cli/internal/completion/sources"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/pkg/database"
+)
+
+// ItemKind says what an item completes
+type ItemKind int
+
+// Kinds of completion items
+const (
+	KindKeyword ItemKind = iota
+	KindTable
+	KindColumn
+	KindFunction
+	KindSnippet
+	KindAI
+	KindHistory
+	KindIndex
+	KindJoin
+)
+
+// itemKinds maps the engine's item kinds to ItemKind
+var itemKinds = map[completion.ItemKind]ItemKind{
+	completion.KindKeyword:  KindKeyword,
+	completion.KindTable:    KindTable,
+	completion.KindColumn:   KindColumn,
+	completion.KindFunction: KindFunction,
+	completion.KindSnippet:  KindSnippet,
+	completion.KindAI:       KindAI,
+	completion.KindHistory:  KindHistory,
+	completion.KindIndex:    KindIndex,
+	completion.KindJoin:     KindJoin,
+}
+
+// String returns a readable name for the kind, e.g. "Column"
+func (k ItemKind) String() string {
+	return k.engine().Name()
+}
+
+// engine converts k back to the engine's item kind
+func (k ItemKind) engine() completion.ItemKind {
+	for ek, v := range itemKinds {
+		if v == k {
+			return ek
+		}
+	}
+	return -1
+}
+
+// Item is one completion suggestion
+type Item struct {
+	Label      string // shown in a list of suggestions
+	InsertText string // replaces the word at the cursor
+	Kind       ItemKind
+	Detail     string // type or description
+	Source     string // name of the source that suggested it
+}
+
+// Context describes the cursor position a Source completes
+type Context struct {
+	Query      string   // full query text
+	Cursor     int      // byte offset of the cursor in Query
+	Word       string   // word being typed
+	WordStart  int      // byte offset of Word
+	Database   string   // current database name
+	Tables     []string // tables of the schema
+	LinePrefix string   // text before the cursor on its line
+}
+
+// Source provides completion items; register extra ones with
+// Completer.RegisterSource
+type Source interface {
+	// Name identifies the source
+	Name() string
+	// Complete returns the items for a cursor position
+	Complete(ctx Context) ([]Item, error)
+	// Priority orders sources; higher comes first
+	Priority() int
+}
+
+// engineSource adapts a Source to the completion engine
+type engineSource struct {
+	Source
+}
+
+func (s engineSource) Complete(ctx completion.Context) ([]completion.CompletionItem, error) {
+	items, err := s.Source.Complete(Context{
+		Query:      ctx.Query,
+		Cursor:     ctx.Cursor,
+		Word:       ctx.Word,
+		WordStart:  ctx.WordStart,
+		Database:   ctx.Database,
+		Tables:     ctx.Tables,
+		LinePrefix: ctx.LinePrefix,
+	})
+	out := make([]completion.CompletionItem, len(items))
+	for i, item := range items {
+		out[i] = completion.CompletionItem{
+			Label:      item.Label,
+			InsertText: item.InsertText,
+			Kind:       item.Kind.engine(),
+			Detail:     item.Detail,
+			Source:     s.Name(),
+			FilterText: item.Label,
+		}
+	}
+	return out, err
+}
+
+// Completer completes SQL from keywords of the connection's dialect, its
+// schema and the queries added to its history. It is not safe for
+// concurrent use.
+type Completer struct {
+	c *sources.Completer
+}
+
+// New returns a Completer with generic SQL keywords and no schema
+func New() *Completer {
+	return &Completer{c: sources.NewCompleter()}
+}
+
+// RegisterSource adds a source of completions
+func (c *Completer) RegisterSource(source Source) {
+	c.c.RegisterSource(engineSource{source})
+}
+
+// SetDriver adds the keywords of a driver's dialect ("postgres", "mysql",
+// ...); empty keeps generic SQL only
+func (c *Completer) SetDriver(driver string) {
+	c.c.SetDriver(driver)
+}
+
+// SetSchema replaces the tables, columns, indexes and foreign keys that
+// are suggested
+func (c *Completer) SetSchema(schema *database.Schema) {
+	if schema == nil {
+		c.c.SetSchema(nil)
+		return
+	}
+	tables := make(map[string]db.Table, len(schema.Tables))
+	for name, t := range schema.Tables {
+		table := db.Table{Name: t.Name}
+		for _, col := range t.Columns {
+			table.Columns = append(table.Columns, db.Column{Name: col.Name, Type: col.Type, Nullable: col.Nullable, IsPK: col.PrimaryKey})
+		}
+		for _, idx := range t.Indexes {
+			table.Indexes = append(table.Indexes, db.Index{Name: idx.Name, Columns: idx.Columns, Unique: idx.Unique})
+		}
+		for _, fk := range t.ForeignKeys {
+			table.ForeignKeys = append(table.ForeignKeys, db.ForeignKey{Columns: fk.Columns, RefTable: fk.RefTable, RefColumns: fk.RefColumns})
+		}
+		tables[name] = table
+	}
+	c.c.SetSchema(&db.Schema{Tables: tables})
+}
+
+// AddHistory remembers a query for history completions
+func (c *Completer) AddHistory(query string) {
+	c.c.AddHistory(query)
+}
+
+// Complete returns the suggestions for the byte offset cursor in query,
+// best first. database is the current database name, if any.
+func (c *Completer) Complete(query string, cursor int, database string) []Item {
+	items := c.c.Complete(query, cursor, database)
+	out := make([]Item, len(items))
+	for i, item := range items {
+		out[i] = Item{
+			Label:      item.Label,
+			InsertText: item.InsertText,
+			Kind:       itemKinds[item.Kind],
+			Detail:     item.Detail,
+			Source:     item.Source,
+		}
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+// Package database opens SQDesk database connections for use outside the
+// TUI. It connects to MySQL, PostgreSQL, SQLite, CockroachDB, BigQuery and
+// Cassandra through the app's connector layer, behind a narrow Connector
+// and types of its own.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// Config describes one connection
+type Config struct {
+	Driver   string // postgres, cockroachdb, mysql, mariadb, sqlite, bigquery, cassandra
+	Host     string // the database file for SQLite, the project for BigQuery
+	Port     int
+	User     string
+	Password string
+	Database string
+	// Socket is a unix socket path used instead of Host (Postgres, MySQL)
+	Socket string
+	// SSLMode is disable, require, verify-ca or verify-full. SSLRootCert
+	// is a CA bundle; SSLCert and SSLKey are a client certificate.
+	SSLMode     string
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+	// CredentialsFile is the service account JSON key path (BigQuery)
+	CredentialsFile string
+	// ReadOnly refuses statements that modify data and opens the session
+	// read-only where the database supports it
+	ReadOnly bool
+	// ConnectTimeout bounds connecting; zero is the app's default
+	ConnectTimeout time.Duration
+}
+
+// config converts c to the app's connection config
+func (c *Config) config() *config.DatabaseConfig {
+	cfg := &config.DatabaseConfig{
+		Driver:          c.Driver,
+		Host:            c.Host,
+		Port:            c.Port,
+		User:            c.User,
+		Password:        c.Password,
+		Database:        c.Database,
+		Socket:          c.Socket,
+		SSLMode:         c.SSLMode,
+		SSLRootCert:     c.SSLRootCert,
+		SSLCert:         c.SSLCert,
+		SSLKey:          c.SSLKey,
+		CredentialsFile: c.CredentialsFile,
+		ReadOnly:        c.ReadOnly,
+	}
+	if c.ConnectTimeout > 0 {
+		// Whole seconds, at least one
+		cfg.ConnectTimeout = max(int(c.ConnectTimeout/time.Second), 1)
+	}
+	return cfg
+}
+
+// ParseURL parses a connection URL such as postgres://user@host/db, a Go
+// MySQL DSN or sqlite:///path/to/file.db into a Config
+func ParseURL(raw string) (*Config, error) {
+	cfg, err := config.ParseConnectionURL(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{
+		Driver:          cfg.Driver,
+		Host:            cfg.Host,
+		Port:            cfg.Port,
+		User:            cfg.User,
+		Password:        cfg.Password,
+		Database:        cfg.Database,
+		Socket:          cfg.Socket,
+		SSLMode:         cfg.SSLMode,
+		SSLRootCert:     cfg.SSLRootCert,
+		SSLCert:         cfg.SSLCert,
+		SSLKey:          cfg.SSLKey,
+		CredentialsFile: cfg.CredentialsFile,
+	}, nil
+}
+
+// Connector is an open database connection. It is not safe for
+// concurrent use.
+type Connector interface {
+	// Driver returns the driver name, e.g. "postgres"
+	Driver() string
+	// Database returns the current database name
+	Database() string
+	// Query runs sql, which may hold several statements, and returns one
+	// result set per statement
+	Query(ctx context.Context, sql string) ([]ResultSet, error)
+	// Execute runs a statement that returns no rows and reports the
+	// affected row count
+	Execute(ctx context.Context, sql string) (int64, error)
+	// Stream runs a query, calls onColumns once with its columns and then
+	// onRow for each row without holding the result in memory where the
+	// driver allows. values is reused between calls.
+	Stream(ctx context.Context, sql string, onColumns func(columns []ColumnType) error, onRow func(values []Value) error) error
+	// Schema reads the tables with their columns, indexes and foreign keys
+	Schema() (*Schema, error)
+	// SetResultLimit caps the rows and bytes Query keeps of a result set;
+	// zero means no limit. Truncated marks a capped result set.
+	SetResultLimit(rows int, bytes int64)
+	// Close closes the connection
+	Close() error
+}
+
+// Open connects to cfg, giving up after the connection's timeout or when
+// ctx is cancelled. The caller closes the connector.
+func Open(ctx context.Context, cfg *Config) (Connector, error) {
+	c, err := db.Open(ctx, cfg.config())
+	if err != nil {
+		return nil, err
+	}
+	return &connector{c: c}, nil
+}
+
+// QuoteIdentifier quotes name for the SQL dialect of driver
+func QuoteIdentifier(driver, name string) string {
+	return db.QuoteIdentifier(driver, name)
+}
+
+// connector adapts an app connector to Connector
+type connector struct {
+	c db.Connector
+}
+
+func (c *connector) Driver() string   { return c.c.GetDriverName() }
+func (c *connector) Database() string { return c.c.GetDatabaseName() }
+func (c *connector) Close() error     { return c.c.Close() }
+
+func (c *connector) Query(ctx context.Context, sql string) ([]ResultSet, error) {
+	sets, err := db.QueryMulti(ctx, c.c, sql)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ResultSet, len(sets))
+	for i, set := range sets {
+		out[i] = resultSet(set)
+	}
+	return out, nil
+}
+
+func (c *connector) Execute(ctx context.Context, sql string) (int64, error) {
+	return c.c.Execute(ctx, sql)
+}
+
+func (c *connector) Stream(ctx context.Context, sql string, onColumns func([]ColumnType) error, onRow func([]Value) error) error {
+	streamer, ok := db.GetRowStreamer(c.c)
+	if !ok {
+		// Drivers without streaming hold the first result set instead
+		sets, err := c.Query(ctx, sql)
+		if err != nil || len(sets) == 0 {
+			return err
+		}
+		if err := onColumns(sets[0].Columns); err != nil {
+			return err
+		}
+		for _, row := range sets[0].Rows {
+			if err := onRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var values []Value
+	return streamer.StreamQuery(ctx, sql, func(columns []db.ColumnType) error {
+		values = make([]Value, len(columns))
+		return onColumns(columnTypes(columns))
+	}, func(row []db.Value) error {
+		for i, v := range row {
+			values[i] = value(v)
+		}
+		return onRow(values)
+	})
+}
+
+func (c *connector) Schema() (*Schema, error) {
+	schema, err := c.c.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+	return newSchema(schema), nil
+}
+
+func (c *connector) SetResultLimit(rows int, bytes int64) {
+	db.SetResultLimit(c.c, db.ResultLimit{Rows: rows, Bytes: bytes})
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"github.com/febritecno/sqdesk-cli/internal/db"
+)
+
+// ColumnKind groups result columns by how their values are shown
+type ColumnKind int
+
+const (
+	ColumnText ColumnKind = iota
+	ColumnNumber
+	ColumnBool
+	ColumnDate // date without time of day
+	ColumnTime // timestamp, datetime or time of day
+	ColumnBinary
+)
+
+// columnKinds maps the app's column kinds to ColumnKind
+var columnKinds = map[db.ColumnKind]ColumnKind{
+	db.ColumnText:   ColumnText,
+	db.ColumnNumber: ColumnNumber,
+	db.ColumnBool:   ColumnBool,
+	db.ColumnDate:   ColumnDate,
+	db.ColumnTime:   ColumnTime,
+	db.ColumnBinary: ColumnBinary,
+}
+
+// ColumnType describes a result column
+type ColumnType struct {
+	Name         string
+	DatabaseType string // driver-reported type name, e.g. INT4 or VARCHAR
+	Kind         ColumnKind
+}
+
+// Value is one value of a result row
+type Value struct {
+	Data interface{} // string, int64, uint64, float64, Decimal, bool, time.Time, []byte, ...; nil when Null
+	Null bool
+}
+
+// Decimal is the exact text of a NUMERIC/DECIMAL value
+type Decimal string
+
+// Text formats the value by its column type as SQDesk shows and exports
+// it. NULL is empty.
+func (v Value) Text(colType ColumnType) string {
+	return v.app().Text(colType.app())
+}
+
+// String formats the value as text, with NULL shown as "NULL"
+func (v Value) String() string {
+	return v.app().String()
+}
+
+// ResultSet is one set of rows returned by a query, each row in column
+// order
+type ResultSet struct {
+	Columns []ColumnType
+	Rows    [][]Value
+	// Truncated is set when fetching stopped at the result limit
+	Truncated bool
+}
+
+// Schema is the tables of a database by name
+type Schema struct {
+	Tables map[string]Table
+}
+
+// Table is a table with its columns, indexes and foreign keys
+type Table struct {
+	Name        string
+	Columns     []Column
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+// Column is a table column
+type Column struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	PrimaryKey bool
+	Default    string // default expression; empty when there is none
+}
+
+// Index is an index of a table
+type Index struct {
+	Name    string
+	Columns []string // column names or expressions, in key order
+	Unique  bool
+	Primary bool
+}
+
+// ForeignKey is a foreign key of a table
+type ForeignKey struct {
+	Name       string
+	Columns    []string // referencing columns, in key order
+	RefTable   string
+	RefColumns []string // referenced columns, parallel to Columns
+}
+
+// value converts an app value
+func value(v db.Value) Value {
+	if d, ok := v.Data.(db.Decimal); ok {
+		return Value{Data: Decimal(d), Null: v.Null}
+	}
+	return Value{Data: v.Data, Null: v.Null}
+}
+
+// app converts v back to an app value
+func (v Value) app() db.Value {
+	if d, ok := v.Data.(Decimal); ok {
+		return db.Value{Data: db.Decimal(d), Null: v.Null}
+	}
+	return db.Value{Data: v.Data, Null: v.Null}
+}
+
+// columnTypes converts app column types
+func columnTypes(columns []db.ColumnType) []ColumnType {
+	out := make([]ColumnType, len(columns))
+	for i, col := range columns {
+		out[i] = ColumnType{Name: col.Name, DatabaseType: col.DatabaseType, Kind: columnKinds[col.Kind]}
+	}
+	return out
+}
+
+// app converts t back to an app column type
+func (t ColumnType) app() db.ColumnType {
+	kind := db.ColumnText
+	for k, v := range columnKinds {
+		if v == t.Kind {
+			kind = k
+		}
+	}
+	return db.ColumnType{Name: t.Name, DatabaseType: t.DatabaseType, Kind: kind}
+}
+
+// resultSet converts an app result set, putting row values in column order
+func resultSet(set db.ResultSet) ResultSet {
+	types := make([]db.ColumnType, len(set.Columns))
+	for i, name := range set.Columns {
+		if i < len(set.ColumnTypes) {
+			types[i] = set.ColumnTypes[i]
+		}
+		types[i].Name = name
+	}
+	out := ResultSet{Columns: columnTypes(types), Rows: make([][]Value, len(set.Rows)), Truncated: set.Truncated}
+	for i, row := range set.Rows {
+		values := make([]Value, len(set.Columns))
+		for j, name := range set.Columns {
+			values[j] = value(row[name])
+		}
+		out.Rows[i] = values
+	}
+	return out
+}
+
+// newSchema converts an app schema
+func newSchema(schema *db.Schema) *Schema {
+	out := &Schema{Tables: make(map[string]Table, len(schema.Tables))}
+	for name, t := range schema.Tables {
+		table := Table{Name: t.Name}
+		for _, col := range t.Columns {
+			table.Columns = append(table.Columns, Column{Name: col.Name, Type: col.Type, Nullable: col.Nullable, PrimaryKey: col.IsPK, Default: col.Default})
+		}
+		for _, idx := range t.Indexes {
+			table.Indexes = append(table.Indexes, Index{Name: idx.Name, Columns: idx.Columns, Unique: idx.Unique, Primary: idx.Primary})
+		}
+		for _, fk := range t.ForeignKeys {
+			table.ForeignKeys = append(table.ForeignKeys, ForeignKey{Name: fk.Name, Columns: fk.Columns, RefTable: fk.RefTable, RefColumns: fk.RefColumns})
+		}
+		out.Tables[name] = table
+	}
+	return out
+}
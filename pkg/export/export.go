@@ -0,0 +1,132 @@
+// Package export streams every row of a query to CSV, JSON or a Markdown
+// table, optionally masking sensitive columns, without holding the result
+// in memory.
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/febritecno/sqdesk-cli/internal/config"
+	"github.com/febritecno/sqdesk-cli/internal/db"
+	"github.com/febritecno/sqdesk-cli/internal/export"
+	"github.com/febritecno/sqdesk-cli/pkg/database"
+)
+
+// Format is the file format of an export
+type Format string
+
+const (
+	// CSV writes a header and one record per row; NULL is empty
+	CSV Format = "csv"
+	// JSON writes an array with one object per row, keys in column order
+	JSON Format = "json"
+	// Markdown writes a GitHub-flavored Markdown table; numeric columns
+	// are right-aligned and NULL is written as NULL
+	Markdown Format = "md"
+)
+
+// MaskRule masks the columns whose name matches Column
+type MaskRule struct {
+	// Column is matched case-insensitively against result column names;
+	// * matches any run of characters, e.g. *email*
+	Column string
+	// Method is hash (default), redact or fake
+	Method string
+}
+
+// Options configure an export
+type Options struct {
+	Format Format
+	// Mask lists the rules for columns to mask; the first matching rule
+	// applies
+	Mask []MaskRule
+}
+
+// Result sums up a finished export
+type Result struct {
+	Rows          int64
+	MaskedColumns int
+}
+
+// ValidateMask reports the first invalid rule of rules
+func ValidateMask(rules []MaskRule) error {
+	return export.ValidateMask(maskRules(rules))
+}
+
+// Write runs sql on c and writes every row to w. Values are formatted
+// like copied cells; NULL is never masked.
+func Write(ctx context.Context, c database.Connector, sql string, w io.Writer, opts Options) (Result, error) {
+	return write(ctx, c.Stream, sql, w, opts)
+}
+
+// WriteResultSet writes the rows of a result set already in memory to w,
+// like Write
+func WriteResultSet(w io.Writer, set database.ResultSet, opts Options) (Result, error) {
+	replay := func(_ context.Context, _ string, onColumns func([]database.ColumnType) error, onRow func([]database.Value) error) error {
+		if err := onColumns(set.Columns); err != nil {
+			return err
+		}
+		for _, row := range set.Rows {
+			if err := onRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return write(context.Background(), replay, "", w, opts)
+}
+
+// streamFunc is the signature of Connector.Stream
+type streamFunc func(ctx context.Context, sql string, onColumns func([]database.ColumnType) error, onRow func([]database.Value) error) error
+
+// write exports the rows of stream through the app's exporter
+func write(ctx context.Context, stream streamFunc, sql string, w io.Writer, opts Options) (Result, error) {
+	result, err := export.Write(ctx, streamer(stream), sql, w, export.Options{
+		Format: export.Format(opts.Format),
+		Mask:   maskRules(opts.Mask),
+	})
+	return Result{Rows: result.Rows, MaskedColumns: result.MaskedColumns}, err
+}
+
+// columnKinds maps column kinds to the app's
+var columnKinds = map[database.ColumnKind]db.ColumnKind{
+	database.ColumnText:   db.ColumnText,
+	database.ColumnNumber: db.ColumnNumber,
+	database.ColumnBool:   db.ColumnBool,
+	database.ColumnDate:   db.ColumnDate,
+	database.ColumnTime:   db.ColumnTime,
+	database.ColumnBinary: db.ColumnBinary,
+}
+
+// streamer adapts a streamFunc to the app's row streaming
+type streamer streamFunc
+
+func (s streamer) StreamQuery(ctx context.Context, sql string, onColumns func([]db.ColumnType) error, onRow func([]db.Value) error) error {
+	var values []db.Value
+	return s(ctx, sql, func(columns []database.ColumnType) error {
+		types := make([]db.ColumnType, len(columns))
+		for i, col := range columns {
+			types[i] = db.ColumnType{Name: col.Name, DatabaseType: col.DatabaseType, Kind: columnKinds[col.Kind]}
+		}
+		values = make([]db.Value, len(columns))
+		return onColumns(types)
+	}, func(row []database.Value) error {
+		for i, v := range row {
+			values[i] = db.Value{Data: v.Data, Null: v.Null}
+			if d, ok := v.Data.(database.Decimal); ok {
+				values[i].Data = db.Decimal(d)
+			}
+		}
+		return onRow(values)
+	})
+}
+
+// maskRules converts mask rules to the app's
+func maskRules(rules []MaskRule) []config.MaskRule {
+	out := make([]config.MaskRule, len(rules))
+	for i, r := range rules {
+		out[i] = config.MaskRule{Column: r.Column, Method: r.Method}
+	}
+	return out
+}